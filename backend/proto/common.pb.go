@@ -394,7 +394,7 @@ func (x *Timestamp) GetNanos() int32 {
 // Money representation
 type Money struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AmountCents   int64                  `protobuf:"varint,1,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"` // Amount in cents to avoid floating point
+	AmountCents   int64                  `protobuf:"varint,1,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"` // Amount in minor units of the currency (cents for USD, yen for JPY) to avoid floating point
 	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`                           // ISO 4217 currency code (USD, EUR, etc.)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
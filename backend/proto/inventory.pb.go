@@ -617,6 +617,7 @@ type ReservationResult struct {
 	Reserved          bool                   `protobuf:"varint,3,opt,name=reserved,proto3" json:"reserved,omitempty"`
 	AvailableQuantity int32                  `protobuf:"varint,4,opt,name=available_quantity,json=availableQuantity,proto3" json:"available_quantity,omitempty"`
 	Error             string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	ReservationId     string                 `protobuf:"bytes,6,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"` // Reservation line ID; release it alone to free just this item
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
@@ -686,6 +687,13 @@ func (x *ReservationResult) GetError() string {
 	return ""
 }
 
+func (x *ReservationResult) GetReservationId() string {
+	if x != nil {
+		return x.ReservationId
+	}
+	return ""
+}
+
 // Release reservation request
 type ReleaseReservationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1307,7 +1315,7 @@ const file_inventory_proto_rawDesc = "" +
 	"\x14ReserveStockResponse\x12%\n" +
 	"\x0ereservation_id\x18\x01 \x01(\tR\rreservationId\x12\x18\n" +
 	"\asuccess\x18\x02 \x01(\bR\asuccess\x126\n" +
-	"\aresults\x18\x03 \x03(\v2\x1c.inventory.ReservationResultR\aresults\"\xb2\x01\n" +
+	"\aresults\x18\x03 \x03(\v2\x1c.inventory.ReservationResultR\aresults\"\xd9\x01\n" +
 	"\x11ReservationResult\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1d\n" +
@@ -1315,7 +1323,8 @@ const file_inventory_proto_rawDesc = "" +
 	"variant_id\x18\x02 \x01(\tR\tvariantId\x12\x1a\n" +
 	"\breserved\x18\x03 \x01(\bR\breserved\x12-\n" +
 	"\x12available_quantity\x18\x04 \x01(\x05R\x11availableQuantity\x12\x14\n" +
-	"\x05error\x18\x05 \x01(\tR\x05error\"]\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12%\n" +
+	"\x0ereservation_id\x18\x06 \x01(\tR\rreservationId\"]\n" +
 	"\x19ReleaseReservationRequest\x12%\n" +
 	"\x0ereservation_id\x18\x01 \x01(\tR\rreservationId\x12\x19\n" +
 	"\border_id\x18\x02 \x01(\tR\aorderId\"6\n" +
@@ -22,6 +22,59 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Order item fulfilment status
+type OrderItemStatus int32
+
+const (
+	OrderItemStatus_ITEM_PENDING   OrderItemStatus = 0
+	OrderItemStatus_ITEM_FULFILLED OrderItemStatus = 1
+	OrderItemStatus_ITEM_CANCELLED OrderItemStatus = 2
+	OrderItemStatus_ITEM_RETURNED  OrderItemStatus = 3
+)
+
+// Enum value maps for OrderItemStatus.
+var (
+	OrderItemStatus_name = map[int32]string{
+		0: "ITEM_PENDING",
+		1: "ITEM_FULFILLED",
+		2: "ITEM_CANCELLED",
+		3: "ITEM_RETURNED",
+	}
+	OrderItemStatus_value = map[string]int32{
+		"ITEM_PENDING":   0,
+		"ITEM_FULFILLED": 1,
+		"ITEM_CANCELLED": 2,
+		"ITEM_RETURNED":  3,
+	}
+)
+
+func (x OrderItemStatus) Enum() *OrderItemStatus {
+	p := new(OrderItemStatus)
+	*p = x
+	return p
+}
+
+func (x OrderItemStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderItemStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_order_proto_enumTypes[0].Descriptor()
+}
+
+func (OrderItemStatus) Type() protoreflect.EnumType {
+	return &file_order_proto_enumTypes[0]
+}
+
+func (x OrderItemStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderItemStatus.Descriptor instead.
+func (OrderItemStatus) EnumDescriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{0}
+}
+
 // Order status
 type OrderStatus int32
 
@@ -77,11 +130,11 @@ func (x OrderStatus) String() string {
 }
 
 func (OrderStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_order_proto_enumTypes[0].Descriptor()
+	return file_order_proto_enumTypes[1].Descriptor()
 }
 
 func (OrderStatus) Type() protoreflect.EnumType {
-	return &file_order_proto_enumTypes[0]
+	return &file_order_proto_enumTypes[1]
 }
 
 func (x OrderStatus) Number() protoreflect.EnumNumber {
@@ -90,7 +143,166 @@ func (x OrderStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use OrderStatus.Descriptor instead.
 func (OrderStatus) EnumDescriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{0}
+	return file_order_proto_rawDescGZIP(), []int{1}
+}
+
+// Return status
+type ReturnStatus int32
+
+const (
+	ReturnStatus_RETURN_REQUESTED ReturnStatus = 0
+	ReturnStatus_RETURN_APPROVED  ReturnStatus = 1
+	ReturnStatus_RETURN_REJECTED  ReturnStatus = 2
+	ReturnStatus_RETURN_RECEIVED  ReturnStatus = 3
+	ReturnStatus_RETURN_REFUNDED  ReturnStatus = 4
+)
+
+// Enum value maps for ReturnStatus.
+var (
+	ReturnStatus_name = map[int32]string{
+		0: "RETURN_REQUESTED",
+		1: "RETURN_APPROVED",
+		2: "RETURN_REJECTED",
+		3: "RETURN_RECEIVED",
+		4: "RETURN_REFUNDED",
+	}
+	ReturnStatus_value = map[string]int32{
+		"RETURN_REQUESTED": 0,
+		"RETURN_APPROVED":  1,
+		"RETURN_REJECTED":  2,
+		"RETURN_RECEIVED":  3,
+		"RETURN_REFUNDED":  4,
+	}
+)
+
+func (x ReturnStatus) Enum() *ReturnStatus {
+	p := new(ReturnStatus)
+	*p = x
+	return p
+}
+
+func (x ReturnStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReturnStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_order_proto_enumTypes[2].Descriptor()
+}
+
+func (ReturnStatus) Type() protoreflect.EnumType {
+	return &file_order_proto_enumTypes[2]
+}
+
+func (x ReturnStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReturnStatus.Descriptor instead.
+func (ReturnStatus) EnumDescriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{2}
+}
+
+// Shipment status as reported by the carrier
+type ShipmentStatus int32
+
+const (
+	ShipmentStatus_SHIPMENT_LABEL_CREATED    ShipmentStatus = 0
+	ShipmentStatus_SHIPMENT_IN_TRANSIT       ShipmentStatus = 1
+	ShipmentStatus_SHIPMENT_OUT_FOR_DELIVERY ShipmentStatus = 2
+	ShipmentStatus_SHIPMENT_DELIVERED        ShipmentStatus = 3
+	ShipmentStatus_SHIPMENT_EXCEPTION        ShipmentStatus = 4
+)
+
+// Enum value maps for ShipmentStatus.
+var (
+	ShipmentStatus_name = map[int32]string{
+		0: "SHIPMENT_LABEL_CREATED",
+		1: "SHIPMENT_IN_TRANSIT",
+		2: "SHIPMENT_OUT_FOR_DELIVERY",
+		3: "SHIPMENT_DELIVERED",
+		4: "SHIPMENT_EXCEPTION",
+	}
+	ShipmentStatus_value = map[string]int32{
+		"SHIPMENT_LABEL_CREATED":    0,
+		"SHIPMENT_IN_TRANSIT":       1,
+		"SHIPMENT_OUT_FOR_DELIVERY": 2,
+		"SHIPMENT_DELIVERED":        3,
+		"SHIPMENT_EXCEPTION":        4,
+	}
+)
+
+func (x ShipmentStatus) Enum() *ShipmentStatus {
+	p := new(ShipmentStatus)
+	*p = x
+	return p
+}
+
+func (x ShipmentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ShipmentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_order_proto_enumTypes[3].Descriptor()
+}
+
+func (ShipmentStatus) Type() protoreflect.EnumType {
+	return &file_order_proto_enumTypes[3]
+}
+
+func (x ShipmentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ShipmentStatus.Descriptor instead.
+func (ShipmentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{3}
+}
+
+// Rendering of an invoice document
+type InvoiceFormat int32
+
+const (
+	InvoiceFormat_INVOICE_FORMAT_PDF  InvoiceFormat = 0
+	InvoiceFormat_INVOICE_FORMAT_JSON InvoiceFormat = 1 // UBL 2.1 invoice in JSON
+)
+
+// Enum value maps for InvoiceFormat.
+var (
+	InvoiceFormat_name = map[int32]string{
+		0: "INVOICE_FORMAT_PDF",
+		1: "INVOICE_FORMAT_JSON",
+	}
+	InvoiceFormat_value = map[string]int32{
+		"INVOICE_FORMAT_PDF":  0,
+		"INVOICE_FORMAT_JSON": 1,
+	}
+)
+
+func (x InvoiceFormat) Enum() *InvoiceFormat {
+	p := new(InvoiceFormat)
+	*p = x
+	return p
+}
+
+func (x InvoiceFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (InvoiceFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_order_proto_enumTypes[4].Descriptor()
+}
+
+func (InvoiceFormat) Type() protoreflect.EnumType {
+	return &file_order_proto_enumTypes[4]
+}
+
+func (x InvoiceFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use InvoiceFormat.Descriptor instead.
+func (InvoiceFormat) EnumDescriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{4}
 }
 
 // Order message
@@ -112,6 +324,11 @@ type Order struct {
 	Tracking        *TrackingInfo          `protobuf:"bytes,14,opt,name=tracking,proto3" json:"tracking,omitempty"`
 	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	CouponCode      string                 `protobuf:"bytes,17,opt,name=coupon_code,json=couponCode,proto3" json:"coupon_code,omitempty"`
+	Refunded        *Money                 `protobuf:"bytes,18,opt,name=refunded,proto3" json:"refunded,omitempty"`
+	PaymentStatus   string                 `protobuf:"bytes,19,opt,name=payment_status,json=paymentStatus,proto3" json:"payment_status,omitempty"` // DISPUTED once the payment is disputed
+	DisputeId       string                 `protobuf:"bytes,20,opt,name=dispute_id,json=disputeId,proto3" json:"dispute_id,omitempty"`
+	DisputedAt      *timestamppb.Timestamp `protobuf:"bytes,21,opt,name=disputed_at,json=disputedAt,proto3" json:"disputed_at,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -258,6 +475,41 @@ func (x *Order) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Order) GetCouponCode() string {
+	if x != nil {
+		return x.CouponCode
+	}
+	return ""
+}
+
+func (x *Order) GetRefunded() *Money {
+	if x != nil {
+		return x.Refunded
+	}
+	return nil
+}
+
+func (x *Order) GetPaymentStatus() string {
+	if x != nil {
+		return x.PaymentStatus
+	}
+	return ""
+}
+
+func (x *Order) GetDisputeId() string {
+	if x != nil {
+		return x.DisputeId
+	}
+	return ""
+}
+
+func (x *Order) GetDisputedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DisputedAt
+	}
+	return nil
+}
+
 // Order item
 type OrderItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -270,6 +522,7 @@ type OrderItem struct {
 	Quantity      int32                  `protobuf:"varint,7,opt,name=quantity,proto3" json:"quantity,omitempty"`
 	UnitPrice     *Money                 `protobuf:"bytes,8,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
 	TotalPrice    *Money                 `protobuf:"bytes,9,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	Status        OrderItemStatus        `protobuf:"varint,10,opt,name=status,proto3,enum=order.OrderItemStatus" json:"status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -367,6 +620,13 @@ func (x *OrderItem) GetTotalPrice() *Money {
 	return nil
 }
 
+func (x *OrderItem) GetStatus() OrderItemStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderItemStatus_ITEM_PENDING
+}
+
 // Tracking information
 type TrackingInfo struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
@@ -453,6 +713,7 @@ type CreateOrderRequest struct {
 	BillingAddressId  string                 `protobuf:"bytes,4,opt,name=billing_address_id,json=billingAddressId,proto3" json:"billing_address_id,omitempty"`
 	PaymentMethod     string                 `protobuf:"bytes,5,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
 	CouponCode        string                 `protobuf:"bytes,6,opt,name=coupon_code,json=couponCode,proto3" json:"coupon_code,omitempty"`
+	IdempotencyKey    string                 `protobuf:"bytes,7,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // Client-generated key; retries with the same key replay the original order
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
@@ -529,6 +790,13 @@ func (x *CreateOrderRequest) GetCouponCode() string {
 	return ""
 }
 
+func (x *CreateOrderRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type OrderItemRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
@@ -801,9 +1069,15 @@ func (x *ListOrdersRequest) GetFilters() *OrderFilters {
 
 type OrderFilters struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        OrderStatus            `protobuf:"varint,1,opt,name=status,proto3,enum=order.OrderStatus" json:"status,omitempty"`
-	FromDate      *Timestamp             `protobuf:"bytes,2,opt,name=from_date,json=fromDate,proto3" json:"from_date,omitempty"`
-	ToDate        *Timestamp             `protobuf:"bytes,3,opt,name=to_date,json=toDate,proto3" json:"to_date,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,1,opt,name=status,proto3,enum=order.OrderStatus" json:"status,omitempty"`            // Unused by SearchOrders: the zero value is PENDING, use statuses instead
+	FromDate      *Timestamp             `protobuf:"bytes,2,opt,name=from_date,json=fromDate,proto3" json:"from_date,omitempty"`                // Inclusive lower bound on created_at
+	ToDate        *Timestamp             `protobuf:"bytes,3,opt,name=to_date,json=toDate,proto3" json:"to_date,omitempty"`                      // Exclusive upper bound on created_at
+	Statuses      []OrderStatus          `protobuf:"varint,4,rep,packed,name=statuses,proto3,enum=order.OrderStatus" json:"statuses,omitempty"` // Empty matches any status
+	MinTotal      *Money                 `protobuf:"bytes,5,opt,name=min_total,json=minTotal,proto3" json:"min_total,omitempty"`
+	MaxTotal      *Money                 `protobuf:"bytes,6,opt,name=max_total,json=maxTotal,proto3" json:"max_total,omitempty"`
+	PaymentStatus string                 `protobuf:"bytes,7,opt,name=payment_status,json=paymentStatus,proto3" json:"payment_status,omitempty"`
+	ProductId     string                 `protobuf:"bytes,8,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"` // Orders containing this product
+	UserId        string                 `protobuf:"bytes,9,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -859,6 +1133,48 @@ func (x *OrderFilters) GetToDate() *Timestamp {
 	return nil
 }
 
+func (x *OrderFilters) GetStatuses() []OrderStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *OrderFilters) GetMinTotal() *Money {
+	if x != nil {
+		return x.MinTotal
+	}
+	return nil
+}
+
+func (x *OrderFilters) GetMaxTotal() *Money {
+	if x != nil {
+		return x.MaxTotal
+	}
+	return nil
+}
+
+func (x *OrderFilters) GetPaymentStatus() string {
+	if x != nil {
+		return x.PaymentStatus
+	}
+	return ""
+}
+
+func (x *OrderFilters) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *OrderFilters) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
 type ListOrdersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
@@ -911,30 +1227,30 @@ func (x *ListOrdersResponse) GetPagination() *PaginationResponse {
 	return nil
 }
 
-// Cancel order request
-type CancelOrderRequest struct {
+// Search orders request; pass the previous response's next_cursor to get the next page
+type SearchOrdersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Filters       *OrderFilters          `protobuf:"bytes,1,opt,name=filters,proto3" json:"filters,omitempty"`
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CancelOrderRequest) Reset() {
-	*x = CancelOrderRequest{}
+func (x *SearchOrdersRequest) Reset() {
+	*x = SearchOrdersRequest{}
 	mi := &file_order_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelOrderRequest) String() string {
+func (x *SearchOrdersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelOrderRequest) ProtoMessage() {}
+func (*SearchOrdersRequest) ProtoMessage() {}
 
-func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+func (x *SearchOrdersRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_order_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -946,54 +1262,54 @@ func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
-func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SearchOrdersRequest.ProtoReflect.Descriptor instead.
+func (*SearchOrdersRequest) Descriptor() ([]byte, []int) {
 	return file_order_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *CancelOrderRequest) GetId() string {
+func (x *SearchOrdersRequest) GetFilters() *OrderFilters {
 	if x != nil {
-		return x.Id
+		return x.Filters
 	}
-	return ""
+	return nil
 }
 
-func (x *CancelOrderRequest) GetUserId() string {
+func (x *SearchOrdersRequest) GetCursor() string {
 	if x != nil {
-		return x.UserId
+		return x.Cursor
 	}
 	return ""
 }
 
-func (x *CancelOrderRequest) GetReason() string {
+func (x *SearchOrdersRequest) GetLimit() int32 {
 	if x != nil {
-		return x.Reason
+		return x.Limit
 	}
-	return ""
+	return 0
 }
 
-type CancelOrderResponse struct {
+type SearchOrdersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Order         *Order                 `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // Empty on the last page
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CancelOrderResponse) Reset() {
-	*x = CancelOrderResponse{}
+func (x *SearchOrdersResponse) Reset() {
+	*x = SearchOrdersResponse{}
 	mi := &file_order_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelOrderResponse) String() string {
+func (x *SearchOrdersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelOrderResponse) ProtoMessage() {}
+func (*SearchOrdersResponse) ProtoMessage() {}
 
-func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+func (x *SearchOrdersResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_order_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1005,50 +1321,49 @@ func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
-func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SearchOrdersResponse.ProtoReflect.Descriptor instead.
+func (*SearchOrdersResponse) Descriptor() ([]byte, []int) {
 	return file_order_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *CancelOrderResponse) GetSuccess() bool {
+func (x *SearchOrdersResponse) GetOrders() []*Order {
 	if x != nil {
-		return x.Success
+		return x.Orders
 	}
-	return false
+	return nil
 }
 
-func (x *CancelOrderResponse) GetOrder() *Order {
+func (x *SearchOrdersResponse) GetNextCursor() string {
 	if x != nil {
-		return x.Order
+		return x.NextCursor
 	}
-	return nil
+	return ""
 }
 
-// Update order status request
-type UpdateOrderStatusRequest struct {
+// Cancel order request
+type CancelOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Status        OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=order.OrderStatus" json:"status,omitempty"`
-	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
-	Tracking      *TrackingInfo          `protobuf:"bytes,4,opt,name=tracking,proto3" json:"tracking,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateOrderStatusRequest) Reset() {
-	*x = UpdateOrderStatusRequest{}
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
 	mi := &file_order_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateOrderStatusRequest) String() string {
+func (x *CancelOrderRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateOrderStatusRequest) ProtoMessage() {}
+func (*CancelOrderRequest) ProtoMessage() {}
 
-func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_order_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1060,61 +1375,1955 @@ func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
 	return file_order_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *UpdateOrderStatusRequest) GetId() string {
+func (x *CancelOrderRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *UpdateOrderStatusRequest) GetStatus() OrderStatus {
+func (x *CancelOrderRequest) GetUserId() string {
 	if x != nil {
-		return x.Status
+		return x.UserId
 	}
-	return OrderStatus_PENDING
+	return ""
 }
 
-func (x *UpdateOrderStatusRequest) GetNote() string {
+func (x *CancelOrderRequest) GetReason() string {
 	if x != nil {
-		return x.Note
+		return x.Reason
+	}
+	return ""
+}
+
+type CancelOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Order         *Order                 `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_order_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CancelOrderResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CancelOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+// Update order status request
+type UpdateOrderStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=order.OrderStatus" json:"status,omitempty"`
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	Tracking      *TrackingInfo          `protobuf:"bytes,4,opt,name=tracking,proto3" json:"tracking,omitempty"`
+	UpdatedBy     string                 `protobuf:"bytes,5,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderStatusRequest) Reset() {
+	*x = UpdateOrderStatusRequest{}
+	mi := &file_order_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderStatusRequest) ProtoMessage() {}
+
+func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateOrderStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateOrderStatusRequest) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_PENDING
+}
+
+func (x *UpdateOrderStatusRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *UpdateOrderStatusRequest) GetTracking() *TrackingInfo {
+	if x != nil {
+		return x.Tracking
+	}
+	return nil
+}
+
+func (x *UpdateOrderStatusRequest) GetUpdatedBy() string {
+	if x != nil {
+		return x.UpdatedBy
+	}
+	return ""
+}
+
+type UpdateOrderStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderStatusResponse) Reset() {
+	*x = UpdateOrderStatusResponse{}
+	mi := &file_order_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderStatusResponse) ProtoMessage() {}
+
+func (x *UpdateOrderStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateOrderStatusResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpdateOrderStatusResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+// Get order status history
+type GetOrderStatusHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderStatusHistoryRequest) Reset() {
+	*x = GetOrderStatusHistoryRequest{}
+	mi := &file_order_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderStatusHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderStatusHistoryRequest) ProtoMessage() {}
+
+func (x *GetOrderStatusHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderStatusHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderStatusHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetOrderStatusHistoryRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetOrderStatusHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	History       []*OrderStatusHistory  `protobuf:"bytes,1,rep,name=history,proto3" json:"history,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderStatusHistoryResponse) Reset() {
+	*x = GetOrderStatusHistoryResponse{}
+	mi := &file_order_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderStatusHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderStatusHistoryResponse) ProtoMessage() {}
+
+func (x *GetOrderStatusHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderStatusHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderStatusHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetOrderStatusHistoryResponse) GetHistory() []*OrderStatusHistory {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+type OrderStatusHistory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        OrderStatus            `protobuf:"varint,1,opt,name=status,proto3,enum=order.OrderStatus" json:"status,omitempty"`
+	Note          string                 `protobuf:"bytes,2,opt,name=note,proto3" json:"note,omitempty"`
+	Timestamp     *Timestamp             `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	UpdatedBy     string                 `protobuf:"bytes,4,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`
+	FromStatus    *OrderStatus           `protobuf:"varint,5,opt,name=from_status,json=fromStatus,proto3,enum=order.OrderStatus,oneof" json:"from_status,omitempty"` // Unset for the entry that created the order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusHistory) Reset() {
+	*x = OrderStatusHistory{}
+	mi := &file_order_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusHistory) ProtoMessage() {}
+
+func (x *OrderStatusHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusHistory.ProtoReflect.Descriptor instead.
+func (*OrderStatusHistory) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *OrderStatusHistory) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_PENDING
+}
+
+func (x *OrderStatusHistory) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *OrderStatusHistory) GetTimestamp() *Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *OrderStatusHistory) GetUpdatedBy() string {
+	if x != nil {
+		return x.UpdatedBy
+	}
+	return ""
+}
+
+func (x *OrderStatusHistory) GetFromStatus() OrderStatus {
+	if x != nil && x.FromStatus != nil {
+		return *x.FromStatus
+	}
+	return OrderStatus_PENDING
+}
+
+// Cancel order item request
+type CancelOrderItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ItemId        string                 `protobuf:"bytes,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderItemRequest) Reset() {
+	*x = CancelOrderItemRequest{}
+	mi := &file_order_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderItemRequest) ProtoMessage() {}
+
+func (x *CancelOrderItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderItemRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderItemRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CancelOrderItemRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CancelOrderItemRequest) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *CancelOrderItemRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CancelOrderItemRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type CancelOrderItemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	RefundAmount  *Money                 `protobuf:"bytes,2,opt,name=refund_amount,json=refundAmount,proto3" json:"refund_amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderItemResponse) Reset() {
+	*x = CancelOrderItemResponse{}
+	mi := &file_order_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderItemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderItemResponse) ProtoMessage() {}
+
+func (x *CancelOrderItemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderItemResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderItemResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CancelOrderItemResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *CancelOrderItemResponse) GetRefundAmount() *Money {
+	if x != nil {
+		return x.RefundAmount
+	}
+	return nil
+}
+
+// Return merchandise authorization
+type OrderReturn struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId         string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId          string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Items           []*OrderReturnItem     `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	Status          ReturnStatus           `protobuf:"varint,5,opt,name=status,proto3,enum=order.ReturnStatus" json:"status,omitempty"`
+	Reason          string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	RejectionReason string                 `protobuf:"bytes,7,opt,name=rejection_reason,json=rejectionReason,proto3" json:"rejection_reason,omitempty"`
+	RefundAmount    *Money                 `protobuf:"bytes,8,opt,name=refund_amount,json=refundAmount,proto3" json:"refund_amount,omitempty"`
+	ReviewedBy      string                 `protobuf:"bytes,9,opt,name=reviewed_by,json=reviewedBy,proto3" json:"reviewed_by,omitempty"`
+	ReceivedBy      string                 `protobuf:"bytes,10,opt,name=received_by,json=receivedBy,proto3" json:"received_by,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *OrderReturn) Reset() {
+	*x = OrderReturn{}
+	mi := &file_order_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderReturn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderReturn) ProtoMessage() {}
+
+func (x *OrderReturn) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderReturn.ProtoReflect.Descriptor instead.
+func (*OrderReturn) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *OrderReturn) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetItems() []*OrderReturnItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *OrderReturn) GetStatus() ReturnStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ReturnStatus_RETURN_REQUESTED
+}
+
+func (x *OrderReturn) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetRejectionReason() string {
+	if x != nil {
+		return x.RejectionReason
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetRefundAmount() *Money {
+	if x != nil {
+		return x.RefundAmount
+	}
+	return nil
+}
+
+func (x *OrderReturn) GetReviewedBy() string {
+	if x != nil {
+		return x.ReviewedBy
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetReceivedBy() string {
+	if x != nil {
+		return x.ReceivedBy
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *OrderReturn) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type OrderReturnItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderItemId   string                 `protobuf:"bytes,2,opt,name=order_item_id,json=orderItemId,proto3" json:"order_item_id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     string                 `protobuf:"bytes,4,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Restocked     bool                   `protobuf:"varint,6,opt,name=restocked,proto3" json:"restocked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderReturnItem) Reset() {
+	*x = OrderReturnItem{}
+	mi := &file_order_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderReturnItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderReturnItem) ProtoMessage() {}
+
+func (x *OrderReturnItem) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderReturnItem.ProtoReflect.Descriptor instead.
+func (*OrderReturnItem) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *OrderReturnItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OrderReturnItem) GetOrderItemId() string {
+	if x != nil {
+		return x.OrderItemId
+	}
+	return ""
+}
+
+func (x *OrderReturnItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *OrderReturnItem) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *OrderReturnItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderReturnItem) GetRestocked() bool {
+	if x != nil {
+		return x.Restocked
+	}
+	return false
+}
+
+// Request return
+type RequestReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ItemIds       []string               `protobuf:"bytes,3,rep,name=item_ids,json=itemIds,proto3" json:"item_ids,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestReturnRequest) Reset() {
+	*x = RequestReturnRequest{}
+	mi := &file_order_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestReturnRequest) ProtoMessage() {}
+
+func (x *RequestReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestReturnRequest.ProtoReflect.Descriptor instead.
+func (*RequestReturnRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RequestReturnRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *RequestReturnRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RequestReturnRequest) GetItemIds() []string {
+	if x != nil {
+		return x.ItemIds
+	}
+	return nil
+}
+
+func (x *RequestReturnRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RequestReturnResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderReturn   *OrderReturn           `protobuf:"bytes,1,opt,name=order_return,json=orderReturn,proto3" json:"order_return,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestReturnResponse) Reset() {
+	*x = RequestReturnResponse{}
+	mi := &file_order_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestReturnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestReturnResponse) ProtoMessage() {}
+
+func (x *RequestReturnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestReturnResponse.ProtoReflect.Descriptor instead.
+func (*RequestReturnResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RequestReturnResponse) GetOrderReturn() *OrderReturn {
+	if x != nil {
+		return x.OrderReturn
+	}
+	return nil
+}
+
+// Approve return (Admin)
+type ApproveReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnId      string                 `protobuf:"bytes,1,opt,name=return_id,json=returnId,proto3" json:"return_id,omitempty"`
+	ApprovedBy    string                 `protobuf:"bytes,2,opt,name=approved_by,json=approvedBy,proto3" json:"approved_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveReturnRequest) Reset() {
+	*x = ApproveReturnRequest{}
+	mi := &file_order_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveReturnRequest) ProtoMessage() {}
+
+func (x *ApproveReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveReturnRequest.ProtoReflect.Descriptor instead.
+func (*ApproveReturnRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ApproveReturnRequest) GetReturnId() string {
+	if x != nil {
+		return x.ReturnId
+	}
+	return ""
+}
+
+func (x *ApproveReturnRequest) GetApprovedBy() string {
+	if x != nil {
+		return x.ApprovedBy
+	}
+	return ""
+}
+
+type ApproveReturnResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderReturn   *OrderReturn           `protobuf:"bytes,1,opt,name=order_return,json=orderReturn,proto3" json:"order_return,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveReturnResponse) Reset() {
+	*x = ApproveReturnResponse{}
+	mi := &file_order_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveReturnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveReturnResponse) ProtoMessage() {}
+
+func (x *ApproveReturnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveReturnResponse.ProtoReflect.Descriptor instead.
+func (*ApproveReturnResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ApproveReturnResponse) GetOrderReturn() *OrderReturn {
+	if x != nil {
+		return x.OrderReturn
+	}
+	return nil
+}
+
+// Reject return (Admin)
+type RejectReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnId      string                 `protobuf:"bytes,1,opt,name=return_id,json=returnId,proto3" json:"return_id,omitempty"`
+	RejectedBy    string                 `protobuf:"bytes,2,opt,name=rejected_by,json=rejectedBy,proto3" json:"rejected_by,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectReturnRequest) Reset() {
+	*x = RejectReturnRequest{}
+	mi := &file_order_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectReturnRequest) ProtoMessage() {}
+
+func (x *RejectReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectReturnRequest.ProtoReflect.Descriptor instead.
+func (*RejectReturnRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RejectReturnRequest) GetReturnId() string {
+	if x != nil {
+		return x.ReturnId
+	}
+	return ""
+}
+
+func (x *RejectReturnRequest) GetRejectedBy() string {
+	if x != nil {
+		return x.RejectedBy
+	}
+	return ""
+}
+
+func (x *RejectReturnRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RejectReturnResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderReturn   *OrderReturn           `protobuf:"bytes,1,opt,name=order_return,json=orderReturn,proto3" json:"order_return,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectReturnResponse) Reset() {
+	*x = RejectReturnResponse{}
+	mi := &file_order_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectReturnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectReturnResponse) ProtoMessage() {}
+
+func (x *RejectReturnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectReturnResponse.ProtoReflect.Descriptor instead.
+func (*RejectReturnResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RejectReturnResponse) GetOrderReturn() *OrderReturn {
+	if x != nil {
+		return x.OrderReturn
+	}
+	return nil
+}
+
+// Receive return (Admin)
+type ReceiveReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnId      string                 `protobuf:"bytes,1,opt,name=return_id,json=returnId,proto3" json:"return_id,omitempty"`
+	ReceivedBy    string                 `protobuf:"bytes,2,opt,name=received_by,json=receivedBy,proto3" json:"received_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceiveReturnRequest) Reset() {
+	*x = ReceiveReturnRequest{}
+	mi := &file_order_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceiveReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiveReturnRequest) ProtoMessage() {}
+
+func (x *ReceiveReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiveReturnRequest.ProtoReflect.Descriptor instead.
+func (*ReceiveReturnRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ReceiveReturnRequest) GetReturnId() string {
+	if x != nil {
+		return x.ReturnId
+	}
+	return ""
+}
+
+func (x *ReceiveReturnRequest) GetReceivedBy() string {
+	if x != nil {
+		return x.ReceivedBy
+	}
+	return ""
+}
+
+type ReceiveReturnResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderReturn   *OrderReturn           `protobuf:"bytes,1,opt,name=order_return,json=orderReturn,proto3" json:"order_return,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceiveReturnResponse) Reset() {
+	*x = ReceiveReturnResponse{}
+	mi := &file_order_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceiveReturnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiveReturnResponse) ProtoMessage() {}
+
+func (x *ReceiveReturnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiveReturnResponse.ProtoReflect.Descriptor instead.
+func (*ReceiveReturnResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ReceiveReturnResponse) GetOrderReturn() *OrderReturn {
+	if x != nil {
+		return x.OrderReturn
+	}
+	return nil
+}
+
+// Refund return (Admin)
+type RefundReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnId      string                 `protobuf:"bytes,1,opt,name=return_id,json=returnId,proto3" json:"return_id,omitempty"`
+	RefundedBy    string                 `protobuf:"bytes,2,opt,name=refunded_by,json=refundedBy,proto3" json:"refunded_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundReturnRequest) Reset() {
+	*x = RefundReturnRequest{}
+	mi := &file_order_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundReturnRequest) ProtoMessage() {}
+
+func (x *RefundReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundReturnRequest.ProtoReflect.Descriptor instead.
+func (*RefundReturnRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *RefundReturnRequest) GetReturnId() string {
+	if x != nil {
+		return x.ReturnId
+	}
+	return ""
+}
+
+func (x *RefundReturnRequest) GetRefundedBy() string {
+	if x != nil {
+		return x.RefundedBy
+	}
+	return ""
+}
+
+type RefundReturnResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderReturn   *OrderReturn           `protobuf:"bytes,1,opt,name=order_return,json=orderReturn,proto3" json:"order_return,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundReturnResponse) Reset() {
+	*x = RefundReturnResponse{}
+	mi := &file_order_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundReturnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundReturnResponse) ProtoMessage() {}
+
+func (x *RefundReturnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundReturnResponse.ProtoReflect.Descriptor instead.
+func (*RefundReturnResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RefundReturnResponse) GetOrderReturn() *OrderReturn {
+	if x != nil {
+		return x.OrderReturn
+	}
+	return nil
+}
+
+// Get return
+type GetReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnId      string                 `protobuf:"bytes,1,opt,name=return_id,json=returnId,proto3" json:"return_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReturnRequest) Reset() {
+	*x = GetReturnRequest{}
+	mi := &file_order_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReturnRequest) ProtoMessage() {}
+
+func (x *GetReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReturnRequest.ProtoReflect.Descriptor instead.
+func (*GetReturnRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetReturnRequest) GetReturnId() string {
+	if x != nil {
+		return x.ReturnId
+	}
+	return ""
+}
+
+type GetReturnResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderReturn   *OrderReturn           `protobuf:"bytes,1,opt,name=order_return,json=orderReturn,proto3" json:"order_return,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReturnResponse) Reset() {
+	*x = GetReturnResponse{}
+	mi := &file_order_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReturnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReturnResponse) ProtoMessage() {}
+
+func (x *GetReturnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReturnResponse.ProtoReflect.Descriptor instead.
+func (*GetReturnResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetReturnResponse) GetOrderReturn() *OrderReturn {
+	if x != nil {
+		return x.OrderReturn
+	}
+	return nil
+}
+
+// List order returns
+type ListOrderReturnsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrderReturnsRequest) Reset() {
+	*x = ListOrderReturnsRequest{}
+	mi := &file_order_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrderReturnsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrderReturnsRequest) ProtoMessage() {}
+
+func (x *ListOrderReturnsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrderReturnsRequest.ProtoReflect.Descriptor instead.
+func (*ListOrderReturnsRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListOrderReturnsRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type ListOrderReturnsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Returns       []*OrderReturn         `protobuf:"bytes,1,rep,name=returns,proto3" json:"returns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrderReturnsResponse) Reset() {
+	*x = ListOrderReturnsResponse{}
+	mi := &file_order_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrderReturnsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrderReturnsResponse) ProtoMessage() {}
+
+func (x *ListOrderReturnsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrderReturnsResponse.ProtoReflect.Descriptor instead.
+func (*ListOrderReturnsResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListOrderReturnsResponse) GetReturns() []*OrderReturn {
+	if x != nil {
+		return x.Returns
+	}
+	return nil
+}
+
+// Shipment is one parcel of an order handed to a carrier
+type Shipment struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId        string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Carrier        string                 `protobuf:"bytes,3,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber string                 `protobuf:"bytes,4,opt,name=tracking_number,json=trackingNumber,proto3" json:"tracking_number,omitempty"`
+	Status         ShipmentStatus         `protobuf:"varint,5,opt,name=status,proto3,enum=order.ShipmentStatus" json:"status,omitempty"`
+	Items          []*ShipmentItem        `protobuf:"bytes,6,rep,name=items,proto3" json:"items,omitempty"`
+	ShippedAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=shipped_at,json=shippedAt,proto3" json:"shipped_at,omitempty"`
+	DeliveredAt    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Shipment) Reset() {
+	*x = Shipment{}
+	mi := &file_order_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Shipment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Shipment) ProtoMessage() {}
+
+func (x *Shipment) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Shipment.ProtoReflect.Descriptor instead.
+func (*Shipment) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *Shipment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Shipment) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Shipment) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+func (x *Shipment) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+func (x *Shipment) GetStatus() ShipmentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ShipmentStatus_SHIPMENT_LABEL_CREATED
+}
+
+func (x *Shipment) GetItems() []*ShipmentItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Shipment) GetShippedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ShippedAt
+	}
+	return nil
+}
+
+func (x *Shipment) GetDeliveredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliveredAt
+	}
+	return nil
+}
+
+func (x *Shipment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Shipment) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// Order line quantity packed in a shipment
+type ShipmentItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderItemId   string                 `protobuf:"bytes,2,opt,name=order_item_id,json=orderItemId,proto3" json:"order_item_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShipmentItem) Reset() {
+	*x = ShipmentItem{}
+	mi := &file_order_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShipmentItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShipmentItem) ProtoMessage() {}
+
+func (x *ShipmentItem) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShipmentItem.ProtoReflect.Descriptor instead.
+func (*ShipmentItem) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ShipmentItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ShipmentItem) GetOrderItemId() string {
+	if x != nil {
+		return x.OrderItemId
+	}
+	return ""
+}
+
+func (x *ShipmentItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type CreateShipmentRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrderId        string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Carrier        string                 `protobuf:"bytes,2,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber string                 `protobuf:"bytes,3,opt,name=tracking_number,json=trackingNumber,proto3" json:"tracking_number,omitempty"`
+	Items          []*ShipmentItemRequest `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	CreatedBy      string                 `protobuf:"bytes,5,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateShipmentRequest) Reset() {
+	*x = CreateShipmentRequest{}
+	mi := &file_order_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShipmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateShipmentRequest) ProtoMessage() {}
+
+func (x *CreateShipmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShipmentRequest.ProtoReflect.Descriptor instead.
+func (*CreateShipmentRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *CreateShipmentRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CreateShipmentRequest) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+func (x *CreateShipmentRequest) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+func (x *CreateShipmentRequest) GetItems() []*ShipmentItemRequest {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *CreateShipmentRequest) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+type ShipmentItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderItemId   string                 `protobuf:"bytes,1,opt,name=order_item_id,json=orderItemId,proto3" json:"order_item_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShipmentItemRequest) Reset() {
+	*x = ShipmentItemRequest{}
+	mi := &file_order_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShipmentItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShipmentItemRequest) ProtoMessage() {}
+
+func (x *ShipmentItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShipmentItemRequest.ProtoReflect.Descriptor instead.
+func (*ShipmentItemRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ShipmentItemRequest) GetOrderItemId() string {
+	if x != nil {
+		return x.OrderItemId
+	}
+	return ""
+}
+
+func (x *ShipmentItemRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type CreateShipmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Shipment      *Shipment              `protobuf:"bytes,1,opt,name=shipment,proto3" json:"shipment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateShipmentResponse) Reset() {
+	*x = CreateShipmentResponse{}
+	mi := &file_order_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShipmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateShipmentResponse) ProtoMessage() {}
+
+func (x *CreateShipmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShipmentResponse.ProtoReflect.Descriptor instead.
+func (*CreateShipmentResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CreateShipmentResponse) GetShipment() *Shipment {
+	if x != nil {
+		return x.Shipment
+	}
+	return nil
+}
+
+type IngestTrackingEventRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Carrier        string                 `protobuf:"bytes,1,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber string                 `protobuf:"bytes,2,opt,name=tracking_number,json=trackingNumber,proto3" json:"tracking_number,omitempty"`
+	Status         ShipmentStatus         `protobuf:"varint,3,opt,name=status,proto3,enum=order.ShipmentStatus" json:"status,omitempty"`
+	OccurredAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	Location       string                 `protobuf:"bytes,5,opt,name=location,proto3" json:"location,omitempty"`
+	Description    string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *IngestTrackingEventRequest) Reset() {
+	*x = IngestTrackingEventRequest{}
+	mi := &file_order_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestTrackingEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestTrackingEventRequest) ProtoMessage() {}
+
+func (x *IngestTrackingEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestTrackingEventRequest.ProtoReflect.Descriptor instead.
+func (*IngestTrackingEventRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *IngestTrackingEventRequest) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+func (x *IngestTrackingEventRequest) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+func (x *IngestTrackingEventRequest) GetStatus() ShipmentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ShipmentStatus_SHIPMENT_LABEL_CREATED
+}
+
+func (x *IngestTrackingEventRequest) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *IngestTrackingEventRequest) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *IngestTrackingEventRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type IngestTrackingEventResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Shipment      *Shipment              `protobuf:"bytes,1,opt,name=shipment,proto3" json:"shipment,omitempty"`
+	Duplicate     bool                   `protobuf:"varint,2,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
+	OrderStatus   OrderStatus            `protobuf:"varint,3,opt,name=order_status,json=orderStatus,proto3,enum=order.OrderStatus" json:"order_status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestTrackingEventResponse) Reset() {
+	*x = IngestTrackingEventResponse{}
+	mi := &file_order_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestTrackingEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestTrackingEventResponse) ProtoMessage() {}
+
+func (x *IngestTrackingEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestTrackingEventResponse.ProtoReflect.Descriptor instead.
+func (*IngestTrackingEventResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *IngestTrackingEventResponse) GetShipment() *Shipment {
+	if x != nil {
+		return x.Shipment
+	}
+	return nil
+}
+
+func (x *IngestTrackingEventResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+func (x *IngestTrackingEventResponse) GetOrderStatus() OrderStatus {
+	if x != nil {
+		return x.OrderStatus
 	}
-	return ""
+	return OrderStatus_PENDING
 }
 
-func (x *UpdateOrderStatusRequest) GetTracking() *TrackingInfo {
+type ListOrderShipmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrderShipmentsRequest) Reset() {
+	*x = ListOrderShipmentsRequest{}
+	mi := &file_order_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrderShipmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrderShipmentsRequest) ProtoMessage() {}
+
+func (x *ListOrderShipmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[45]
 	if x != nil {
-		return x.Tracking
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-type UpdateOrderStatusResponse struct {
+// Deprecated: Use ListOrderShipmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListOrderShipmentsRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ListOrderShipmentsRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type ListOrderShipmentsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Shipments     []*Shipment            `protobuf:"bytes,1,rep,name=shipments,proto3" json:"shipments,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateOrderStatusResponse) Reset() {
-	*x = UpdateOrderStatusResponse{}
-	mi := &file_order_proto_msgTypes[14]
+func (x *ListOrderShipmentsResponse) Reset() {
+	*x = ListOrderShipmentsResponse{}
+	mi := &file_order_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateOrderStatusResponse) String() string {
+func (x *ListOrderShipmentsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateOrderStatusResponse) ProtoMessage() {}
+func (*ListOrderShipmentsResponse) ProtoMessage() {}
 
-func (x *UpdateOrderStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[14]
+func (x *ListOrderShipmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1125,41 +3334,159 @@ func (x *UpdateOrderStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateOrderStatusResponse.ProtoReflect.Descriptor instead.
-func (*UpdateOrderStatusResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ListOrderShipmentsResponse.ProtoReflect.Descriptor instead.
+func (*ListOrderShipmentsResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *UpdateOrderStatusResponse) GetOrder() *Order {
+func (x *ListOrderShipmentsResponse) GetShipments() []*Shipment {
 	if x != nil {
-		return x.Order
+		return x.Shipments
 	}
 	return nil
 }
 
-// Get order status history
-type GetOrderStatusHistoryRequest struct {
+// Invoice issued for an order; amounts mirror the order when it was issued
+type Invoice struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId        string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId         string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Number         string                 `protobuf:"bytes,4,opt,name=number,proto3" json:"number,omitempty"`
+	Subtotal       *Money                 `protobuf:"bytes,5,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	DiscountAmount *Money                 `protobuf:"bytes,6,opt,name=discount_amount,json=discountAmount,proto3" json:"discount_amount,omitempty"`
+	ShippingAmount *Money                 `protobuf:"bytes,7,opt,name=shipping_amount,json=shippingAmount,proto3" json:"shipping_amount,omitempty"`
+	TaxAmount      *Money                 `protobuf:"bytes,8,opt,name=tax_amount,json=taxAmount,proto3" json:"tax_amount,omitempty"`
+	TotalAmount    *Money                 `protobuf:"bytes,9,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	IssuedAt       *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Invoice) Reset() {
+	*x = Invoice{}
+	mi := &file_order_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Invoice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Invoice) ProtoMessage() {}
+
+func (x *Invoice) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Invoice.ProtoReflect.Descriptor instead.
+func (*Invoice) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *Invoice) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Invoice) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Invoice) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Invoice) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *Invoice) GetSubtotal() *Money {
+	if x != nil {
+		return x.Subtotal
+	}
+	return nil
+}
+
+func (x *Invoice) GetDiscountAmount() *Money {
+	if x != nil {
+		return x.DiscountAmount
+	}
+	return nil
+}
+
+func (x *Invoice) GetShippingAmount() *Money {
+	if x != nil {
+		return x.ShippingAmount
+	}
+	return nil
+}
+
+func (x *Invoice) GetTaxAmount() *Money {
+	if x != nil {
+		return x.TaxAmount
+	}
+	return nil
+}
+
+func (x *Invoice) GetTotalAmount() *Money {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return nil
+}
+
+func (x *Invoice) GetIssuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+type GetInvoiceRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // For authorization
+	Format        InvoiceFormat          `protobuf:"varint,3,opt,name=format,proto3,enum=order.InvoiceFormat" json:"format,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrderStatusHistoryRequest) Reset() {
-	*x = GetOrderStatusHistoryRequest{}
-	mi := &file_order_proto_msgTypes[15]
+func (x *GetInvoiceRequest) Reset() {
+	*x = GetInvoiceRequest{}
+	mi := &file_order_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderStatusHistoryRequest) String() string {
+func (x *GetInvoiceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderStatusHistoryRequest) ProtoMessage() {}
+func (*GetInvoiceRequest) ProtoMessage() {}
 
-func (x *GetOrderStatusHistoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[15]
+func (x *GetInvoiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1170,40 +3497,57 @@ func (x *GetOrderStatusHistoryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderStatusHistoryRequest.ProtoReflect.Descriptor instead.
-func (*GetOrderStatusHistoryRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use GetInvoiceRequest.ProtoReflect.Descriptor instead.
+func (*GetInvoiceRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *GetOrderStatusHistoryRequest) GetOrderId() string {
+func (x *GetInvoiceRequest) GetOrderId() string {
 	if x != nil {
 		return x.OrderId
 	}
 	return ""
 }
 
-type GetOrderStatusHistoryResponse struct {
+func (x *GetInvoiceRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetInvoiceRequest) GetFormat() InvoiceFormat {
+	if x != nil {
+		return x.Format
+	}
+	return InvoiceFormat_INVOICE_FORMAT_PDF
+}
+
+type GetInvoiceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	History       []*OrderStatusHistory  `protobuf:"bytes,1,rep,name=history,proto3" json:"history,omitempty"`
+	Invoice       *Invoice               `protobuf:"bytes,1,opt,name=invoice,proto3" json:"invoice,omitempty"`
+	Content       []byte                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	FileName      string                 `protobuf:"bytes,4,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrderStatusHistoryResponse) Reset() {
-	*x = GetOrderStatusHistoryResponse{}
-	mi := &file_order_proto_msgTypes[16]
+func (x *GetInvoiceResponse) Reset() {
+	*x = GetInvoiceResponse{}
+	mi := &file_order_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderStatusHistoryResponse) String() string {
+func (x *GetInvoiceResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderStatusHistoryResponse) ProtoMessage() {}
+func (*GetInvoiceResponse) ProtoMessage() {}
 
-func (x *GetOrderStatusHistoryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[16]
+func (x *GetInvoiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1214,43 +3558,64 @@ func (x *GetOrderStatusHistoryResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderStatusHistoryResponse.ProtoReflect.Descriptor instead.
-func (*GetOrderStatusHistoryResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use GetInvoiceResponse.ProtoReflect.Descriptor instead.
+func (*GetInvoiceResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *GetOrderStatusHistoryResponse) GetHistory() []*OrderStatusHistory {
+func (x *GetInvoiceResponse) GetInvoice() *Invoice {
 	if x != nil {
-		return x.History
+		return x.Invoice
 	}
 	return nil
 }
 
-type OrderStatusHistory struct {
+func (x *GetInvoiceResponse) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *GetInvoiceResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *GetInvoiceResponse) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+type FlagOrderDisputedRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        OrderStatus            `protobuf:"varint,1,opt,name=status,proto3,enum=order.OrderStatus" json:"status,omitempty"`
-	Note          string                 `protobuf:"bytes,2,opt,name=note,proto3" json:"note,omitempty"`
-	Timestamp     *Timestamp             `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	UpdatedBy     string                 `protobuf:"bytes,4,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	PaymentId     string                 `protobuf:"bytes,2,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	DisputeId     string                 `protobuf:"bytes,3,opt,name=dispute_id,json=disputeId,proto3" json:"dispute_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderStatusHistory) Reset() {
-	*x = OrderStatusHistory{}
-	mi := &file_order_proto_msgTypes[17]
+func (x *FlagOrderDisputedRequest) Reset() {
+	*x = FlagOrderDisputedRequest{}
+	mi := &file_order_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderStatusHistory) String() string {
+func (x *FlagOrderDisputedRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderStatusHistory) ProtoMessage() {}
+func (*FlagOrderDisputedRequest) ProtoMessage() {}
 
-func (x *OrderStatusHistory) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[17]
+func (x *FlagOrderDisputedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1261,44 +3626,96 @@ func (x *OrderStatusHistory) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderStatusHistory.ProtoReflect.Descriptor instead.
-func (*OrderStatusHistory) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use FlagOrderDisputedRequest.ProtoReflect.Descriptor instead.
+func (*FlagOrderDisputedRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{50}
 }
 
-func (x *OrderStatusHistory) GetStatus() OrderStatus {
+func (x *FlagOrderDisputedRequest) GetOrderId() string {
 	if x != nil {
-		return x.Status
+		return x.OrderId
 	}
-	return OrderStatus_PENDING
+	return ""
 }
 
-func (x *OrderStatusHistory) GetNote() string {
+func (x *FlagOrderDisputedRequest) GetPaymentId() string {
 	if x != nil {
-		return x.Note
+		return x.PaymentId
 	}
 	return ""
 }
 
-func (x *OrderStatusHistory) GetTimestamp() *Timestamp {
+func (x *FlagOrderDisputedRequest) GetDisputeId() string {
 	if x != nil {
-		return x.Timestamp
+		return x.DisputeId
 	}
-	return nil
+	return ""
 }
 
-func (x *OrderStatusHistory) GetUpdatedBy() string {
+func (x *FlagOrderDisputedRequest) GetReason() string {
 	if x != nil {
-		return x.UpdatedBy
+		return x.Reason
 	}
 	return ""
 }
 
+type FlagOrderDisputedResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Order          *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	AlreadyFlagged bool                   `protobuf:"varint,2,opt,name=already_flagged,json=alreadyFlagged,proto3" json:"already_flagged,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FlagOrderDisputedResponse) Reset() {
+	*x = FlagOrderDisputedResponse{}
+	mi := &file_order_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlagOrderDisputedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlagOrderDisputedResponse) ProtoMessage() {}
+
+func (x *FlagOrderDisputedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlagOrderDisputedResponse.ProtoReflect.Descriptor instead.
+func (*FlagOrderDisputedResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *FlagOrderDisputedResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *FlagOrderDisputedResponse) GetAlreadyFlagged() bool {
+	if x != nil {
+		return x.AlreadyFlagged
+	}
+	return false
+}
+
 var File_order_proto protoreflect.FileDescriptor
 
 const file_order_proto_rawDesc = "" +
 	"\n" +
-	"\vorder.proto\x12\x05order\x1a\fcommon.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xae\x05\n" +
+	"\vorder.proto\x12\x05order\x1a\fcommon.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xfd\x06\n" +
 	"\x05Order\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12&\n" +
@@ -1319,7 +3736,15 @@ const file_order_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x8f\x02\n" +
+	"updated_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1f\n" +
+	"\vcoupon_code\x18\x11 \x01(\tR\n" +
+	"couponCode\x12)\n" +
+	"\brefunded\x18\x12 \x01(\v2\r.common.MoneyR\brefunded\x12%\n" +
+	"\x0epayment_status\x18\x13 \x01(\tR\rpaymentStatus\x12\x1d\n" +
+	"\n" +
+	"dispute_id\x18\x14 \x01(\tR\tdisputeId\x12;\n" +
+	"\vdisputed_at\x18\x15 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"disputedAt\"\xbf\x02\n" +
 	"\tOrderItem\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
@@ -1333,14 +3758,16 @@ const file_order_proto_rawDesc = "" +
 	"\n" +
 	"unit_price\x18\b \x01(\v2\r.common.MoneyR\tunitPrice\x12.\n" +
 	"\vtotal_price\x18\t \x01(\v2\r.common.MoneyR\n" +
-	"totalPrice\"\xd7\x01\n" +
+	"totalPrice\x12.\n" +
+	"\x06status\x18\n" +
+	" \x01(\x0e2\x16.order.OrderItemStatusR\x06status\"\xd7\x01\n" +
 	"\fTrackingInfo\x12'\n" +
 	"\x0ftracking_number\x18\x01 \x01(\tR\x0etrackingNumber\x12\x18\n" +
 	"\acarrier\x18\x02 \x01(\tR\acarrier\x12\x10\n" +
 	"\x03url\x18\x03 \x01(\tR\x03url\x120\n" +
 	"\n" +
 	"shipped_at\x18\x04 \x01(\v2\x11.common.TimestampR\tshippedAt\x12@\n" +
-	"\x12estimated_delivery\x18\x05 \x01(\v2\x11.common.TimestampR\x11estimatedDelivery\"\x82\x02\n" +
+	"\x12estimated_delivery\x18\x05 \x01(\v2\x11.common.TimestampR\x11estimatedDelivery\"\xab\x02\n" +
 	"\x12CreateOrderRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12-\n" +
 	"\x05items\x18\x02 \x03(\v2\x17.order.OrderItemRequestR\x05items\x12.\n" +
@@ -1348,7 +3775,8 @@ const file_order_proto_rawDesc = "" +
 	"\x12billing_address_id\x18\x04 \x01(\tR\x10billingAddressId\x12%\n" +
 	"\x0epayment_method\x18\x05 \x01(\tR\rpaymentMethod\x12\x1f\n" +
 	"\vcoupon_code\x18\x06 \x01(\tR\n" +
-	"couponCode\"l\n" +
+	"couponCode\x12'\n" +
+	"\x0fidempotency_key\x18\a \x01(\tR\x0eidempotencyKey\"l\n" +
 	"\x10OrderItemRequest\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1d\n" +
@@ -1368,40 +3796,219 @@ const file_order_proto_rawDesc = "" +
 	"\n" +
 	"pagination\x18\x02 \x01(\v2\x19.common.PaginationRequestR\n" +
 	"pagination\x12-\n" +
-	"\afilters\x18\x03 \x01(\v2\x13.order.OrderFiltersR\afilters\"\x96\x01\n" +
+	"\afilters\x18\x03 \x01(\v2\x13.order.OrderFiltersR\afilters\"\xfd\x02\n" +
 	"\fOrderFilters\x12*\n" +
 	"\x06status\x18\x01 \x01(\x0e2\x12.order.OrderStatusR\x06status\x12.\n" +
 	"\tfrom_date\x18\x02 \x01(\v2\x11.common.TimestampR\bfromDate\x12*\n" +
-	"\ato_date\x18\x03 \x01(\v2\x11.common.TimestampR\x06toDate\"v\n" +
+	"\ato_date\x18\x03 \x01(\v2\x11.common.TimestampR\x06toDate\x12.\n" +
+	"\bstatuses\x18\x04 \x03(\x0e2\x12.order.OrderStatusR\bstatuses\x12*\n" +
+	"\tmin_total\x18\x05 \x01(\v2\r.common.MoneyR\bminTotal\x12*\n" +
+	"\tmax_total\x18\x06 \x01(\v2\r.common.MoneyR\bmaxTotal\x12%\n" +
+	"\x0epayment_status\x18\a \x01(\tR\rpaymentStatus\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\b \x01(\tR\tproductId\x12\x17\n" +
+	"\auser_id\x18\t \x01(\tR\x06userId\"v\n" +
 	"\x12ListOrdersResponse\x12$\n" +
 	"\x06orders\x18\x01 \x03(\v2\f.order.OrderR\x06orders\x12:\n" +
 	"\n" +
 	"pagination\x18\x02 \x01(\v2\x1a.common.PaginationResponseR\n" +
-	"pagination\"U\n" +
+	"pagination\"r\n" +
+	"\x13SearchOrdersRequest\x12-\n" +
+	"\afilters\x18\x01 \x01(\v2\x13.order.OrderFiltersR\afilters\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"]\n" +
+	"\x14SearchOrdersResponse\x12$\n" +
+	"\x06orders\x18\x01 \x03(\v2\f.order.OrderR\x06orders\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"U\n" +
 	"\x12CancelOrderRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
 	"\x06reason\x18\x03 \x01(\tR\x06reason\"S\n" +
 	"\x13CancelOrderResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\"\n" +
-	"\x05order\x18\x02 \x01(\v2\f.order.OrderR\x05order\"\x9b\x01\n" +
+	"\x05order\x18\x02 \x01(\v2\f.order.OrderR\x05order\"\xba\x01\n" +
 	"\x18UpdateOrderStatusRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12*\n" +
 	"\x06status\x18\x02 \x01(\x0e2\x12.order.OrderStatusR\x06status\x12\x12\n" +
 	"\x04note\x18\x03 \x01(\tR\x04note\x12/\n" +
-	"\btracking\x18\x04 \x01(\v2\x13.order.TrackingInfoR\btracking\"?\n" +
+	"\btracking\x18\x04 \x01(\v2\x13.order.TrackingInfoR\btracking\x12\x1d\n" +
+	"\n" +
+	"updated_by\x18\x05 \x01(\tR\tupdatedBy\"?\n" +
 	"\x19UpdateOrderStatusResponse\x12\"\n" +
 	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\"9\n" +
 	"\x1cGetOrderStatusHistoryRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\"T\n" +
 	"\x1dGetOrderStatusHistoryResponse\x123\n" +
-	"\ahistory\x18\x01 \x03(\v2\x19.order.OrderStatusHistoryR\ahistory\"\xa4\x01\n" +
+	"\ahistory\x18\x01 \x03(\v2\x19.order.OrderStatusHistoryR\ahistory\"\xee\x01\n" +
 	"\x12OrderStatusHistory\x12*\n" +
 	"\x06status\x18\x01 \x01(\x0e2\x12.order.OrderStatusR\x06status\x12\x12\n" +
 	"\x04note\x18\x02 \x01(\tR\x04note\x12/\n" +
 	"\ttimestamp\x18\x03 \x01(\v2\x11.common.TimestampR\ttimestamp\x12\x1d\n" +
 	"\n" +
-	"updated_by\x18\x04 \x01(\tR\tupdatedBy*\xaa\x01\n" +
+	"updated_by\x18\x04 \x01(\tR\tupdatedBy\x128\n" +
+	"\vfrom_status\x18\x05 \x01(\x0e2\x12.order.OrderStatusH\x00R\n" +
+	"fromStatus\x88\x01\x01B\x0e\n" +
+	"\f_from_status\"}\n" +
+	"\x16CancelOrderItemRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\aitem_id\x18\x02 \x01(\tR\x06itemId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"q\n" +
+	"\x17CancelOrderItemResponse\x12\"\n" +
+	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\x122\n" +
+	"\rrefund_amount\x18\x02 \x01(\v2\r.common.MoneyR\frefundAmount\"\xdb\x03\n" +
+	"\vOrderReturn\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12,\n" +
+	"\x05items\x18\x04 \x03(\v2\x16.order.OrderReturnItemR\x05items\x12+\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x13.order.ReturnStatusR\x06status\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12)\n" +
+	"\x10rejection_reason\x18\a \x01(\tR\x0frejectionReason\x122\n" +
+	"\rrefund_amount\x18\b \x01(\v2\r.common.MoneyR\frefundAmount\x12\x1f\n" +
+	"\vreviewed_by\x18\t \x01(\tR\n" +
+	"reviewedBy\x12\x1f\n" +
+	"\vreceived_by\x18\n" +
+	" \x01(\tR\n" +
+	"receivedBy\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xbd\x01\n" +
+	"\x0fOrderReturnItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\"\n" +
+	"\rorder_item_id\x18\x02 \x01(\tR\vorderItemId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\tR\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x04 \x01(\tR\tvariantId\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x05R\bquantity\x12\x1c\n" +
+	"\trestocked\x18\x06 \x01(\bR\trestocked\"}\n" +
+	"\x14RequestReturnRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x19\n" +
+	"\bitem_ids\x18\x03 \x03(\tR\aitemIds\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"N\n" +
+	"\x15RequestReturnResponse\x125\n" +
+	"\forder_return\x18\x01 \x01(\v2\x12.order.OrderReturnR\vorderReturn\"T\n" +
+	"\x14ApproveReturnRequest\x12\x1b\n" +
+	"\treturn_id\x18\x01 \x01(\tR\breturnId\x12\x1f\n" +
+	"\vapproved_by\x18\x02 \x01(\tR\n" +
+	"approvedBy\"N\n" +
+	"\x15ApproveReturnResponse\x125\n" +
+	"\forder_return\x18\x01 \x01(\v2\x12.order.OrderReturnR\vorderReturn\"k\n" +
+	"\x13RejectReturnRequest\x12\x1b\n" +
+	"\treturn_id\x18\x01 \x01(\tR\breturnId\x12\x1f\n" +
+	"\vrejected_by\x18\x02 \x01(\tR\n" +
+	"rejectedBy\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"M\n" +
+	"\x14RejectReturnResponse\x125\n" +
+	"\forder_return\x18\x01 \x01(\v2\x12.order.OrderReturnR\vorderReturn\"T\n" +
+	"\x14ReceiveReturnRequest\x12\x1b\n" +
+	"\treturn_id\x18\x01 \x01(\tR\breturnId\x12\x1f\n" +
+	"\vreceived_by\x18\x02 \x01(\tR\n" +
+	"receivedBy\"N\n" +
+	"\x15ReceiveReturnResponse\x125\n" +
+	"\forder_return\x18\x01 \x01(\v2\x12.order.OrderReturnR\vorderReturn\"S\n" +
+	"\x13RefundReturnRequest\x12\x1b\n" +
+	"\treturn_id\x18\x01 \x01(\tR\breturnId\x12\x1f\n" +
+	"\vrefunded_by\x18\x02 \x01(\tR\n" +
+	"refundedBy\"M\n" +
+	"\x14RefundReturnResponse\x125\n" +
+	"\forder_return\x18\x01 \x01(\v2\x12.order.OrderReturnR\vorderReturn\"/\n" +
+	"\x10GetReturnRequest\x12\x1b\n" +
+	"\treturn_id\x18\x01 \x01(\tR\breturnId\"J\n" +
+	"\x11GetReturnResponse\x125\n" +
+	"\forder_return\x18\x01 \x01(\v2\x12.order.OrderReturnR\vorderReturn\"4\n" +
+	"\x17ListOrderReturnsRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"H\n" +
+	"\x18ListOrderReturnsResponse\x12,\n" +
+	"\areturns\x18\x01 \x03(\v2\x12.order.OrderReturnR\areturns\"\xc2\x03\n" +
+	"\bShipment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x18\n" +
+	"\acarrier\x18\x03 \x01(\tR\acarrier\x12'\n" +
+	"\x0ftracking_number\x18\x04 \x01(\tR\x0etrackingNumber\x12-\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x15.order.ShipmentStatusR\x06status\x12)\n" +
+	"\x05items\x18\x06 \x03(\v2\x13.order.ShipmentItemR\x05items\x129\n" +
+	"\n" +
+	"shipped_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tshippedAt\x12=\n" +
+	"\fdelivered_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vdeliveredAt\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"^\n" +
+	"\fShipmentItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\"\n" +
+	"\rorder_item_id\x18\x02 \x01(\tR\vorderItemId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\"\xc6\x01\n" +
+	"\x15CreateShipmentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x18\n" +
+	"\acarrier\x18\x02 \x01(\tR\acarrier\x12'\n" +
+	"\x0ftracking_number\x18\x03 \x01(\tR\x0etrackingNumber\x120\n" +
+	"\x05items\x18\x04 \x03(\v2\x1a.order.ShipmentItemRequestR\x05items\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x05 \x01(\tR\tcreatedBy\"U\n" +
+	"\x13ShipmentItemRequest\x12\"\n" +
+	"\rorder_item_id\x18\x01 \x01(\tR\vorderItemId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"E\n" +
+	"\x16CreateShipmentResponse\x12+\n" +
+	"\bshipment\x18\x01 \x01(\v2\x0f.order.ShipmentR\bshipment\"\x89\x02\n" +
+	"\x1aIngestTrackingEventRequest\x12\x18\n" +
+	"\acarrier\x18\x01 \x01(\tR\acarrier\x12'\n" +
+	"\x0ftracking_number\x18\x02 \x01(\tR\x0etrackingNumber\x12-\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x15.order.ShipmentStatusR\x06status\x12;\n" +
+	"\voccurred_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x1a\n" +
+	"\blocation\x18\x05 \x01(\tR\blocation\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\"\x9f\x01\n" +
+	"\x1bIngestTrackingEventResponse\x12+\n" +
+	"\bshipment\x18\x01 \x01(\v2\x0f.order.ShipmentR\bshipment\x12\x1c\n" +
+	"\tduplicate\x18\x02 \x01(\bR\tduplicate\x125\n" +
+	"\forder_status\x18\x03 \x01(\x0e2\x12.order.OrderStatusR\vorderStatus\"6\n" +
+	"\x19ListOrderShipmentsRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"K\n" +
+	"\x1aListOrderShipmentsResponse\x12-\n" +
+	"\tshipments\x18\x01 \x03(\v2\x0f.order.ShipmentR\tshipments\"\x99\x03\n" +
+	"\aInvoice\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06number\x18\x04 \x01(\tR\x06number\x12)\n" +
+	"\bsubtotal\x18\x05 \x01(\v2\r.common.MoneyR\bsubtotal\x126\n" +
+	"\x0fdiscount_amount\x18\x06 \x01(\v2\r.common.MoneyR\x0ediscountAmount\x126\n" +
+	"\x0fshipping_amount\x18\a \x01(\v2\r.common.MoneyR\x0eshippingAmount\x12,\n" +
+	"\n" +
+	"tax_amount\x18\b \x01(\v2\r.common.MoneyR\ttaxAmount\x120\n" +
+	"\ftotal_amount\x18\t \x01(\v2\r.common.MoneyR\vtotalAmount\x127\n" +
+	"\tissued_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\"u\n" +
+	"\x11GetInvoiceRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12,\n" +
+	"\x06format\x18\x03 \x01(\x0e2\x14.order.InvoiceFormatR\x06format\"\x98\x01\n" +
+	"\x12GetInvoiceResponse\x12(\n" +
+	"\ainvoice\x18\x01 \x01(\v2\x0e.order.InvoiceR\ainvoice\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\fR\acontent\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\x12\x1b\n" +
+	"\tfile_name\x18\x04 \x01(\tR\bfileName\"\x8b\x01\n" +
+	"\x18FlagOrderDisputedRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x02 \x01(\tR\tpaymentId\x12\x1d\n" +
+	"\n" +
+	"dispute_id\x18\x03 \x01(\tR\tdisputeId\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"h\n" +
+	"\x19FlagOrderDisputedResponse\x12\"\n" +
+	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\x12'\n" +
+	"\x0falready_flagged\x18\x02 \x01(\bR\x0ealreadyFlagged*^\n" +
+	"\x0fOrderItemStatus\x12\x10\n" +
+	"\fITEM_PENDING\x10\x00\x12\x12\n" +
+	"\x0eITEM_FULFILLED\x10\x01\x12\x12\n" +
+	"\x0eITEM_CANCELLED\x10\x02\x12\x11\n" +
+	"\rITEM_RETURNED\x10\x03*\xaa\x01\n" +
 	"\vOrderStatus\x12\v\n" +
 	"\aPENDING\x10\x00\x12\x0e\n" +
 	"\n" +
@@ -1413,15 +4020,45 @@ const file_order_proto_rawDesc = "" +
 	"\aSHIPPED\x10\x06\x12\r\n" +
 	"\tDELIVERED\x10\a\x12\r\n" +
 	"\tCANCELLED\x10\b\x12\f\n" +
-	"\bREFUNDED\x10\t2\xd6\x03\n" +
+	"\bREFUNDED\x10\t*x\n" +
+	"\fReturnStatus\x12\x14\n" +
+	"\x10RETURN_REQUESTED\x10\x00\x12\x13\n" +
+	"\x0fRETURN_APPROVED\x10\x01\x12\x13\n" +
+	"\x0fRETURN_REJECTED\x10\x02\x12\x13\n" +
+	"\x0fRETURN_RECEIVED\x10\x03\x12\x13\n" +
+	"\x0fRETURN_REFUNDED\x10\x04*\x94\x01\n" +
+	"\x0eShipmentStatus\x12\x1a\n" +
+	"\x16SHIPMENT_LABEL_CREATED\x10\x00\x12\x17\n" +
+	"\x13SHIPMENT_IN_TRANSIT\x10\x01\x12\x1d\n" +
+	"\x19SHIPMENT_OUT_FOR_DELIVERY\x10\x02\x12\x16\n" +
+	"\x12SHIPMENT_DELIVERED\x10\x03\x12\x16\n" +
+	"\x12SHIPMENT_EXCEPTION\x10\x04*@\n" +
+	"\rInvoiceFormat\x12\x16\n" +
+	"\x12INVOICE_FORMAT_PDF\x10\x00\x12\x17\n" +
+	"\x13INVOICE_FORMAT_JSON\x10\x012\x9f\f\n" +
 	"\fOrderService\x12D\n" +
 	"\vCreateOrder\x12\x19.order.CreateOrderRequest\x1a\x1a.order.CreateOrderResponse\x12;\n" +
 	"\bGetOrder\x12\x16.order.GetOrderRequest\x1a\x17.order.GetOrderResponse\x12A\n" +
 	"\n" +
-	"ListOrders\x12\x18.order.ListOrdersRequest\x1a\x19.order.ListOrdersResponse\x12D\n" +
+	"ListOrders\x12\x18.order.ListOrdersRequest\x1a\x19.order.ListOrdersResponse\x12G\n" +
+	"\fSearchOrders\x12\x1a.order.SearchOrdersRequest\x1a\x1b.order.SearchOrdersResponse\x12D\n" +
 	"\vCancelOrder\x12\x19.order.CancelOrderRequest\x1a\x1a.order.CancelOrderResponse\x12V\n" +
 	"\x11UpdateOrderStatus\x12\x1f.order.UpdateOrderStatusRequest\x1a .order.UpdateOrderStatusResponse\x12b\n" +
-	"\x15GetOrderStatusHistory\x12#.order.GetOrderStatusHistoryRequest\x1a$.order.GetOrderStatusHistoryResponseB/Z-github.com/cqchien/ecomerce-rec/backend/protob\x06proto3"
+	"\x15GetOrderStatusHistory\x12#.order.GetOrderStatusHistoryRequest\x1a$.order.GetOrderStatusHistoryResponse\x12P\n" +
+	"\x0fCancelOrderItem\x12\x1d.order.CancelOrderItemRequest\x1a\x1e.order.CancelOrderItemResponse\x12J\n" +
+	"\rRequestReturn\x12\x1b.order.RequestReturnRequest\x1a\x1c.order.RequestReturnResponse\x12J\n" +
+	"\rApproveReturn\x12\x1b.order.ApproveReturnRequest\x1a\x1c.order.ApproveReturnResponse\x12G\n" +
+	"\fRejectReturn\x12\x1a.order.RejectReturnRequest\x1a\x1b.order.RejectReturnResponse\x12J\n" +
+	"\rReceiveReturn\x12\x1b.order.ReceiveReturnRequest\x1a\x1c.order.ReceiveReturnResponse\x12G\n" +
+	"\fRefundReturn\x12\x1a.order.RefundReturnRequest\x1a\x1b.order.RefundReturnResponse\x12>\n" +
+	"\tGetReturn\x12\x17.order.GetReturnRequest\x1a\x18.order.GetReturnResponse\x12S\n" +
+	"\x10ListOrderReturns\x12\x1e.order.ListOrderReturnsRequest\x1a\x1f.order.ListOrderReturnsResponse\x12M\n" +
+	"\x0eCreateShipment\x12\x1c.order.CreateShipmentRequest\x1a\x1d.order.CreateShipmentResponse\x12\\\n" +
+	"\x13IngestTrackingEvent\x12!.order.IngestTrackingEventRequest\x1a\".order.IngestTrackingEventResponse\x12Y\n" +
+	"\x12ListOrderShipments\x12 .order.ListOrderShipmentsRequest\x1a!.order.ListOrderShipmentsResponse\x12A\n" +
+	"\n" +
+	"GetInvoice\x12\x18.order.GetInvoiceRequest\x1a\x19.order.GetInvoiceResponse\x12V\n" +
+	"\x11FlagOrderDisputed\x12\x1f.order.FlagOrderDisputedRequest\x1a .order.FlagOrderDisputedResponseB/Z-github.com/cqchien/ecomerce-rec/backend/protob\x06proto3"
 
 var (
 	file_order_proto_rawDescOnce sync.Once
@@ -1435,86 +4072,197 @@ func file_order_proto_rawDescGZIP() []byte {
 	return file_order_proto_rawDescData
 }
 
-var file_order_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_order_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_order_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_order_proto_msgTypes = make([]protoimpl.MessageInfo, 52)
 var file_order_proto_goTypes = []any{
-	(OrderStatus)(0),                      // 0: order.OrderStatus
-	(*Order)(nil),                         // 1: order.Order
-	(*OrderItem)(nil),                     // 2: order.OrderItem
-	(*TrackingInfo)(nil),                  // 3: order.TrackingInfo
-	(*CreateOrderRequest)(nil),            // 4: order.CreateOrderRequest
-	(*OrderItemRequest)(nil),              // 5: order.OrderItemRequest
-	(*CreateOrderResponse)(nil),           // 6: order.CreateOrderResponse
-	(*GetOrderRequest)(nil),               // 7: order.GetOrderRequest
-	(*GetOrderResponse)(nil),              // 8: order.GetOrderResponse
-	(*ListOrdersRequest)(nil),             // 9: order.ListOrdersRequest
-	(*OrderFilters)(nil),                  // 10: order.OrderFilters
-	(*ListOrdersResponse)(nil),            // 11: order.ListOrdersResponse
-	(*CancelOrderRequest)(nil),            // 12: order.CancelOrderRequest
-	(*CancelOrderResponse)(nil),           // 13: order.CancelOrderResponse
-	(*UpdateOrderStatusRequest)(nil),      // 14: order.UpdateOrderStatusRequest
-	(*UpdateOrderStatusResponse)(nil),     // 15: order.UpdateOrderStatusResponse
-	(*GetOrderStatusHistoryRequest)(nil),  // 16: order.GetOrderStatusHistoryRequest
-	(*GetOrderStatusHistoryResponse)(nil), // 17: order.GetOrderStatusHistoryResponse
-	(*OrderStatusHistory)(nil),            // 18: order.OrderStatusHistory
-	(*Money)(nil),                         // 19: common.Money
-	(*Address)(nil),                       // 20: common.Address
-	(*timestamppb.Timestamp)(nil),         // 21: google.protobuf.Timestamp
-	(*Timestamp)(nil),                     // 22: common.Timestamp
-	(*PaginationRequest)(nil),             // 23: common.PaginationRequest
-	(*PaginationResponse)(nil),            // 24: common.PaginationResponse
+	(OrderItemStatus)(0),                  // 0: order.OrderItemStatus
+	(OrderStatus)(0),                      // 1: order.OrderStatus
+	(ReturnStatus)(0),                     // 2: order.ReturnStatus
+	(ShipmentStatus)(0),                   // 3: order.ShipmentStatus
+	(InvoiceFormat)(0),                    // 4: order.InvoiceFormat
+	(*Order)(nil),                         // 5: order.Order
+	(*OrderItem)(nil),                     // 6: order.OrderItem
+	(*TrackingInfo)(nil),                  // 7: order.TrackingInfo
+	(*CreateOrderRequest)(nil),            // 8: order.CreateOrderRequest
+	(*OrderItemRequest)(nil),              // 9: order.OrderItemRequest
+	(*CreateOrderResponse)(nil),           // 10: order.CreateOrderResponse
+	(*GetOrderRequest)(nil),               // 11: order.GetOrderRequest
+	(*GetOrderResponse)(nil),              // 12: order.GetOrderResponse
+	(*ListOrdersRequest)(nil),             // 13: order.ListOrdersRequest
+	(*OrderFilters)(nil),                  // 14: order.OrderFilters
+	(*ListOrdersResponse)(nil),            // 15: order.ListOrdersResponse
+	(*SearchOrdersRequest)(nil),           // 16: order.SearchOrdersRequest
+	(*SearchOrdersResponse)(nil),          // 17: order.SearchOrdersResponse
+	(*CancelOrderRequest)(nil),            // 18: order.CancelOrderRequest
+	(*CancelOrderResponse)(nil),           // 19: order.CancelOrderResponse
+	(*UpdateOrderStatusRequest)(nil),      // 20: order.UpdateOrderStatusRequest
+	(*UpdateOrderStatusResponse)(nil),     // 21: order.UpdateOrderStatusResponse
+	(*GetOrderStatusHistoryRequest)(nil),  // 22: order.GetOrderStatusHistoryRequest
+	(*GetOrderStatusHistoryResponse)(nil), // 23: order.GetOrderStatusHistoryResponse
+	(*OrderStatusHistory)(nil),            // 24: order.OrderStatusHistory
+	(*CancelOrderItemRequest)(nil),        // 25: order.CancelOrderItemRequest
+	(*CancelOrderItemResponse)(nil),       // 26: order.CancelOrderItemResponse
+	(*OrderReturn)(nil),                   // 27: order.OrderReturn
+	(*OrderReturnItem)(nil),               // 28: order.OrderReturnItem
+	(*RequestReturnRequest)(nil),          // 29: order.RequestReturnRequest
+	(*RequestReturnResponse)(nil),         // 30: order.RequestReturnResponse
+	(*ApproveReturnRequest)(nil),          // 31: order.ApproveReturnRequest
+	(*ApproveReturnResponse)(nil),         // 32: order.ApproveReturnResponse
+	(*RejectReturnRequest)(nil),           // 33: order.RejectReturnRequest
+	(*RejectReturnResponse)(nil),          // 34: order.RejectReturnResponse
+	(*ReceiveReturnRequest)(nil),          // 35: order.ReceiveReturnRequest
+	(*ReceiveReturnResponse)(nil),         // 36: order.ReceiveReturnResponse
+	(*RefundReturnRequest)(nil),           // 37: order.RefundReturnRequest
+	(*RefundReturnResponse)(nil),          // 38: order.RefundReturnResponse
+	(*GetReturnRequest)(nil),              // 39: order.GetReturnRequest
+	(*GetReturnResponse)(nil),             // 40: order.GetReturnResponse
+	(*ListOrderReturnsRequest)(nil),       // 41: order.ListOrderReturnsRequest
+	(*ListOrderReturnsResponse)(nil),      // 42: order.ListOrderReturnsResponse
+	(*Shipment)(nil),                      // 43: order.Shipment
+	(*ShipmentItem)(nil),                  // 44: order.ShipmentItem
+	(*CreateShipmentRequest)(nil),         // 45: order.CreateShipmentRequest
+	(*ShipmentItemRequest)(nil),           // 46: order.ShipmentItemRequest
+	(*CreateShipmentResponse)(nil),        // 47: order.CreateShipmentResponse
+	(*IngestTrackingEventRequest)(nil),    // 48: order.IngestTrackingEventRequest
+	(*IngestTrackingEventResponse)(nil),   // 49: order.IngestTrackingEventResponse
+	(*ListOrderShipmentsRequest)(nil),     // 50: order.ListOrderShipmentsRequest
+	(*ListOrderShipmentsResponse)(nil),    // 51: order.ListOrderShipmentsResponse
+	(*Invoice)(nil),                       // 52: order.Invoice
+	(*GetInvoiceRequest)(nil),             // 53: order.GetInvoiceRequest
+	(*GetInvoiceResponse)(nil),            // 54: order.GetInvoiceResponse
+	(*FlagOrderDisputedRequest)(nil),      // 55: order.FlagOrderDisputedRequest
+	(*FlagOrderDisputedResponse)(nil),     // 56: order.FlagOrderDisputedResponse
+	(*Money)(nil),                         // 57: common.Money
+	(*Address)(nil),                       // 58: common.Address
+	(*timestamppb.Timestamp)(nil),         // 59: google.protobuf.Timestamp
+	(*Timestamp)(nil),                     // 60: common.Timestamp
+	(*PaginationRequest)(nil),             // 61: common.PaginationRequest
+	(*PaginationResponse)(nil),            // 62: common.PaginationResponse
 }
 var file_order_proto_depIdxs = []int32{
-	2,  // 0: order.Order.items:type_name -> order.OrderItem
-	19, // 1: order.Order.subtotal:type_name -> common.Money
-	19, // 2: order.Order.shipping:type_name -> common.Money
-	19, // 3: order.Order.tax:type_name -> common.Money
-	19, // 4: order.Order.discount:type_name -> common.Money
-	19, // 5: order.Order.total:type_name -> common.Money
-	0,  // 6: order.Order.status:type_name -> order.OrderStatus
-	20, // 7: order.Order.shipping_address:type_name -> common.Address
-	20, // 8: order.Order.billing_address:type_name -> common.Address
-	3,  // 9: order.Order.tracking:type_name -> order.TrackingInfo
-	21, // 10: order.Order.created_at:type_name -> google.protobuf.Timestamp
-	21, // 11: order.Order.updated_at:type_name -> google.protobuf.Timestamp
-	19, // 12: order.OrderItem.unit_price:type_name -> common.Money
-	19, // 13: order.OrderItem.total_price:type_name -> common.Money
-	22, // 14: order.TrackingInfo.shipped_at:type_name -> common.Timestamp
-	22, // 15: order.TrackingInfo.estimated_delivery:type_name -> common.Timestamp
-	5,  // 16: order.CreateOrderRequest.items:type_name -> order.OrderItemRequest
-	1,  // 17: order.CreateOrderResponse.order:type_name -> order.Order
-	1,  // 18: order.GetOrderResponse.order:type_name -> order.Order
-	23, // 19: order.ListOrdersRequest.pagination:type_name -> common.PaginationRequest
-	10, // 20: order.ListOrdersRequest.filters:type_name -> order.OrderFilters
-	0,  // 21: order.OrderFilters.status:type_name -> order.OrderStatus
-	22, // 22: order.OrderFilters.from_date:type_name -> common.Timestamp
-	22, // 23: order.OrderFilters.to_date:type_name -> common.Timestamp
-	1,  // 24: order.ListOrdersResponse.orders:type_name -> order.Order
-	24, // 25: order.ListOrdersResponse.pagination:type_name -> common.PaginationResponse
-	1,  // 26: order.CancelOrderResponse.order:type_name -> order.Order
-	0,  // 27: order.UpdateOrderStatusRequest.status:type_name -> order.OrderStatus
-	3,  // 28: order.UpdateOrderStatusRequest.tracking:type_name -> order.TrackingInfo
-	1,  // 29: order.UpdateOrderStatusResponse.order:type_name -> order.Order
-	18, // 30: order.GetOrderStatusHistoryResponse.history:type_name -> order.OrderStatusHistory
-	0,  // 31: order.OrderStatusHistory.status:type_name -> order.OrderStatus
-	22, // 32: order.OrderStatusHistory.timestamp:type_name -> common.Timestamp
-	4,  // 33: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
-	7,  // 34: order.OrderService.GetOrder:input_type -> order.GetOrderRequest
-	9,  // 35: order.OrderService.ListOrders:input_type -> order.ListOrdersRequest
-	12, // 36: order.OrderService.CancelOrder:input_type -> order.CancelOrderRequest
-	14, // 37: order.OrderService.UpdateOrderStatus:input_type -> order.UpdateOrderStatusRequest
-	16, // 38: order.OrderService.GetOrderStatusHistory:input_type -> order.GetOrderStatusHistoryRequest
-	6,  // 39: order.OrderService.CreateOrder:output_type -> order.CreateOrderResponse
-	8,  // 40: order.OrderService.GetOrder:output_type -> order.GetOrderResponse
-	11, // 41: order.OrderService.ListOrders:output_type -> order.ListOrdersResponse
-	13, // 42: order.OrderService.CancelOrder:output_type -> order.CancelOrderResponse
-	15, // 43: order.OrderService.UpdateOrderStatus:output_type -> order.UpdateOrderStatusResponse
-	17, // 44: order.OrderService.GetOrderStatusHistory:output_type -> order.GetOrderStatusHistoryResponse
-	39, // [39:45] is the sub-list for method output_type
-	33, // [33:39] is the sub-list for method input_type
-	33, // [33:33] is the sub-list for extension type_name
-	33, // [33:33] is the sub-list for extension extendee
-	0,  // [0:33] is the sub-list for field type_name
+	6,  // 0: order.Order.items:type_name -> order.OrderItem
+	57, // 1: order.Order.subtotal:type_name -> common.Money
+	57, // 2: order.Order.shipping:type_name -> common.Money
+	57, // 3: order.Order.tax:type_name -> common.Money
+	57, // 4: order.Order.discount:type_name -> common.Money
+	57, // 5: order.Order.total:type_name -> common.Money
+	1,  // 6: order.Order.status:type_name -> order.OrderStatus
+	58, // 7: order.Order.shipping_address:type_name -> common.Address
+	58, // 8: order.Order.billing_address:type_name -> common.Address
+	7,  // 9: order.Order.tracking:type_name -> order.TrackingInfo
+	59, // 10: order.Order.created_at:type_name -> google.protobuf.Timestamp
+	59, // 11: order.Order.updated_at:type_name -> google.protobuf.Timestamp
+	57, // 12: order.Order.refunded:type_name -> common.Money
+	59, // 13: order.Order.disputed_at:type_name -> google.protobuf.Timestamp
+	57, // 14: order.OrderItem.unit_price:type_name -> common.Money
+	57, // 15: order.OrderItem.total_price:type_name -> common.Money
+	0,  // 16: order.OrderItem.status:type_name -> order.OrderItemStatus
+	60, // 17: order.TrackingInfo.shipped_at:type_name -> common.Timestamp
+	60, // 18: order.TrackingInfo.estimated_delivery:type_name -> common.Timestamp
+	9,  // 19: order.CreateOrderRequest.items:type_name -> order.OrderItemRequest
+	5,  // 20: order.CreateOrderResponse.order:type_name -> order.Order
+	5,  // 21: order.GetOrderResponse.order:type_name -> order.Order
+	61, // 22: order.ListOrdersRequest.pagination:type_name -> common.PaginationRequest
+	14, // 23: order.ListOrdersRequest.filters:type_name -> order.OrderFilters
+	1,  // 24: order.OrderFilters.status:type_name -> order.OrderStatus
+	60, // 25: order.OrderFilters.from_date:type_name -> common.Timestamp
+	60, // 26: order.OrderFilters.to_date:type_name -> common.Timestamp
+	1,  // 27: order.OrderFilters.statuses:type_name -> order.OrderStatus
+	57, // 28: order.OrderFilters.min_total:type_name -> common.Money
+	57, // 29: order.OrderFilters.max_total:type_name -> common.Money
+	5,  // 30: order.ListOrdersResponse.orders:type_name -> order.Order
+	62, // 31: order.ListOrdersResponse.pagination:type_name -> common.PaginationResponse
+	14, // 32: order.SearchOrdersRequest.filters:type_name -> order.OrderFilters
+	5,  // 33: order.SearchOrdersResponse.orders:type_name -> order.Order
+	5,  // 34: order.CancelOrderResponse.order:type_name -> order.Order
+	1,  // 35: order.UpdateOrderStatusRequest.status:type_name -> order.OrderStatus
+	7,  // 36: order.UpdateOrderStatusRequest.tracking:type_name -> order.TrackingInfo
+	5,  // 37: order.UpdateOrderStatusResponse.order:type_name -> order.Order
+	24, // 38: order.GetOrderStatusHistoryResponse.history:type_name -> order.OrderStatusHistory
+	1,  // 39: order.OrderStatusHistory.status:type_name -> order.OrderStatus
+	60, // 40: order.OrderStatusHistory.timestamp:type_name -> common.Timestamp
+	1,  // 41: order.OrderStatusHistory.from_status:type_name -> order.OrderStatus
+	5,  // 42: order.CancelOrderItemResponse.order:type_name -> order.Order
+	57, // 43: order.CancelOrderItemResponse.refund_amount:type_name -> common.Money
+	28, // 44: order.OrderReturn.items:type_name -> order.OrderReturnItem
+	2,  // 45: order.OrderReturn.status:type_name -> order.ReturnStatus
+	57, // 46: order.OrderReturn.refund_amount:type_name -> common.Money
+	59, // 47: order.OrderReturn.created_at:type_name -> google.protobuf.Timestamp
+	59, // 48: order.OrderReturn.updated_at:type_name -> google.protobuf.Timestamp
+	27, // 49: order.RequestReturnResponse.order_return:type_name -> order.OrderReturn
+	27, // 50: order.ApproveReturnResponse.order_return:type_name -> order.OrderReturn
+	27, // 51: order.RejectReturnResponse.order_return:type_name -> order.OrderReturn
+	27, // 52: order.ReceiveReturnResponse.order_return:type_name -> order.OrderReturn
+	27, // 53: order.RefundReturnResponse.order_return:type_name -> order.OrderReturn
+	27, // 54: order.GetReturnResponse.order_return:type_name -> order.OrderReturn
+	27, // 55: order.ListOrderReturnsResponse.returns:type_name -> order.OrderReturn
+	3,  // 56: order.Shipment.status:type_name -> order.ShipmentStatus
+	44, // 57: order.Shipment.items:type_name -> order.ShipmentItem
+	59, // 58: order.Shipment.shipped_at:type_name -> google.protobuf.Timestamp
+	59, // 59: order.Shipment.delivered_at:type_name -> google.protobuf.Timestamp
+	59, // 60: order.Shipment.created_at:type_name -> google.protobuf.Timestamp
+	59, // 61: order.Shipment.updated_at:type_name -> google.protobuf.Timestamp
+	46, // 62: order.CreateShipmentRequest.items:type_name -> order.ShipmentItemRequest
+	43, // 63: order.CreateShipmentResponse.shipment:type_name -> order.Shipment
+	3,  // 64: order.IngestTrackingEventRequest.status:type_name -> order.ShipmentStatus
+	59, // 65: order.IngestTrackingEventRequest.occurred_at:type_name -> google.protobuf.Timestamp
+	43, // 66: order.IngestTrackingEventResponse.shipment:type_name -> order.Shipment
+	1,  // 67: order.IngestTrackingEventResponse.order_status:type_name -> order.OrderStatus
+	43, // 68: order.ListOrderShipmentsResponse.shipments:type_name -> order.Shipment
+	57, // 69: order.Invoice.subtotal:type_name -> common.Money
+	57, // 70: order.Invoice.discount_amount:type_name -> common.Money
+	57, // 71: order.Invoice.shipping_amount:type_name -> common.Money
+	57, // 72: order.Invoice.tax_amount:type_name -> common.Money
+	57, // 73: order.Invoice.total_amount:type_name -> common.Money
+	59, // 74: order.Invoice.issued_at:type_name -> google.protobuf.Timestamp
+	4,  // 75: order.GetInvoiceRequest.format:type_name -> order.InvoiceFormat
+	52, // 76: order.GetInvoiceResponse.invoice:type_name -> order.Invoice
+	5,  // 77: order.FlagOrderDisputedResponse.order:type_name -> order.Order
+	8,  // 78: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
+	11, // 79: order.OrderService.GetOrder:input_type -> order.GetOrderRequest
+	13, // 80: order.OrderService.ListOrders:input_type -> order.ListOrdersRequest
+	16, // 81: order.OrderService.SearchOrders:input_type -> order.SearchOrdersRequest
+	18, // 82: order.OrderService.CancelOrder:input_type -> order.CancelOrderRequest
+	20, // 83: order.OrderService.UpdateOrderStatus:input_type -> order.UpdateOrderStatusRequest
+	22, // 84: order.OrderService.GetOrderStatusHistory:input_type -> order.GetOrderStatusHistoryRequest
+	25, // 85: order.OrderService.CancelOrderItem:input_type -> order.CancelOrderItemRequest
+	29, // 86: order.OrderService.RequestReturn:input_type -> order.RequestReturnRequest
+	31, // 87: order.OrderService.ApproveReturn:input_type -> order.ApproveReturnRequest
+	33, // 88: order.OrderService.RejectReturn:input_type -> order.RejectReturnRequest
+	35, // 89: order.OrderService.ReceiveReturn:input_type -> order.ReceiveReturnRequest
+	37, // 90: order.OrderService.RefundReturn:input_type -> order.RefundReturnRequest
+	39, // 91: order.OrderService.GetReturn:input_type -> order.GetReturnRequest
+	41, // 92: order.OrderService.ListOrderReturns:input_type -> order.ListOrderReturnsRequest
+	45, // 93: order.OrderService.CreateShipment:input_type -> order.CreateShipmentRequest
+	48, // 94: order.OrderService.IngestTrackingEvent:input_type -> order.IngestTrackingEventRequest
+	50, // 95: order.OrderService.ListOrderShipments:input_type -> order.ListOrderShipmentsRequest
+	53, // 96: order.OrderService.GetInvoice:input_type -> order.GetInvoiceRequest
+	55, // 97: order.OrderService.FlagOrderDisputed:input_type -> order.FlagOrderDisputedRequest
+	10, // 98: order.OrderService.CreateOrder:output_type -> order.CreateOrderResponse
+	12, // 99: order.OrderService.GetOrder:output_type -> order.GetOrderResponse
+	15, // 100: order.OrderService.ListOrders:output_type -> order.ListOrdersResponse
+	17, // 101: order.OrderService.SearchOrders:output_type -> order.SearchOrdersResponse
+	19, // 102: order.OrderService.CancelOrder:output_type -> order.CancelOrderResponse
+	21, // 103: order.OrderService.UpdateOrderStatus:output_type -> order.UpdateOrderStatusResponse
+	23, // 104: order.OrderService.GetOrderStatusHistory:output_type -> order.GetOrderStatusHistoryResponse
+	26, // 105: order.OrderService.CancelOrderItem:output_type -> order.CancelOrderItemResponse
+	30, // 106: order.OrderService.RequestReturn:output_type -> order.RequestReturnResponse
+	32, // 107: order.OrderService.ApproveReturn:output_type -> order.ApproveReturnResponse
+	34, // 108: order.OrderService.RejectReturn:output_type -> order.RejectReturnResponse
+	36, // 109: order.OrderService.ReceiveReturn:output_type -> order.ReceiveReturnResponse
+	38, // 110: order.OrderService.RefundReturn:output_type -> order.RefundReturnResponse
+	40, // 111: order.OrderService.GetReturn:output_type -> order.GetReturnResponse
+	42, // 112: order.OrderService.ListOrderReturns:output_type -> order.ListOrderReturnsResponse
+	47, // 113: order.OrderService.CreateShipment:output_type -> order.CreateShipmentResponse
+	49, // 114: order.OrderService.IngestTrackingEvent:output_type -> order.IngestTrackingEventResponse
+	51, // 115: order.OrderService.ListOrderShipments:output_type -> order.ListOrderShipmentsResponse
+	54, // 116: order.OrderService.GetInvoice:output_type -> order.GetInvoiceResponse
+	56, // 117: order.OrderService.FlagOrderDisputed:output_type -> order.FlagOrderDisputedResponse
+	98, // [98:118] is the sub-list for method output_type
+	78, // [78:98] is the sub-list for method input_type
+	78, // [78:78] is the sub-list for extension type_name
+	78, // [78:78] is the sub-list for extension extendee
+	0,  // [0:78] is the sub-list for field type_name
 }
 
 func init() { file_order_proto_init() }
@@ -1523,13 +4271,14 @@ func file_order_proto_init() {
 		return
 	}
 	file_common_proto_init()
+	file_order_proto_msgTypes[19].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_order_proto_rawDesc), len(file_order_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   18,
+			NumEnums:      5,
+			NumMessages:   52,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -25,12 +25,15 @@ const (
 type PaymentStatus int32
 
 const (
-	PaymentStatus_PENDING    PaymentStatus = 0
-	PaymentStatus_PROCESSING PaymentStatus = 1
-	PaymentStatus_SUCCEEDED  PaymentStatus = 2
-	PaymentStatus_FAILED     PaymentStatus = 3
-	PaymentStatus_CANCELLED  PaymentStatus = 4
-	PaymentStatus_REFUNDED   PaymentStatus = 5
+	PaymentStatus_PENDING            PaymentStatus = 0
+	PaymentStatus_PROCESSING         PaymentStatus = 1
+	PaymentStatus_SUCCEEDED          PaymentStatus = 2
+	PaymentStatus_FAILED             PaymentStatus = 3
+	PaymentStatus_CANCELLED          PaymentStatus = 4
+	PaymentStatus_REFUNDED           PaymentStatus = 5
+	PaymentStatus_PARTIALLY_REFUNDED PaymentStatus = 6
+	PaymentStatus_AUTHORIZED         PaymentStatus = 7 // Funds held, not captured yet
+	PaymentStatus_CAPTURED           PaymentStatus = 8 // Authorization captured, in full or in part
 )
 
 // Enum value maps for PaymentStatus.
@@ -42,14 +45,20 @@ var (
 		3: "FAILED",
 		4: "CANCELLED",
 		5: "REFUNDED",
+		6: "PARTIALLY_REFUNDED",
+		7: "AUTHORIZED",
+		8: "CAPTURED",
 	}
 	PaymentStatus_value = map[string]int32{
-		"PENDING":    0,
-		"PROCESSING": 1,
-		"SUCCEEDED":  2,
-		"FAILED":     3,
-		"CANCELLED":  4,
-		"REFUNDED":   5,
+		"PENDING":            0,
+		"PROCESSING":         1,
+		"SUCCEEDED":          2,
+		"FAILED":             3,
+		"CANCELLED":          4,
+		"REFUNDED":           5,
+		"PARTIALLY_REFUNDED": 6,
+		"AUTHORIZED":         7,
+		"CAPTURED":           8,
 	}
 )
 
@@ -136,21 +145,144 @@ func (PaymentMethodType) EnumDescriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{1}
 }
 
+// Kind of difference between payments and provider settlement data
+type DiscrepancyType int32
+
+const (
+	DiscrepancyType_DISCREPANCY_TYPE_UNSPECIFIED DiscrepancyType = 0
+	DiscrepancyType_AMOUNT_MISMATCH              DiscrepancyType = 1 // Provider settled another amount or currency than recorded
+	DiscrepancyType_MISSING_CHARGE               DiscrepancyType = 2 // Payment collected funds but the provider has no charge
+	DiscrepancyType_UNMATCHED_CHARGE             DiscrepancyType = 3 // Provider charge for no known payment
+	DiscrepancyType_ORPHANED_REFUND              DiscrepancyType = 4 // Provider refund without a refund record
+)
+
+// Enum value maps for DiscrepancyType.
+var (
+	DiscrepancyType_name = map[int32]string{
+		0: "DISCREPANCY_TYPE_UNSPECIFIED",
+		1: "AMOUNT_MISMATCH",
+		2: "MISSING_CHARGE",
+		3: "UNMATCHED_CHARGE",
+		4: "ORPHANED_REFUND",
+	}
+	DiscrepancyType_value = map[string]int32{
+		"DISCREPANCY_TYPE_UNSPECIFIED": 0,
+		"AMOUNT_MISMATCH":              1,
+		"MISSING_CHARGE":               2,
+		"UNMATCHED_CHARGE":             3,
+		"ORPHANED_REFUND":              4,
+	}
+)
+
+func (x DiscrepancyType) Enum() *DiscrepancyType {
+	p := new(DiscrepancyType)
+	*p = x
+	return p
+}
+
+func (x DiscrepancyType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DiscrepancyType) Descriptor() protoreflect.EnumDescriptor {
+	return file_payment_proto_enumTypes[2].Descriptor()
+}
+
+func (DiscrepancyType) Type() protoreflect.EnumType {
+	return &file_payment_proto_enumTypes[2]
+}
+
+func (x DiscrepancyType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DiscrepancyType.Descriptor instead.
+func (DiscrepancyType) EnumDescriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{2}
+}
+
+// State of a dispute opened by the customer's bank
+type DisputeStatus int32
+
+const (
+	DisputeStatus_DISPUTE_STATUS_UNSPECIFIED DisputeStatus = 0
+	DisputeStatus_NEEDS_RESPONSE             DisputeStatus = 1 // Evidence can be submitted until evidence_due_by
+	DisputeStatus_UNDER_REVIEW               DisputeStatus = 2 // Evidence submitted, waiting for the bank
+	DisputeStatus_WON                        DisputeStatus = 3 // Funds stay with the merchant
+	DisputeStatus_LOST                       DisputeStatus = 4 // Funds returned to the customer
+)
+
+// Enum value maps for DisputeStatus.
+var (
+	DisputeStatus_name = map[int32]string{
+		0: "DISPUTE_STATUS_UNSPECIFIED",
+		1: "NEEDS_RESPONSE",
+		2: "UNDER_REVIEW",
+		3: "WON",
+		4: "LOST",
+	}
+	DisputeStatus_value = map[string]int32{
+		"DISPUTE_STATUS_UNSPECIFIED": 0,
+		"NEEDS_RESPONSE":             1,
+		"UNDER_REVIEW":               2,
+		"WON":                        3,
+		"LOST":                       4,
+	}
+)
+
+func (x DisputeStatus) Enum() *DisputeStatus {
+	p := new(DisputeStatus)
+	*p = x
+	return p
+}
+
+func (x DisputeStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DisputeStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_payment_proto_enumTypes[3].Descriptor()
+}
+
+func (DisputeStatus) Type() protoreflect.EnumType {
+	return &file_payment_proto_enumTypes[3]
+}
+
+func (x DisputeStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DisputeStatus.Descriptor instead.
+func (DisputeStatus) EnumDescriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{3}
+}
+
 // Payment message
 type Payment struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Amount        *Money                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
-	Status        PaymentStatus          `protobuf:"varint,5,opt,name=status,proto3,enum=payment.PaymentStatus" json:"status,omitempty"`
-	Method        PaymentMethodType      `protobuf:"varint,6,opt,name=method,proto3,enum=payment.PaymentMethodType" json:"method,omitempty"`
-	TransactionId string                 `protobuf:"bytes,7,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
-	ErrorMessage  string                 `protobuf:"bytes,8,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	CreatedAt     *Timestamp             `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *Timestamp             `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Id                     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId                string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId                 string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount                 *Money                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Status                 PaymentStatus          `protobuf:"varint,5,opt,name=status,proto3,enum=payment.PaymentStatus" json:"status,omitempty"`
+	Method                 PaymentMethodType      `protobuf:"varint,6,opt,name=method,proto3,enum=payment.PaymentMethodType" json:"method,omitempty"`
+	TransactionId          string                 `protobuf:"bytes,7,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	ErrorMessage           string                 `protobuf:"bytes,8,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	CreatedAt              *Timestamp             `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt              *Timestamp             `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	RefundedAmount         *Money                 `protobuf:"bytes,11,opt,name=refunded_amount,json=refundedAmount,proto3" json:"refunded_amount,omitempty"`                           // Cumulative amount refunded
+	CapturedAmount         *Money                 `protobuf:"bytes,12,opt,name=captured_amount,json=capturedAmount,proto3" json:"captured_amount,omitempty"`                           // Amount captured from the authorization
+	AuthorizationExpiresAt *Timestamp             `protobuf:"bytes,13,opt,name=authorization_expires_at,json=authorizationExpiresAt,proto3" json:"authorization_expires_at,omitempty"` // When an uncaptured authorization is released
+	Provider               string                 `protobuf:"bytes,14,opt,name=provider,proto3" json:"provider,omitempty"`                                                             // Provider handling the payment, e.g. stripe, paypal
+	ApprovalUrl            string                 `protobuf:"bytes,15,opt,name=approval_url,json=approvalUrl,proto3" json:"approval_url,omitempty"`                                    // Where the buyer approves the payment while it is PROCESSING, e.g. a PayPal order
+	SettlementAmount       *Money                 `protobuf:"bytes,16,opt,name=settlement_amount,json=settlementAmount,proto3" json:"settlement_amount,omitempty"`                     // Collected amount in the settlement currency, at the exchange rate snapshot
+	ExchangeRate           float64                `protobuf:"fixed64,17,opt,name=exchange_rate,json=exchangeRate,proto3" json:"exchange_rate,omitempty"`                               // Settlement currency units per unit of the payment currency, 0 without a snapshot
+	ExchangeRateAt         *Timestamp             `protobuf:"bytes,18,opt,name=exchange_rate_at,json=exchangeRateAt,proto3" json:"exchange_rate_at,omitempty"`                         // When the exchange rate was quoted
+	RiskScore              int32                  `protobuf:"varint,19,opt,name=risk_score,json=riskScore,proto3" json:"risk_score,omitempty"`                                         // Fraud risk score of the latest attempt
+	RiskDecision           string                 `protobuf:"bytes,20,opt,name=risk_decision,json=riskDecision,proto3" json:"risk_decision,omitempty"`                                 // ALLOW, REVIEW or DENY; empty before the first attempt
+	RiskReasons            []string               `protobuf:"bytes,21,rep,name=risk_reasons,json=riskReasons,proto3" json:"risk_reasons,omitempty"`                                    // Rules that made up the risk score
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
 func (x *Payment) Reset() {
@@ -253,6 +385,83 @@ func (x *Payment) GetUpdatedAt() *Timestamp {
 	return nil
 }
 
+func (x *Payment) GetRefundedAmount() *Money {
+	if x != nil {
+		return x.RefundedAmount
+	}
+	return nil
+}
+
+func (x *Payment) GetCapturedAmount() *Money {
+	if x != nil {
+		return x.CapturedAmount
+	}
+	return nil
+}
+
+func (x *Payment) GetAuthorizationExpiresAt() *Timestamp {
+	if x != nil {
+		return x.AuthorizationExpiresAt
+	}
+	return nil
+}
+
+func (x *Payment) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Payment) GetApprovalUrl() string {
+	if x != nil {
+		return x.ApprovalUrl
+	}
+	return ""
+}
+
+func (x *Payment) GetSettlementAmount() *Money {
+	if x != nil {
+		return x.SettlementAmount
+	}
+	return nil
+}
+
+func (x *Payment) GetExchangeRate() float64 {
+	if x != nil {
+		return x.ExchangeRate
+	}
+	return 0
+}
+
+func (x *Payment) GetExchangeRateAt() *Timestamp {
+	if x != nil {
+		return x.ExchangeRateAt
+	}
+	return nil
+}
+
+func (x *Payment) GetRiskScore() int32 {
+	if x != nil {
+		return x.RiskScore
+	}
+	return 0
+}
+
+func (x *Payment) GetRiskDecision() string {
+	if x != nil {
+		return x.RiskDecision
+	}
+	return ""
+}
+
+func (x *Payment) GetRiskReasons() []string {
+	if x != nil {
+		return x.RiskReasons
+	}
+	return nil
+}
+
 // Payment method
 type PaymentMethod struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -604,29 +813,29 @@ func (x *ConfirmPaymentResponse) GetPayment() *Payment {
 	return nil
 }
 
-// Cancel payment request
-type CancelPaymentRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
-	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Authorize payment request
+type AuthorizePaymentRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PaymentIntentId string                 `protobuf:"bytes,1,opt,name=payment_intent_id,json=paymentIntentId,proto3" json:"payment_intent_id,omitempty"`
+	PaymentMethodId string                 `protobuf:"bytes,2,opt,name=payment_method_id,json=paymentMethodId,proto3" json:"payment_method_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *CancelPaymentRequest) Reset() {
-	*x = CancelPaymentRequest{}
+func (x *AuthorizePaymentRequest) Reset() {
+	*x = AuthorizePaymentRequest{}
 	mi := &file_payment_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelPaymentRequest) String() string {
+func (x *AuthorizePaymentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelPaymentRequest) ProtoMessage() {}
+func (*AuthorizePaymentRequest) ProtoMessage() {}
 
-func (x *CancelPaymentRequest) ProtoReflect() protoreflect.Message {
+func (x *AuthorizePaymentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -638,46 +847,46 @@ func (x *CancelPaymentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelPaymentRequest.ProtoReflect.Descriptor instead.
-func (*CancelPaymentRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use AuthorizePaymentRequest.ProtoReflect.Descriptor instead.
+func (*AuthorizePaymentRequest) Descriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *CancelPaymentRequest) GetPaymentId() string {
+func (x *AuthorizePaymentRequest) GetPaymentIntentId() string {
 	if x != nil {
-		return x.PaymentId
+		return x.PaymentIntentId
 	}
 	return ""
 }
 
-func (x *CancelPaymentRequest) GetReason() string {
+func (x *AuthorizePaymentRequest) GetPaymentMethodId() string {
 	if x != nil {
-		return x.Reason
+		return x.PaymentMethodId
 	}
 	return ""
 }
 
-type CancelPaymentResponse struct {
+type AuthorizePaymentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Payment       *Payment               `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CancelPaymentResponse) Reset() {
-	*x = CancelPaymentResponse{}
+func (x *AuthorizePaymentResponse) Reset() {
+	*x = AuthorizePaymentResponse{}
 	mi := &file_payment_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelPaymentResponse) String() string {
+func (x *AuthorizePaymentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelPaymentResponse) ProtoMessage() {}
+func (*AuthorizePaymentResponse) ProtoMessage() {}
 
-func (x *CancelPaymentResponse) ProtoReflect() protoreflect.Message {
+func (x *AuthorizePaymentResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -689,42 +898,41 @@ func (x *CancelPaymentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelPaymentResponse.ProtoReflect.Descriptor instead.
-func (*CancelPaymentResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use AuthorizePaymentResponse.ProtoReflect.Descriptor instead.
+func (*AuthorizePaymentResponse) Descriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *CancelPaymentResponse) GetPayment() *Payment {
+func (x *AuthorizePaymentResponse) GetPayment() *Payment {
 	if x != nil {
 		return x.Payment
 	}
 	return nil
 }
 
-// Refund payment request
-type RefundPaymentRequest struct {
+// Capture payment request
+type CapturePaymentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
-	Amount        *Money                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"` // Partial or full refund
-	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Amount        *Money                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"` // Omitted to capture the full authorization
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RefundPaymentRequest) Reset() {
-	*x = RefundPaymentRequest{}
+func (x *CapturePaymentRequest) Reset() {
+	*x = CapturePaymentRequest{}
 	mi := &file_payment_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RefundPaymentRequest) String() string {
+func (x *CapturePaymentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RefundPaymentRequest) ProtoMessage() {}
+func (*CapturePaymentRequest) ProtoMessage() {}
 
-func (x *RefundPaymentRequest) ProtoReflect() protoreflect.Message {
+func (x *CapturePaymentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -736,54 +944,46 @@ func (x *RefundPaymentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RefundPaymentRequest.ProtoReflect.Descriptor instead.
-func (*RefundPaymentRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CapturePaymentRequest.ProtoReflect.Descriptor instead.
+func (*CapturePaymentRequest) Descriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *RefundPaymentRequest) GetPaymentId() string {
+func (x *CapturePaymentRequest) GetPaymentId() string {
 	if x != nil {
 		return x.PaymentId
 	}
 	return ""
 }
 
-func (x *RefundPaymentRequest) GetAmount() *Money {
+func (x *CapturePaymentRequest) GetAmount() *Money {
 	if x != nil {
 		return x.Amount
 	}
 	return nil
 }
 
-func (x *RefundPaymentRequest) GetReason() string {
-	if x != nil {
-		return x.Reason
-	}
-	return ""
-}
-
-type RefundPaymentResponse struct {
+type CapturePaymentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Payment       *Payment               `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
-	RefundId      string                 `protobuf:"bytes,2,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RefundPaymentResponse) Reset() {
-	*x = RefundPaymentResponse{}
+func (x *CapturePaymentResponse) Reset() {
+	*x = CapturePaymentResponse{}
 	mi := &file_payment_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RefundPaymentResponse) String() string {
+func (x *CapturePaymentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RefundPaymentResponse) ProtoMessage() {}
+func (*CapturePaymentResponse) ProtoMessage() {}
 
-func (x *RefundPaymentResponse) ProtoReflect() protoreflect.Message {
+func (x *CapturePaymentResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -795,47 +995,40 @@ func (x *RefundPaymentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RefundPaymentResponse.ProtoReflect.Descriptor instead.
-func (*RefundPaymentResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CapturePaymentResponse.ProtoReflect.Descriptor instead.
+func (*CapturePaymentResponse) Descriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *RefundPaymentResponse) GetPayment() *Payment {
+func (x *CapturePaymentResponse) GetPayment() *Payment {
 	if x != nil {
 		return x.Payment
 	}
 	return nil
 }
 
-func (x *RefundPaymentResponse) GetRefundId() string {
-	if x != nil {
-		return x.RefundId
-	}
-	return ""
-}
-
-// Get payment status request
-type GetPaymentStatusRequest struct {
+// Void payment request
+type VoidPaymentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPaymentStatusRequest) Reset() {
-	*x = GetPaymentStatusRequest{}
+func (x *VoidPaymentRequest) Reset() {
+	*x = VoidPaymentRequest{}
 	mi := &file_payment_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPaymentStatusRequest) String() string {
+func (x *VoidPaymentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPaymentStatusRequest) ProtoMessage() {}
+func (*VoidPaymentRequest) ProtoMessage() {}
 
-func (x *GetPaymentStatusRequest) ProtoReflect() protoreflect.Message {
+func (x *VoidPaymentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -847,39 +1040,39 @@ func (x *GetPaymentStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPaymentStatusRequest.ProtoReflect.Descriptor instead.
-func (*GetPaymentStatusRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use VoidPaymentRequest.ProtoReflect.Descriptor instead.
+func (*VoidPaymentRequest) Descriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *GetPaymentStatusRequest) GetPaymentId() string {
+func (x *VoidPaymentRequest) GetPaymentId() string {
 	if x != nil {
 		return x.PaymentId
 	}
 	return ""
 }
 
-type GetPaymentStatusResponse struct {
+type VoidPaymentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Payment       *Payment               `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPaymentStatusResponse) Reset() {
-	*x = GetPaymentStatusResponse{}
+func (x *VoidPaymentResponse) Reset() {
+	*x = VoidPaymentResponse{}
 	mi := &file_payment_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPaymentStatusResponse) String() string {
+func (x *VoidPaymentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPaymentStatusResponse) ProtoMessage() {}
+func (*VoidPaymentResponse) ProtoMessage() {}
 
-func (x *GetPaymentStatusResponse) ProtoReflect() protoreflect.Message {
+func (x *VoidPaymentResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -891,40 +1084,41 @@ func (x *GetPaymentStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPaymentStatusResponse.ProtoReflect.Descriptor instead.
-func (*GetPaymentStatusResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use VoidPaymentResponse.ProtoReflect.Descriptor instead.
+func (*VoidPaymentResponse) Descriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *GetPaymentStatusResponse) GetPayment() *Payment {
+func (x *VoidPaymentResponse) GetPayment() *Payment {
 	if x != nil {
 		return x.Payment
 	}
 	return nil
 }
 
-// Get payment methods request
-type GetPaymentMethodsRequest struct {
+// Cancel payment request
+type CancelPaymentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPaymentMethodsRequest) Reset() {
-	*x = GetPaymentMethodsRequest{}
+func (x *CancelPaymentRequest) Reset() {
+	*x = CancelPaymentRequest{}
 	mi := &file_payment_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPaymentMethodsRequest) String() string {
+func (x *CancelPaymentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPaymentMethodsRequest) ProtoMessage() {}
+func (*CancelPaymentRequest) ProtoMessage() {}
 
-func (x *GetPaymentMethodsRequest) ProtoReflect() protoreflect.Message {
+func (x *CancelPaymentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -936,39 +1130,46 @@ func (x *GetPaymentMethodsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPaymentMethodsRequest.ProtoReflect.Descriptor instead.
-func (*GetPaymentMethodsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CancelPaymentRequest.ProtoReflect.Descriptor instead.
+func (*CancelPaymentRequest) Descriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *GetPaymentMethodsRequest) GetUserId() string {
+func (x *CancelPaymentRequest) GetPaymentId() string {
 	if x != nil {
-		return x.UserId
+		return x.PaymentId
 	}
 	return ""
 }
 
-type GetPaymentMethodsResponse struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	PaymentMethods []*PaymentMethod       `protobuf:"bytes,1,rep,name=payment_methods,json=paymentMethods,proto3" json:"payment_methods,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *CancelPaymentRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
 }
 
-func (x *GetPaymentMethodsResponse) Reset() {
-	*x = GetPaymentMethodsResponse{}
+type CancelPaymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payment       *Payment               `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelPaymentResponse) Reset() {
+	*x = CancelPaymentResponse{}
 	mi := &file_payment_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPaymentMethodsResponse) String() string {
+func (x *CancelPaymentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPaymentMethodsResponse) ProtoMessage() {}
+func (*CancelPaymentResponse) ProtoMessage() {}
 
-func (x *GetPaymentMethodsResponse) ProtoReflect() protoreflect.Message {
+func (x *CancelPaymentResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -980,44 +1181,1161 @@ func (x *GetPaymentMethodsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPaymentMethodsResponse.ProtoReflect.Descriptor instead.
-func (*GetPaymentMethodsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CancelPaymentResponse.ProtoReflect.Descriptor instead.
+func (*CancelPaymentResponse) Descriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *GetPaymentMethodsResponse) GetPaymentMethods() []*PaymentMethod {
+func (x *CancelPaymentResponse) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+// Refund payment request
+type RefundPaymentRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId      string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	Amount         *Money                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`                                       // Partial or full refund
+	Reason         string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`                                       // duplicate, fraudulent or requested_by_customer; other text is kept as a note
+	IdempotencyKey string                 `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // Caller's key for the refund; a retry with the same key returns the first refund instead of refunding again
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RefundPaymentRequest) Reset() {
+	*x = RefundPaymentRequest{}
+	mi := &file_payment_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundPaymentRequest) ProtoMessage() {}
+
+func (x *RefundPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundPaymentRequest.ProtoReflect.Descriptor instead.
+func (*RefundPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RefundPaymentRequest) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *RefundPaymentRequest) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *RefundPaymentRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RefundPaymentRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type RefundPaymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payment       *Payment               `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
+	RefundId      string                 `protobuf:"bytes,2,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundPaymentResponse) Reset() {
+	*x = RefundPaymentResponse{}
+	mi := &file_payment_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundPaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundPaymentResponse) ProtoMessage() {}
+
+func (x *RefundPaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundPaymentResponse.ProtoReflect.Descriptor instead.
+func (*RefundPaymentResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RefundPaymentResponse) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+func (x *RefundPaymentResponse) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
+// Get payment status request
+type GetPaymentStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"` // Gets the order's active payment, or its latest one, when payment_id is empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentStatusRequest) Reset() {
+	*x = GetPaymentStatusRequest{}
+	mi := &file_payment_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentStatusRequest) ProtoMessage() {}
+
+func (x *GetPaymentStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetPaymentStatusRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetPaymentStatusRequest) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *GetPaymentStatusRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetPaymentStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payment       *Payment               `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentStatusResponse) Reset() {
+	*x = GetPaymentStatusResponse{}
+	mi := &file_payment_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentStatusResponse) ProtoMessage() {}
+
+func (x *GetPaymentStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetPaymentStatusResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetPaymentStatusResponse) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+// Get payment methods request
+type GetPaymentMethodsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentMethodsRequest) Reset() {
+	*x = GetPaymentMethodsRequest{}
+	mi := &file_payment_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentMethodsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentMethodsRequest) ProtoMessage() {}
+
+func (x *GetPaymentMethodsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentMethodsRequest.ProtoReflect.Descriptor instead.
+func (*GetPaymentMethodsRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetPaymentMethodsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetPaymentMethodsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PaymentMethods []*PaymentMethod       `protobuf:"bytes,1,rep,name=payment_methods,json=paymentMethods,proto3" json:"payment_methods,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetPaymentMethodsResponse) Reset() {
+	*x = GetPaymentMethodsResponse{}
+	mi := &file_payment_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentMethodsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentMethodsResponse) ProtoMessage() {}
+
+func (x *GetPaymentMethodsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentMethodsResponse.ProtoReflect.Descriptor instead.
+func (*GetPaymentMethodsResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetPaymentMethodsResponse) GetPaymentMethods() []*PaymentMethod {
 	if x != nil {
 		return x.PaymentMethods
 	}
 	return nil
 }
 
-// Add payment method request
-type AddPaymentMethodRequest struct {
+// Add payment method request
+type AddPaymentMethodRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Type          PaymentMethodType      `protobuf:"varint,2,opt,name=type,proto3,enum=payment.PaymentMethodType" json:"type,omitempty"`
+	Token         string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"` // Tokenized payment info
+	SetAsDefault  bool                   `protobuf:"varint,4,opt,name=set_as_default,json=setAsDefault,proto3" json:"set_as_default,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPaymentMethodRequest) Reset() {
+	*x = AddPaymentMethodRequest{}
+	mi := &file_payment_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPaymentMethodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPaymentMethodRequest) ProtoMessage() {}
+
+func (x *AddPaymentMethodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPaymentMethodRequest.ProtoReflect.Descriptor instead.
+func (*AddPaymentMethodRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AddPaymentMethodRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AddPaymentMethodRequest) GetType() PaymentMethodType {
+	if x != nil {
+		return x.Type
+	}
+	return PaymentMethodType_CREDIT_CARD
+}
+
+func (x *AddPaymentMethodRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *AddPaymentMethodRequest) GetSetAsDefault() bool {
+	if x != nil {
+		return x.SetAsDefault
+	}
+	return false
+}
+
+type AddPaymentMethodResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentMethod *PaymentMethod         `protobuf:"bytes,1,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPaymentMethodResponse) Reset() {
+	*x = AddPaymentMethodResponse{}
+	mi := &file_payment_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPaymentMethodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPaymentMethodResponse) ProtoMessage() {}
+
+func (x *AddPaymentMethodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPaymentMethodResponse.ProtoReflect.Descriptor instead.
+func (*AddPaymentMethodResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AddPaymentMethodResponse) GetPaymentMethod() *PaymentMethod {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return nil
+}
+
+// Remove payment method request
+type RemovePaymentMethodRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PaymentMethodId string                 `protobuf:"bytes,1,opt,name=payment_method_id,json=paymentMethodId,proto3" json:"payment_method_id,omitempty"`
+	UserId          string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RemovePaymentMethodRequest) Reset() {
+	*x = RemovePaymentMethodRequest{}
+	mi := &file_payment_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePaymentMethodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePaymentMethodRequest) ProtoMessage() {}
+
+func (x *RemovePaymentMethodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePaymentMethodRequest.ProtoReflect.Descriptor instead.
+func (*RemovePaymentMethodRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RemovePaymentMethodRequest) GetPaymentMethodId() string {
+	if x != nil {
+		return x.PaymentMethodId
+	}
+	return ""
+}
+
+func (x *RemovePaymentMethodRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type RemovePaymentMethodResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePaymentMethodResponse) Reset() {
+	*x = RemovePaymentMethodResponse{}
+	mi := &file_payment_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePaymentMethodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePaymentMethodResponse) ProtoMessage() {}
+
+func (x *RemovePaymentMethodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePaymentMethodResponse.ProtoReflect.Descriptor instead.
+func (*RemovePaymentMethodResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RemovePaymentMethodResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// Reconciliation discrepancy
+type ReconciliationDiscrepancy struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type                  DiscrepancyType        `protobuf:"varint,2,opt,name=type,proto3,enum=payment.DiscrepancyType" json:"type,omitempty"`
+	PaymentId             string                 `protobuf:"bytes,3,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"` // Empty when no payment matches
+	ProviderPaymentId     string                 `protobuf:"bytes,4,opt,name=provider_payment_id,json=providerPaymentId,proto3" json:"provider_payment_id,omitempty"`
+	ProviderTransactionId string                 `protobuf:"bytes,5,opt,name=provider_transaction_id,json=providerTransactionId,proto3" json:"provider_transaction_id,omitempty"`
+	ExpectedAmount        *Money                 `protobuf:"bytes,6,opt,name=expected_amount,json=expectedAmount,proto3" json:"expected_amount,omitempty"` // What the payment records say
+	ActualAmount          *Money                 `protobuf:"bytes,7,opt,name=actual_amount,json=actualAmount,proto3" json:"actual_amount,omitempty"`       // What the provider settled
+	Details               string                 `protobuf:"bytes,8,opt,name=details,proto3" json:"details,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *ReconciliationDiscrepancy) Reset() {
+	*x = ReconciliationDiscrepancy{}
+	mi := &file_payment_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReconciliationDiscrepancy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconciliationDiscrepancy) ProtoMessage() {}
+
+func (x *ReconciliationDiscrepancy) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconciliationDiscrepancy.ProtoReflect.Descriptor instead.
+func (*ReconciliationDiscrepancy) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ReconciliationDiscrepancy) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReconciliationDiscrepancy) GetType() DiscrepancyType {
+	if x != nil {
+		return x.Type
+	}
+	return DiscrepancyType_DISCREPANCY_TYPE_UNSPECIFIED
+}
+
+func (x *ReconciliationDiscrepancy) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *ReconciliationDiscrepancy) GetProviderPaymentId() string {
+	if x != nil {
+		return x.ProviderPaymentId
+	}
+	return ""
+}
+
+func (x *ReconciliationDiscrepancy) GetProviderTransactionId() string {
+	if x != nil {
+		return x.ProviderTransactionId
+	}
+	return ""
+}
+
+func (x *ReconciliationDiscrepancy) GetExpectedAmount() *Money {
+	if x != nil {
+		return x.ExpectedAmount
+	}
+	return nil
+}
+
+func (x *ReconciliationDiscrepancy) GetActualAmount() *Money {
+	if x != nil {
+		return x.ActualAmount
+	}
+	return nil
+}
+
+func (x *ReconciliationDiscrepancy) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+// Reconciliation report of one provider and period
+type ReconciliationReport struct {
+	state            protoimpl.MessageState       `protogen:"open.v1"`
+	Id               string                       `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Provider         string                       `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	PeriodStart      *Timestamp                   `protobuf:"bytes,3,opt,name=period_start,json=periodStart,proto3" json:"period_start,omitempty"`
+	PeriodEnd        *Timestamp                   `protobuf:"bytes,4,opt,name=period_end,json=periodEnd,proto3" json:"period_end,omitempty"` // Exclusive
+	TransactionCount int32                        `protobuf:"varint,5,opt,name=transaction_count,json=transactionCount,proto3" json:"transaction_count,omitempty"`
+	PaymentCount     int32                        `protobuf:"varint,6,opt,name=payment_count,json=paymentCount,proto3" json:"payment_count,omitempty"`
+	Discrepancies    []*ReconciliationDiscrepancy `protobuf:"bytes,7,rep,name=discrepancies,proto3" json:"discrepancies,omitempty"`
+	CreatedAt        *Timestamp                   `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ReconciliationReport) Reset() {
+	*x = ReconciliationReport{}
+	mi := &file_payment_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReconciliationReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconciliationReport) ProtoMessage() {}
+
+func (x *ReconciliationReport) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconciliationReport.ProtoReflect.Descriptor instead.
+func (*ReconciliationReport) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ReconciliationReport) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReconciliationReport) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ReconciliationReport) GetPeriodStart() *Timestamp {
+	if x != nil {
+		return x.PeriodStart
+	}
+	return nil
+}
+
+func (x *ReconciliationReport) GetPeriodEnd() *Timestamp {
+	if x != nil {
+		return x.PeriodEnd
+	}
+	return nil
+}
+
+func (x *ReconciliationReport) GetTransactionCount() int32 {
+	if x != nil {
+		return x.TransactionCount
+	}
+	return 0
+}
+
+func (x *ReconciliationReport) GetPaymentCount() int32 {
+	if x != nil {
+		return x.PaymentCount
+	}
+	return 0
+}
+
+func (x *ReconciliationReport) GetDiscrepancies() []*ReconciliationDiscrepancy {
+	if x != nil {
+		return x.Discrepancies
+	}
+	return nil
+}
+
+func (x *ReconciliationReport) GetCreatedAt() *Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// Get reconciliation report request: by report_id, or by provider and the
+// UTC day of the period (YYYY-MM-DD), or the provider's latest report
+type GetReconciliationReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReportId      string                 `protobuf:"bytes,1,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`
+	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Date          string                 `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReconciliationReportRequest) Reset() {
+	*x = GetReconciliationReportRequest{}
+	mi := &file_payment_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReconciliationReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReconciliationReportRequest) ProtoMessage() {}
+
+func (x *GetReconciliationReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReconciliationReportRequest.ProtoReflect.Descriptor instead.
+func (*GetReconciliationReportRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetReconciliationReportRequest) GetReportId() string {
+	if x != nil {
+		return x.ReportId
+	}
+	return ""
+}
+
+func (x *GetReconciliationReportRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *GetReconciliationReportRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+type GetReconciliationReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Report        *ReconciliationReport  `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReconciliationReportResponse) Reset() {
+	*x = GetReconciliationReportResponse{}
+	mi := &file_payment_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReconciliationReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReconciliationReportResponse) ProtoMessage() {}
+
+func (x *GetReconciliationReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReconciliationReportResponse.ProtoReflect.Descriptor instead.
+func (*GetReconciliationReportResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetReconciliationReportResponse) GetReport() *ReconciliationReport {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+// Evidence challenging a dispute; all fields are free text
+type DisputeEvidence struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	ProductDescription       string                 `protobuf:"bytes,1,opt,name=product_description,json=productDescription,proto3" json:"product_description,omitempty"`
+	CustomerName             string                 `protobuf:"bytes,2,opt,name=customer_name,json=customerName,proto3" json:"customer_name,omitempty"`
+	CustomerEmail            string                 `protobuf:"bytes,3,opt,name=customer_email,json=customerEmail,proto3" json:"customer_email,omitempty"`
+	CustomerPurchaseIp       string                 `protobuf:"bytes,4,opt,name=customer_purchase_ip,json=customerPurchaseIp,proto3" json:"customer_purchase_ip,omitempty"`
+	BillingAddress           string                 `protobuf:"bytes,5,opt,name=billing_address,json=billingAddress,proto3" json:"billing_address,omitempty"`
+	ShippingAddress          string                 `protobuf:"bytes,6,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
+	ShippingCarrier          string                 `protobuf:"bytes,7,opt,name=shipping_carrier,json=shippingCarrier,proto3" json:"shipping_carrier,omitempty"`
+	ShippingTrackingNumber   string                 `protobuf:"bytes,8,opt,name=shipping_tracking_number,json=shippingTrackingNumber,proto3" json:"shipping_tracking_number,omitempty"`
+	ShippingDate             string                 `protobuf:"bytes,9,opt,name=shipping_date,json=shippingDate,proto3" json:"shipping_date,omitempty"`
+	RefundPolicyDisclosure   string                 `protobuf:"bytes,10,opt,name=refund_policy_disclosure,json=refundPolicyDisclosure,proto3" json:"refund_policy_disclosure,omitempty"`
+	RefundRefusalExplanation string                 `protobuf:"bytes,11,opt,name=refund_refusal_explanation,json=refundRefusalExplanation,proto3" json:"refund_refusal_explanation,omitempty"`
+	UncategorizedText        string                 `protobuf:"bytes,12,opt,name=uncategorized_text,json=uncategorizedText,proto3" json:"uncategorized_text,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *DisputeEvidence) Reset() {
+	*x = DisputeEvidence{}
+	mi := &file_payment_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisputeEvidence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisputeEvidence) ProtoMessage() {}
+
+func (x *DisputeEvidence) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisputeEvidence.ProtoReflect.Descriptor instead.
+func (*DisputeEvidence) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DisputeEvidence) GetProductDescription() string {
+	if x != nil {
+		return x.ProductDescription
+	}
+	return ""
+}
+
+func (x *DisputeEvidence) GetCustomerName() string {
+	if x != nil {
+		return x.CustomerName
+	}
+	return ""
+}
+
+func (x *DisputeEvidence) GetCustomerEmail() string {
+	if x != nil {
+		return x.CustomerEmail
+	}
+	return ""
+}
+
+func (x *DisputeEvidence) GetCustomerPurchaseIp() string {
+	if x != nil {
+		return x.CustomerPurchaseIp
+	}
+	return ""
+}
+
+func (x *DisputeEvidence) GetBillingAddress() string {
+	if x != nil {
+		return x.BillingAddress
+	}
+	return ""
+}
+
+func (x *DisputeEvidence) GetShippingAddress() string {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return ""
+}
+
+func (x *DisputeEvidence) GetShippingCarrier() string {
+	if x != nil {
+		return x.ShippingCarrier
+	}
+	return ""
+}
+
+func (x *DisputeEvidence) GetShippingTrackingNumber() string {
+	if x != nil {
+		return x.ShippingTrackingNumber
+	}
+	return ""
+}
+
+func (x *DisputeEvidence) GetShippingDate() string {
+	if x != nil {
+		return x.ShippingDate
+	}
+	return ""
+}
+
+func (x *DisputeEvidence) GetRefundPolicyDisclosure() string {
+	if x != nil {
+		return x.RefundPolicyDisclosure
+	}
+	return ""
+}
+
+func (x *DisputeEvidence) GetRefundRefusalExplanation() string {
+	if x != nil {
+		return x.RefundRefusalExplanation
+	}
+	return ""
+}
+
+func (x *DisputeEvidence) GetUncategorizedText() string {
+	if x != nil {
+		return x.UncategorizedText
+	}
+	return ""
+}
+
+// Dispute (chargeback) of a payment
+type Dispute struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Id                  string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PaymentId           string                 `protobuf:"bytes,2,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	OrderId             string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Provider            string                 `protobuf:"bytes,4,opt,name=provider,proto3" json:"provider,omitempty"`
+	ProviderDisputeId   string                 `protobuf:"bytes,5,opt,name=provider_dispute_id,json=providerDisputeId,proto3" json:"provider_dispute_id,omitempty"`
+	Status              DisputeStatus          `protobuf:"varint,6,opt,name=status,proto3,enum=payment.DisputeStatus" json:"status,omitempty"`
+	Reason              string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"` // Provider reason, e.g. fraudulent, product_not_received
+	Amount              *Money                 `protobuf:"bytes,8,opt,name=amount,proto3" json:"amount,omitempty"`
+	EvidenceDueBy       *Timestamp             `protobuf:"bytes,9,opt,name=evidence_due_by,json=evidenceDueBy,proto3" json:"evidence_due_by,omitempty"`
+	EvidenceSubmittedAt *Timestamp             `protobuf:"bytes,10,opt,name=evidence_submitted_at,json=evidenceSubmittedAt,proto3" json:"evidence_submitted_at,omitempty"`
+	Evidence            *DisputeEvidence       `protobuf:"bytes,11,opt,name=evidence,proto3" json:"evidence,omitempty"`
+	OrderFlaggedAt      *Timestamp             `protobuf:"bytes,12,opt,name=order_flagged_at,json=orderFlaggedAt,proto3" json:"order_flagged_at,omitempty"` // When the order service flagged the order
+	OpenedAt            *Timestamp             `protobuf:"bytes,13,opt,name=opened_at,json=openedAt,proto3" json:"opened_at,omitempty"`
+	ClosedAt            *Timestamp             `protobuf:"bytes,14,opt,name=closed_at,json=closedAt,proto3" json:"closed_at,omitempty"`
+	UpdatedAt           *Timestamp             `protobuf:"bytes,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Dispute) Reset() {
+	*x = Dispute{}
+	mi := &file_payment_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Dispute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dispute) ProtoMessage() {}
+
+func (x *Dispute) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dispute.ProtoReflect.Descriptor instead.
+func (*Dispute) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Dispute) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Dispute) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *Dispute) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Dispute) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Dispute) GetProviderDisputeId() string {
+	if x != nil {
+		return x.ProviderDisputeId
+	}
+	return ""
+}
+
+func (x *Dispute) GetStatus() DisputeStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DisputeStatus_DISPUTE_STATUS_UNSPECIFIED
+}
+
+func (x *Dispute) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Dispute) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Dispute) GetEvidenceDueBy() *Timestamp {
+	if x != nil {
+		return x.EvidenceDueBy
+	}
+	return nil
+}
+
+func (x *Dispute) GetEvidenceSubmittedAt() *Timestamp {
+	if x != nil {
+		return x.EvidenceSubmittedAt
+	}
+	return nil
+}
+
+func (x *Dispute) GetEvidence() *DisputeEvidence {
+	if x != nil {
+		return x.Evidence
+	}
+	return nil
+}
+
+func (x *Dispute) GetOrderFlaggedAt() *Timestamp {
+	if x != nil {
+		return x.OrderFlaggedAt
+	}
+	return nil
+}
+
+func (x *Dispute) GetOpenedAt() *Timestamp {
+	if x != nil {
+		return x.OpenedAt
+	}
+	return nil
+}
+
+func (x *Dispute) GetClosedAt() *Timestamp {
+	if x != nil {
+		return x.ClosedAt
+	}
+	return nil
+}
+
+func (x *Dispute) GetUpdatedAt() *Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// List disputes request; every filter is optional
+type ListDisputesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Type          PaymentMethodType      `protobuf:"varint,2,opt,name=type,proto3,enum=payment.PaymentMethodType" json:"type,omitempty"`
-	Token         string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"` // Tokenized payment info
-	SetAsDefault  bool                   `protobuf:"varint,4,opt,name=set_as_default,json=setAsDefault,proto3" json:"set_as_default,omitempty"`
+	Status        DisputeStatus          `protobuf:"varint,1,opt,name=status,proto3,enum=payment.DisputeStatus" json:"status,omitempty"`
+	PaymentId     string                 `protobuf:"bytes,2,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	DueBefore     *Timestamp             `protobuf:"bytes,4,opt,name=due_before,json=dueBefore,proto3" json:"due_before,omitempty"` // Only disputes whose evidence is due before this time
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddPaymentMethodRequest) Reset() {
-	*x = AddPaymentMethodRequest{}
-	mi := &file_payment_proto_msgTypes[14]
+func (x *ListDisputesRequest) Reset() {
+	*x = ListDisputesRequest{}
+	mi := &file_payment_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddPaymentMethodRequest) String() string {
+func (x *ListDisputesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddPaymentMethodRequest) ProtoMessage() {}
+func (*ListDisputesRequest) ProtoMessage() {}
 
-func (x *AddPaymentMethodRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_payment_proto_msgTypes[14]
+func (x *ListDisputesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1028,61 +2346,75 @@ func (x *AddPaymentMethodRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddPaymentMethodRequest.ProtoReflect.Descriptor instead.
-func (*AddPaymentMethodRequest) Descriptor() ([]byte, []int) {
-	return file_payment_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ListDisputesRequest.ProtoReflect.Descriptor instead.
+func (*ListDisputesRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *AddPaymentMethodRequest) GetUserId() string {
+func (x *ListDisputesRequest) GetStatus() DisputeStatus {
 	if x != nil {
-		return x.UserId
+		return x.Status
 	}
-	return ""
+	return DisputeStatus_DISPUTE_STATUS_UNSPECIFIED
 }
 
-func (x *AddPaymentMethodRequest) GetType() PaymentMethodType {
+func (x *ListDisputesRequest) GetPaymentId() string {
 	if x != nil {
-		return x.Type
+		return x.PaymentId
 	}
-	return PaymentMethodType_CREDIT_CARD
+	return ""
 }
 
-func (x *AddPaymentMethodRequest) GetToken() string {
+func (x *ListDisputesRequest) GetOrderId() string {
 	if x != nil {
-		return x.Token
+		return x.OrderId
 	}
 	return ""
 }
 
-func (x *AddPaymentMethodRequest) GetSetAsDefault() bool {
+func (x *ListDisputesRequest) GetDueBefore() *Timestamp {
 	if x != nil {
-		return x.SetAsDefault
+		return x.DueBefore
 	}
-	return false
+	return nil
 }
 
-type AddPaymentMethodResponse struct {
+func (x *ListDisputesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListDisputesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListDisputesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PaymentMethod *PaymentMethod         `protobuf:"bytes,1,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	Disputes      []*Dispute             `protobuf:"bytes,1,rep,name=disputes,proto3" json:"disputes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddPaymentMethodResponse) Reset() {
-	*x = AddPaymentMethodResponse{}
-	mi := &file_payment_proto_msgTypes[15]
+func (x *ListDisputesResponse) Reset() {
+	*x = ListDisputesResponse{}
+	mi := &file_payment_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddPaymentMethodResponse) String() string {
+func (x *ListDisputesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddPaymentMethodResponse) ProtoMessage() {}
+func (*ListDisputesResponse) ProtoMessage() {}
 
-func (x *AddPaymentMethodResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_payment_proto_msgTypes[15]
+func (x *ListDisputesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1093,42 +2425,42 @@ func (x *AddPaymentMethodResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddPaymentMethodResponse.ProtoReflect.Descriptor instead.
-func (*AddPaymentMethodResponse) Descriptor() ([]byte, []int) {
-	return file_payment_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use ListDisputesResponse.ProtoReflect.Descriptor instead.
+func (*ListDisputesResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *AddPaymentMethodResponse) GetPaymentMethod() *PaymentMethod {
+func (x *ListDisputesResponse) GetDisputes() []*Dispute {
 	if x != nil {
-		return x.PaymentMethod
+		return x.Disputes
 	}
 	return nil
 }
 
-// Remove payment method request
-type RemovePaymentMethodRequest struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	PaymentMethodId string                 `protobuf:"bytes,1,opt,name=payment_method_id,json=paymentMethodId,proto3" json:"payment_method_id,omitempty"`
-	UserId          string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+// Submit dispute evidence request
+type SubmitDisputeEvidenceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DisputeId     string                 `protobuf:"bytes,1,opt,name=dispute_id,json=disputeId,proto3" json:"dispute_id,omitempty"`
+	Evidence      *DisputeEvidence       `protobuf:"bytes,2,opt,name=evidence,proto3" json:"evidence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RemovePaymentMethodRequest) Reset() {
-	*x = RemovePaymentMethodRequest{}
-	mi := &file_payment_proto_msgTypes[16]
+func (x *SubmitDisputeEvidenceRequest) Reset() {
+	*x = SubmitDisputeEvidenceRequest{}
+	mi := &file_payment_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemovePaymentMethodRequest) String() string {
+func (x *SubmitDisputeEvidenceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemovePaymentMethodRequest) ProtoMessage() {}
+func (*SubmitDisputeEvidenceRequest) ProtoMessage() {}
 
-func (x *RemovePaymentMethodRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_payment_proto_msgTypes[16]
+func (x *SubmitDisputeEvidenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1139,47 +2471,47 @@ func (x *RemovePaymentMethodRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemovePaymentMethodRequest.ProtoReflect.Descriptor instead.
-func (*RemovePaymentMethodRequest) Descriptor() ([]byte, []int) {
-	return file_payment_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use SubmitDisputeEvidenceRequest.ProtoReflect.Descriptor instead.
+func (*SubmitDisputeEvidenceRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *RemovePaymentMethodRequest) GetPaymentMethodId() string {
+func (x *SubmitDisputeEvidenceRequest) GetDisputeId() string {
 	if x != nil {
-		return x.PaymentMethodId
+		return x.DisputeId
 	}
 	return ""
 }
 
-func (x *RemovePaymentMethodRequest) GetUserId() string {
+func (x *SubmitDisputeEvidenceRequest) GetEvidence() *DisputeEvidence {
 	if x != nil {
-		return x.UserId
+		return x.Evidence
 	}
-	return ""
+	return nil
 }
 
-type RemovePaymentMethodResponse struct {
+type SubmitDisputeEvidenceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Dispute       *Dispute               `protobuf:"bytes,1,opt,name=dispute,proto3" json:"dispute,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RemovePaymentMethodResponse) Reset() {
-	*x = RemovePaymentMethodResponse{}
-	mi := &file_payment_proto_msgTypes[17]
+func (x *SubmitDisputeEvidenceResponse) Reset() {
+	*x = SubmitDisputeEvidenceResponse{}
+	mi := &file_payment_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemovePaymentMethodResponse) String() string {
+func (x *SubmitDisputeEvidenceResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemovePaymentMethodResponse) ProtoMessage() {}
+func (*SubmitDisputeEvidenceResponse) ProtoMessage() {}
 
-func (x *RemovePaymentMethodResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_payment_proto_msgTypes[17]
+func (x *SubmitDisputeEvidenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1190,23 +2522,23 @@ func (x *RemovePaymentMethodResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemovePaymentMethodResponse.ProtoReflect.Descriptor instead.
-func (*RemovePaymentMethodResponse) Descriptor() ([]byte, []int) {
-	return file_payment_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use SubmitDisputeEvidenceResponse.ProtoReflect.Descriptor instead.
+func (*SubmitDisputeEvidenceResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *RemovePaymentMethodResponse) GetSuccess() bool {
+func (x *SubmitDisputeEvidenceResponse) GetDispute() *Dispute {
 	if x != nil {
-		return x.Success
+		return x.Dispute
 	}
-	return false
+	return nil
 }
 
 var File_payment_proto protoreflect.FileDescriptor
 
 const file_payment_proto_rawDesc = "" +
 	"\n" +
-	"\rpayment.proto\x12\apayment\x1a\fcommon.proto\"\x88\x03\n" +
+	"\rpayment.proto\x12\apayment\x1a\fcommon.proto\"\x89\a\n" +
 	"\aPayment\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
 	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x17\n" +
@@ -1220,7 +2552,19 @@ const file_payment_proto_rawDesc = "" +
 	"created_at\x18\t \x01(\v2\x11.common.TimestampR\tcreatedAt\x120\n" +
 	"\n" +
 	"updated_at\x18\n" +
-	" \x01(\v2\x11.common.TimestampR\tupdatedAt\"\x9d\x02\n" +
+	" \x01(\v2\x11.common.TimestampR\tupdatedAt\x126\n" +
+	"\x0frefunded_amount\x18\v \x01(\v2\r.common.MoneyR\x0erefundedAmount\x126\n" +
+	"\x0fcaptured_amount\x18\f \x01(\v2\r.common.MoneyR\x0ecapturedAmount\x12K\n" +
+	"\x18authorization_expires_at\x18\r \x01(\v2\x11.common.TimestampR\x16authorizationExpiresAt\x12\x1a\n" +
+	"\bprovider\x18\x0e \x01(\tR\bprovider\x12!\n" +
+	"\fapproval_url\x18\x0f \x01(\tR\vapprovalUrl\x12:\n" +
+	"\x11settlement_amount\x18\x10 \x01(\v2\r.common.MoneyR\x10settlementAmount\x12#\n" +
+	"\rexchange_rate\x18\x11 \x01(\x01R\fexchangeRate\x12;\n" +
+	"\x10exchange_rate_at\x18\x12 \x01(\v2\x11.common.TimestampR\x0eexchangeRateAt\x12\x1d\n" +
+	"\n" +
+	"risk_score\x18\x13 \x01(\x05R\triskScore\x12#\n" +
+	"\rrisk_decision\x18\x14 \x01(\tR\friskDecision\x12!\n" +
+	"\frisk_reasons\x18\x15 \x03(\tR\vriskReasons\"\x9d\x02\n" +
 	"\rPaymentMethod\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12.\n" +
@@ -1251,24 +2595,42 @@ const file_payment_proto_rawDesc = "" +
 	"\x11payment_intent_id\x18\x01 \x01(\tR\x0fpaymentIntentId\x12*\n" +
 	"\x11payment_method_id\x18\x02 \x01(\tR\x0fpaymentMethodId\"D\n" +
 	"\x16ConfirmPaymentResponse\x12*\n" +
+	"\apayment\x18\x01 \x01(\v2\x10.payment.PaymentR\apayment\"q\n" +
+	"\x17AuthorizePaymentRequest\x12*\n" +
+	"\x11payment_intent_id\x18\x01 \x01(\tR\x0fpaymentIntentId\x12*\n" +
+	"\x11payment_method_id\x18\x02 \x01(\tR\x0fpaymentMethodId\"F\n" +
+	"\x18AuthorizePaymentResponse\x12*\n" +
+	"\apayment\x18\x01 \x01(\v2\x10.payment.PaymentR\apayment\"]\n" +
+	"\x15CapturePaymentRequest\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12%\n" +
+	"\x06amount\x18\x02 \x01(\v2\r.common.MoneyR\x06amount\"D\n" +
+	"\x16CapturePaymentResponse\x12*\n" +
+	"\apayment\x18\x01 \x01(\v2\x10.payment.PaymentR\apayment\"3\n" +
+	"\x12VoidPaymentRequest\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\"A\n" +
+	"\x13VoidPaymentResponse\x12*\n" +
 	"\apayment\x18\x01 \x01(\v2\x10.payment.PaymentR\apayment\"M\n" +
 	"\x14CancelPaymentRequest\x12\x1d\n" +
 	"\n" +
 	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x16\n" +
 	"\x06reason\x18\x02 \x01(\tR\x06reason\"C\n" +
 	"\x15CancelPaymentResponse\x12*\n" +
-	"\apayment\x18\x01 \x01(\v2\x10.payment.PaymentR\apayment\"t\n" +
+	"\apayment\x18\x01 \x01(\v2\x10.payment.PaymentR\apayment\"\x9d\x01\n" +
 	"\x14RefundPaymentRequest\x12\x1d\n" +
 	"\n" +
 	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12%\n" +
 	"\x06amount\x18\x02 \x01(\v2\r.common.MoneyR\x06amount\x12\x16\n" +
-	"\x06reason\x18\x03 \x01(\tR\x06reason\"`\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12'\n" +
+	"\x0fidempotency_key\x18\x04 \x01(\tR\x0eidempotencyKey\"`\n" +
 	"\x15RefundPaymentResponse\x12*\n" +
 	"\apayment\x18\x01 \x01(\v2\x10.payment.PaymentR\apayment\x12\x1b\n" +
-	"\trefund_id\x18\x02 \x01(\tR\brefundId\"8\n" +
+	"\trefund_id\x18\x02 \x01(\tR\brefundId\"S\n" +
 	"\x17GetPaymentStatusRequest\x12\x1d\n" +
 	"\n" +
-	"payment_id\x18\x01 \x01(\tR\tpaymentId\"F\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\"F\n" +
 	"\x18GetPaymentStatusResponse\x12*\n" +
 	"\apayment\x18\x01 \x01(\v2\x10.payment.PaymentR\apayment\"3\n" +
 	"\x18GetPaymentMethodsRequest\x12\x17\n" +
@@ -1286,7 +2648,84 @@ const file_payment_proto_rawDesc = "" +
 	"\x11payment_method_id\x18\x01 \x01(\tR\x0fpaymentMethodId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\"7\n" +
 	"\x1bRemovePaymentMethodResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess*d\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xe6\x02\n" +
+	"\x19ReconciliationDiscrepancy\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12,\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x18.payment.DiscrepancyTypeR\x04type\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x03 \x01(\tR\tpaymentId\x12.\n" +
+	"\x13provider_payment_id\x18\x04 \x01(\tR\x11providerPaymentId\x126\n" +
+	"\x17provider_transaction_id\x18\x05 \x01(\tR\x15providerTransactionId\x126\n" +
+	"\x0fexpected_amount\x18\x06 \x01(\v2\r.common.MoneyR\x0eexpectedAmount\x122\n" +
+	"\ractual_amount\x18\a \x01(\v2\r.common.MoneyR\factualAmount\x12\x18\n" +
+	"\adetails\x18\b \x01(\tR\adetails\"\xf8\x02\n" +
+	"\x14ReconciliationReport\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x124\n" +
+	"\fperiod_start\x18\x03 \x01(\v2\x11.common.TimestampR\vperiodStart\x120\n" +
+	"\n" +
+	"period_end\x18\x04 \x01(\v2\x11.common.TimestampR\tperiodEnd\x12+\n" +
+	"\x11transaction_count\x18\x05 \x01(\x05R\x10transactionCount\x12#\n" +
+	"\rpayment_count\x18\x06 \x01(\x05R\fpaymentCount\x12H\n" +
+	"\rdiscrepancies\x18\a \x03(\v2\".payment.ReconciliationDiscrepancyR\rdiscrepancies\x120\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x11.common.TimestampR\tcreatedAt\"m\n" +
+	"\x1eGetReconciliationReportRequest\x12\x1b\n" +
+	"\treport_id\x18\x01 \x01(\tR\breportId\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x12\n" +
+	"\x04date\x18\x03 \x01(\tR\x04date\"X\n" +
+	"\x1fGetReconciliationReportResponse\x125\n" +
+	"\x06report\x18\x01 \x01(\v2\x1d.payment.ReconciliationReportR\x06report\"\xc5\x04\n" +
+	"\x0fDisputeEvidence\x12/\n" +
+	"\x13product_description\x18\x01 \x01(\tR\x12productDescription\x12#\n" +
+	"\rcustomer_name\x18\x02 \x01(\tR\fcustomerName\x12%\n" +
+	"\x0ecustomer_email\x18\x03 \x01(\tR\rcustomerEmail\x120\n" +
+	"\x14customer_purchase_ip\x18\x04 \x01(\tR\x12customerPurchaseIp\x12'\n" +
+	"\x0fbilling_address\x18\x05 \x01(\tR\x0ebillingAddress\x12)\n" +
+	"\x10shipping_address\x18\x06 \x01(\tR\x0fshippingAddress\x12)\n" +
+	"\x10shipping_carrier\x18\a \x01(\tR\x0fshippingCarrier\x128\n" +
+	"\x18shipping_tracking_number\x18\b \x01(\tR\x16shippingTrackingNumber\x12#\n" +
+	"\rshipping_date\x18\t \x01(\tR\fshippingDate\x128\n" +
+	"\x18refund_policy_disclosure\x18\n" +
+	" \x01(\tR\x16refundPolicyDisclosure\x12<\n" +
+	"\x1arefund_refusal_explanation\x18\v \x01(\tR\x18refundRefusalExplanation\x12-\n" +
+	"\x12uncategorized_text\x18\f \x01(\tR\x11uncategorizedText\"\x95\x05\n" +
+	"\aDispute\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x02 \x01(\tR\tpaymentId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x1a\n" +
+	"\bprovider\x18\x04 \x01(\tR\bprovider\x12.\n" +
+	"\x13provider_dispute_id\x18\x05 \x01(\tR\x11providerDisputeId\x12.\n" +
+	"\x06status\x18\x06 \x01(\x0e2\x16.payment.DisputeStatusR\x06status\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x12%\n" +
+	"\x06amount\x18\b \x01(\v2\r.common.MoneyR\x06amount\x129\n" +
+	"\x0fevidence_due_by\x18\t \x01(\v2\x11.common.TimestampR\revidenceDueBy\x12E\n" +
+	"\x15evidence_submitted_at\x18\n" +
+	" \x01(\v2\x11.common.TimestampR\x13evidenceSubmittedAt\x124\n" +
+	"\bevidence\x18\v \x01(\v2\x18.payment.DisputeEvidenceR\bevidence\x12;\n" +
+	"\x10order_flagged_at\x18\f \x01(\v2\x11.common.TimestampR\x0eorderFlaggedAt\x12.\n" +
+	"\topened_at\x18\r \x01(\v2\x11.common.TimestampR\bopenedAt\x12.\n" +
+	"\tclosed_at\x18\x0e \x01(\v2\x11.common.TimestampR\bclosedAt\x120\n" +
+	"\n" +
+	"updated_at\x18\x0f \x01(\v2\x11.common.TimestampR\tupdatedAt\"\xdf\x01\n" +
+	"\x13ListDisputesRequest\x12.\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x16.payment.DisputeStatusR\x06status\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x02 \x01(\tR\tpaymentId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x120\n" +
+	"\n" +
+	"due_before\x18\x04 \x01(\v2\x11.common.TimestampR\tdueBefore\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x06 \x01(\x05R\x06offset\"D\n" +
+	"\x14ListDisputesResponse\x12,\n" +
+	"\bdisputes\x18\x01 \x03(\v2\x10.payment.DisputeR\bdisputes\"s\n" +
+	"\x1cSubmitDisputeEvidenceRequest\x12\x1d\n" +
+	"\n" +
+	"dispute_id\x18\x01 \x01(\tR\tdisputeId\x124\n" +
+	"\bevidence\x18\x02 \x01(\v2\x18.payment.DisputeEvidenceR\bevidence\"K\n" +
+	"\x1dSubmitDisputeEvidenceResponse\x12*\n" +
+	"\adispute\x18\x01 \x01(\v2\x10.payment.DisputeR\adispute*\x9a\x01\n" +
 	"\rPaymentStatus\x12\v\n" +
 	"\aPENDING\x10\x00\x12\x0e\n" +
 	"\n" +
@@ -1295,7 +2734,11 @@ const file_payment_proto_rawDesc = "" +
 	"\n" +
 	"\x06FAILED\x10\x03\x12\r\n" +
 	"\tCANCELLED\x10\x04\x12\f\n" +
-	"\bREFUNDED\x10\x05*i\n" +
+	"\bREFUNDED\x10\x05\x12\x16\n" +
+	"\x12PARTIALLY_REFUNDED\x10\x06\x12\x0e\n" +
+	"\n" +
+	"AUTHORIZED\x10\a\x12\f\n" +
+	"\bCAPTURED\x10\b*i\n" +
 	"\x11PaymentMethodType\x12\x0f\n" +
 	"\vCREDIT_CARD\x10\x00\x12\x0e\n" +
 	"\n" +
@@ -1303,16 +2746,34 @@ const file_payment_proto_rawDesc = "" +
 	"\n" +
 	"\x06PAYPAL\x10\x02\x12\x11\n" +
 	"\rBANK_TRANSFER\x10\x03\x12\x14\n" +
-	"\x10CASH_ON_DELIVERY\x10\x042\xd5\x05\n" +
+	"\x10CASH_ON_DELIVERY\x10\x04*\x87\x01\n" +
+	"\x0fDiscrepancyType\x12 \n" +
+	"\x1cDISCREPANCY_TYPE_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fAMOUNT_MISMATCH\x10\x01\x12\x12\n" +
+	"\x0eMISSING_CHARGE\x10\x02\x12\x14\n" +
+	"\x10UNMATCHED_CHARGE\x10\x03\x12\x13\n" +
+	"\x0fORPHANED_REFUND\x10\x04*h\n" +
+	"\rDisputeStatus\x12\x1e\n" +
+	"\x1aDISPUTE_STATUS_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eNEEDS_RESPONSE\x10\x01\x12\x10\n" +
+	"\fUNDER_REVIEW\x10\x02\x12\a\n" +
+	"\x03WON\x10\x03\x12\b\n" +
+	"\x04LOST\x10\x042\xee\t\n" +
 	"\x0ePaymentService\x12`\n" +
 	"\x13CreatePaymentIntent\x12#.payment.CreatePaymentIntentRequest\x1a$.payment.CreatePaymentIntentResponse\x12Q\n" +
-	"\x0eConfirmPayment\x12\x1e.payment.ConfirmPaymentRequest\x1a\x1f.payment.ConfirmPaymentResponse\x12N\n" +
+	"\x0eConfirmPayment\x12\x1e.payment.ConfirmPaymentRequest\x1a\x1f.payment.ConfirmPaymentResponse\x12W\n" +
+	"\x10AuthorizePayment\x12 .payment.AuthorizePaymentRequest\x1a!.payment.AuthorizePaymentResponse\x12Q\n" +
+	"\x0eCapturePayment\x12\x1e.payment.CapturePaymentRequest\x1a\x1f.payment.CapturePaymentResponse\x12H\n" +
+	"\vVoidPayment\x12\x1b.payment.VoidPaymentRequest\x1a\x1c.payment.VoidPaymentResponse\x12N\n" +
 	"\rCancelPayment\x12\x1d.payment.CancelPaymentRequest\x1a\x1e.payment.CancelPaymentResponse\x12N\n" +
 	"\rRefundPayment\x12\x1d.payment.RefundPaymentRequest\x1a\x1e.payment.RefundPaymentResponse\x12W\n" +
 	"\x10GetPaymentStatus\x12 .payment.GetPaymentStatusRequest\x1a!.payment.GetPaymentStatusResponse\x12Z\n" +
 	"\x11GetPaymentMethods\x12!.payment.GetPaymentMethodsRequest\x1a\".payment.GetPaymentMethodsResponse\x12W\n" +
 	"\x10AddPaymentMethod\x12 .payment.AddPaymentMethodRequest\x1a!.payment.AddPaymentMethodResponse\x12`\n" +
-	"\x13RemovePaymentMethod\x12#.payment.RemovePaymentMethodRequest\x1a$.payment.RemovePaymentMethodResponseB/Z-github.com/cqchien/ecomerce-rec/backend/protob\x06proto3"
+	"\x13RemovePaymentMethod\x12#.payment.RemovePaymentMethodRequest\x1a$.payment.RemovePaymentMethodResponse\x12l\n" +
+	"\x17GetReconciliationReport\x12'.payment.GetReconciliationReportRequest\x1a(.payment.GetReconciliationReportResponse\x12K\n" +
+	"\fListDisputes\x12\x1c.payment.ListDisputesRequest\x1a\x1d.payment.ListDisputesResponse\x12f\n" +
+	"\x15SubmitDisputeEvidence\x12%.payment.SubmitDisputeEvidenceRequest\x1a&.payment.SubmitDisputeEvidenceResponseB/Z-github.com/cqchien/ecomerce-rec/backend/protob\x06proto3"
 
 var (
 	file_payment_proto_rawDescOnce sync.Once
@@ -1326,74 +2787,135 @@ func file_payment_proto_rawDescGZIP() []byte {
 	return file_payment_proto_rawDescData
 }
 
-var file_payment_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_payment_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
 var file_payment_proto_goTypes = []any{
-	(PaymentStatus)(0),                  // 0: payment.PaymentStatus
-	(PaymentMethodType)(0),              // 1: payment.PaymentMethodType
-	(*Payment)(nil),                     // 2: payment.Payment
-	(*PaymentMethod)(nil),               // 3: payment.PaymentMethod
-	(*CreatePaymentIntentRequest)(nil),  // 4: payment.CreatePaymentIntentRequest
-	(*CreatePaymentIntentResponse)(nil), // 5: payment.CreatePaymentIntentResponse
-	(*ConfirmPaymentRequest)(nil),       // 6: payment.ConfirmPaymentRequest
-	(*ConfirmPaymentResponse)(nil),      // 7: payment.ConfirmPaymentResponse
-	(*CancelPaymentRequest)(nil),        // 8: payment.CancelPaymentRequest
-	(*CancelPaymentResponse)(nil),       // 9: payment.CancelPaymentResponse
-	(*RefundPaymentRequest)(nil),        // 10: payment.RefundPaymentRequest
-	(*RefundPaymentResponse)(nil),       // 11: payment.RefundPaymentResponse
-	(*GetPaymentStatusRequest)(nil),     // 12: payment.GetPaymentStatusRequest
-	(*GetPaymentStatusResponse)(nil),    // 13: payment.GetPaymentStatusResponse
-	(*GetPaymentMethodsRequest)(nil),    // 14: payment.GetPaymentMethodsRequest
-	(*GetPaymentMethodsResponse)(nil),   // 15: payment.GetPaymentMethodsResponse
-	(*AddPaymentMethodRequest)(nil),     // 16: payment.AddPaymentMethodRequest
-	(*AddPaymentMethodResponse)(nil),    // 17: payment.AddPaymentMethodResponse
-	(*RemovePaymentMethodRequest)(nil),  // 18: payment.RemovePaymentMethodRequest
-	(*RemovePaymentMethodResponse)(nil), // 19: payment.RemovePaymentMethodResponse
-	nil,                                 // 20: payment.CreatePaymentIntentRequest.MetadataEntry
-	(*Money)(nil),                       // 21: common.Money
-	(*Timestamp)(nil),                   // 22: common.Timestamp
+	(PaymentStatus)(0),                      // 0: payment.PaymentStatus
+	(PaymentMethodType)(0),                  // 1: payment.PaymentMethodType
+	(DiscrepancyType)(0),                    // 2: payment.DiscrepancyType
+	(DisputeStatus)(0),                      // 3: payment.DisputeStatus
+	(*Payment)(nil),                         // 4: payment.Payment
+	(*PaymentMethod)(nil),                   // 5: payment.PaymentMethod
+	(*CreatePaymentIntentRequest)(nil),      // 6: payment.CreatePaymentIntentRequest
+	(*CreatePaymentIntentResponse)(nil),     // 7: payment.CreatePaymentIntentResponse
+	(*ConfirmPaymentRequest)(nil),           // 8: payment.ConfirmPaymentRequest
+	(*ConfirmPaymentResponse)(nil),          // 9: payment.ConfirmPaymentResponse
+	(*AuthorizePaymentRequest)(nil),         // 10: payment.AuthorizePaymentRequest
+	(*AuthorizePaymentResponse)(nil),        // 11: payment.AuthorizePaymentResponse
+	(*CapturePaymentRequest)(nil),           // 12: payment.CapturePaymentRequest
+	(*CapturePaymentResponse)(nil),          // 13: payment.CapturePaymentResponse
+	(*VoidPaymentRequest)(nil),              // 14: payment.VoidPaymentRequest
+	(*VoidPaymentResponse)(nil),             // 15: payment.VoidPaymentResponse
+	(*CancelPaymentRequest)(nil),            // 16: payment.CancelPaymentRequest
+	(*CancelPaymentResponse)(nil),           // 17: payment.CancelPaymentResponse
+	(*RefundPaymentRequest)(nil),            // 18: payment.RefundPaymentRequest
+	(*RefundPaymentResponse)(nil),           // 19: payment.RefundPaymentResponse
+	(*GetPaymentStatusRequest)(nil),         // 20: payment.GetPaymentStatusRequest
+	(*GetPaymentStatusResponse)(nil),        // 21: payment.GetPaymentStatusResponse
+	(*GetPaymentMethodsRequest)(nil),        // 22: payment.GetPaymentMethodsRequest
+	(*GetPaymentMethodsResponse)(nil),       // 23: payment.GetPaymentMethodsResponse
+	(*AddPaymentMethodRequest)(nil),         // 24: payment.AddPaymentMethodRequest
+	(*AddPaymentMethodResponse)(nil),        // 25: payment.AddPaymentMethodResponse
+	(*RemovePaymentMethodRequest)(nil),      // 26: payment.RemovePaymentMethodRequest
+	(*RemovePaymentMethodResponse)(nil),     // 27: payment.RemovePaymentMethodResponse
+	(*ReconciliationDiscrepancy)(nil),       // 28: payment.ReconciliationDiscrepancy
+	(*ReconciliationReport)(nil),            // 29: payment.ReconciliationReport
+	(*GetReconciliationReportRequest)(nil),  // 30: payment.GetReconciliationReportRequest
+	(*GetReconciliationReportResponse)(nil), // 31: payment.GetReconciliationReportResponse
+	(*DisputeEvidence)(nil),                 // 32: payment.DisputeEvidence
+	(*Dispute)(nil),                         // 33: payment.Dispute
+	(*ListDisputesRequest)(nil),             // 34: payment.ListDisputesRequest
+	(*ListDisputesResponse)(nil),            // 35: payment.ListDisputesResponse
+	(*SubmitDisputeEvidenceRequest)(nil),    // 36: payment.SubmitDisputeEvidenceRequest
+	(*SubmitDisputeEvidenceResponse)(nil),   // 37: payment.SubmitDisputeEvidenceResponse
+	nil,                                     // 38: payment.CreatePaymentIntentRequest.MetadataEntry
+	(*Money)(nil),                           // 39: common.Money
+	(*Timestamp)(nil),                       // 40: common.Timestamp
 }
 var file_payment_proto_depIdxs = []int32{
-	21, // 0: payment.Payment.amount:type_name -> common.Money
+	39, // 0: payment.Payment.amount:type_name -> common.Money
 	0,  // 1: payment.Payment.status:type_name -> payment.PaymentStatus
 	1,  // 2: payment.Payment.method:type_name -> payment.PaymentMethodType
-	22, // 3: payment.Payment.created_at:type_name -> common.Timestamp
-	22, // 4: payment.Payment.updated_at:type_name -> common.Timestamp
-	1,  // 5: payment.PaymentMethod.type:type_name -> payment.PaymentMethodType
-	22, // 6: payment.PaymentMethod.created_at:type_name -> common.Timestamp
-	21, // 7: payment.CreatePaymentIntentRequest.amount:type_name -> common.Money
-	1,  // 8: payment.CreatePaymentIntentRequest.method:type_name -> payment.PaymentMethodType
-	20, // 9: payment.CreatePaymentIntentRequest.metadata:type_name -> payment.CreatePaymentIntentRequest.MetadataEntry
-	0,  // 10: payment.CreatePaymentIntentResponse.status:type_name -> payment.PaymentStatus
-	2,  // 11: payment.ConfirmPaymentResponse.payment:type_name -> payment.Payment
-	2,  // 12: payment.CancelPaymentResponse.payment:type_name -> payment.Payment
-	21, // 13: payment.RefundPaymentRequest.amount:type_name -> common.Money
-	2,  // 14: payment.RefundPaymentResponse.payment:type_name -> payment.Payment
-	2,  // 15: payment.GetPaymentStatusResponse.payment:type_name -> payment.Payment
-	3,  // 16: payment.GetPaymentMethodsResponse.payment_methods:type_name -> payment.PaymentMethod
-	1,  // 17: payment.AddPaymentMethodRequest.type:type_name -> payment.PaymentMethodType
-	3,  // 18: payment.AddPaymentMethodResponse.payment_method:type_name -> payment.PaymentMethod
-	4,  // 19: payment.PaymentService.CreatePaymentIntent:input_type -> payment.CreatePaymentIntentRequest
-	6,  // 20: payment.PaymentService.ConfirmPayment:input_type -> payment.ConfirmPaymentRequest
-	8,  // 21: payment.PaymentService.CancelPayment:input_type -> payment.CancelPaymentRequest
-	10, // 22: payment.PaymentService.RefundPayment:input_type -> payment.RefundPaymentRequest
-	12, // 23: payment.PaymentService.GetPaymentStatus:input_type -> payment.GetPaymentStatusRequest
-	14, // 24: payment.PaymentService.GetPaymentMethods:input_type -> payment.GetPaymentMethodsRequest
-	16, // 25: payment.PaymentService.AddPaymentMethod:input_type -> payment.AddPaymentMethodRequest
-	18, // 26: payment.PaymentService.RemovePaymentMethod:input_type -> payment.RemovePaymentMethodRequest
-	5,  // 27: payment.PaymentService.CreatePaymentIntent:output_type -> payment.CreatePaymentIntentResponse
-	7,  // 28: payment.PaymentService.ConfirmPayment:output_type -> payment.ConfirmPaymentResponse
-	9,  // 29: payment.PaymentService.CancelPayment:output_type -> payment.CancelPaymentResponse
-	11, // 30: payment.PaymentService.RefundPayment:output_type -> payment.RefundPaymentResponse
-	13, // 31: payment.PaymentService.GetPaymentStatus:output_type -> payment.GetPaymentStatusResponse
-	15, // 32: payment.PaymentService.GetPaymentMethods:output_type -> payment.GetPaymentMethodsResponse
-	17, // 33: payment.PaymentService.AddPaymentMethod:output_type -> payment.AddPaymentMethodResponse
-	19, // 34: payment.PaymentService.RemovePaymentMethod:output_type -> payment.RemovePaymentMethodResponse
-	27, // [27:35] is the sub-list for method output_type
-	19, // [19:27] is the sub-list for method input_type
-	19, // [19:19] is the sub-list for extension type_name
-	19, // [19:19] is the sub-list for extension extendee
-	0,  // [0:19] is the sub-list for field type_name
+	40, // 3: payment.Payment.created_at:type_name -> common.Timestamp
+	40, // 4: payment.Payment.updated_at:type_name -> common.Timestamp
+	39, // 5: payment.Payment.refunded_amount:type_name -> common.Money
+	39, // 6: payment.Payment.captured_amount:type_name -> common.Money
+	40, // 7: payment.Payment.authorization_expires_at:type_name -> common.Timestamp
+	39, // 8: payment.Payment.settlement_amount:type_name -> common.Money
+	40, // 9: payment.Payment.exchange_rate_at:type_name -> common.Timestamp
+	1,  // 10: payment.PaymentMethod.type:type_name -> payment.PaymentMethodType
+	40, // 11: payment.PaymentMethod.created_at:type_name -> common.Timestamp
+	39, // 12: payment.CreatePaymentIntentRequest.amount:type_name -> common.Money
+	1,  // 13: payment.CreatePaymentIntentRequest.method:type_name -> payment.PaymentMethodType
+	38, // 14: payment.CreatePaymentIntentRequest.metadata:type_name -> payment.CreatePaymentIntentRequest.MetadataEntry
+	0,  // 15: payment.CreatePaymentIntentResponse.status:type_name -> payment.PaymentStatus
+	4,  // 16: payment.ConfirmPaymentResponse.payment:type_name -> payment.Payment
+	4,  // 17: payment.AuthorizePaymentResponse.payment:type_name -> payment.Payment
+	39, // 18: payment.CapturePaymentRequest.amount:type_name -> common.Money
+	4,  // 19: payment.CapturePaymentResponse.payment:type_name -> payment.Payment
+	4,  // 20: payment.VoidPaymentResponse.payment:type_name -> payment.Payment
+	4,  // 21: payment.CancelPaymentResponse.payment:type_name -> payment.Payment
+	39, // 22: payment.RefundPaymentRequest.amount:type_name -> common.Money
+	4,  // 23: payment.RefundPaymentResponse.payment:type_name -> payment.Payment
+	4,  // 24: payment.GetPaymentStatusResponse.payment:type_name -> payment.Payment
+	5,  // 25: payment.GetPaymentMethodsResponse.payment_methods:type_name -> payment.PaymentMethod
+	1,  // 26: payment.AddPaymentMethodRequest.type:type_name -> payment.PaymentMethodType
+	5,  // 27: payment.AddPaymentMethodResponse.payment_method:type_name -> payment.PaymentMethod
+	2,  // 28: payment.ReconciliationDiscrepancy.type:type_name -> payment.DiscrepancyType
+	39, // 29: payment.ReconciliationDiscrepancy.expected_amount:type_name -> common.Money
+	39, // 30: payment.ReconciliationDiscrepancy.actual_amount:type_name -> common.Money
+	40, // 31: payment.ReconciliationReport.period_start:type_name -> common.Timestamp
+	40, // 32: payment.ReconciliationReport.period_end:type_name -> common.Timestamp
+	28, // 33: payment.ReconciliationReport.discrepancies:type_name -> payment.ReconciliationDiscrepancy
+	40, // 34: payment.ReconciliationReport.created_at:type_name -> common.Timestamp
+	29, // 35: payment.GetReconciliationReportResponse.report:type_name -> payment.ReconciliationReport
+	3,  // 36: payment.Dispute.status:type_name -> payment.DisputeStatus
+	39, // 37: payment.Dispute.amount:type_name -> common.Money
+	40, // 38: payment.Dispute.evidence_due_by:type_name -> common.Timestamp
+	40, // 39: payment.Dispute.evidence_submitted_at:type_name -> common.Timestamp
+	32, // 40: payment.Dispute.evidence:type_name -> payment.DisputeEvidence
+	40, // 41: payment.Dispute.order_flagged_at:type_name -> common.Timestamp
+	40, // 42: payment.Dispute.opened_at:type_name -> common.Timestamp
+	40, // 43: payment.Dispute.closed_at:type_name -> common.Timestamp
+	40, // 44: payment.Dispute.updated_at:type_name -> common.Timestamp
+	3,  // 45: payment.ListDisputesRequest.status:type_name -> payment.DisputeStatus
+	40, // 46: payment.ListDisputesRequest.due_before:type_name -> common.Timestamp
+	33, // 47: payment.ListDisputesResponse.disputes:type_name -> payment.Dispute
+	32, // 48: payment.SubmitDisputeEvidenceRequest.evidence:type_name -> payment.DisputeEvidence
+	33, // 49: payment.SubmitDisputeEvidenceResponse.dispute:type_name -> payment.Dispute
+	6,  // 50: payment.PaymentService.CreatePaymentIntent:input_type -> payment.CreatePaymentIntentRequest
+	8,  // 51: payment.PaymentService.ConfirmPayment:input_type -> payment.ConfirmPaymentRequest
+	10, // 52: payment.PaymentService.AuthorizePayment:input_type -> payment.AuthorizePaymentRequest
+	12, // 53: payment.PaymentService.CapturePayment:input_type -> payment.CapturePaymentRequest
+	14, // 54: payment.PaymentService.VoidPayment:input_type -> payment.VoidPaymentRequest
+	16, // 55: payment.PaymentService.CancelPayment:input_type -> payment.CancelPaymentRequest
+	18, // 56: payment.PaymentService.RefundPayment:input_type -> payment.RefundPaymentRequest
+	20, // 57: payment.PaymentService.GetPaymentStatus:input_type -> payment.GetPaymentStatusRequest
+	22, // 58: payment.PaymentService.GetPaymentMethods:input_type -> payment.GetPaymentMethodsRequest
+	24, // 59: payment.PaymentService.AddPaymentMethod:input_type -> payment.AddPaymentMethodRequest
+	26, // 60: payment.PaymentService.RemovePaymentMethod:input_type -> payment.RemovePaymentMethodRequest
+	30, // 61: payment.PaymentService.GetReconciliationReport:input_type -> payment.GetReconciliationReportRequest
+	34, // 62: payment.PaymentService.ListDisputes:input_type -> payment.ListDisputesRequest
+	36, // 63: payment.PaymentService.SubmitDisputeEvidence:input_type -> payment.SubmitDisputeEvidenceRequest
+	7,  // 64: payment.PaymentService.CreatePaymentIntent:output_type -> payment.CreatePaymentIntentResponse
+	9,  // 65: payment.PaymentService.ConfirmPayment:output_type -> payment.ConfirmPaymentResponse
+	11, // 66: payment.PaymentService.AuthorizePayment:output_type -> payment.AuthorizePaymentResponse
+	13, // 67: payment.PaymentService.CapturePayment:output_type -> payment.CapturePaymentResponse
+	15, // 68: payment.PaymentService.VoidPayment:output_type -> payment.VoidPaymentResponse
+	17, // 69: payment.PaymentService.CancelPayment:output_type -> payment.CancelPaymentResponse
+	19, // 70: payment.PaymentService.RefundPayment:output_type -> payment.RefundPaymentResponse
+	21, // 71: payment.PaymentService.GetPaymentStatus:output_type -> payment.GetPaymentStatusResponse
+	23, // 72: payment.PaymentService.GetPaymentMethods:output_type -> payment.GetPaymentMethodsResponse
+	25, // 73: payment.PaymentService.AddPaymentMethod:output_type -> payment.AddPaymentMethodResponse
+	27, // 74: payment.PaymentService.RemovePaymentMethod:output_type -> payment.RemovePaymentMethodResponse
+	31, // 75: payment.PaymentService.GetReconciliationReport:output_type -> payment.GetReconciliationReportResponse
+	35, // 76: payment.PaymentService.ListDisputes:output_type -> payment.ListDisputesResponse
+	37, // 77: payment.PaymentService.SubmitDisputeEvidence:output_type -> payment.SubmitDisputeEvidenceResponse
+	64, // [64:78] is the sub-list for method output_type
+	50, // [50:64] is the sub-list for method input_type
+	50, // [50:50] is the sub-list for extension type_name
+	50, // [50:50] is the sub-list for extension extendee
+	0,  // [0:50] is the sub-list for field type_name
 }
 
 func init() { file_payment_proto_init() }
@@ -1407,8 +2929,8 @@ func file_payment_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_payment_proto_rawDesc), len(file_payment_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   19,
+			NumEnums:      4,
+			NumMessages:   35,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -18,6 +18,8 @@ const (
 	EventTypeOrderCancelled   EventType = "ORDER_CANCELLED"
 	EventTypePaymentCompleted EventType = "PAYMENT_COMPLETED"
 	EventTypePaymentFailed    EventType = "PAYMENT_FAILED"
+	EventTypePaymentCancelled EventType = "PAYMENT_CANCELLED"
+	EventTypePaymentDisputed  EventType = "PAYMENT_DISPUTED"
 	EventTypeInventoryUpdated EventType = "INVENTORY_UPDATED"
 	EventTypeCartUpdated      EventType = "CART_UPDATED"
 )
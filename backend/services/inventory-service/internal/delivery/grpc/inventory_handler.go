@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"errors"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -101,6 +102,7 @@ func (s *inventoryServer) ReserveStock(ctx context.Context, req *pb.ReserveStock
 		protoResults := make([]*pb.ReservationResult, len(results))
 		for i, result := range results {
 			protoResults[i] = &pb.ReservationResult{
+				ReservationId:     result.ReservationID,
 				ProductId:         result.ProductID,
 				VariantId:         result.VariantID,
 				Reserved:          result.Reserved,
@@ -120,6 +122,7 @@ func (s *inventoryServer) ReserveStock(ctx context.Context, req *pb.ReserveStock
 	protoResults := make([]*pb.ReservationResult, len(results))
 	for i, result := range results {
 		protoResults[i] = &pb.ReservationResult{
+			ReservationId:     result.ReservationID,
 			ProductId:         result.ProductID,
 			VariantId:         result.VariantID,
 			Reserved:          result.Reserved,
@@ -143,9 +146,14 @@ func (s *inventoryServer) ReleaseReservation(ctx context.Context, req *pb.Releas
 		return nil, status.Error(codes.InvalidArgument, "reservation_id or order_id is required")
 	}
 
+	// Nothing pending is reported as unsuccessful; any other failure is an
+	// error, so the caller knows to retry
 	if err := s.inventoryUC.ReleaseReservation(ctx, req.ReservationId, req.OrderId); err != nil {
+		if errors.Is(err, domain.ErrNoPendingReservation) {
+			return &pb.ReleaseReservationResponse{Success: false}, nil
+		}
 		s.logger.Error("Failed to release reservation", "error", err)
-		return &pb.ReleaseReservationResponse{Success: false}, nil
+		return nil, status.Error(codes.Internal, "failed to release reservation")
 	}
 
 	return &pb.ReleaseReservationResponse{Success: true}, nil
@@ -159,9 +167,14 @@ func (s *inventoryServer) CommitReservation(ctx context.Context, req *pb.CommitR
 		return nil, status.Error(codes.InvalidArgument, "reservation_id or order_id is required")
 	}
 
+	// Nothing pending is reported as unsuccessful; any other failure is an
+	// error, so the caller knows to retry
 	if err := s.inventoryUC.CommitReservation(ctx, req.ReservationId, req.OrderId); err != nil {
+		if errors.Is(err, domain.ErrNoPendingReservation) {
+			return &pb.CommitReservationResponse{Success: false}, nil
+		}
 		s.logger.Error("Failed to commit reservation", "error", err)
-		return &pb.CommitReservationResponse{Success: false}, nil
+		return nil, status.Error(codes.Internal, "failed to commit reservation")
 	}
 
 	return &pb.CommitReservationResponse{Success: true}, nil
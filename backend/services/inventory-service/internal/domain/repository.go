@@ -1,6 +1,14 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ErrNoPendingReservation is returned when a reservation is released or
+// committed but nothing is pending for it, because it was already released,
+// committed or expired
+var ErrNoPendingReservation = errors.New("no pending reservation")
 
 // Stock represents inventory stock in the domain layer
 type Stock struct {
@@ -35,6 +43,7 @@ type ReservationItem struct {
 
 // ReservationResult represents the result of a reservation attempt
 type ReservationResult struct {
+	ReservationID     string // ID of the reservation line, set when reserved
 	ProductID         string
 	VariantID         string
 	Reserved          bool
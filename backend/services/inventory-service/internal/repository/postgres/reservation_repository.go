@@ -189,6 +189,7 @@ func (r *reservationRepository) ReserveStock(orderID string, items []domain.Rese
 		}
 
 		results = append(results, domain.ReservationResult{
+			ReservationID:     reservation.ID,
 			ProductID:         item.ProductID,
 			VariantID:         item.VariantID,
 			Reserved:          true,
@@ -229,7 +230,7 @@ func (r *reservationRepository) ReleaseReservation(reservationID string) error {
 
 	if len(reservations) == 0 {
 		tx.Rollback()
-		return fmt.Errorf("no pending reservations found for: %s", reservationID)
+		return fmt.Errorf("%w found for: %s", domain.ErrNoPendingReservation, reservationID)
 	}
 
 	// Release stock for each reservation
@@ -300,7 +301,7 @@ func (r *reservationRepository) CommitReservation(reservationID string) error {
 
 	if len(reservations) == 0 {
 		tx.Rollback()
-		return fmt.Errorf("no pending reservations found for: %s", reservationID)
+		return fmt.Errorf("%w found for: %s", domain.ErrNoPendingReservation, reservationID)
 	}
 
 	// Commit each reservation
@@ -100,11 +100,16 @@ func (uc *InventoryUseCase) ReleaseReservation(ctx context.Context, reservationI
 		identifier = orderID
 	}
 
-	// Get reservations to invalidate cache
+	// Get reservations to invalidate cache; a reservation ID identifies a single line
 	reservations, err := uc.reservationRepo.GetByOrderID(identifier)
 	if err != nil {
 		return fmt.Errorf("failed to get reservations: %w", err)
 	}
+	if len(reservations) == 0 && reservationID != "" {
+		if reservation, err := uc.reservationRepo.GetByID(reservationID); err == nil {
+			reservations = append(reservations, *reservation)
+		}
+	}
 
 	// Release reservation
 	if err := uc.reservationRepo.ReleaseReservation(identifier); err != nil {
@@ -1,16 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	pb "github.com/cqchien/ecomerce-rec/backend/proto"
 	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/delivery/grpc"
 	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/delivery/http"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
 	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/infrastructure/database"
+	grpcClient "github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/infrastructure/grpc"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/infrastructure/invoice"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/infrastructure/kafka"
 	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/infrastructure/redis"
 	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/repository/postgres"
 	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/usecase"
@@ -38,11 +44,59 @@ func main() {
 		redisClient = nil
 	}
 
-	// Initialize repository
+	// Initialize downstream service clients
+	serviceClients, err := grpcClient.NewServiceClients(cfg.ProductServiceAddr, cfg.InventoryServiceAddr, cfg.PaymentServiceAddr, cfg.UserServiceAddr)
+	if err != nil {
+		logger.Fatalf("Failed to create service clients: %v", err)
+	}
+
+	// Initialize repositories
 	orderRepo := postgres.NewOrderRepository(db, redisClient)
+	sagaRepo := postgres.NewSagaRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	idempotencyRepo := postgres.NewIdempotencyRepository(db)
+	couponRepo := postgres.NewCouponRepository(db)
+	returnRepo := postgres.NewReturnRepository(db)
+	shipmentRepo := postgres.NewShipmentRepository(db)
+	invoiceRepo := postgres.NewInvoiceRepository(db)
+
+	// Initialize Kafka publisher
+	kafkaPublisher := kafka.NewPublisher(strings.Split(cfg.KafkaBrokers, ","), cfg.KafkaTopic)
+	defer kafkaPublisher.Close()
+	logger.Infof("Kafka publisher initialized for topic %s", cfg.KafkaTopic)
 
 	// Initialize use case
-	orderUseCase := usecase.NewOrderUseCase(orderRepo)
+	pricing := domain.PricingPolicy{
+		Currency:              cfg.Currency,
+		ShippingFee:           domain.ToCents(cfg.ShippingFee),
+		FreeShippingThreshold: domain.ToCents(cfg.FreeShippingThreshold),
+		TaxRate:               cfg.TaxRate,
+	}
+	orderUseCase := usecase.NewOrderUseCase(orderRepo, sagaRepo, idempotencyRepo, couponRepo, returnRepo, shipmentRepo, serviceClients, serviceClients, serviceClients, serviceClients, pricing, cfg.PaymentWindow)
+	seller := domain.Seller{
+		Name:    cfg.InvoiceSellerName,
+		Address: cfg.InvoiceSellerAddress,
+		TaxID:   cfg.InvoiceSellerTaxID,
+	}
+	invoiceService := usecase.NewInvoiceService(orderRepo, invoiceRepo, invoice.NewRenderer(), seller)
+
+	// Start background job for recovering interrupted checkouts
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	orderUseCase.StartSagaRecoveryJob(ctx)
+
+	// Start background job cancelling orders left unpaid past the payment window
+	orderUseCase.StartUnpaidOrderExpiryJob(ctx)
+
+	// Start background job retrying refunds of cancelled and returned items
+	orderUseCase.StartPendingRefundJob(ctx)
+
+	// Start background job committing the stock of confirmed orders
+	orderUseCase.StartStockCommitJob(ctx)
+
+	// Start relay publishing order events from the outbox
+	outboxRelay := usecase.NewOutboxRelay(outboxRepo, kafkaPublisher)
+	outboxRelay.Start(ctx)
 
 	// Start HTTP server
 	httpServer := http.NewServer(cfg.HTTPPort)
@@ -55,7 +109,7 @@ func main() {
 
 	// Start gRPC server
 	grpcServer := grpcLib.NewServer()
-	orderHandler := grpc.NewOrderHandler(orderUseCase)
+	orderHandler := grpc.NewOrderHandler(orderUseCase, invoiceService)
 	pb.RegisterOrderServiceServer(grpcServer, orderHandler)
 
 	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GRPCPort))
@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/cqchien/ecomerce-rec/backend/proto"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// InvoiceService defines the interface for invoice business logic
+type InvoiceService interface {
+	GetInvoice(ctx context.Context, orderID, userID string) (*domain.Invoice, error)
+}
+
+// GetInvoice returns the invoice of a delivered order in the requested format
+func (h *OrderHandler) GetInvoice(ctx context.Context, req *pb.GetInvoiceRequest) (*pb.GetInvoiceResponse, error) {
+	logger.Infof("GetInvoice request for order: %s", req.OrderId)
+
+	if req.OrderId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "order_id is required")
+	}
+
+	invoice, err := h.invoiceService.GetInvoice(ctx, req.OrderId, req.UserId)
+	if err != nil {
+		logger.Errorf("Failed to get invoice: %v", err)
+		return nil, invoiceError("failed to get invoice", err)
+	}
+
+	format := domain.InvoiceFormatPDF
+	if req.Format == pb.InvoiceFormat_INVOICE_FORMAT_JSON {
+		format = domain.InvoiceFormatJSON
+	}
+	content, contentType, fileName, err := invoice.Content(format)
+	if err != nil {
+		return nil, invoiceError("failed to get invoice", err)
+	}
+
+	return &pb.GetInvoiceResponse{
+		Invoice:     domainInvoiceToProto(invoice),
+		Content:     content,
+		ContentType: contentType,
+		FileName:    fileName,
+	}, nil
+}
+
+// invoiceError maps an invoice error to a gRPC status
+func invoiceError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvoiceNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrInvoiceNotAllowed):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
+// domainInvoiceToProto converts domain Invoice to proto Invoice
+func domainInvoiceToProto(i *domain.Invoice) *pb.Invoice {
+	return &pb.Invoice{
+		Id:             i.ID,
+		OrderId:        i.OrderID,
+		UserId:         i.UserID,
+		Number:         i.Number,
+		Subtotal:       centsToMoney(i.Subtotal, i.Currency),
+		DiscountAmount: centsToMoney(i.DiscountAmount, i.Currency),
+		ShippingAmount: centsToMoney(i.ShippingAmount, i.Currency),
+		TaxAmount:      centsToMoney(i.TaxAmount, i.Currency),
+		TotalAmount:    centsToMoney(i.TotalAmount, i.Currency),
+		IssuedAt:       timestamppb.New(i.IssuedAt),
+	}
+}
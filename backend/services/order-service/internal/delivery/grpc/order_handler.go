@@ -2,6 +2,9 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	pb "github.com/cqchien/ecomerce-rec/backend/proto"
 	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
@@ -13,25 +16,41 @@ import (
 
 // OrderUseCase defines the interface for order business logic
 type OrderUseCase interface {
-	CreateOrder(ctx context.Context, userID, shippingAddress, billingAddress, paymentMethod string, items []domain.OrderItem) (*domain.Order, error)
+	CreateOrder(ctx context.Context, idempotencyKey, userID, shippingAddressID, billingAddressID, paymentMethod, couponCode string, items []domain.OrderItem) (*domain.Order, error)
 	GetOrder(ctx context.Context, orderID string) (*domain.Order, error)
-	UpdateOrderStatus(ctx context.Context, orderID string, newStatus domain.OrderStatus) error
-	CancelOrder(ctx context.Context, orderID string) error
+	UpdateOrderStatus(ctx context.Context, orderID string, newStatus domain.OrderStatus, changedBy, reason string) error
+	CancelOrder(ctx context.Context, orderID, cancelledBy, reason string) error
 	GetUserOrders(ctx context.Context, userID string, limit, offset int) ([]*domain.Order, error)
 	UpdateTrackingNumber(ctx context.Context, orderID, trackingNumber string) error
 	GetOrdersByStatus(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.Order, error)
+	GetOrderStatusHistory(ctx context.Context, orderID string) ([]domain.StatusChange, error)
+	CancelOrderItem(ctx context.Context, orderID, itemID, cancelledBy, reason string) (*domain.Order, int64, error)
+	RequestReturn(ctx context.Context, orderID, userID string, itemIDs []string, reason string) (*domain.OrderReturn, error)
+	ApproveReturn(ctx context.Context, returnID, approvedBy string) (*domain.OrderReturn, error)
+	RejectReturn(ctx context.Context, returnID, rejectedBy, reason string) (*domain.OrderReturn, error)
+	ReceiveReturn(ctx context.Context, returnID, receivedBy string) (*domain.OrderReturn, error)
+	RefundReturn(ctx context.Context, returnID, refundedBy string) (*domain.OrderReturn, error)
+	GetReturn(ctx context.Context, returnID string) (*domain.OrderReturn, error)
+	GetOrderReturns(ctx context.Context, orderID string) ([]*domain.OrderReturn, error)
+	CreateShipment(ctx context.Context, orderID, carrier, trackingNumber string, items []domain.ShipmentItem, createdBy string) (*domain.Shipment, error)
+	IngestTrackingEvent(ctx context.Context, event domain.TrackingEvent) (*domain.Shipment, *domain.Order, bool, error)
+	GetOrderShipments(ctx context.Context, orderID string) ([]*domain.Shipment, error)
+	SearchOrders(ctx context.Context, filter domain.OrderFilter, cursor string, limit int) ([]*domain.Order, string, error)
+	FlagOrderDisputed(ctx context.Context, orderID, paymentID, disputeID, reason string) (*domain.Order, bool, error)
 }
 
 // OrderHandler implements the gRPC OrderService
 type OrderHandler struct {
 	pb.UnimplementedOrderServiceServer
-	orderUseCase OrderUseCase
+	orderUseCase   OrderUseCase
+	invoiceService InvoiceService
 }
 
 // NewOrderHandler creates a new order handler
-func NewOrderHandler(orderUseCase OrderUseCase) *OrderHandler {
+func NewOrderHandler(orderUseCase OrderUseCase, invoiceService InvoiceService) *OrderHandler {
 	return &OrderHandler{
-		orderUseCase: orderUseCase,
+		orderUseCase:   orderUseCase,
+		invoiceService: invoiceService,
 	}
 }
 
@@ -44,37 +63,44 @@ func (h *OrderHandler) CreateOrder(ctx context.Context, req *pb.CreateOrderReque
 	for i, item := range req.Items {
 		items[i] = domain.OrderItem{
 			ProductID: item.ProductId,
+			VariantID: item.VariantId,
 			Quantity:  item.Quantity,
 		}
 	}
 
-	// For now, use address IDs as placeholders (would normally lookup from user service)
-	shippingAddr := req.ShippingAddressId
-	if shippingAddr == "" {
-		shippingAddr = "default_shipping"
-	}
-	billingAddr := req.BillingAddressId
-	if billingAddr == "" {
-		billingAddr = shippingAddr // Use shipping as billing if not provided
-	}
-
-	// Create order
+	// Addresses are snapshotted from user-service; an empty shipping address ID
+	// selects the user's default address and billing falls back to shipping
 	createdOrder, err := h.orderUseCase.CreateOrder(
 		ctx,
+		req.IdempotencyKey,
 		req.UserId,
-		shippingAddr,
-		billingAddr,
+		req.ShippingAddressId,
+		req.BillingAddressId,
 		req.PaymentMethod,
+		req.CouponCode,
 		items,
 	)
 	if err != nil {
 		logger.Errorf("Failed to create order: %v", err)
-		return nil, status.Errorf(codes.Internal, "failed to create order: %v", err)
+		switch {
+		case errors.Is(err, domain.ErrProductNotFound), errors.Is(err, domain.ErrVariantNotFound), errors.Is(err, domain.ErrCouponNotFound),
+			errors.Is(err, domain.ErrAddressNotFound), errors.Is(err, domain.ErrInvalidPaymentMethod):
+			return nil, status.Errorf(codes.InvalidArgument, "failed to create order: %v", err)
+		case errors.Is(err, domain.ErrProductUnavailable), errors.Is(err, domain.ErrStockUnavailable),
+			errors.Is(err, domain.ErrCouponInvalid), errors.Is(err, domain.ErrCouponExhausted):
+			return nil, status.Errorf(codes.FailedPrecondition, "failed to create order: %v", err)
+		case errors.Is(err, domain.ErrIdempotencyKeyReused):
+			return nil, status.Errorf(codes.AlreadyExists, "failed to create order: %v", err)
+		case errors.Is(err, domain.ErrRequestInProgress):
+			return nil, status.Errorf(codes.Aborted, "failed to create order: %v", err)
+		default:
+			return nil, status.Errorf(codes.Internal, "failed to create order: %v", err)
+		}
 	}
 
 	return &pb.CreateOrderResponse{
 		Order:           domainOrderToProto(createdOrder),
-		PaymentIntentId: "",
+		PaymentIntentId: createdOrder.PaymentID,
 	}, nil
 }
 
@@ -98,29 +124,17 @@ func (h *OrderHandler) UpdateOrderStatus(ctx context.Context, req *pb.UpdateOrde
 	logger.Infof("UpdateOrderStatus request for order: %s to status: %s", req.Id, req.Status)
 
 	// Map proto status to domain status
-	var domainStatus domain.OrderStatus
-	switch req.Status {
-	case pb.OrderStatus_PENDING:
-		domainStatus = domain.OrderStatusPending
-	case pb.OrderStatus_CONFIRMED:
-		domainStatus = domain.OrderStatusConfirmed
-	case pb.OrderStatus_PREPARING:
-		domainStatus = domain.OrderStatusProcessing
-	case pb.OrderStatus_SHIPPED:
-		domainStatus = domain.OrderStatusShipped
-	case pb.OrderStatus_DELIVERED:
-		domainStatus = domain.OrderStatusDelivered
-	case pb.OrderStatus_CANCELLED:
-		domainStatus = domain.OrderStatusCancelled
-	case pb.OrderStatus_REFUNDED:
-		domainStatus = domain.OrderStatusRefunded
-	default:
+	domainStatus, ok := protoStatusToDomain(req.Status)
+	if !ok {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid order status")
 	}
 
-	err := h.orderUseCase.UpdateOrderStatus(ctx, req.Id, domainStatus)
+	err := h.orderUseCase.UpdateOrderStatus(ctx, req.Id, domainStatus, req.UpdatedBy, req.Note)
 	if err != nil {
 		logger.Errorf("Failed to update order status: %v", err)
+		if errors.Is(err, domain.ErrOrderConflict) {
+			return nil, status.Errorf(codes.Aborted, "failed to update order status: %v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to update order status: %v", err)
 	}
 
@@ -137,13 +151,45 @@ func (h *OrderHandler) UpdateOrderStatus(ctx context.Context, req *pb.UpdateOrde
 	}, nil
 }
 
+// SearchOrders lists orders across users for admins with filters and cursor pagination
+func (h *OrderHandler) SearchOrders(ctx context.Context, req *pb.SearchOrdersRequest) (*pb.SearchOrdersResponse, error) {
+	logger.Infof("SearchOrders request with cursor: %q", req.Cursor)
+
+	filter, err := protoFiltersToDomain(req.Filters)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	orders, nextCursor, err := h.orderUseCase.SearchOrders(ctx, filter, req.Cursor, int(req.Limit))
+	if err != nil {
+		logger.Errorf("Failed to search orders: %v", err)
+		if errors.Is(err, domain.ErrInvalidCursor) || errors.Is(err, domain.ErrInvalidFilter) {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to search orders: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to search orders: %v", err)
+	}
+
+	protoOrders := make([]*pb.Order, len(orders))
+	for i, o := range orders {
+		protoOrders[i] = domainOrderToProto(o)
+	}
+
+	return &pb.SearchOrdersResponse{
+		Orders:     protoOrders,
+		NextCursor: nextCursor,
+	}, nil
+}
+
 // CancelOrder cancels an order
 func (h *OrderHandler) CancelOrder(ctx context.Context, req *pb.CancelOrderRequest) (*pb.CancelOrderResponse, error) {
 	logger.Infof("CancelOrder request for ID: %s", req.Id)
 
-	err := h.orderUseCase.CancelOrder(ctx, req.Id)
+	err := h.orderUseCase.CancelOrder(ctx, req.Id, req.UserId, req.Reason)
 	if err != nil {
 		logger.Errorf("Failed to cancel order: %v", err)
+		if errors.Is(err, domain.ErrOrderConflict) {
+			return nil, status.Errorf(codes.Aborted, "failed to cancel order: %v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to cancel order: %v", err)
 	}
 
@@ -194,51 +240,263 @@ func (h *OrderHandler) ListOrders(ctx context.Context, req *pb.ListOrdersRequest
 	}, nil
 }
 
-// GetOrderStatusHistory retrieves order status history (stub implementation)
+// GetOrderStatusHistory retrieves the status transitions of an order, oldest first
 func (h *OrderHandler) GetOrderStatusHistory(ctx context.Context, req *pb.GetOrderStatusHistoryRequest) (*pb.GetOrderStatusHistoryResponse, error) {
-	// Stub implementation - would need to track status history in database
+	logger.Infof("GetOrderStatusHistory request for order: %s", req.OrderId)
+
+	if req.OrderId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "order_id is required")
+	}
+
+	history, err := h.orderUseCase.GetOrderStatusHistory(ctx, req.OrderId)
+	if err != nil {
+		logger.Errorf("Failed to get order status history: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to get order status history: %v", err)
+	}
+
+	protoHistory := make([]*pb.OrderStatusHistory, len(history))
+	for i, change := range history {
+		protoHistory[i] = &pb.OrderStatusHistory{
+			Status:    domainStatusToProto(change.ToStatus),
+			Note:      change.Reason,
+			Timestamp: &pb.Timestamp{Seconds: change.CreatedAt.Unix(), Nanos: int32(change.CreatedAt.Nanosecond())},
+			UpdatedBy: change.ChangedBy,
+		}
+		// The entry that created the order has no previous status
+		if change.FromStatus != "" {
+			fromStatus := domainStatusToProto(change.FromStatus)
+			protoHistory[i].FromStatus = &fromStatus
+		}
+	}
+
 	return &pb.GetOrderStatusHistoryResponse{
-		History: []*pb.OrderStatusHistory{},
+		History: protoHistory,
 	}, nil
 }
 
+// CancelOrderItem cancels a single line of an order and refunds its share of the total
+func (h *OrderHandler) CancelOrderItem(ctx context.Context, req *pb.CancelOrderItemRequest) (*pb.CancelOrderItemResponse, error) {
+	logger.Infof("CancelOrderItem request for order: %s item: %s", req.OrderId, req.ItemId)
+
+	if req.OrderId == "" || req.ItemId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "order_id and item_id are required")
+	}
+
+	updatedOrder, refund, err := h.orderUseCase.CancelOrderItem(ctx, req.OrderId, req.ItemId, req.UserId, req.Reason)
+	if err != nil {
+		logger.Errorf("Failed to cancel order item: %v", err)
+		return nil, itemError("failed to cancel order item", err)
+	}
+
+	return &pb.CancelOrderItemResponse{
+		Order:        domainOrderToProto(updatedOrder),
+		RefundAmount: centsToMoney(refund, updatedOrder.Currency),
+	}, nil
+}
+
+// FlagOrderDisputed flags an order whose payment is disputed
+func (h *OrderHandler) FlagOrderDisputed(ctx context.Context, req *pb.FlagOrderDisputedRequest) (*pb.FlagOrderDisputedResponse, error) {
+	logger.Infof("FlagOrderDisputed request for order: %s dispute: %s", req.OrderId, req.DisputeId)
+
+	if req.OrderId == "" || req.PaymentId == "" || req.DisputeId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "order_id, payment_id and dispute_id are required")
+	}
+
+	order, alreadyFlagged, err := h.orderUseCase.FlagOrderDisputed(ctx, req.OrderId, req.PaymentId, req.DisputeId, req.Reason)
+	if err != nil {
+		logger.Errorf("Failed to flag order as disputed: %v", err)
+		switch {
+		case errors.Is(err, domain.ErrOrderNotFound):
+			return nil, status.Errorf(codes.NotFound, "failed to flag order: %v", err)
+		case errors.Is(err, domain.ErrPaymentMismatch):
+			return nil, status.Errorf(codes.FailedPrecondition, "failed to flag order: %v", err)
+		case errors.Is(err, domain.ErrOrderConflict):
+			return nil, status.Errorf(codes.Aborted, "failed to flag order: %v", err)
+		default:
+			return nil, status.Errorf(codes.Internal, "failed to flag order: %v", err)
+		}
+	}
+
+	return &pb.FlagOrderDisputedResponse{
+		Order:          domainOrderToProto(order),
+		AlreadyFlagged: alreadyFlagged,
+	}, nil
+}
+
+// itemError maps a line-item operation error to a gRPC status
+func itemError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrOrderItemNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrItemNotCancellable):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrOrderConflict):
+		return status.Errorf(codes.Aborted, "%s: %v", msg, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
 // domainOrderToProto converts domain Order to proto Order
 func domainOrderToProto(o *domain.Order) *pb.Order {
 	items := make([]*pb.OrderItem, len(o.Items))
 	for i, item := range o.Items {
 		items[i] = &pb.OrderItem{
-			Id:        item.ID,
-			ProductId: item.ProductID,
-			Quantity:  item.Quantity,
+			Id:         item.ID,
+			ProductId:  item.ProductID,
+			VariantId:  item.VariantID,
+			Name:       item.ProductName,
+			Sku:        item.SKU,
+			Quantity:   item.Quantity,
+			UnitPrice:  centsToMoney(item.Price, o.Currency),
+			TotalPrice: centsToMoney(item.Subtotal, o.Currency),
+			Status:     domainItemStatusToProto(item.Status),
 		}
 	}
 
-	// Map domain status to proto status
-	var protoStatus pb.OrderStatus
-	switch o.Status {
-	case domain.OrderStatusPending:
-		protoStatus = pb.OrderStatus_PENDING
+	var disputedAt *timestamppb.Timestamp
+	if o.DisputedAt != nil {
+		disputedAt = timestamppb.New(*o.DisputedAt)
+	}
+
+	return &pb.Order{
+		Id:              o.ID,
+		UserId:          o.UserID,
+		Items:           items,
+		Subtotal:        centsToMoney(o.Subtotal, o.Currency),
+		Shipping:        centsToMoney(o.ShippingAmount, o.Currency),
+		Tax:             centsToMoney(o.TaxAmount, o.Currency),
+		Discount:        centsToMoney(o.DiscountAmount, o.Currency),
+		Total:           centsToMoney(o.TotalAmount, o.Currency),
+		CouponCode:      o.CouponCode,
+		Refunded:        centsToMoney(o.RefundedAmount, o.Currency),
+		Status:          domainStatusToProto(o.Status),
+		ShippingAddress: domainAddressToProto(o.UserID, o.ShippingAddress),
+		BillingAddress:  domainAddressToProto(o.UserID, o.BillingAddress),
+		PaymentMethod:   o.PaymentMethod,
+		PaymentId:       o.PaymentID,
+		PaymentStatus:   o.PaymentStatus,
+		DisputeId:       o.DisputeID,
+		DisputedAt:      disputedAt,
+		CreatedAt:       timestamppb.New(o.CreatedAt),
+		UpdatedAt:       timestamppb.New(o.UpdatedAt),
+	}
+}
+
+// centsToMoney wraps an amount in cents of currency in proto money
+func centsToMoney(cents int64, currency string) *pb.Money {
+	return &pb.Money{
+		AmountCents: cents,
+		Currency:    currency,
+	}
+}
+
+// domainAddressToProto converts an order address snapshot to proto Address
+func domainAddressToProto(userID string, a domain.Address) *pb.Address {
+	if a.IsEmpty() {
+		return nil
+	}
+	return &pb.Address{
+		Id:           a.ID,
+		UserId:       userID,
+		FirstName:    a.FirstName,
+		LastName:     a.LastName,
+		AddressLine1: a.AddressLine1,
+		AddressLine2: a.AddressLine2,
+		City:         a.City,
+		State:        a.State,
+		PostalCode:   a.PostalCode,
+		Country:      a.Country,
+		Phone:        a.Phone,
+	}
+}
+
+// protoFiltersToDomain converts proto OrderFilters to a domain OrderFilter
+func protoFiltersToDomain(f *pb.OrderFilters) (domain.OrderFilter, error) {
+	var filter domain.OrderFilter
+	if f == nil {
+		return filter, nil
+	}
+
+	filter.UserID = f.UserId
+	filter.PaymentStatus = f.PaymentStatus
+	filter.ProductID = f.ProductId
+	for _, s := range f.Statuses {
+		status, ok := protoStatusToDomain(s)
+		if !ok {
+			return filter, fmt.Errorf("unsupported order status filter: %s", s)
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+	if f.FromDate != nil {
+		from := time.Unix(f.FromDate.Seconds, int64(f.FromDate.Nanos))
+		filter.CreatedFrom = &from
+	}
+	if f.ToDate != nil {
+		to := time.Unix(f.ToDate.Seconds, int64(f.ToDate.Nanos))
+		filter.CreatedTo = &to
+	}
+	if f.MinTotal != nil {
+		filter.MinTotal = &f.MinTotal.AmountCents
+	}
+	if f.MaxTotal != nil {
+		filter.MaxTotal = &f.MaxTotal.AmountCents
+	}
+	return filter, nil
+}
+
+// protoStatusToDomain maps a proto order status to the domain status
+func protoStatusToDomain(s pb.OrderStatus) (domain.OrderStatus, bool) {
+	switch s {
+	case pb.OrderStatus_PENDING:
+		return domain.OrderStatusPending, true
+	case pb.OrderStatus_CONFIRMED:
+		return domain.OrderStatusConfirmed, true
+	case pb.OrderStatus_PREPARING:
+		return domain.OrderStatusProcessing, true
+	case pb.OrderStatus_SHIPPED:
+		return domain.OrderStatusShipped, true
+	case pb.OrderStatus_DELIVERED:
+		return domain.OrderStatusDelivered, true
+	case pb.OrderStatus_CANCELLED:
+		return domain.OrderStatusCancelled, true
+	case pb.OrderStatus_REFUNDED:
+		return domain.OrderStatusRefunded, true
+	default:
+		return "", false
+	}
+}
+
+// domainStatusToProto maps a domain order status to the proto status
+func domainStatusToProto(s domain.OrderStatus) pb.OrderStatus {
+	switch s {
 	case domain.OrderStatusConfirmed:
-		protoStatus = pb.OrderStatus_CONFIRMED
+		return pb.OrderStatus_CONFIRMED
 	case domain.OrderStatusProcessing:
-		protoStatus = pb.OrderStatus_PREPARING
+		return pb.OrderStatus_PREPARING
 	case domain.OrderStatusShipped:
-		protoStatus = pb.OrderStatus_SHIPPED
+		return pb.OrderStatus_SHIPPED
 	case domain.OrderStatusDelivered:
-		protoStatus = pb.OrderStatus_DELIVERED
+		return pb.OrderStatus_DELIVERED
 	case domain.OrderStatusCancelled:
-		protoStatus = pb.OrderStatus_CANCELLED
+		return pb.OrderStatus_CANCELLED
 	case domain.OrderStatusRefunded:
-		protoStatus = pb.OrderStatus_REFUNDED
+		return pb.OrderStatus_REFUNDED
+	default:
+		return pb.OrderStatus_PENDING
 	}
+}
 
-	return &pb.Order{
-		Id:            o.ID,
-		UserId:        o.UserID,
-		Items:         items,
-		Status:        protoStatus,
-		PaymentMethod: o.PaymentMethod,
-		CreatedAt:     timestamppb.New(o.CreatedAt),
-		UpdatedAt:     timestamppb.New(o.UpdatedAt),
+// domainItemStatusToProto maps a domain order item status to the proto status
+func domainItemStatusToProto(s domain.OrderItemStatus) pb.OrderItemStatus {
+	switch s {
+	case domain.OrderItemStatusFulfilled:
+		return pb.OrderItemStatus_ITEM_FULFILLED
+	case domain.OrderItemStatusCancelled:
+		return pb.OrderItemStatus_ITEM_CANCELLED
+	case domain.OrderItemStatusReturned:
+		return pb.OrderItemStatus_ITEM_RETURNED
+	default:
+		return pb.OrderItemStatus_ITEM_PENDING
 	}
 }
@@ -0,0 +1,184 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/cqchien/ecomerce-rec/backend/proto"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RequestReturn opens a return for delivered items of an order
+func (h *OrderHandler) RequestReturn(ctx context.Context, req *pb.RequestReturnRequest) (*pb.RequestReturnResponse, error) {
+	logger.Infof("RequestReturn request for order: %s", req.OrderId)
+
+	if req.OrderId == "" || req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "order_id and user_id are required")
+	}
+	if req.Reason == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "reason is required")
+	}
+
+	orderReturn, err := h.orderUseCase.RequestReturn(ctx, req.OrderId, req.UserId, req.ItemIds, req.Reason)
+	if err != nil {
+		logger.Errorf("Failed to request return: %v", err)
+		return nil, returnError("failed to request return", err)
+	}
+
+	return &pb.RequestReturnResponse{OrderReturn: domainReturnToProto(orderReturn)}, nil
+}
+
+// ApproveReturn authorizes a requested return
+func (h *OrderHandler) ApproveReturn(ctx context.Context, req *pb.ApproveReturnRequest) (*pb.ApproveReturnResponse, error) {
+	logger.Infof("ApproveReturn request for return: %s", req.ReturnId)
+
+	orderReturn, err := h.orderUseCase.ApproveReturn(ctx, req.ReturnId, req.ApprovedBy)
+	if err != nil {
+		logger.Errorf("Failed to approve return: %v", err)
+		return nil, returnError("failed to approve return", err)
+	}
+
+	return &pb.ApproveReturnResponse{OrderReturn: domainReturnToProto(orderReturn)}, nil
+}
+
+// RejectReturn declines a requested return
+func (h *OrderHandler) RejectReturn(ctx context.Context, req *pb.RejectReturnRequest) (*pb.RejectReturnResponse, error) {
+	logger.Infof("RejectReturn request for return: %s", req.ReturnId)
+
+	if req.Reason == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "reason is required")
+	}
+
+	orderReturn, err := h.orderUseCase.RejectReturn(ctx, req.ReturnId, req.RejectedBy, req.Reason)
+	if err != nil {
+		logger.Errorf("Failed to reject return: %v", err)
+		return nil, returnError("failed to reject return", err)
+	}
+
+	return &pb.RejectReturnResponse{OrderReturn: domainReturnToProto(orderReturn)}, nil
+}
+
+// ReceiveReturn records receipt of the returned items and restocks them
+func (h *OrderHandler) ReceiveReturn(ctx context.Context, req *pb.ReceiveReturnRequest) (*pb.ReceiveReturnResponse, error) {
+	logger.Infof("ReceiveReturn request for return: %s", req.ReturnId)
+
+	orderReturn, err := h.orderUseCase.ReceiveReturn(ctx, req.ReturnId, req.ReceivedBy)
+	if err != nil {
+		logger.Errorf("Failed to receive return: %v", err)
+		return nil, returnError("failed to receive return", err)
+	}
+
+	return &pb.ReceiveReturnResponse{OrderReturn: domainReturnToProto(orderReturn)}, nil
+}
+
+// RefundReturn refunds a received return
+func (h *OrderHandler) RefundReturn(ctx context.Context, req *pb.RefundReturnRequest) (*pb.RefundReturnResponse, error) {
+	logger.Infof("RefundReturn request for return: %s", req.ReturnId)
+
+	orderReturn, err := h.orderUseCase.RefundReturn(ctx, req.ReturnId, req.RefundedBy)
+	if err != nil {
+		logger.Errorf("Failed to refund return: %v", err)
+		return nil, returnError("failed to refund return", err)
+	}
+
+	return &pb.RefundReturnResponse{OrderReturn: domainReturnToProto(orderReturn)}, nil
+}
+
+// GetReturn retrieves a return by ID
+func (h *OrderHandler) GetReturn(ctx context.Context, req *pb.GetReturnRequest) (*pb.GetReturnResponse, error) {
+	logger.Infof("GetReturn request for return: %s", req.ReturnId)
+
+	orderReturn, err := h.orderUseCase.GetReturn(ctx, req.ReturnId)
+	if err != nil {
+		logger.Errorf("Failed to get return: %v", err)
+		return nil, returnError("failed to get return", err)
+	}
+
+	return &pb.GetReturnResponse{OrderReturn: domainReturnToProto(orderReturn)}, nil
+}
+
+// ListOrderReturns retrieves the returns of an order, newest first
+func (h *OrderHandler) ListOrderReturns(ctx context.Context, req *pb.ListOrderReturnsRequest) (*pb.ListOrderReturnsResponse, error) {
+	logger.Infof("ListOrderReturns request for order: %s", req.OrderId)
+
+	if req.OrderId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "order_id is required")
+	}
+
+	returns, err := h.orderUseCase.GetOrderReturns(ctx, req.OrderId)
+	if err != nil {
+		logger.Errorf("Failed to list order returns: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list order returns: %v", err)
+	}
+
+	protoReturns := make([]*pb.OrderReturn, len(returns))
+	for i, r := range returns {
+		protoReturns[i] = domainReturnToProto(r)
+	}
+
+	return &pb.ListOrderReturnsResponse{Returns: protoReturns}, nil
+}
+
+// returnError maps a return workflow error to a gRPC status
+func returnError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrReturnNotFound), errors.Is(err, domain.ErrOrderItemNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrReturnNotAllowed), errors.Is(err, domain.ErrInvalidReturnTransition), errors.Is(err, domain.ErrItemNotReturnable):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrOrderConflict):
+		return status.Errorf(codes.Aborted, "%s: %v", msg, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
+// domainReturnToProto converts domain OrderReturn to proto OrderReturn
+func domainReturnToProto(r *domain.OrderReturn) *pb.OrderReturn {
+	items := make([]*pb.OrderReturnItem, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = &pb.OrderReturnItem{
+			Id:          item.ID,
+			OrderItemId: item.OrderItemID,
+			ProductId:   item.ProductID,
+			VariantId:   item.VariantID,
+			Quantity:    item.Quantity,
+			Restocked:   item.Restocked,
+		}
+	}
+
+	return &pb.OrderReturn{
+		Id:              r.ID,
+		OrderId:         r.OrderID,
+		UserId:          r.UserID,
+		Items:           items,
+		Status:          domainReturnStatusToProto(r.Status),
+		Reason:          r.Reason,
+		RejectionReason: r.RejectionReason,
+		RefundAmount:    centsToMoney(r.RefundAmount, r.Currency),
+		ReviewedBy:      r.ReviewedBy,
+		ReceivedBy:      r.ReceivedBy,
+		CreatedAt:       timestamppb.New(r.CreatedAt),
+		UpdatedAt:       timestamppb.New(r.UpdatedAt),
+	}
+}
+
+// domainReturnStatusToProto maps a domain return status to the proto status
+func domainReturnStatusToProto(s domain.ReturnStatus) pb.ReturnStatus {
+	switch s {
+	case domain.ReturnStatusApproved:
+		return pb.ReturnStatus_RETURN_APPROVED
+	case domain.ReturnStatusRejected:
+		return pb.ReturnStatus_RETURN_REJECTED
+	case domain.ReturnStatusReceived:
+		return pb.ReturnStatus_RETURN_RECEIVED
+	case domain.ReturnStatusRefunded:
+		return pb.ReturnStatus_RETURN_REFUNDED
+	default:
+		return pb.ReturnStatus_RETURN_REQUESTED
+	}
+}
@@ -0,0 +1,178 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "github.com/cqchien/ecomerce-rec/backend/proto"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CreateShipment packs order lines into a new shipment
+func (h *OrderHandler) CreateShipment(ctx context.Context, req *pb.CreateShipmentRequest) (*pb.CreateShipmentResponse, error) {
+	logger.Infof("CreateShipment request for order: %s", req.OrderId)
+
+	if req.OrderId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "order_id is required")
+	}
+	if req.Carrier == "" || req.TrackingNumber == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "carrier and tracking_number are required")
+	}
+	if len(req.Items) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "at least one item is required")
+	}
+
+	items := make([]domain.ShipmentItem, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = domain.ShipmentItem{
+			OrderItemID: item.OrderItemId,
+			Quantity:    item.Quantity,
+		}
+	}
+
+	shipment, err := h.orderUseCase.CreateShipment(ctx, req.OrderId, req.Carrier, req.TrackingNumber, items, req.CreatedBy)
+	if err != nil {
+		logger.Errorf("Failed to create shipment: %v", err)
+		return nil, shipmentError("failed to create shipment", err)
+	}
+
+	return &pb.CreateShipmentResponse{Shipment: domainShipmentToProto(shipment)}, nil
+}
+
+// IngestTrackingEvent applies a carrier tracking update to its shipment and order
+func (h *OrderHandler) IngestTrackingEvent(ctx context.Context, req *pb.IngestTrackingEventRequest) (*pb.IngestTrackingEventResponse, error) {
+	logger.Infof("IngestTrackingEvent request for %s %s", req.Carrier, req.TrackingNumber)
+
+	if req.Carrier == "" || req.TrackingNumber == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "carrier and tracking_number are required")
+	}
+
+	var occurredAt time.Time
+	if req.OccurredAt != nil {
+		occurredAt = req.OccurredAt.AsTime()
+	}
+
+	shipment, order, duplicate, err := h.orderUseCase.IngestTrackingEvent(ctx, domain.TrackingEvent{
+		Carrier:        req.Carrier,
+		TrackingNumber: req.TrackingNumber,
+		Status:         protoShipmentStatusToDomain(req.Status),
+		Location:       req.Location,
+		Description:    req.Description,
+		OccurredAt:     occurredAt,
+	})
+	if err != nil {
+		logger.Errorf("Failed to ingest tracking event: %v", err)
+		return nil, shipmentError("failed to ingest tracking event", err)
+	}
+
+	return &pb.IngestTrackingEventResponse{
+		Shipment:    domainShipmentToProto(shipment),
+		Duplicate:   duplicate,
+		OrderStatus: domainStatusToProto(order.Status),
+	}, nil
+}
+
+// ListOrderShipments retrieves the shipments of an order
+func (h *OrderHandler) ListOrderShipments(ctx context.Context, req *pb.ListOrderShipmentsRequest) (*pb.ListOrderShipmentsResponse, error) {
+	logger.Infof("ListOrderShipments request for order: %s", req.OrderId)
+
+	if req.OrderId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "order_id is required")
+	}
+
+	shipments, err := h.orderUseCase.GetOrderShipments(ctx, req.OrderId)
+	if err != nil {
+		logger.Errorf("Failed to list order shipments: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list order shipments: %v", err)
+	}
+
+	protoShipments := make([]*pb.Shipment, len(shipments))
+	for i, s := range shipments {
+		protoShipments[i] = domainShipmentToProto(s)
+	}
+
+	return &pb.ListOrderShipmentsResponse{Shipments: protoShipments}, nil
+}
+
+// shipmentError maps a shipment error to a gRPC status
+func shipmentError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrShipmentNotFound), errors.Is(err, domain.ErrOrderItemNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrInvalidTrackingEvent):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrShipmentNotAllowed):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrOrderConflict):
+		return status.Errorf(codes.Aborted, "%s: %v", msg, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
+// domainShipmentToProto converts domain Shipment to proto Shipment
+func domainShipmentToProto(s *domain.Shipment) *pb.Shipment {
+	items := make([]*pb.ShipmentItem, len(s.Items))
+	for i, item := range s.Items {
+		items[i] = &pb.ShipmentItem{
+			Id:          item.ID,
+			OrderItemId: item.OrderItemID,
+			Quantity:    item.Quantity,
+		}
+	}
+
+	shipment := &pb.Shipment{
+		Id:             s.ID,
+		OrderId:        s.OrderID,
+		Carrier:        s.Carrier,
+		TrackingNumber: s.TrackingNumber,
+		Status:         domainShipmentStatusToProto(s.Status),
+		Items:          items,
+		CreatedAt:      timestamppb.New(s.CreatedAt),
+		UpdatedAt:      timestamppb.New(s.UpdatedAt),
+	}
+	if s.ShippedAt != nil {
+		shipment.ShippedAt = timestamppb.New(*s.ShippedAt)
+	}
+	if s.DeliveredAt != nil {
+		shipment.DeliveredAt = timestamppb.New(*s.DeliveredAt)
+	}
+	return shipment
+}
+
+// protoShipmentStatusToDomain maps a proto shipment status to the domain status
+func protoShipmentStatusToDomain(s pb.ShipmentStatus) domain.ShipmentStatus {
+	switch s {
+	case pb.ShipmentStatus_SHIPMENT_IN_TRANSIT:
+		return domain.ShipmentStatusInTransit
+	case pb.ShipmentStatus_SHIPMENT_OUT_FOR_DELIVERY:
+		return domain.ShipmentStatusOutForDelivery
+	case pb.ShipmentStatus_SHIPMENT_DELIVERED:
+		return domain.ShipmentStatusDelivered
+	case pb.ShipmentStatus_SHIPMENT_EXCEPTION:
+		return domain.ShipmentStatusException
+	default:
+		return domain.ShipmentStatusLabelCreated
+	}
+}
+
+// domainShipmentStatusToProto maps a domain shipment status to the proto status
+func domainShipmentStatusToProto(s domain.ShipmentStatus) pb.ShipmentStatus {
+	switch s {
+	case domain.ShipmentStatusInTransit:
+		return pb.ShipmentStatus_SHIPMENT_IN_TRANSIT
+	case domain.ShipmentStatusOutForDelivery:
+		return pb.ShipmentStatus_SHIPMENT_OUT_FOR_DELIVERY
+	case domain.ShipmentStatusDelivered:
+		return pb.ShipmentStatus_SHIPMENT_DELIVERED
+	case domain.ShipmentStatusException:
+		return pb.ShipmentStatus_SHIPMENT_EXCEPTION
+	default:
+		return pb.ShipmentStatus_SHIPMENT_LABEL_CREATED
+	}
+}
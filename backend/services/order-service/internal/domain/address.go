@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAddressNotFound is returned when an address is missing or belongs to another user
+var ErrAddressNotFound = errors.New("address not found for user")
+
+// Address is a postal address snapshotted onto an order when it is placed, so
+// later edits in user-service do not rewrite order history
+type Address struct {
+	ID           string `json:"id,omitempty"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	AddressLine1 string `json:"address_line1"`
+	AddressLine2 string `json:"address_line2,omitempty"`
+	City         string `json:"city"`
+	State        string `json:"state"`
+	PostalCode   string `json:"postal_code"`
+	Country      string `json:"country"`
+	Phone        string `json:"phone,omitempty"`
+	IsDefault    bool   `json:"-"`
+}
+
+// IsEmpty reports whether no address has been set
+func (a Address) IsEmpty() bool {
+	return a.ID == "" && a.AddressLine1 == ""
+}
+
+// SelectAddress picks the address with the given ID from a user's address book,
+// or the default address when no ID is given
+func SelectAddress(addresses []Address, addressID string) (Address, error) {
+	for _, address := range addresses {
+		if addressID == "" && address.IsDefault {
+			return address, nil
+		}
+		if addressID != "" && address.ID == addressID {
+			return address, nil
+		}
+	}
+
+	if addressID == "" {
+		return Address{}, fmt.Errorf("%w: no default address", ErrAddressNotFound)
+	}
+	return Address{}, fmt.Errorf("%w: %s", ErrAddressNotFound, addressID)
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CouponType represents how a coupon discount is calculated
+type CouponType string
+
+const (
+	CouponTypePercentage  CouponType = "PERCENTAGE"
+	CouponTypeFixedAmount CouponType = "FIXED_AMOUNT"
+)
+
+var (
+	ErrCouponNotFound  = errors.New("coupon not found")
+	ErrCouponInvalid   = errors.New("coupon is not valid for this order")
+	ErrCouponExhausted = errors.New("coupon has reached its redemption limit")
+)
+
+// Coupon is a discount code that can be redeemed when placing an order
+type Coupon struct {
+	ID             string
+	Code           string
+	Type           CouponType
+	Value          int64 // Basis points for percentage coupons (1000 = 10%), cents for fixed amount
+	MinOrderAmount int64 // in cents
+	MaxDiscount    int64 // in cents; cap for percentage coupons, 0 for no cap
+	MaxRedemptions int   // 0 for unlimited
+	PerUserLimit   int   // 0 for unlimited
+	TimesRedeemed  int
+	Active         bool
+	StartsAt       *time.Time
+	ExpiresAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// CouponRedemption records a coupon used by an order
+type CouponRedemption struct {
+	ID        string
+	CouponID  string
+	OrderID   string
+	UserID    string
+	Amount    int64 // in cents
+	CreatedAt time.Time
+}
+
+// NormalizeCouponCode returns the canonical form coupon codes are stored in
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks whether the coupon can be applied to an order with the given subtotal.
+// Redemption limits are enforced atomically when the order is saved.
+func (c *Coupon) Validate(subtotal int64, now time.Time) error {
+	if !c.Active {
+		return fmt.Errorf("%w: %s is inactive", ErrCouponInvalid, c.Code)
+	}
+	if c.StartsAt != nil && now.Before(*c.StartsAt) {
+		return fmt.Errorf("%w: %s is not active yet", ErrCouponInvalid, c.Code)
+	}
+	if c.ExpiresAt != nil && !now.Before(*c.ExpiresAt) {
+		return fmt.Errorf("%w: %s has expired", ErrCouponInvalid, c.Code)
+	}
+	if c.MaxRedemptions > 0 && c.TimesRedeemed >= c.MaxRedemptions {
+		return fmt.Errorf("%w: %s", ErrCouponExhausted, c.Code)
+	}
+	if subtotal < c.MinOrderAmount {
+		return fmt.Errorf("%w: %s requires a minimum order of %s", ErrCouponInvalid, c.Code, FormatCents(c.MinOrderAmount))
+	}
+	return nil
+}
+
+// CheckUserLimit checks whether a user who already redeemed the coupon the
+// given number of times may redeem it again
+func (c *Coupon) CheckUserLimit(redeemed int64) error {
+	if c.PerUserLimit > 0 && redeemed >= int64(c.PerUserLimit) {
+		return fmt.Errorf("%w: %s already used by this user", ErrCouponExhausted, c.Code)
+	}
+	return nil
+}
+
+// DiscountFor returns the discount the coupon grants on the given subtotal,
+// never more than the subtotal itself
+func (c *Coupon) DiscountFor(subtotal int64) int64 {
+	var discount int64
+	switch c.Type {
+	case CouponTypePercentage:
+		discount = prorate(subtotal, c.Value, 10000)
+		if c.MaxDiscount > 0 && discount > c.MaxDiscount {
+			discount = c.MaxDiscount
+		}
+	case CouponTypeFixedAmount:
+		discount = c.Value
+	}
+
+	if discount > subtotal {
+		discount = subtotal
+	}
+	return discount
+}
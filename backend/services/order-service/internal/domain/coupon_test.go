@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCouponValidate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		setup    func(c *Coupon)
+		subtotal int64
+		wantErr  error
+	}{
+		{name: "valid coupon", subtotal: 5000},
+		{name: "inactive", setup: func(c *Coupon) { c.Active = false }, subtotal: 5000, wantErr: ErrCouponInvalid},
+		{name: "not started yet", setup: func(c *Coupon) { c.StartsAt = &after }, subtotal: 5000, wantErr: ErrCouponInvalid},
+		{name: "started", setup: func(c *Coupon) { c.StartsAt = &before }, subtotal: 5000},
+		{name: "expired", setup: func(c *Coupon) { c.ExpiresAt = &before }, subtotal: 5000, wantErr: ErrCouponInvalid},
+		{name: "expires at this instant", setup: func(c *Coupon) { c.ExpiresAt = &now }, subtotal: 5000, wantErr: ErrCouponInvalid},
+		{name: "not expired yet", setup: func(c *Coupon) { c.ExpiresAt = &after }, subtotal: 5000},
+		{name: "subtotal below the minimum spend", subtotal: 2999, wantErr: ErrCouponInvalid},
+		{name: "subtotal at the minimum spend", subtotal: 3000},
+		{name: "redemption limit reached", setup: func(c *Coupon) { c.TimesRedeemed = 10 }, subtotal: 5000, wantErr: ErrCouponExhausted},
+		{name: "redemptions left", setup: func(c *Coupon) { c.TimesRedeemed = 9 }, subtotal: 5000},
+		{name: "unlimited redemptions", setup: func(c *Coupon) { c.MaxRedemptions, c.TimesRedeemed = 0, 500 }, subtotal: 5000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coupon := &Coupon{Code: "SAVE10", Type: CouponTypePercentage, Value: 1000, MinOrderAmount: 3000, MaxRedemptions: 10, Active: true}
+			if tt.setup != nil {
+				tt.setup(coupon)
+			}
+
+			err := coupon.Validate(tt.subtotal, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCouponCheckUserLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		redeemed int64
+		wantErr  error
+	}{
+		{name: "first redemption", limit: 1, redeemed: 0},
+		{name: "limit reached", limit: 1, redeemed: 1, wantErr: ErrCouponExhausted},
+		{name: "below the limit", limit: 3, redeemed: 2},
+		{name: "no limit", limit: 0, redeemed: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coupon := &Coupon{Code: "ONCE", PerUserLimit: tt.limit}
+			if err := coupon.CheckUserLimit(tt.redeemed); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckUserLimit(%d) error = %v, want %v", tt.redeemed, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCouponDiscountFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		coupon   Coupon
+		subtotal int64
+		want     int64
+	}{
+		{name: "percentage", coupon: Coupon{Type: CouponTypePercentage, Value: 1000}, subtotal: 4999, want: 500},
+		{name: "percentage capped", coupon: Coupon{Type: CouponTypePercentage, Value: 5000, MaxDiscount: 1500}, subtotal: 10000, want: 1500},
+		{name: "fixed amount", coupon: Coupon{Type: CouponTypeFixedAmount, Value: 900}, subtotal: 5000, want: 900},
+		{name: "never more than the subtotal", coupon: Coupon{Type: CouponTypeFixedAmount, Value: 900}, subtotal: 600, want: 600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.coupon.DiscountFor(tt.subtotal); got != tt.want {
+				t.Errorf("DiscountFor(%d) = %d, want %d", tt.subtotal, got, tt.want)
+			}
+		})
+	}
+}
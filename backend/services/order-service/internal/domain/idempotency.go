@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used with a different request")
+	ErrRequestInProgress    = errors.New("a request with this idempotency key is still in progress")
+)
+
+// IdempotencyRecord maps a client idempotency key to the order it created.
+// A record without an order ID is a claim held by a request still in progress.
+type IdempotencyRecord struct {
+	Key         string
+	UserID      string
+	Fingerprint string
+	OrderID     string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewIdempotencyRecord creates a claim for an idempotency key
+func NewIdempotencyRecord(key, userID, fingerprint string) *IdempotencyRecord {
+	now := time.Now()
+	return &IdempotencyRecord{
+		Key:         key,
+		UserID:      userID,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// IsCompleted reports whether the request holding the key created an order
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.OrderID != ""
+}
+
+// Matches reports whether a request with the given fingerprint is a replay of this one
+func (r *IdempotencyRecord) Matches(fingerprint string) bool {
+	return r.Fingerprint == fingerprint
+}
+
+// CreateOrderFingerprint returns a stable hash of a create-order request.
+// Items are sorted so the same cart submitted in a different order still matches.
+func CreateOrderFingerprint(userID, shippingAddress, billingAddress, paymentMethod, couponCode string, items []OrderItem) string {
+	type fingerprintItem struct {
+		ProductID string `json:"product_id"`
+		VariantID string `json:"variant_id"`
+		Quantity  int32  `json:"quantity"`
+	}
+
+	fpItems := make([]fingerprintItem, len(items))
+	for i, item := range items {
+		fpItems[i] = fingerprintItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+		}
+	}
+	sort.Slice(fpItems, func(i, j int) bool {
+		if fpItems[i].ProductID != fpItems[j].ProductID {
+			return fpItems[i].ProductID < fpItems[j].ProductID
+		}
+		if fpItems[i].VariantID != fpItems[j].VariantID {
+			return fpItems[i].VariantID < fpItems[j].VariantID
+		}
+		return fpItems[i].Quantity < fpItems[j].Quantity
+	})
+
+	data, _ := json.Marshal(struct {
+		UserID          string            `json:"user_id"`
+		ShippingAddress string            `json:"shipping_address"`
+		BillingAddress  string            `json:"billing_address"`
+		PaymentMethod   string            `json:"payment_method"`
+		CouponCode      string            `json:"coupon_code"`
+		Items           []fingerprintItem `json:"items"`
+	}{userID, shippingAddress, billingAddress, paymentMethod, NormalizeCouponCode(couponCode), fpItems})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
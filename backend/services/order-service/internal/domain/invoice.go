@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// InvoiceFormat is a rendering of an invoice document
+type InvoiceFormat string
+
+const (
+	InvoiceFormatPDF  InvoiceFormat = "PDF"
+	InvoiceFormatJSON InvoiceFormat = "JSON" // UBL 2.1 invoice serialized as JSON
+)
+
+var (
+	ErrInvoiceNotFound    = errors.New("invoice not found")
+	ErrInvoiceExists      = errors.New("invoice already issued for order")
+	ErrInvoiceNotAllowed  = errors.New("invoice cannot be issued")
+	ErrInvoiceNotRendered = errors.New("invoice document not rendered")
+)
+
+// Seller identifies the business issuing invoices
+type Seller struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	TaxID   string `json:"tax_id"`
+}
+
+// InvoiceLine is a billed order line
+type InvoiceLine struct {
+	OrderItemID string `json:"order_item_id"`
+	Description string `json:"description"`
+	SKU         string `json:"sku"`
+	Quantity    int32  `json:"quantity"`
+	UnitPrice   int64  `json:"unit_price"` // in cents
+	Amount      int64  `json:"amount"`     // in cents
+}
+
+// Invoice is the immutable billing record of an order. Lines, amounts and
+// parties are snapshotted when it is issued so the rendered documents never
+// change, even if the order is later returned or refunded.
+type Invoice struct {
+	ID             string
+	OrderID        string
+	UserID         string
+	Number         string // Assigned from a gap-free sequence when the invoice is stored
+	Sequence       int64
+	Seller         Seller
+	BillingAddress Address
+	Currency       string
+	Lines          []InvoiceLine
+	Subtotal       int64 // Amounts are in cents of Currency
+	DiscountAmount int64
+	ShippingAmount int64
+	TaxAmount      int64
+	TaxRate        float64
+	TotalAmount    int64
+	PDF            []byte
+	Document       []byte // UBL JSON rendering
+	IssuedAt       time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NewInvoice snapshots a delivered order into an unnumbered invoice. Only the
+// lines still counting towards the order total are billed.
+func NewInvoice(order *Order, seller Seller) (*Invoice, error) {
+	if order.Status != OrderStatusDelivered {
+		return nil, fmt.Errorf("%w: order is %s", ErrInvoiceNotAllowed, order.Status)
+	}
+
+	lines := make([]InvoiceLine, 0, len(order.Items))
+	for _, item := range order.Items {
+		if !item.IsActive() {
+			continue
+		}
+		description := item.ProductName
+		if item.VariantName != "" {
+			description += " - " + item.VariantName
+		}
+		lines = append(lines, InvoiceLine{
+			OrderItemID: item.ID,
+			Description: description,
+			SKU:         item.SKU,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.Price,
+			Amount:      item.Subtotal,
+		})
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: order has no billable items", ErrInvoiceNotAllowed)
+	}
+
+	billing := order.BillingAddress
+	if billing.IsEmpty() {
+		billing = order.ShippingAddress
+	}
+
+	now := time.Now()
+	return &Invoice{
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		Seller:         seller,
+		BillingAddress: billing,
+		Currency:       order.Currency,
+		Lines:          lines,
+		Subtotal:       order.Subtotal,
+		DiscountAmount: order.DiscountAmount,
+		ShippingAmount: order.ShippingAmount,
+		TaxAmount:      order.TaxAmount,
+		TaxRate:        order.TaxRate,
+		TotalAmount:    order.TotalAmount,
+		IssuedAt:       now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// AssignNumber gives the invoice its position in the invoice sequence
+func (i *Invoice) AssignNumber(sequence int64) {
+	i.Sequence = sequence
+	i.Number = fmt.Sprintf("INV-%08d", sequence)
+}
+
+// IsRendered reports whether both documents have been rendered
+func (i *Invoice) IsRendered() bool {
+	return len(i.PDF) > 0 && len(i.Document) > 0
+}
+
+// Content returns the rendered document in the given format with its content
+// type and a suggested file name
+func (i *Invoice) Content(format InvoiceFormat) ([]byte, string, string, error) {
+	switch format {
+	case InvoiceFormatPDF:
+		if len(i.PDF) == 0 {
+			return nil, "", "", ErrInvoiceNotRendered
+		}
+		return i.PDF, "application/pdf", i.Number + ".pdf", nil
+	case InvoiceFormatJSON:
+		if len(i.Document) == 0 {
+			return nil, "", "", ErrInvoiceNotRendered
+		}
+		return i.Document, "application/json", i.Number + ".json", nil
+	default:
+		return nil, "", "", fmt.Errorf("unsupported invoice format %q", format)
+	}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// DefaultCurrency is the ISO 4217 code used when no currency is configured
+const DefaultCurrency = "USD"
+
+// Amounts are int64 minor units (cents) throughout order-service. Rounding only
+// happens where a rate or proportion is applied, always half away from zero, so
+// an order's components add up exactly to its total.
+
+// ToCents converts a decimal amount, such as a configured fee, to cents
+func ToCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// FormatCents renders cents as a decimal amount for logs and messages
+func FormatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// applyRate returns amount × rate rounded to whole cents
+func applyRate(amount int64, rate float64) int64 {
+	return int64(math.Round(float64(amount) * rate))
+}
+
+// prorate returns amount × part / whole rounded to whole cents. It uses 128-bit
+// intermediate arithmetic so large amounts cannot overflow. part must not exceed whole.
+func prorate(amount, part, whole int64) int64 {
+	if amount <= 0 || part <= 0 || whole <= 0 {
+		return 0
+	}
+	if part >= whole {
+		return amount
+	}
+
+	hi, lo := bits.Mul64(uint64(amount), uint64(part))
+	lo, carry := bits.Add64(lo, uint64(whole)/2, 0)
+	quotient, _ := bits.Div64(hi+carry, lo, uint64(whole))
+	return int64(quotient)
+}
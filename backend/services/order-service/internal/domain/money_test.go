@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProrate(t *testing.T) {
+	tests := []struct {
+		name                string
+		amount, part, whole int64
+		want                int64
+	}{
+		{name: "exact share", amount: 1000, part: 1, whole: 4, want: 250},
+		{name: "half cent rounds up", amount: 5, part: 1, whole: 2, want: 3},
+		{name: "below half a cent rounds down", amount: 10, part: 1, whole: 3, want: 3},
+		{name: "above half a cent rounds up", amount: 10, part: 2, whole: 3, want: 7},
+		{name: "percentage in basis points", amount: 1999, part: 1500, whole: 10000, want: 300},
+		{name: "whole amount", amount: 1999, part: 3, whole: 3, want: 1999},
+		{name: "part above whole is capped", amount: 1999, part: 4, whole: 3, want: 1999},
+		{name: "no part", amount: 1999, part: 0, whole: 3, want: 0},
+		{name: "no amount", amount: 0, part: 1, whole: 3, want: 0},
+		{name: "negative amount", amount: -100, part: 1, whole: 2, want: 0},
+		{name: "no whole", amount: 1999, part: 1, whole: 0, want: 0},
+		{name: "product beyond int64", amount: math.MaxInt64, part: math.MaxInt64 - 1, whole: math.MaxInt64, want: math.MaxInt64 - 1},
+		{name: "large amount halved", amount: math.MaxInt64, part: 1 << 40, whole: 1 << 41, want: 1 << 62},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prorate(tt.amount, tt.part, tt.whole); got != tt.want {
+				t.Errorf("prorate(%d, %d, %d) = %d, want %d", tt.amount, tt.part, tt.whole, got, tt.want)
+			}
+		})
+	}
+}
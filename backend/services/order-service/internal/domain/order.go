@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -18,80 +19,228 @@ const (
 	OrderStatusRefunded   OrderStatus = "REFUNDED"
 )
 
+// OrderItemStatus represents the fulfilment state of a single order line
+type OrderItemStatus string
+
+const (
+	OrderItemStatusPending   OrderItemStatus = "PENDING"
+	OrderItemStatusFulfilled OrderItemStatus = "FULFILLED"
+	OrderItemStatusCancelled OrderItemStatus = "CANCELLED"
+	OrderItemStatusReturned  OrderItemStatus = "RETURNED"
+)
+
+var (
+	ErrOrderNotFound      = errors.New("order not found")
+	ErrOrderItemNotFound  = errors.New("order item not found")
+	ErrItemNotCancellable = errors.New("order item cannot be cancelled")
+	ErrItemNotReturnable  = errors.New("order item cannot be returned")
+	ErrPaymentMismatch    = errors.New("payment does not belong to the order")
+	// ErrOrderConflict is returned when an order was changed by another writer
+	// since it was loaded; reload it and apply the change again
+	ErrOrderConflict = errors.New("order was changed concurrently")
+)
+
+// PaymentStatusDisputed is the payment status of orders whose payment the
+// customer's bank is disputing
+const PaymentStatusDisputed = "DISPUTED"
+
 // OrderItem represents a single item in an order
 type OrderItem struct {
-	ID        string
-	OrderID   string
-	ProductID string
-	Quantity  int32
-	Price     float64
-	Subtotal  float64
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID            string
+	OrderID       string
+	ProductID     string
+	VariantID     string
+	ProductName   string
+	VariantName   string
+	SKU           string
+	Quantity      int32
+	Price         int64 // Unit price in cents
+	Subtotal      int64 // in cents
+	Status        OrderItemStatus
+	ReservationID string     // Inventory reservation line holding stock for this item
+	RefundAmount  int64      // Amount the order total dropped by when the line was cancelled or returned, in cents
+	RefundedAt    *time.Time // When RefundAmount was refunded; nil while the refund is pending
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// IsActive reports whether the item still counts towards the order total
+func (i *OrderItem) IsActive() bool {
+	return i.Status != OrderItemStatusCancelled && i.Status != OrderItemStatusReturned
+}
+
+// HasPendingRefund reports whether the line is owed a refund not issued yet
+func (i *OrderItem) HasPendingRefund() bool {
+	return i.RefundAmount > 0 && i.RefundedAt == nil
+}
+
+// RefundKey returns the idempotency key the line's refund is issued with, so
+// a refund retried after a failure is only issued once
+func (i *OrderItem) RefundKey() string {
+	return "order-item-" + i.ID
+}
+
+// ApplyProduct snapshots the authoritative catalog data onto the item.
+// The variant price takes precedence over the product price when a variant is ordered.
+func (i *OrderItem) ApplyProduct(product Product) error {
+	if product.ID != i.ProductID {
+		return fmt.Errorf("%w: %s", ErrProductNotFound, i.ProductID)
+	}
+	if !product.IsPurchasable() {
+		return fmt.Errorf("%w: %s is %s", ErrProductUnavailable, product.ID, product.Status)
+	}
+
+	i.ProductName = product.Name
+	i.SKU = product.SKU
+	i.VariantName = ""
+	i.Price = product.Price
+
+	if i.VariantID != "" {
+		variant, err := product.FindVariant(i.VariantID)
+		if err != nil {
+			return err
+		}
+		i.VariantName = variant.Name
+		if variant.SKU != "" {
+			i.SKU = variant.SKU
+		}
+		if variant.Price > 0 {
+			i.Price = variant.Price
+		}
+	}
+
+	return nil
 }
 
 // Order represents an order entity
 type Order struct {
-	ID              string
-	UserID          string
-	Status          OrderStatus
-	Items           []OrderItem
-	TotalAmount     float64
-	ShippingAddress string
-	BillingAddress  string
-	PaymentMethod   string
-	PaymentStatus   string
-	TrackingNumber  string
-	Notes           string
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	ID               string
+	UserID           string
+	Status           OrderStatus
+	Items            []OrderItem
+	Currency         string
+	Subtotal         int64 // Amounts are in cents of Currency
+	DiscountAmount   int64
+	ShippingAmount   int64
+	TaxAmount        int64
+	TaxRate          float64
+	TotalAmount      int64 // Grand total: subtotal - discount + shipping + tax
+	RefundedAmount   int64
+	CouponCode       string
+	ShippingAddress  Address
+	BillingAddress   Address
+	PaymentMethod    string
+	PaymentStatus    string
+	PaymentID        string
+	TrackingNumber   string
+	Notes            string
+	DisputeID        string     // Payment service dispute of the order's payment, if any
+	DisputedAt       *time.Time // When the order was flagged as disputed
+	StockCommittedAt *time.Time // When inventory turned the stock reservation into a sale; nil while it is only held
+	Version          int64      // Incremented on every update, to detect concurrent writers
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+
+	// statusChanges holds transitions not yet persisted to the status history
+	statusChanges []StatusChange
+	// events holds domain events not yet written to the outbox
+	events []EventType
+	// idempotencyKey is the client key the order is being placed with
+	idempotencyKey string
 }
 
 // NewOrder creates a new order
-func NewOrder(userID, shippingAddress, billingAddress, paymentMethod string, items []OrderItem) (*Order, error) {
+func NewOrder(userID string, shippingAddress, billingAddress Address, paymentMethod string, items []OrderItem) (*Order, error) {
 	if userID == "" {
 		return nil, errors.New("user ID is required")
 	}
 	if len(items) == 0 {
 		return nil, errors.New("order must have at least one item")
 	}
-	if shippingAddress == "" {
+	if shippingAddress.IsEmpty() {
 		return nil, errors.New("shipping address is required")
 	}
 
 	now := time.Now()
 
-	var totalAmount float64
 	for i := range items {
-		items[i].Subtotal = float64(items[i].Quantity) * items[i].Price
+		items[i].Subtotal = int64(items[i].Quantity) * items[i].Price
+		items[i].Status = OrderItemStatusPending
 		items[i].CreatedAt = now
 		items[i].UpdatedAt = now
-		totalAmount += items[i].Subtotal
 	}
 
-	return &Order{
+	order := &Order{
 		ID:              "",
 		UserID:          userID,
 		Status:          OrderStatusPending,
+		Currency:        DefaultCurrency,
 		Items:           items,
-		TotalAmount:     totalAmount,
 		ShippingAddress: shippingAddress,
 		BillingAddress:  billingAddress,
 		PaymentMethod:   paymentMethod,
-		PaymentStatus:   "PENDING",
+		PaymentStatus:   PaymentStatusPending,
 		CreatedAt:       now,
 		UpdatedAt:       now,
-	}, nil
+	}
+	order.recalculateTotals()
+	order.recordStatusChange("", OrderStatusPending, userID, "order placed")
+	order.recordEvent(EventTypeOrderCreated)
+
+	return order, nil
+}
+
+// ApplyCoupon validates the coupon against the order and applies its discount
+func (o *Order) ApplyCoupon(coupon *Coupon, now time.Time) error {
+	if err := coupon.Validate(o.Subtotal, now); err != nil {
+		return err
+	}
+
+	o.CouponCode = coupon.Code
+	o.DiscountAmount = coupon.DiscountFor(o.Subtotal)
+	o.recalculateTotals()
+	return nil
 }
 
-// CanTransitionTo checks if the order can transition to the given status
+// ApplyPricing applies the currency, shipping and tax rules to the order
+func (o *Order) ApplyPricing(policy PricingPolicy) {
+	if policy.Currency != "" {
+		o.Currency = policy.Currency
+	}
+	o.ShippingAmount = policy.ShippingFor(o.Subtotal - o.DiscountAmount)
+	o.TaxRate = policy.TaxRate
+	o.recalculateTotals()
+}
+
+// recalculateTotals derives the subtotal, tax and grand total from the active
+// items and the discount and shipping already applied
+func (o *Order) recalculateTotals() {
+	var subtotal int64
+	for _, item := range o.Items {
+		if item.IsActive() {
+			subtotal += item.Subtotal
+		}
+	}
+	o.Subtotal = subtotal
+
+	if o.DiscountAmount > o.Subtotal {
+		o.DiscountAmount = o.Subtotal
+	}
+	taxable := o.Subtotal - o.DiscountAmount
+	o.TaxAmount = applyRate(taxable, o.TaxRate)
+	o.TotalAmount = taxable + o.ShippingAmount + o.TaxAmount
+}
+
+// CanTransitionTo checks if the order can transition to the given status. A
+// delivered order is only refunded by returning its lines, which refunds and
+// restocks them, so DELIVERED has no transition here.
 func (o *Order) CanTransitionTo(newStatus OrderStatus) error {
 	validTransitions := map[OrderStatus][]OrderStatus{
 		OrderStatusPending:    {OrderStatusConfirmed, OrderStatusCancelled},
 		OrderStatusConfirmed:  {OrderStatusProcessing, OrderStatusCancelled},
 		OrderStatusProcessing: {OrderStatusShipped, OrderStatusCancelled},
 		OrderStatusShipped:    {OrderStatusDelivered, OrderStatusCancelled},
-		OrderStatusDelivered:  {OrderStatusRefunded},
+		OrderStatusDelivered:  {},
 		OrderStatusCancelled:  {OrderStatusRefunded},
 		OrderStatusRefunded:   {},
 	}
@@ -110,20 +259,298 @@ func (o *Order) CanTransitionTo(newStatus OrderStatus) error {
 	return errors.New("invalid status transition from " + string(o.Status) + " to " + string(newStatus))
 }
 
-// UpdateStatus updates the order status with validation
-func (o *Order) UpdateStatus(newStatus OrderStatus) error {
+// UpdateStatus updates the order status with validation and records who made
+// the transition and why
+func (o *Order) UpdateStatus(newStatus OrderStatus, changedBy, reason string) error {
 	if err := o.CanTransitionTo(newStatus); err != nil {
 		return err
 	}
 
+	o.setStatus(newStatus, changedBy, reason)
+	return nil
+}
+
+// setStatus moves the order to a status already validated by the caller and
+// records the transition
+func (o *Order) setStatus(newStatus OrderStatus, changedBy, reason string) {
+	previous := o.Status
 	o.Status = newStatus
 	o.UpdatedAt = time.Now()
-	return nil
+	if newStatus == OrderStatusShipped {
+		o.fulfilPendingItems()
+	}
+	o.recordStatusChange(previous, newStatus, changedBy, reason)
+	if newStatus == OrderStatusCancelled {
+		o.recordEvent(EventTypeOrderCancelled)
+	} else {
+		o.recordEvent(EventTypeOrderUpdated)
+	}
 }
 
 // Cancel cancels the order
-func (o *Order) Cancel() error {
-	return o.UpdateStatus(OrderStatusCancelled)
+func (o *Order) Cancel(changedBy, reason string) error {
+	return o.UpdateStatus(OrderStatusCancelled, changedBy, reason)
+}
+
+// CancelPaid cancels an order whose payment was collected. Every line still
+// active is cancelled and owes its share of the total as a pending refund, so
+// everything the customer is still charged is refunded. Returns the amount owed.
+func (o *Order) CancelPaid(changedBy, reason string) (int64, error) {
+	if err := o.CanTransitionTo(OrderStatusCancelled); err != nil {
+		return 0, err
+	}
+
+	var refund int64
+	for i := range o.Items {
+		if o.Items[i].IsActive() {
+			refund += o.removeItem(&o.Items[i], OrderItemStatusCancelled)
+		}
+	}
+	if err := o.Cancel(changedBy, reason); err != nil {
+		return 0, err
+	}
+	return refund, nil
+}
+
+// ConfirmPayment confirms a PENDING order once its payment has been collected
+// and records the payment status
+func (o *Order) ConfirmPayment(payment Payment, changedBy string) error {
+	if !payment.IsCollected() {
+		return fmt.Errorf("payment %s of order %s is %s, not collected", payment.ID, o.ID, payment.Status)
+	}
+	if err := o.UpdateStatus(OrderStatusConfirmed, changedBy, "payment "+payment.ID+" collected"); err != nil {
+		return err
+	}
+	o.PaymentStatus = payment.Status
+	return nil
+}
+
+// NeedsStockCommit reports whether the order was confirmed but its stock
+// reservation not committed yet. Until it is, inventory expires the
+// reservation and puts the stock back on sale.
+func (o *Order) NeedsStockCommit() bool {
+	if o.StockCommittedAt != nil {
+		return false
+	}
+	switch o.Status {
+	case OrderStatusConfirmed, OrderStatusProcessing, OrderStatusShipped, OrderStatusDelivered:
+		return true
+	}
+	return false
+}
+
+// MarkStockCommitted records that inventory committed the order's stock reservation
+func (o *Order) MarkStockCommitted(at time.Time) {
+	o.StockCommittedAt = &at
+	o.UpdatedAt = time.Now()
+}
+
+// CancelItem cancels a single line that has not shipped yet and returns the
+// amount by which the order total dropped. Cancelling the last active line
+// cancels the whole order.
+func (o *Order) CancelItem(itemID, changedBy, reason string) (*OrderItem, int64, error) {
+	item, err := o.FindItem(itemID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if item.Status != OrderItemStatusPending {
+		return nil, 0, fmt.Errorf("%w: item is %s", ErrItemNotCancellable, item.Status)
+	}
+	if o.Status != OrderStatusPending && o.Status != OrderStatusConfirmed && o.Status != OrderStatusProcessing {
+		return nil, 0, fmt.Errorf("%w: order is %s", ErrItemNotCancellable, o.Status)
+	}
+
+	refund := o.removeItem(item, OrderItemStatusCancelled)
+	if !o.hasActiveItems() {
+		if err := o.Cancel(changedBy, reason); err != nil {
+			return nil, 0, err
+		}
+		return item, refund, nil
+	}
+
+	o.recordEvent(EventTypeOrderUpdated)
+	return item, refund, nil
+}
+
+// ReturnItem marks a fulfilled line as returned and returns the amount by which
+// the order total dropped. Shipping is not refunded on returns.
+func (o *Order) ReturnItem(itemID, changedBy, reason string) (*OrderItem, int64, error) {
+	item, err := o.FindItem(itemID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if item.Status != OrderItemStatusFulfilled {
+		return nil, 0, fmt.Errorf("%w: item is %s", ErrItemNotReturnable, item.Status)
+	}
+
+	// Returning the last line refunds the order, which the generic status
+	// transitions do not allow
+	refund := o.removeItem(item, OrderItemStatusReturned)
+	if !o.hasActiveItems() && o.Status == OrderStatusDelivered {
+		o.setStatus(OrderStatusRefunded, changedBy, reason)
+		return item, refund, nil
+	}
+
+	o.recordEvent(EventTypeOrderUpdated)
+	return item, refund, nil
+}
+
+// PendingRefunds returns the cancelled and returned lines owed a refund not issued yet
+func (o *Order) PendingRefunds() []*OrderItem {
+	var items []*OrderItem
+	for i := range o.Items {
+		if o.Items[i].HasPendingRefund() {
+			items = append(items, &o.Items[i])
+		}
+	}
+	return items
+}
+
+// RecordItemRefund settles the pending refund of a line and adds the amount in
+// cents refunded to the customer, which is zero when the payment was never
+// captured and there was nothing to refund
+func (o *Order) RecordItemRefund(itemID string, amount int64) error {
+	item, err := o.FindItem(itemID)
+	if err != nil {
+		return err
+	}
+	if !item.HasPendingRefund() {
+		return fmt.Errorf("item %s has no pending refund", itemID)
+	}
+
+	now := time.Now()
+	item.RefundedAt = &now
+	item.UpdatedAt = now
+	o.RefundedAmount += amount
+	o.UpdatedAt = now
+	o.recordEvent(EventTypeOrderUpdated)
+	return nil
+}
+
+// removeItem takes a line out of the order totals and returns how much the
+// total dropped, which becomes the line's pending refund. The coupon discount
+// shrinks in proportion to the remaining subtotal; shipping is only dropped
+// when a cancellation leaves nothing to ship.
+func (o *Order) removeItem(item *OrderItem, status OrderItemStatus) int64 {
+	previousTotal := o.TotalAmount
+	previousSubtotal := o.Subtotal
+
+	item.Status = status
+	item.UpdatedAt = time.Now()
+	o.UpdatedAt = item.UpdatedAt
+
+	if previousSubtotal > 0 {
+		remaining := previousSubtotal - item.Subtotal
+		o.DiscountAmount = prorate(o.DiscountAmount, remaining, previousSubtotal)
+	}
+	if status == OrderItemStatusCancelled && !o.hasActiveItems() {
+		o.ShippingAmount = 0
+	}
+	o.recalculateTotals()
+
+	item.RefundAmount = previousTotal - o.TotalAmount
+	return item.RefundAmount
+}
+
+// fulfilPendingItems marks every pending line as fulfilled once the order ships
+func (o *Order) fulfilPendingItems() {
+	for i := range o.Items {
+		if o.Items[i].Status == OrderItemStatusPending {
+			o.Items[i].Status = OrderItemStatusFulfilled
+			o.Items[i].UpdatedAt = o.UpdatedAt
+		}
+	}
+}
+
+// hasActiveItems reports whether any line still counts towards the order
+func (o *Order) hasActiveItems() bool {
+	for _, item := range o.Items {
+		if item.IsActive() {
+			return true
+		}
+	}
+	return false
+}
+
+// FindItem returns the order line with the given ID
+func (o *Order) FindItem(itemID string) (*OrderItem, error) {
+	for i := range o.Items {
+		if o.Items[i].ID == itemID {
+			return &o.Items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrOrderItemNotFound, itemID)
+}
+
+// recordStatusChange queues a transition to be written with the order
+func (o *Order) recordStatusChange(from, to OrderStatus, changedBy, reason string) {
+	if changedBy == "" {
+		changedBy = SystemActor
+	}
+	o.statusChanges = append(o.statusChanges, StatusChange{
+		OrderID:    o.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		ChangedBy:  changedBy,
+		Reason:     reason,
+		CreatedAt:  o.UpdatedAt,
+	})
+}
+
+// PendingStatusChanges returns the transitions not yet persisted
+func (o *Order) PendingStatusChanges() []StatusChange {
+	return o.statusChanges
+}
+
+// recordEvent queues a domain event to be written to the outbox with the order
+func (o *Order) recordEvent(eventType EventType) {
+	o.events = append(o.events, eventType)
+}
+
+// PendingEvents returns the domain events not yet written to the outbox
+func (o *Order) PendingEvents() []EventType {
+	return o.events
+}
+
+// SetIdempotencyKey sets the client key the order is placed with. The key is
+// completed in the same write that creates the order.
+func (o *Order) SetIdempotencyKey(key string) {
+	o.idempotencyKey = key
+}
+
+// IdempotencyKey returns the client key the order is placed with, if any
+func (o *Order) IdempotencyKey() string {
+	return o.idempotencyKey
+}
+
+// MarkPersisted clears the status changes and events once they have been saved
+func (o *Order) MarkPersisted() {
+	o.statusChanges = nil
+	o.events = nil
+}
+
+// FlagDisputed marks the order as disputed once the customer's bank opens a
+// dispute against its payment; its payment status becomes DISPUTED, so such
+// orders can be found with the payment status search filter. Flagging it
+// again for the same dispute does nothing.
+//
+// Returns:
+//   - bool: false if the order was already flagged for the dispute
+//   - error: ErrPaymentMismatch if the payment is not the order's payment
+func (o *Order) FlagDisputed(paymentID, disputeID string, at time.Time) (bool, error) {
+	if o.PaymentID != "" && paymentID != o.PaymentID {
+		return false, fmt.Errorf("%w: order %s is paid with %s, not %s", ErrPaymentMismatch, o.ID, o.PaymentID, paymentID)
+	}
+	if o.DisputeID == disputeID {
+		return false, nil
+	}
+
+	o.DisputeID = disputeID
+	o.DisputedAt = &at
+	o.PaymentStatus = PaymentStatusDisputed
+	o.UpdatedAt = time.Now()
+	o.recordEvent(EventTypeOrderUpdated)
+	return true, nil
 }
 
 // SetTrackingNumber sets the tracking number for shipped orders
@@ -134,5 +561,6 @@ func (o *Order) SetTrackingNumber(trackingNumber string) error {
 
 	o.TrackingNumber = trackingNumber
 	o.UpdatedAt = time.Now()
+	o.recordEvent(EventTypeOrderUpdated)
 	return nil
 }
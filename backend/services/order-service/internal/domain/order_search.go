@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultSearchLimit is the page size used when none is given
+	DefaultSearchLimit = 20
+	// MaxSearchLimit caps the page size of an order search
+	MaxSearchLimit = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+	ErrInvalidFilter = errors.New("invalid order filter")
+)
+
+// OrderFilter narrows an admin search across orders. Zero values match everything.
+type OrderFilter struct {
+	UserID        string
+	Statuses      []OrderStatus
+	CreatedFrom   *time.Time // Inclusive
+	CreatedTo     *time.Time // Exclusive
+	MinTotal      *int64     // in cents
+	MaxTotal      *int64     // in cents
+	PaymentStatus string
+	ProductID     string
+}
+
+// Validate checks that the ranges of the filter are consistent
+func (f OrderFilter) Validate() error {
+	if f.CreatedFrom != nil && f.CreatedTo != nil && !f.CreatedFrom.Before(*f.CreatedTo) {
+		return fmt.Errorf("%w: from date must be before to date", ErrInvalidFilter)
+	}
+	if f.MinTotal != nil && f.MaxTotal != nil && *f.MinTotal > *f.MaxTotal {
+		return fmt.Errorf("%w: min total exceeds max total", ErrInvalidFilter)
+	}
+	return nil
+}
+
+// OrderCursor is the keyset position of the last order of a page. Orders are
+// listed newest first by (created_at, id), so the next page holds the orders
+// strictly before this position.
+type OrderCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// CursorAfter returns the cursor pointing past the given order
+func CursorAfter(order *Order) OrderCursor {
+	return OrderCursor{CreatedAt: order.CreatedAt, ID: order.ID}
+}
+
+// Encode returns the opaque token handed to clients
+func (c OrderCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeOrderCursor parses a token produced by Encode
+func DecodeOrderCursor(token string) (*OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return nil, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &OrderCursor{CreatedAt: t, ID: id}, nil
+}
@@ -0,0 +1,235 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestOrder places an order of 10.00 + 20.00 + 2 × 30.00 with a 9.00 coupon,
+// 5.00 shipping and 10% tax: 81.00 + 5.00 + 8.10 = 94.10
+func newTestOrder(t *testing.T) *Order {
+	t.Helper()
+
+	order, err := NewOrder("user-1", Address{AddressLine1: "1 Main St"}, Address{}, "CARD", []OrderItem{
+		{ID: "item-1", ProductID: "p1", Quantity: 1, Price: 1000},
+		{ID: "item-2", ProductID: "p2", Quantity: 1, Price: 2000},
+		{ID: "item-3", ProductID: "p3", Quantity: 2, Price: 3000},
+	})
+	if err != nil {
+		t.Fatalf("NewOrder: %v", err)
+	}
+	coupon := &Coupon{Code: "SAVE9", Type: CouponTypeFixedAmount, Value: 900, Active: true}
+	if err := order.ApplyCoupon(coupon, time.Now()); err != nil {
+		t.Fatalf("ApplyCoupon: %v", err)
+	}
+	order.ApplyPricing(PricingPolicy{ShippingFee: 500, TaxRate: 0.1})
+	if order.TotalAmount != 9410 {
+		t.Fatalf("TotalAmount = %d, want 9410", order.TotalAmount)
+	}
+	return order
+}
+
+func TestOrderCancelItem(t *testing.T) {
+	tests := []struct {
+		name         string
+		setup        func(o *Order)
+		cancel       []string // Items cancelled before the one under test
+		itemID       string
+		wantErr      error
+		wantRefund   int64
+		wantTotal    int64
+		wantDiscount int64
+		wantShipping int64
+		wantStatus   OrderStatus
+	}{
+		{
+			// Discount 9.00 × 80/90 = 8.00; total 72.00 + 5.00 + 7.20
+			name:         "pending line refunds its share of discount and tax",
+			itemID:       "item-1",
+			wantRefund:   990,
+			wantTotal:    8420,
+			wantDiscount: 800,
+			wantShipping: 500,
+			wantStatus:   OrderStatusPending,
+		},
+		{
+			// Discount 9.00 × 30/90 = 3.00; total 27.00 + 5.00 + 2.70
+			name:         "line of a confirmed order",
+			setup:        func(o *Order) { o.Status = OrderStatusConfirmed },
+			itemID:       "item-3",
+			wantRefund:   5940,
+			wantTotal:    3470,
+			wantDiscount: 300,
+			wantShipping: 500,
+			wantStatus:   OrderStatusConfirmed,
+		},
+		{
+			name:         "last active line cancels the order and refunds shipping",
+			cancel:       []string{"item-1", "item-2"},
+			itemID:       "item-3",
+			wantRefund:   6440, // 54.00 + 5.40 tax + 5.00 shipping
+			wantTotal:    0,
+			wantDiscount: 0,
+			wantShipping: 0,
+			wantStatus:   OrderStatusCancelled,
+		},
+		{
+			name:    "unknown line",
+			itemID:  "item-9",
+			wantErr: ErrOrderItemNotFound,
+		},
+		{
+			name:    "line already cancelled",
+			cancel:  []string{"item-1"},
+			itemID:  "item-1",
+			wantErr: ErrItemNotCancellable,
+		},
+		{
+			name: "line fulfilled when the order shipped",
+			setup: func(o *Order) {
+				o.Status = OrderStatusProcessing
+				if err := o.UpdateStatus(OrderStatusShipped, "admin", "handed to carrier"); err != nil {
+					t.Fatalf("UpdateStatus: %v", err)
+				}
+			},
+			itemID:  "item-1",
+			wantErr: ErrItemNotCancellable,
+		},
+		{
+			name:    "pending line of a shipped order",
+			setup:   func(o *Order) { o.Status = OrderStatusShipped },
+			itemID:  "item-1",
+			wantErr: ErrItemNotCancellable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := newTestOrder(t)
+			if tt.setup != nil {
+				tt.setup(order)
+			}
+			for _, itemID := range tt.cancel {
+				if _, _, err := order.CancelItem(itemID, "user-1", "changed my mind"); err != nil {
+					t.Fatalf("CancelItem(%s): %v", itemID, err)
+				}
+			}
+			before := order.TotalAmount
+
+			item, refund, err := order.CancelItem(tt.itemID, "user-1", "changed my mind")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if order.TotalAmount != before {
+					t.Errorf("TotalAmount changed from %d to %d on a rejected cancellation", before, order.TotalAmount)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CancelItem: %v", err)
+			}
+
+			if refund != tt.wantRefund {
+				t.Errorf("refund = %d, want %d", refund, tt.wantRefund)
+			}
+			if refund != before-order.TotalAmount {
+				t.Errorf("refund = %d, but the total dropped by %d", refund, before-order.TotalAmount)
+			}
+			if item.Status != OrderItemStatusCancelled || item.RefundAmount != refund || !item.HasPendingRefund() {
+				t.Errorf("item is %s with refund %d pending %v, want CANCELLED with refund %d pending",
+					item.Status, item.RefundAmount, item.HasPendingRefund(), refund)
+			}
+			if order.TotalAmount != tt.wantTotal {
+				t.Errorf("TotalAmount = %d, want %d", order.TotalAmount, tt.wantTotal)
+			}
+			if order.DiscountAmount != tt.wantDiscount {
+				t.Errorf("DiscountAmount = %d, want %d", order.DiscountAmount, tt.wantDiscount)
+			}
+			if order.ShippingAmount != tt.wantShipping {
+				t.Errorf("ShippingAmount = %d, want %d", order.ShippingAmount, tt.wantShipping)
+			}
+			if order.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", order.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestOrderCancelPaid(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     OrderStatus
+		cancel     []string // Items cancelled before the order
+		wantErr    bool
+		wantRefund int64
+	}{
+		{name: "whole total is owed back", status: OrderStatusConfirmed, wantRefund: 9410},
+		{name: "cancelled lines are not refunded twice", status: OrderStatusConfirmed, cancel: []string{"item-1"}, wantRefund: 9410 - 990},
+		{name: "delivered order cannot be cancelled", status: OrderStatusDelivered, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := newTestOrder(t)
+			order.Status = tt.status
+			for _, itemID := range tt.cancel {
+				if _, _, err := order.CancelItem(itemID, "user-1", "changed my mind"); err != nil {
+					t.Fatalf("CancelItem(%s): %v", itemID, err)
+				}
+			}
+
+			refund, err := order.CancelPaid("user-1", "changed my mind")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("CancelPaid succeeded, want an error")
+				}
+				for _, item := range order.Items {
+					if item.Status != OrderItemStatusPending {
+						t.Errorf("item %s is %s after a rejected cancellation", item.ID, item.Status)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CancelPaid: %v", err)
+			}
+
+			if refund != tt.wantRefund {
+				t.Errorf("refund = %d, want %d", refund, tt.wantRefund)
+			}
+			var pending int64
+			for _, item := range order.PendingRefunds() {
+				pending += item.RefundAmount
+			}
+			if pending != 9410 {
+				t.Errorf("pending refunds add up to %d, want the paid total 9410", pending)
+			}
+			if order.TotalAmount != 0 || order.Status != OrderStatusCancelled {
+				t.Errorf("order is %s with total %d, want CANCELLED with total 0", order.Status, order.TotalAmount)
+			}
+		})
+	}
+}
+
+// A delivered order only becomes REFUNDED by returning its lines
+func TestOrderRefundedOnlyByReturns(t *testing.T) {
+	order := newTestOrder(t)
+	order.Status = OrderStatusShipped
+	order.fulfilPendingItems()
+	order.Status = OrderStatusDelivered
+
+	if err := order.UpdateStatus(OrderStatusRefunded, "admin", "refund"); err == nil {
+		t.Fatal("UpdateStatus refunded a delivered order, want an error")
+	}
+
+	for _, itemID := range []string{"item-1", "item-2", "item-3"} {
+		if _, _, err := order.ReturnItem(itemID, "admin", "damaged"); err != nil {
+			t.Fatalf("ReturnItem(%s): %v", itemID, err)
+		}
+	}
+	if order.Status != OrderStatusRefunded {
+		t.Errorf("Status = %s after returning every line, want %s", order.Status, OrderStatusRefunded)
+	}
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "errors"
+
+// Payment statuses as reported by payment-service
+const (
+	PaymentStatusPending           = "PENDING"
+	PaymentStatusProcessing        = "PROCESSING"
+	PaymentStatusSucceeded         = "SUCCEEDED"
+	PaymentStatusFailed            = "FAILED"
+	PaymentStatusCancelled         = "CANCELLED"
+	PaymentStatusRefunded          = "REFUNDED"
+	PaymentStatusPartiallyRefunded = "PARTIALLY_REFUNDED"
+	PaymentStatusAuthorized        = "AUTHORIZED"
+	PaymentStatusCaptured          = "CAPTURED"
+)
+
+// ErrPaymentNotFound is returned when an order has no payment in payment-service
+var ErrPaymentNotFound = errors.New("payment not found")
+
+// Payment is the payment-service state of an order's payment
+type Payment struct {
+	ID     string
+	Status string
+}
+
+// IsClosed reports whether the payment failed or was cancelled, so there is
+// nothing left to cancel and nothing was collected
+func (p Payment) IsClosed() bool {
+	return p.Status == PaymentStatusFailed || p.Status == PaymentStatusCancelled
+}
+
+// IsCollected reports whether the customer's funds were taken, or are held
+// for capture
+func (p Payment) IsCollected() bool {
+	switch p.Status {
+	case PaymentStatusSucceeded, PaymentStatusAuthorized, PaymentStatusCaptured,
+		PaymentStatusRefunded, PaymentStatusPartiallyRefunded:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,17 @@
+package domain
+
+// PricingPolicy holds the shipping and tax rules applied when an order is placed
+type PricingPolicy struct {
+	Currency              string
+	ShippingFee           int64   // in cents
+	FreeShippingThreshold int64   // in cents; orders above this amount ship free, 0 disables
+	TaxRate               float64 // Fraction of the discounted subtotal, e.g. 0.08
+}
+
+// ShippingFor returns the shipping charge in cents for a discounted subtotal
+func (p PricingPolicy) ShippingFor(amount int64) int64 {
+	if p.FreeShippingThreshold > 0 && amount > p.FreeShippingThreshold {
+		return 0
+	}
+	return p.ShippingFee
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ProductStatus mirrors the lifecycle status of a product in product-service
+type ProductStatus string
+
+const (
+	ProductStatusDraft        ProductStatus = "DRAFT"
+	ProductStatusActive       ProductStatus = "ACTIVE"
+	ProductStatusInactive     ProductStatus = "INACTIVE"
+	ProductStatusOutOfStock   ProductStatus = "OUT_OF_STOCK"
+	ProductStatusDiscontinued ProductStatus = "DISCONTINUED"
+)
+
+var (
+	ErrProductUnavailable = errors.New("product is not available for purchase")
+	ErrVariantNotFound    = errors.New("product variant not found")
+)
+
+// ProductVariant is the catalog data for a purchasable variant of a product
+type ProductVariant struct {
+	ID    string
+	Name  string
+	SKU   string
+	Price int64 // in cents
+}
+
+// Product is the catalog data order-service needs to price an order item
+type Product struct {
+	ID       string
+	Name     string
+	SKU      string
+	Price    int64 // in cents
+	Status   ProductStatus
+	Variants []ProductVariant
+}
+
+// IsPurchasable reports whether new orders may be placed for the product.
+// Out-of-stock products are left to inventory-service to reject.
+func (p *Product) IsPurchasable() bool {
+	return p.Status == ProductStatusActive || p.Status == ProductStatusOutOfStock
+}
+
+// FindVariant returns the variant with the given ID
+func (p *Product) FindVariant(variantID string) (*ProductVariant, error) {
+	for i := range p.Variants {
+		if p.Variants[i].ID == variantID {
+			return &p.Variants[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrVariantNotFound, variantID)
+}
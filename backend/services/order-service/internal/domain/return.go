@@ -0,0 +1,207 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ReturnStatus represents the state of a return merchandise authorization
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "REQUESTED"
+	ReturnStatusApproved  ReturnStatus = "APPROVED"
+	ReturnStatusRejected  ReturnStatus = "REJECTED"
+	ReturnStatusReceived  ReturnStatus = "RECEIVED"
+	ReturnStatusRefunded  ReturnStatus = "REFUNDED"
+)
+
+var (
+	ErrReturnNotFound          = errors.New("return not found")
+	ErrReturnNotAllowed        = errors.New("items cannot be returned")
+	ErrInvalidReturnTransition = errors.New("invalid return status transition")
+)
+
+// OrderReturnItem is an order line included in a return
+type OrderReturnItem struct {
+	ID          string
+	ReturnID    string
+	OrderItemID string
+	ProductID   string
+	VariantID   string
+	Quantity    int32
+	Restocked   bool
+}
+
+// OrderReturn is a customer's request to send back delivered items, tracked
+// from authorization through receipt to refund
+type OrderReturn struct {
+	ID              string
+	OrderID         string
+	UserID          string
+	Status          ReturnStatus
+	Reason          string
+	RejectionReason string
+	ReviewedBy      string
+	ReceivedBy      string
+	Currency        string
+	RefundAmount    int64 // in cents of Currency
+	Items           []OrderReturnItem
+	ReviewedAt      *time.Time
+	ReceivedAt      *time.Time
+	RefundedAt      *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// NewOrderReturn requests a return of the given lines of a delivered order
+func NewOrderReturn(order *Order, userID string, itemIDs []string, reason string) (*OrderReturn, error) {
+	if order.UserID != userID {
+		return nil, fmt.Errorf("%w: order belongs to another user", ErrReturnNotAllowed)
+	}
+	if order.Status != OrderStatusDelivered {
+		return nil, fmt.Errorf("%w: order is %s", ErrReturnNotAllowed, order.Status)
+	}
+	if len(itemIDs) == 0 {
+		return nil, fmt.Errorf("%w: no items selected", ErrReturnNotAllowed)
+	}
+	if reason == "" {
+		return nil, errors.New("return reason is required")
+	}
+
+	seen := make(map[string]bool, len(itemIDs))
+	items := make([]OrderReturnItem, 0, len(itemIDs))
+	for _, itemID := range itemIDs {
+		if seen[itemID] {
+			continue
+		}
+		seen[itemID] = true
+
+		item, err := order.FindItem(itemID)
+		if err != nil {
+			return nil, err
+		}
+		if item.Status != OrderItemStatusFulfilled {
+			return nil, fmt.Errorf("%w: item %s is %s", ErrReturnNotAllowed, itemID, item.Status)
+		}
+		items = append(items, OrderReturnItem{
+			OrderItemID: item.ID,
+			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			Quantity:    item.Quantity,
+		})
+	}
+
+	now := time.Now()
+	return &OrderReturn{
+		OrderID:   order.ID,
+		UserID:    userID,
+		Status:    ReturnStatusRequested,
+		Reason:    reason,
+		Currency:  order.Currency,
+		Items:     items,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// IsOpen reports whether the return still holds its items
+func (r *OrderReturn) IsOpen() bool {
+	return r.Status != ReturnStatusRejected && r.Status != ReturnStatusRefunded
+}
+
+// Approve authorizes the customer to send the items back
+func (r *OrderReturn) Approve(approvedBy string) error {
+	if err := r.transitionTo(ReturnStatusApproved); err != nil {
+		return err
+	}
+	r.ReviewedBy = approvedBy
+	now := r.UpdatedAt
+	r.ReviewedAt = &now
+	return nil
+}
+
+// Reject declines the return with the given reason
+func (r *OrderReturn) Reject(rejectedBy, reason string) error {
+	if reason == "" {
+		return errors.New("rejection reason is required")
+	}
+	if err := r.transitionTo(ReturnStatusRejected); err != nil {
+		return err
+	}
+	r.ReviewedBy = rejectedBy
+	r.RejectionReason = reason
+	now := r.UpdatedAt
+	r.ReviewedAt = &now
+	return nil
+}
+
+// MarkReceived records that the returned items arrived at the warehouse
+func (r *OrderReturn) MarkReceived(receivedBy string) error {
+	if err := r.transitionTo(ReturnStatusReceived); err != nil {
+		return err
+	}
+	r.ReceivedBy = receivedBy
+	now := r.UpdatedAt
+	r.ReceivedAt = &now
+	return nil
+}
+
+// IsRestocked reports whether every returned item has been added back to stock
+func (r *OrderReturn) IsRestocked() bool {
+	for _, item := range r.Items {
+		if !item.Restocked {
+			return false
+		}
+	}
+	return true
+}
+
+// MarkRefunded closes the return once the refunds of its lines on the order
+// have been issued, and records their total as the refund amount
+func (r *OrderReturn) MarkRefunded(order *Order) error {
+	if !r.IsRestocked() {
+		return fmt.Errorf("%w: items have not been restocked", ErrInvalidReturnTransition)
+	}
+
+	var refund int64
+	for _, returnItem := range r.Items {
+		item, err := order.FindItem(returnItem.OrderItemID)
+		if err != nil {
+			return err
+		}
+		if item.Status != OrderItemStatusReturned || item.HasPendingRefund() {
+			return fmt.Errorf("%w: item %s has not been refunded", ErrInvalidReturnTransition, item.ID)
+		}
+		refund += item.RefundAmount
+	}
+
+	if err := r.transitionTo(ReturnStatusRefunded); err != nil {
+		return err
+	}
+	r.RefundAmount = refund
+	now := r.UpdatedAt
+	r.RefundedAt = &now
+	return nil
+}
+
+// transitionTo moves the return to a new status if the transition is allowed
+func (r *OrderReturn) transitionTo(status ReturnStatus) error {
+	validTransitions := map[ReturnStatus][]ReturnStatus{
+		ReturnStatusRequested: {ReturnStatusApproved, ReturnStatusRejected},
+		ReturnStatusApproved:  {ReturnStatusReceived},
+		ReturnStatusReceived:  {ReturnStatusRefunded},
+		ReturnStatusRejected:  {},
+		ReturnStatusRefunded:  {},
+	}
+
+	for _, allowed := range validTransitions[r.Status] {
+		if allowed == status {
+			r.Status = status
+			r.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: from %s to %s", ErrInvalidReturnTransition, r.Status, status)
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// SagaState represents the progress of a checkout saga
+type SagaState string
+
+const (
+	SagaStateStarted         SagaState = "STARTED"
+	SagaStateReservingStock  SagaState = "RESERVING_STOCK"
+	SagaStateStockReserved   SagaState = "STOCK_RESERVED"
+	SagaStateCreatingPayment SagaState = "CREATING_PAYMENT"
+	SagaStatePaymentCreated  SagaState = "PAYMENT_CREATED"
+	SagaStateCompleted       SagaState = "COMPLETED"
+	SagaStateCompensating    SagaState = "COMPENSATING"
+	SagaStateCompensated     SagaState = "COMPENSATED"
+)
+
+var (
+	ErrSagaNotFound      = errors.New("checkout saga not found")
+	ErrStockUnavailable  = errors.New("insufficient stock for order")
+	ErrProductNotFound   = errors.New("product not found")
+	ErrPaymentNotCreated = errors.New("payment intent could not be created")
+	// ErrPaymentNotCancellable is returned when the payment already went through
+	ErrPaymentNotCancellable = errors.New("payment can no longer be cancelled")
+	// ErrInvalidPaymentMethod is returned for payment methods payment-service does not support
+	ErrInvalidPaymentMethod = errors.New("invalid payment method")
+)
+
+// CheckoutSaga tracks the distributed steps taken while placing an order,
+// so that a crashed instance can resume or compensate them on restart.
+type CheckoutSaga struct {
+	ID              string
+	OrderID         string
+	State           SagaState
+	ReservationID   string
+	PaymentIntentID string
+	FailureReason   string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// NewCheckoutSaga creates a new saga for the given order
+func NewCheckoutSaga(orderID string) (*CheckoutSaga, error) {
+	if orderID == "" {
+		return nil, errors.New("order ID is required")
+	}
+
+	now := time.Now()
+	return &CheckoutSaga{
+		OrderID:   orderID,
+		State:     SagaStateStarted,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// HasForwardStepsDone reports whether every forward step has succeeded
+func (s *CheckoutSaga) HasForwardStepsDone() bool {
+	return s.State == SagaStatePaymentCreated || s.State == SagaStateCompleted
+}
+
+// Advance moves the saga to the next state
+func (s *CheckoutSaga) Advance(state SagaState) {
+	s.State = state
+	s.UpdatedAt = time.Now()
+}
+
+// RecordReservation stores the inventory reservation created for the order
+func (s *CheckoutSaga) RecordReservation(reservationID string) {
+	s.ReservationID = reservationID
+	s.Advance(SagaStateStockReserved)
+}
+
+// RecordPaymentIntent stores the payment intent created for the order
+func (s *CheckoutSaga) RecordPaymentIntent(paymentIntentID string) {
+	s.PaymentIntentID = paymentIntentID
+	s.Advance(SagaStatePaymentCreated)
+}
+
+// StartCompensation marks the saga as rolling back with the given reason
+func (s *CheckoutSaga) StartCompensation(reason string) {
+	if s.FailureReason == "" {
+		s.FailureReason = reason
+	}
+	s.Advance(SagaStateCompensating)
+}
@@ -0,0 +1,241 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ShipmentStatus represents the carrier-reported state of a shipment
+type ShipmentStatus string
+
+const (
+	ShipmentStatusLabelCreated   ShipmentStatus = "LABEL_CREATED"
+	ShipmentStatusInTransit      ShipmentStatus = "IN_TRANSIT"
+	ShipmentStatusOutForDelivery ShipmentStatus = "OUT_FOR_DELIVERY"
+	ShipmentStatusDelivered      ShipmentStatus = "DELIVERED"
+	ShipmentStatusException      ShipmentStatus = "EXCEPTION"
+)
+
+var (
+	ErrShipmentNotFound     = errors.New("shipment not found")
+	ErrShipmentNotAllowed   = errors.New("shipment cannot be created")
+	ErrInvalidTrackingEvent = errors.New("invalid tracking event")
+)
+
+// ShipmentItem is the quantity of an order line packed in a shipment
+type ShipmentItem struct {
+	ID          string
+	ShipmentID  string
+	OrderItemID string
+	Quantity    int32
+}
+
+// Shipment is one parcel of an order handed to a carrier. An order can be
+// split across several shipments.
+type Shipment struct {
+	ID             string
+	OrderID        string
+	Carrier        string
+	TrackingNumber string
+	Status         ShipmentStatus
+	Items          []ShipmentItem
+	ShippedAt      *time.Time
+	DeliveredAt    *time.Time
+	LastEventAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TrackingEvent is a status update reported by a carrier for a tracking number
+type TrackingEvent struct {
+	ID             string
+	ShipmentID     string
+	Carrier        string
+	TrackingNumber string
+	Status         ShipmentStatus
+	Location       string
+	Description    string
+	OccurredAt     time.Time
+	CreatedAt      time.Time
+}
+
+// NormalizeCarrier returns the canonical form carrier codes are stored in
+func NormalizeCarrier(carrier string) string {
+	return strings.ToUpper(strings.TrimSpace(carrier))
+}
+
+// NewShipment creates a shipment for lines of the order. The quantities must
+// not exceed what is left unpacked after the existing shipments.
+func NewShipment(order *Order, existing []*Shipment, carrier, trackingNumber string, items []ShipmentItem) (*Shipment, error) {
+	carrier = NormalizeCarrier(carrier)
+	trackingNumber = strings.TrimSpace(trackingNumber)
+	if carrier == "" || trackingNumber == "" {
+		return nil, errors.New("carrier and tracking number are required")
+	}
+	if order.Status != OrderStatusConfirmed && order.Status != OrderStatusProcessing && order.Status != OrderStatusShipped {
+		return nil, fmt.Errorf("%w: order is %s", ErrShipmentNotAllowed, order.Status)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no items selected", ErrShipmentNotAllowed)
+	}
+
+	packed := packedQuantities(existing)
+	merged := make([]ShipmentItem, 0, len(items))
+	index := make(map[string]int, len(items))
+	for _, requested := range items {
+		if requested.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity must be positive", ErrShipmentNotAllowed)
+		}
+		item, err := order.FindItem(requested.OrderItemID)
+		if err != nil {
+			return nil, err
+		}
+		if !item.IsActive() {
+			return nil, fmt.Errorf("%w: item %s is %s", ErrShipmentNotAllowed, item.ID, item.Status)
+		}
+
+		packed[item.ID] += requested.Quantity
+		if packed[item.ID] > item.Quantity {
+			return nil, fmt.Errorf("%w: item %s only has %d units left to ship", ErrShipmentNotAllowed, item.ID, item.Quantity-(packed[item.ID]-requested.Quantity))
+		}
+
+		if i, ok := index[item.ID]; ok {
+			merged[i].Quantity += requested.Quantity
+			continue
+		}
+		index[item.ID] = len(merged)
+		merged = append(merged, ShipmentItem{OrderItemID: item.ID, Quantity: requested.Quantity})
+	}
+
+	now := time.Now()
+	return &Shipment{
+		OrderID:        order.ID,
+		Carrier:        carrier,
+		TrackingNumber: trackingNumber,
+		Status:         ShipmentStatusLabelCreated,
+		Items:          merged,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// HasShipped reports whether the carrier has taken the parcel and it is on
+// its way or delivered. A parcel in EXCEPTION, e.g. rejected at pickup, has
+// not shipped.
+func (s *Shipment) HasShipped() bool {
+	switch s.Status {
+	case ShipmentStatusInTransit, ShipmentStatusOutForDelivery, ShipmentStatusDelivered:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsDelivered reports whether the parcel reached the customer
+func (s *Shipment) IsDelivered() bool {
+	return s.Status == ShipmentStatusDelivered
+}
+
+// ApplyTrackingEvent updates the shipment from a carrier event and reports
+// whether its status changed. Events older than the latest one applied and
+// events after delivery are ignored, so out-of-order delivery is harmless.
+func (s *Shipment) ApplyTrackingEvent(event TrackingEvent) bool {
+	if s.IsDelivered() {
+		return false
+	}
+	if s.LastEventAt != nil && event.OccurredAt.Before(*s.LastEventAt) {
+		return false
+	}
+
+	occurredAt := event.OccurredAt
+	s.LastEventAt = &occurredAt
+	s.UpdatedAt = time.Now()
+	if s.Status == event.Status {
+		return false
+	}
+
+	s.Status = event.Status
+	if s.HasShipped() && s.ShippedAt == nil {
+		s.ShippedAt = &occurredAt
+	}
+	if s.IsDelivered() {
+		s.DeliveredAt = &occurredAt
+	}
+	return true
+}
+
+// SyncShipments advances the order to SHIPPED once every active line is packed
+// and every shipment has been taken by the carrier, and to DELIVERED once every
+// shipment has been delivered. It reports whether the order status changed.
+func (o *Order) SyncShipments(shipments []*Shipment, changedBy string) (bool, error) {
+	if len(shipments) == 0 || !o.isFullyPacked(shipments) {
+		return false, nil
+	}
+
+	allShipped, allDelivered := true, true
+	for _, shipment := range shipments {
+		allShipped = allShipped && shipment.HasShipped()
+		allDelivered = allDelivered && shipment.IsDelivered()
+	}
+
+	changed := false
+	if allShipped && (o.Status == OrderStatusConfirmed || o.Status == OrderStatusProcessing) {
+		if o.Status == OrderStatusConfirmed {
+			if err := o.UpdateStatus(OrderStatusProcessing, changedBy, "shipments created"); err != nil {
+				return changed, err
+			}
+		}
+		if err := o.UpdateStatus(OrderStatusShipped, changedBy, "all shipments handed to carrier"); err != nil {
+			return changed, err
+		}
+		if o.TrackingNumber == "" {
+			o.TrackingNumber = shipments[0].TrackingNumber
+		}
+		changed = true
+	}
+	if allDelivered && o.Status == OrderStatusShipped {
+		if err := o.UpdateStatus(OrderStatusDelivered, changedBy, "all shipments delivered"); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// isFullyPacked reports whether every active line is covered by the shipments
+func (o *Order) isFullyPacked(shipments []*Shipment) bool {
+	packed := packedQuantities(shipments)
+	for _, item := range o.Items {
+		if item.IsActive() && packed[item.ID] < item.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckItemUnpacked returns ErrItemNotCancellable if units of the order line
+// are packed in one of the shipments; such a line is fulfilled by its shipment
+// and can only be returned
+func CheckItemUnpacked(shipments []*Shipment, itemID string) error {
+	for _, shipment := range shipments {
+		for _, item := range shipment.Items {
+			if item.OrderItemID == itemID {
+				return fmt.Errorf("%w: item is packed in shipment %s", ErrItemNotCancellable, shipment.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// packedQuantities sums the quantity packed per order line across shipments
+func packedQuantities(shipments []*Shipment) map[string]int32 {
+	packed := make(map[string]int32)
+	for _, shipment := range shipments {
+		for _, item := range shipment.Items {
+			packed[item.OrderItemID] += item.Quantity
+		}
+	}
+	return packed
+}
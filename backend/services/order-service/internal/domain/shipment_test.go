@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrderSyncShipments(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []ShipmentStatus // Of the parcels, one per line
+		wantStatus OrderStatus
+	}{
+		{
+			name:       "labels only",
+			statuses:   []ShipmentStatus{ShipmentStatusLabelCreated, ShipmentStatusLabelCreated, ShipmentStatusLabelCreated},
+			wantStatus: OrderStatusConfirmed,
+		},
+		{
+			name:       "every parcel with the carrier",
+			statuses:   []ShipmentStatus{ShipmentStatusInTransit, ShipmentStatusOutForDelivery, ShipmentStatusDelivered},
+			wantStatus: OrderStatusShipped,
+		},
+		{
+			name:       "parcel in exception has not shipped",
+			statuses:   []ShipmentStatus{ShipmentStatusInTransit, ShipmentStatusInTransit, ShipmentStatusException},
+			wantStatus: OrderStatusConfirmed,
+		},
+		{
+			name:       "every parcel delivered",
+			statuses:   []ShipmentStatus{ShipmentStatusDelivered, ShipmentStatusDelivered, ShipmentStatusDelivered},
+			wantStatus: OrderStatusDelivered,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := newTestOrder(t)
+			order.Status = OrderStatusConfirmed
+
+			var shipments []*Shipment
+			for i, item := range order.Items {
+				shipment, err := NewShipment(order, shipments, "ups", "1Z"+item.ID, []ShipmentItem{{OrderItemID: item.ID, Quantity: item.Quantity}})
+				if err != nil {
+					t.Fatalf("NewShipment(%s): %v", item.ID, err)
+				}
+				shipment.ApplyTrackingEvent(TrackingEvent{Status: tt.statuses[i], OccurredAt: time.Now()})
+				shipments = append(shipments, shipment)
+			}
+
+			if _, err := order.SyncShipments(shipments, "carrier"); err != nil {
+				t.Fatalf("SyncShipments: %v", err)
+			}
+			if order.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", order.Status, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// SystemActor identifies transitions made by order-service itself rather than a user
+const SystemActor = "system"
+
+// StatusChange records a single order status transition
+type StatusChange struct {
+	ID         string
+	OrderID    string
+	FromStatus OrderStatus
+	ToStatus   OrderStatus
+	ChangedBy  string
+	Reason     string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// moneyColumns lists the columns that held decimal amounts before order-service
+// switched to integer cents
+var moneyColumns = map[string][]string{
+	"orders":      {"subtotal", "discount_amount", "shipping_amount", "tax_amount", "total_amount", "refunded_amount"},
+	"order_items": {"price", "subtotal"},
+}
+
+// migrateMoneyToCents converts decimal amount columns of existing tables to
+// bigint cents, rounding half away from zero. It must run before AutoMigrate,
+// which would otherwise truncate the values when changing the column type.
+// Columns that are already integers are skipped, so it is safe to rerun.
+func migrateMoneyToCents(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for table, columns := range moneyColumns {
+			for _, column := range columns {
+				var dataType string
+				if err := tx.Raw(
+					`SELECT data_type FROM information_schema.columns
+					 WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?`,
+					table, column,
+				).Scan(&dataType).Error; err != nil {
+					return fmt.Errorf("failed to inspect %s.%s: %w", table, column, err)
+				}
+				if dataType != "double precision" && dataType != "numeric" && dataType != "real" {
+					continue
+				}
+
+				if err := tx.Exec(fmt.Sprintf(
+					`ALTER TABLE %q ALTER COLUMN %q TYPE bigint USING ROUND(%q::numeric * 100)::bigint`,
+					table, column, column,
+				)).Error; err != nil {
+					return fmt.Errorf("failed to convert %s.%s to cents: %w", table, column, err)
+				}
+				// The cents columns are NOT NULL
+				if err := tx.Exec(fmt.Sprintf(`UPDATE %q SET %q = 0 WHERE %q IS NULL`, table, column, column)).Error; err != nil {
+					return fmt.Errorf("failed to backfill %s.%s: %w", table, column, err)
+				}
+				logger.Infof("Converted %s.%s to cents", table, column)
+			}
+		}
+		return nil
+	})
+}
@@ -24,8 +24,30 @@ func NewPostgresDB(databaseURL string) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// Convert legacy decimal amounts before AutoMigrate touches the columns
+	if err := migrateMoneyToCents(db); err != nil {
+		logger.Errorf("Failed to migrate amounts to cents: %v", err)
+		return nil, err
+	}
+
 	// Auto migrate the schema
-	if err := db.AutoMigrate(&models.Order{}, &models.OrderItem{}); err != nil {
+	if err := db.AutoMigrate(
+		&models.Order{},
+		&models.OrderItem{},
+		&models.CheckoutSaga{},
+		&models.OrderStatusHistory{},
+		&models.OutboxEvent{},
+		&models.IdempotencyKey{},
+		&models.Coupon{},
+		&models.CouponRedemption{},
+		&models.OrderReturn{},
+		&models.OrderReturnItem{},
+		&models.Shipment{},
+		&models.ShipmentItem{},
+		&models.ShipmentTrackingEvent{},
+		&models.Invoice{},
+		&models.InvoiceSequence{},
+	); err != nil {
 		logger.Errorf("Failed to migrate database: %v", err)
 		return nil, err
 	}
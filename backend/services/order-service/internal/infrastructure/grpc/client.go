@@ -12,10 +12,11 @@ type ServiceClients struct {
 	ProductClient   pb.ProductServiceClient
 	InventoryClient pb.InventoryServiceClient
 	PaymentClient   pb.PaymentServiceClient
+	UserClient      pb.UserServiceClient
 }
 
 // NewServiceClients creates new gRPC service clients
-func NewServiceClients(productAddr, inventoryAddr, paymentAddr string) (*ServiceClients, error) {
+func NewServiceClients(productAddr, inventoryAddr, paymentAddr, userAddr string) (*ServiceClients, error) {
 	// Product service client
 	productConn, err := grpc.Dial(productAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
@@ -37,11 +38,19 @@ func NewServiceClients(productAddr, inventoryAddr, paymentAddr string) (*Service
 		return nil, err
 	}
 
+	// User service client
+	userConn, err := grpc.Dial(userAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logger.Errorf("Failed to connect to user service: %v", err)
+		return nil, err
+	}
+
 	logger.Info("gRPC service clients established")
 
 	return &ServiceClients{
 		ProductClient:   pb.NewProductServiceClient(productConn),
 		InventoryClient: pb.NewInventoryServiceClient(inventoryConn),
 		PaymentClient:   pb.NewPaymentServiceClient(paymentConn),
+		UserClient:      pb.NewUserServiceClient(userConn),
 	}, nil
 }
@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/cqchien/ecomerce-rec/backend/proto"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
+)
+
+// ReserveStock reserves stock for every item of an order and returns the reservation ID
+// together with the reservation line ID of each item, in item order
+func (c *ServiceClients) ReserveStock(ctx context.Context, orderID string, items []domain.OrderItem, ttl time.Duration) (string, []string, error) {
+	reservationItems := make([]*pb.ReservationItem, len(items))
+	for i, item := range items {
+		reservationItems[i] = &pb.ReservationItem{
+			ProductId: item.ProductID,
+			VariantId: item.VariantID,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	resp, err := c.InventoryClient.ReserveStock(ctx, &pb.ReserveStockRequest{
+		OrderId:    orderID,
+		Items:      reservationItems,
+		TtlSeconds: int32(ttl.Seconds()),
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to reserve stock: %w", err)
+	}
+
+	if !resp.Success {
+		var reasons []string
+		for _, result := range resp.Results {
+			if !result.Reserved {
+				reasons = append(reasons, fmt.Sprintf("%s: %s", result.ProductId, result.Error))
+			}
+		}
+		return "", nil, fmt.Errorf("%w: %s", domain.ErrStockUnavailable, strings.Join(reasons, "; "))
+	}
+
+	// Inventory reports one result per requested item, in request order
+	lineIDs := make([]string, len(items))
+	for i, result := range resp.Results {
+		if i < len(lineIDs) {
+			lineIDs[i] = result.ReservationId
+		}
+	}
+
+	return resp.ReservationId, lineIDs, nil
+}
+
+// ReleaseReservation releases the stock held for an order. A reservation line ID
+// releases only that line; with no reservation ID every line of the order is released.
+// Inventory reports an unsuccessful release when nothing is pending for the
+// identifier, which keeps compensation idempotent; any other failure is
+// returned, so the compensation is retried.
+func (c *ServiceClients) ReleaseReservation(ctx context.Context, reservationID, orderID string) error {
+	resp, err := c.InventoryClient.ReleaseReservation(ctx, &pb.ReleaseReservationRequest{
+		ReservationId: reservationID,
+		OrderId:       orderID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release reservation: %w", err)
+	}
+
+	if !resp.Success {
+		logger.Infof("No pending reservation released for order %s", orderID)
+	}
+	return nil
+}
+
+// CommitReservation turns the stock held for an order into a sale, so
+// inventory no longer expires it. Inventory reports an unsuccessful commit when
+// nothing is pending for the order, e.g. because it was already committed, so
+// a retried commit succeeds; any other failure is returned to be retried.
+func (c *ServiceClients) CommitReservation(ctx context.Context, orderID string) error {
+	resp, err := c.InventoryClient.CommitReservation(ctx, &pb.CommitReservationRequest{
+		OrderId: orderID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to commit reservation: %w", err)
+	}
+
+	if !resp.Success {
+		logger.Infof("No pending reservation committed for order %s", orderID)
+	}
+	return nil
+}
+
+// RestockItem adds returned units back to the available stock of a product
+func (c *ServiceClients) RestockItem(ctx context.Context, productID, variantID string, quantity int32, reason string) error {
+	if _, err := c.InventoryClient.UpdateStock(ctx, &pb.UpdateStockRequest{
+		ProductId: productID,
+		VariantId: variantID,
+		Quantity:  quantity,
+		Operation: pb.StockOperation_ADD,
+		Reason:    reason,
+	}); err != nil {
+		return fmt.Errorf("failed to restock item: %w", err)
+	}
+	return nil
+}
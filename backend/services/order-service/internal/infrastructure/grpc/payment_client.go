@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/cqchien/ecomerce-rec/backend/proto"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// CreatePaymentIntent creates a payment intent for the order total and returns its ID
+func (c *ServiceClients) CreatePaymentIntent(ctx context.Context, order *domain.Order) (string, error) {
+	method, ok := pb.PaymentMethodType_value[order.PaymentMethod]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", domain.ErrInvalidPaymentMethod, order.PaymentMethod)
+	}
+
+	resp, err := c.PaymentClient.CreatePaymentIntent(ctx, &pb.CreatePaymentIntentRequest{
+		OrderId: order.ID,
+		UserId:  order.UserID,
+		Amount: &pb.Money{
+			AmountCents: order.TotalAmount,
+			Currency:    order.Currency,
+		},
+		Method: pb.PaymentMethodType(method),
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", domain.ErrPaymentNotCreated, err)
+	}
+
+	return resp.PaymentIntentId, nil
+}
+
+// CancelPayment cancels a payment intent created for an order
+func (c *ServiceClients) CancelPayment(ctx context.Context, paymentID, reason string) error {
+	if _, err := c.PaymentClient.CancelPayment(ctx, &pb.CancelPaymentRequest{
+		PaymentId: paymentID,
+		Reason:    reason,
+	}); err != nil {
+		if status.Code(err) == codes.FailedPrecondition {
+			return fmt.Errorf("%w: %v", domain.ErrPaymentNotCancellable, err)
+		}
+		return fmt.Errorf("failed to cancel payment: %w", err)
+	}
+	return nil
+}
+
+// RefundPayment refunds part of a collected payment. Retrying with the same
+// idempotency key does not refund again.
+func (c *ServiceClients) RefundPayment(ctx context.Context, paymentID string, amount int64, currency, reason, idempotencyKey string) error {
+	if _, err := c.PaymentClient.RefundPayment(ctx, &pb.RefundPaymentRequest{
+		PaymentId: paymentID,
+		Amount: &pb.Money{
+			AmountCents: amount,
+			Currency:    currency,
+		},
+		Reason:         reason,
+		IdempotencyKey: idempotencyKey,
+	}); err != nil {
+		return fmt.Errorf("failed to refund payment: %w", err)
+	}
+	return nil
+}
+
+// GetOrderPayment returns the active payment of an order, or its latest
+// payment if every payment of the order failed or was cancelled
+func (c *ServiceClients) GetOrderPayment(ctx context.Context, orderID string) (*domain.Payment, error) {
+	resp, err := c.PaymentClient.GetPaymentStatus(ctx, &pb.GetPaymentStatusRequest{OrderId: orderID})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("%w: order %s", domain.ErrPaymentNotFound, orderID)
+		}
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	return &domain.Payment{
+		ID:     resp.Payment.Id,
+		Status: resp.Payment.Status.String(),
+	}, nil
+}
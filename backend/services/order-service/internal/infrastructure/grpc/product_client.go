@@ -0,0 +1,48 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/cqchien/ecomerce-rec/backend/proto"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// GetProducts fetches catalog data for the given products, keyed by product ID
+func (c *ServiceClients) GetProducts(ctx context.Context, productIDs []string) (map[string]domain.Product, error) {
+	resp, err := c.ProductClient.GetProductsByIds(ctx, &pb.GetProductsByIdsRequest{Ids: productIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	products := make(map[string]domain.Product, len(resp.Products))
+	for _, p := range resp.Products {
+		variants := make([]domain.ProductVariant, len(p.Variants))
+		for i, v := range p.Variants {
+			variants[i] = domain.ProductVariant{
+				ID:    v.Id,
+				Name:  v.Name,
+				SKU:   v.Sku,
+				Price: moneyToCents(v.Price),
+			}
+		}
+
+		products[p.Id] = domain.Product{
+			ID:       p.Id,
+			Name:     p.Name,
+			SKU:      p.Sku,
+			Price:    moneyToCents(p.Price),
+			Status:   domain.ProductStatus(p.Status.String()),
+			Variants: variants,
+		}
+	}
+	return products, nil
+}
+
+// moneyToCents returns the amount of proto money in cents
+func moneyToCents(m *pb.Money) int64 {
+	if m == nil {
+		return 0
+	}
+	return m.AmountCents
+}
@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/cqchien/ecomerce-rec/backend/proto"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// ListAddresses fetches the saved addresses of a user from user-service
+func (c *ServiceClients) ListAddresses(ctx context.Context, userID string) ([]domain.Address, error) {
+	resp, err := c.UserClient.ListAddresses(ctx, &pb.ListAddressesRequest{UserId: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list addresses: %w", err)
+	}
+
+	addresses := make([]domain.Address, 0, len(resp.Addresses))
+	for _, a := range resp.Addresses {
+		// Guard against addresses of other users leaking into the snapshot
+		if a.UserId != "" && a.UserId != userID {
+			continue
+		}
+		addresses = append(addresses, domain.Address{
+			ID:           a.Id,
+			FirstName:    a.FirstName,
+			LastName:     a.LastName,
+			AddressLine1: a.AddressLine1,
+			AddressLine2: a.AddressLine2,
+			City:         a.City,
+			State:        a.State,
+			PostalCode:   a.PostalCode,
+			Country:      a.Country,
+			Phone:        a.Phone,
+			IsDefault:    a.IsDefault,
+		})
+	}
+	return addresses, nil
+}
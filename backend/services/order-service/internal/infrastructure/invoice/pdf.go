@@ -0,0 +1,139 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page size and layout in PDF points
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	pageMargin   = 50
+	bottomMargin = 80
+)
+
+// Fonts are the PDF standard 14 fonts, which every reader ships, so nothing
+// has to be embedded. Courier is monospaced, which makes right-aligning
+// amounts a matter of counting characters.
+const (
+	fontRegular = "F1" // Helvetica
+	fontBold    = "F2" // Helvetica-Bold
+	fontMono    = "F3" // Courier
+)
+
+// courierAdvance is the width of every Courier glyph per point of font size
+const courierAdvance = 0.6
+
+// pdfWriter lays text out on pages and serializes a minimal PDF 1.4 file
+type pdfWriter struct {
+	pages []*bytes.Buffer
+}
+
+// newPage starts a new page and returns it for drawing
+func (w *pdfWriter) newPage() *bytes.Buffer {
+	page := &bytes.Buffer{}
+	w.pages = append(w.pages, page)
+	return page
+}
+
+// text draws a string with its baseline starting at (x, y)
+func text(page *bytes.Buffer, font string, size, x, y float64, s string) {
+	fmt.Fprintf(page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escapePDFString(s))
+}
+
+// textRight draws a Courier string ending at x
+func textRight(page *bytes.Buffer, size, x, y float64, s string) {
+	width := float64(len(encodeWinAnsi(s))) * size * courierAdvance
+	text(page, fontMono, size, x-width, y, s)
+}
+
+// rule draws a horizontal line across the printable width
+func rule(page *bytes.Buffer, y float64) {
+	fmt.Fprintf(page, "0.5 w %d %.2f m %d %.2f l S\n", pageMargin, y, pageWidth-pageMargin, y)
+}
+
+// bytes serializes the pages with their fonts and cross-reference table
+func (w *pdfWriter) bytes() []byte {
+	var out bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1-5 are fixed; each page then takes a page and a content object
+	const firstPage = 6
+	kids := make([]string, len(w.pages))
+	for i := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(w.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	for i, page := range w.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /%s 3 0 R /%s 4 0 R /%s 5 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fontRegular, fontBold, fontMono, firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// escapePDFString encodes s for a PDF literal string
+func escapePDFString(s string) string {
+	var b strings.Builder
+	for _, c := range encodeWinAnsi(s) {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			if c < 0x20 || c > 0x7e {
+				fmt.Fprintf(&b, "\\%03o", c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	return b.String()
+}
+
+// encodeWinAnsi maps s to single-byte WinAnsi codes. Latin-1 characters share
+// their code points; anything else is replaced with '?'.
+func encodeWinAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x20:
+			out = append(out, ' ')
+		case r < 0x7f, r >= 0xa0 && r <= 0xff:
+			out = append(out, byte(r))
+		case r == '€':
+			out = append(out, 0x80)
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
@@ -0,0 +1,158 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// Renderer produces the PDF and UBL JSON documents of an invoice in pure Go
+type Renderer struct{}
+
+// NewRenderer creates a new invoice renderer
+func NewRenderer() *Renderer {
+	return &Renderer{}
+}
+
+// Column positions of the line table
+const (
+	colDescription = pageMargin
+	colSKU         = 290
+	colQuantityEnd = 410
+	colUnitEnd     = 480
+	colAmountEnd   = pageWidth - pageMargin
+	lineHeight     = 14
+)
+
+// RenderPDF lays the invoice out on A4 pages, continuing the line table on as
+// many pages as needed
+func (r *Renderer) RenderPDF(inv *domain.Invoice) ([]byte, error) {
+	w := &pdfWriter{}
+	page := w.newPage()
+	y := float64(pageHeight - pageMargin - 10)
+
+	text(page, fontBold, 22, pageMargin, y, "INVOICE")
+	y -= 30
+
+	// Seller on the left, invoice details on the right
+	top := y
+	text(page, fontBold, 11, pageMargin, y, inv.Seller.Name)
+	sellerLines := strings.FieldsFunc(inv.Seller.Address, func(r rune) bool { return r == '\n' || r == ';' })
+	for _, line := range sellerLines {
+		if line = strings.TrimSpace(line); line != "" {
+			y -= lineHeight
+			text(page, fontRegular, 10, pageMargin, y, line)
+		}
+	}
+	if inv.Seller.TaxID != "" {
+		y -= lineHeight
+		text(page, fontRegular, 10, pageMargin, y, "Tax ID: "+inv.Seller.TaxID)
+	}
+
+	details := [][2]string{
+		{"Invoice number", inv.Number},
+		{"Issue date", inv.IssuedAt.UTC().Format("2006-01-02")},
+		{"Order", inv.OrderID},
+		{"Currency", inv.Currency},
+	}
+	detailY := top
+	for _, d := range details {
+		text(page, fontBold, 9, 330, detailY, d[0])
+		text(page, fontRegular, 9, 410, detailY, d[1])
+		detailY -= lineHeight
+	}
+	if detailY < y {
+		y = detailY
+	}
+	y -= 2 * lineHeight
+
+	text(page, fontBold, 11, pageMargin, y, "Bill to")
+	for _, line := range addressLines(inv.BillingAddress) {
+		y -= lineHeight
+		text(page, fontRegular, 10, pageMargin, y, line)
+	}
+	y -= 2 * lineHeight
+
+	y = lineTableHeader(page, y)
+	for _, line := range inv.Lines {
+		if y < bottomMargin {
+			page = w.newPage()
+			y = lineTableHeader(page, float64(pageHeight-pageMargin))
+		}
+		text(page, fontRegular, 9, colDescription, y, truncate(line.Description, 48))
+		text(page, fontRegular, 9, colSKU, y, truncate(line.SKU, 16))
+		textRight(page, 9, colQuantityEnd, y, fmt.Sprintf("%d", line.Quantity))
+		textRight(page, 9, colUnitEnd, y, domain.FormatCents(line.UnitPrice))
+		textRight(page, 9, colAmountEnd, y, domain.FormatCents(line.Amount))
+		y -= lineHeight
+	}
+
+	totals := [][2]string{{"Subtotal", domain.FormatCents(inv.Subtotal)}}
+	if inv.DiscountAmount > 0 {
+		totals = append(totals, [2]string{"Discount", domain.FormatCents(-inv.DiscountAmount)})
+	}
+	totals = append(totals,
+		[2]string{"Shipping", domain.FormatCents(inv.ShippingAmount)},
+		[2]string{fmt.Sprintf("Tax (%s%%)", formatRate(inv.TaxRate)), domain.FormatCents(inv.TaxAmount)},
+	)
+
+	if y-float64(len(totals)+2)*lineHeight < bottomMargin {
+		page = w.newPage()
+		y = float64(pageHeight - pageMargin)
+	}
+	rule(page, y+lineHeight-4)
+	y -= 4
+	for _, t := range totals {
+		text(page, fontRegular, 10, 350, y, t[0])
+		textRight(page, 10, colAmountEnd, y, t[1])
+		y -= lineHeight
+	}
+	rule(page, y+lineHeight-4)
+	y -= 4
+	text(page, fontBold, 11, 350, y, "Total "+inv.Currency)
+	textRight(page, 11, colAmountEnd, y, domain.FormatCents(inv.TotalAmount))
+
+	return w.bytes(), nil
+}
+
+// lineTableHeader draws the column titles and returns the baseline of the first row
+func lineTableHeader(page *bytes.Buffer, y float64) float64 {
+	text(page, fontBold, 9, colDescription, y, "Description")
+	text(page, fontBold, 9, colSKU, y, "SKU")
+	text(page, fontBold, 9, colQuantityEnd-20, y, "Qty")
+	text(page, fontBold, 9, colUnitEnd-50, y, "Unit price")
+	text(page, fontBold, 9, colAmountEnd-36, y, "Amount")
+	rule(page, y-5)
+	return y - lineHeight - 4
+}
+
+// addressLines formats an address for printing
+func addressLines(a domain.Address) []string {
+	lines := []string{}
+	add := func(parts ...string) {
+		var nonEmpty []string
+		for _, p := range parts {
+			if p = strings.TrimSpace(p); p != "" {
+				nonEmpty = append(nonEmpty, p)
+			}
+		}
+		if len(nonEmpty) > 0 {
+			lines = append(lines, strings.Join(nonEmpty, " "))
+		}
+	}
+	add(a.FirstName, a.LastName)
+	add(a.AddressLine1)
+	add(a.AddressLine2)
+	add(a.City, a.State, a.PostalCode)
+	add(a.Country)
+	return lines
+}
+
+// formatRate renders a fractional rate as a percentage without trailing zeros
+func formatRate(rate float64) string {
+	s := fmt.Sprintf("%.2f", rate*100)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
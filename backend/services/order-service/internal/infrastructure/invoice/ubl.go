@@ -0,0 +1,179 @@
+package invoice
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// The JSON document mirrors the element names of an OASIS UBL 2.1 Invoice,
+// following the EN 16931 core invoice model, so it can be mapped onto UBL XML
+// or imported by accounting systems without a custom schema.
+const (
+	ublCustomizationID   = "urn:cen.eu:en16931:2017"
+	ublCommercialInvoice = "380"
+	ublUnitCode          = "C62" // "one", the UN/ECE unit for pieces
+)
+
+type ublAmount struct {
+	Value      string `json:"value"`
+	CurrencyID string `json:"currencyID"`
+}
+
+type ublAddress struct {
+	StreetName           string `json:"StreetName,omitempty"`
+	AdditionalStreetName string `json:"AdditionalStreetName,omitempty"`
+	CityName             string `json:"CityName,omitempty"`
+	PostalZone           string `json:"PostalZone,omitempty"`
+	CountrySubentity     string `json:"CountrySubentity,omitempty"`
+	Country              string `json:"Country,omitempty"`
+	AddressLine          string `json:"AddressLine,omitempty"`
+}
+
+type ublParty struct {
+	Name          string     `json:"Name"`
+	PostalAddress ublAddress `json:"PostalAddress"`
+	CompanyID     string     `json:"PartyTaxSchemeCompanyID,omitempty"`
+}
+
+type ublAllowanceCharge struct {
+	ChargeIndicator       bool      `json:"ChargeIndicator"`
+	AllowanceChargeReason string    `json:"AllowanceChargeReason"`
+	Amount                ublAmount `json:"Amount"`
+}
+
+type ublTaxTotal struct {
+	TaxAmount ublAmount `json:"TaxAmount"`
+	Percent   string    `json:"Percent"`
+}
+
+type ublMonetaryTotal struct {
+	LineExtensionAmount  ublAmount `json:"LineExtensionAmount"`
+	AllowanceTotalAmount ublAmount `json:"AllowanceTotalAmount"`
+	ChargeTotalAmount    ublAmount `json:"ChargeTotalAmount"`
+	TaxExclusiveAmount   ublAmount `json:"TaxExclusiveAmount"`
+	TaxInclusiveAmount   ublAmount `json:"TaxInclusiveAmount"`
+	PayableAmount        ublAmount `json:"PayableAmount"`
+}
+
+type ublQuantity struct {
+	Value    int32  `json:"value"`
+	UnitCode string `json:"unitCode"`
+}
+
+type ublInvoiceLine struct {
+	ID                  string      `json:"ID"`
+	InvoicedQuantity    ublQuantity `json:"InvoicedQuantity"`
+	LineExtensionAmount ublAmount   `json:"LineExtensionAmount"`
+	Item                ublItem     `json:"Item"`
+	Price               ublPrice    `json:"Price"`
+}
+
+type ublItem struct {
+	Name                      string `json:"Name"`
+	SellersItemIdentification string `json:"SellersItemIdentification,omitempty"`
+}
+
+type ublPrice struct {
+	PriceAmount ublAmount `json:"PriceAmount"`
+}
+
+type ublInvoice struct {
+	CustomizationID         string               `json:"CustomizationID"`
+	ID                      string               `json:"ID"`
+	IssueDate               string               `json:"IssueDate"`
+	InvoiceTypeCode         string               `json:"InvoiceTypeCode"`
+	DocumentCurrencyCode    string               `json:"DocumentCurrencyCode"`
+	OrderReference          string               `json:"OrderReference"`
+	AccountingSupplierParty ublParty             `json:"AccountingSupplierParty"`
+	AccountingCustomerParty ublParty             `json:"AccountingCustomerParty"`
+	AllowanceCharge         []ublAllowanceCharge `json:"AllowanceCharge,omitempty"`
+	TaxTotal                ublTaxTotal          `json:"TaxTotal"`
+	LegalMonetaryTotal      ublMonetaryTotal     `json:"LegalMonetaryTotal"`
+	InvoiceLine             []ublInvoiceLine     `json:"InvoiceLine"`
+}
+
+// RenderJSON produces the UBL 2.1 structured invoice as JSON
+func (r *Renderer) RenderJSON(inv *domain.Invoice) ([]byte, error) {
+	amount := func(cents int64) ublAmount {
+		return ublAmount{Value: domain.FormatCents(cents), CurrencyID: inv.Currency}
+	}
+
+	var allowanceCharges []ublAllowanceCharge
+	if inv.DiscountAmount > 0 {
+		allowanceCharges = append(allowanceCharges, ublAllowanceCharge{
+			ChargeIndicator:       false,
+			AllowanceChargeReason: "Discount",
+			Amount:                amount(inv.DiscountAmount),
+		})
+	}
+	if inv.ShippingAmount > 0 {
+		allowanceCharges = append(allowanceCharges, ublAllowanceCharge{
+			ChargeIndicator:       true,
+			AllowanceChargeReason: "Shipping",
+			Amount:                amount(inv.ShippingAmount),
+		})
+	}
+
+	lines := make([]ublInvoiceLine, len(inv.Lines))
+	for i, line := range inv.Lines {
+		lines[i] = ublInvoiceLine{
+			ID:                  fmt.Sprintf("%d", i+1),
+			InvoicedQuantity:    ublQuantity{Value: line.Quantity, UnitCode: ublUnitCode},
+			LineExtensionAmount: amount(line.Amount),
+			Item: ublItem{
+				Name:                      line.Description,
+				SellersItemIdentification: line.SKU,
+			},
+			Price: ublPrice{PriceAmount: amount(line.UnitPrice)},
+		}
+	}
+
+	customer := inv.BillingAddress
+	document := ublInvoice{
+		CustomizationID:      ublCustomizationID,
+		ID:                   inv.Number,
+		IssueDate:            inv.IssuedAt.UTC().Format("2006-01-02"),
+		InvoiceTypeCode:      ublCommercialInvoice,
+		DocumentCurrencyCode: inv.Currency,
+		OrderReference:       inv.OrderID,
+		AccountingSupplierParty: ublParty{
+			Name:          inv.Seller.Name,
+			PostalAddress: ublAddress{AddressLine: inv.Seller.Address},
+			CompanyID:     inv.Seller.TaxID,
+		},
+		AccountingCustomerParty: ublParty{
+			Name: strings.TrimSpace(customer.FirstName + " " + customer.LastName),
+			PostalAddress: ublAddress{
+				StreetName:           customer.AddressLine1,
+				AdditionalStreetName: customer.AddressLine2,
+				CityName:             customer.City,
+				PostalZone:           customer.PostalCode,
+				CountrySubentity:     customer.State,
+				Country:              customer.Country,
+			},
+		},
+		AllowanceCharge: allowanceCharges,
+		TaxTotal: ublTaxTotal{
+			TaxAmount: amount(inv.TaxAmount),
+			Percent:   formatRate(inv.TaxRate),
+		},
+		LegalMonetaryTotal: ublMonetaryTotal{
+			LineExtensionAmount:  amount(inv.Subtotal),
+			AllowanceTotalAmount: amount(inv.DiscountAmount),
+			ChargeTotalAmount:    amount(inv.ShippingAmount),
+			TaxExclusiveAmount:   amount(inv.Subtotal - inv.DiscountAmount + inv.ShippingAmount),
+			TaxInclusiveAmount:   amount(inv.TotalAmount),
+			PayableAmount:        amount(inv.TotalAmount),
+		},
+		InvoiceLine: lines,
+	}
+
+	data, err := json.MarshalIndent(map[string]ublInvoice{"Invoice": document}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode invoice: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,79 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/segmentio/kafka-go"
+)
+
+// eventMessage is the message envelope shared with event-service publishers
+type eventMessage struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	AggregateID string    `json:"aggregate_id"`
+	Payload     string    `json:"payload"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Publisher implements outbox event publishing using Kafka
+type Publisher struct {
+	writer *kafka.Writer
+}
+
+// NewPublisher creates a new Kafka publisher
+func NewPublisher(brokers []string, topic string) *Publisher {
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		MaxAttempts:  3,
+		BatchTimeout: 10 * time.Millisecond,
+		RequiredAcks: kafka.RequireAll,
+	}
+
+	return &Publisher{
+		writer: writer,
+	}
+}
+
+// Publish publishes an outbox event to Kafka.
+// Messages are keyed by order ID so events of one order stay in partition order;
+// consumers should deduplicate on the event ID since delivery is at-least-once.
+func (p *Publisher) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	payload, err := json.Marshal(eventMessage{
+		ID:          event.ID,
+		Type:        string(event.Type),
+		AggregateID: event.AggregateID,
+		Payload:     event.Payload,
+		CreatedAt:   event.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	msg := kafka.Message{
+		Key:   []byte(event.AggregateID),
+		Value: payload,
+		Headers: []kafka.Header{
+			{Key: "event_id", Value: []byte(event.ID)},
+			{Key: "event_type", Value: []byte(event.Type)},
+			{Key: "aggregate_id", Value: []byte(event.AggregateID)},
+		},
+		Time: event.CreatedAt,
+	}
+
+	if err := p.writer.WriteMessages(ctx, msg); err != nil {
+		return fmt.Errorf("failed to publish event to Kafka: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the Kafka writer
+func (p *Publisher) Close() error {
+	return p.writer.Close()
+}
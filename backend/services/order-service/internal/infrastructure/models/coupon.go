@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// Coupon represents the database model for coupons
+type Coupon struct {
+	ID             string `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
+	Code           string `gorm:"type:varchar(50);not null;uniqueIndex"`
+	Type           string `gorm:"type:varchar(20);not null"`
+	Value          int64  `gorm:"not null"`           // Basis points for percentage coupons, cents for fixed amount
+	MinOrderAmount int64  `gorm:"not null;default:0"` // in cents
+	MaxDiscount    int64  `gorm:"not null;default:0"` // in cents
+	MaxRedemptions int    `gorm:"not null;default:0"`
+	PerUserLimit   int    `gorm:"not null;default:0"`
+	TimesRedeemed  int    `gorm:"not null;default:0"`
+	Active         bool   `gorm:"not null;default:true"`
+	StartsAt       *time.Time
+	ExpiresAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// CouponRedemption represents the database model for coupons used by orders
+type CouponRedemption struct {
+	ID        string `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
+	CouponID  string `gorm:"type:uuid;not null;index:idx_coupon_redemptions_coupon_user"`
+	UserID    string `gorm:"type:uuid;not null;index:idx_coupon_redemptions_coupon_user"`
+	OrderID   string `gorm:"type:uuid;not null;uniqueIndex"`
+	Amount    int64  `gorm:"not null"` // in cents
+	CreatedAt time.Time
+}
+
+// TableName specifies the table name for Coupon
+func (Coupon) TableName() string {
+	return "coupons"
+}
+
+// TableName specifies the table name for CouponRedemption
+func (CouponRedemption) TableName() string {
+	return "coupon_redemptions"
+}
+
+// ToDomain converts database Coupon model to domain Coupon
+func (c *Coupon) ToDomain() *domain.Coupon {
+	return &domain.Coupon{
+		ID:             c.ID,
+		Code:           c.Code,
+		Type:           domain.CouponType(c.Type),
+		Value:          c.Value,
+		MinOrderAmount: c.MinOrderAmount,
+		MaxDiscount:    c.MaxDiscount,
+		MaxRedemptions: c.MaxRedemptions,
+		PerUserLimit:   c.PerUserLimit,
+		TimesRedeemed:  c.TimesRedeemed,
+		Active:         c.Active,
+		StartsAt:       c.StartsAt,
+		ExpiresAt:      c.ExpiresAt,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// IdempotencyKey represents the database model for create-order idempotency keys
+type IdempotencyKey struct {
+	UserID      string `gorm:"type:uuid;primaryKey"`
+	Key         string `gorm:"type:varchar(255);primaryKey"`
+	Fingerprint string `gorm:"type:varchar(64);not null"`
+	OrderID     string `gorm:"type:varchar(36)"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// TableName specifies the table name for IdempotencyKey
+func (IdempotencyKey) TableName() string {
+	return "order_idempotency_keys"
+}
+
+// ToDomain converts database IdempotencyKey model to domain IdempotencyRecord
+func (k *IdempotencyKey) ToDomain() *domain.IdempotencyRecord {
+	return &domain.IdempotencyRecord{
+		Key:         k.Key,
+		UserID:      k.UserID,
+		Fingerprint: k.Fingerprint,
+		OrderID:     k.OrderID,
+		CreatedAt:   k.CreatedAt,
+		UpdatedAt:   k.UpdatedAt,
+	}
+}
+
+// IdempotencyKeyFromDomain converts domain IdempotencyRecord to database IdempotencyKey model
+func IdempotencyKeyFromDomain(record *domain.IdempotencyRecord) *IdempotencyKey {
+	return &IdempotencyKey{
+		UserID:      record.UserID,
+		Key:         record.Key,
+		Fingerprint: record.Fingerprint,
+		OrderID:     record.OrderID,
+		CreatedAt:   record.CreatedAt,
+		UpdatedAt:   record.UpdatedAt,
+	}
+}
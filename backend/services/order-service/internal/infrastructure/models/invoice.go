@@ -0,0 +1,110 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// Invoice represents the database model for issued invoices
+type Invoice struct {
+	ID             string `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
+	OrderID        string `gorm:"type:uuid;not null;uniqueIndex"`
+	UserID         string `gorm:"type:uuid;not null;index"`
+	Number         string `gorm:"type:varchar(20);not null;uniqueIndex"`
+	Sequence       int64  `gorm:"not null;uniqueIndex"`
+	Seller         string `gorm:"type:jsonb;not null"`
+	BillingAddress string `gorm:"type:text"`
+	Currency       string `gorm:"type:varchar(3);not null"`
+	Lines          string `gorm:"type:jsonb;not null"`
+	Subtotal       int64  `gorm:"not null;default:0"` // in cents
+	DiscountAmount int64  `gorm:"not null;default:0"`
+	ShippingAmount int64  `gorm:"not null;default:0"`
+	TaxAmount      int64  `gorm:"not null;default:0"`
+	TaxRate        float64
+	TotalAmount    int64  `gorm:"not null;default:0"`
+	PDF            []byte `gorm:"type:bytea"`
+	Document       []byte `gorm:"type:bytea"`
+	IssuedAt       time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// InvoiceSequence holds the last number handed out by a gap-free sequence.
+// Its row is locked while an invoice is inserted, so a number is only consumed
+// when the invoice commits.
+type InvoiceSequence struct {
+	Name      string `gorm:"type:varchar(50);primaryKey"`
+	LastValue int64  `gorm:"not null;default:0"`
+}
+
+// TableName specifies the table name for Invoice
+func (Invoice) TableName() string {
+	return "invoices"
+}
+
+// TableName specifies the table name for InvoiceSequence
+func (InvoiceSequence) TableName() string {
+	return "invoice_sequences"
+}
+
+// ToDomain converts database Invoice model to domain Invoice
+func (i *Invoice) ToDomain() *domain.Invoice {
+	var seller domain.Seller
+	_ = json.Unmarshal([]byte(i.Seller), &seller)
+	var lines []domain.InvoiceLine
+	_ = json.Unmarshal([]byte(i.Lines), &lines)
+
+	return &domain.Invoice{
+		ID:             i.ID,
+		OrderID:        i.OrderID,
+		UserID:         i.UserID,
+		Number:         i.Number,
+		Sequence:       i.Sequence,
+		Seller:         seller,
+		BillingAddress: addressFromColumn(i.BillingAddress),
+		Currency:       i.Currency,
+		Lines:          lines,
+		Subtotal:       i.Subtotal,
+		DiscountAmount: i.DiscountAmount,
+		ShippingAmount: i.ShippingAmount,
+		TaxAmount:      i.TaxAmount,
+		TaxRate:        i.TaxRate,
+		TotalAmount:    i.TotalAmount,
+		PDF:            i.PDF,
+		Document:       i.Document,
+		IssuedAt:       i.IssuedAt,
+		CreatedAt:      i.CreatedAt,
+		UpdatedAt:      i.UpdatedAt,
+	}
+}
+
+// InvoiceFromDomain converts domain Invoice to database Invoice model
+func InvoiceFromDomain(i *domain.Invoice) *Invoice {
+	seller, _ := json.Marshal(i.Seller)
+	lines, _ := json.Marshal(i.Lines)
+
+	return &Invoice{
+		ID:             i.ID,
+		OrderID:        i.OrderID,
+		UserID:         i.UserID,
+		Number:         i.Number,
+		Sequence:       i.Sequence,
+		Seller:         string(seller),
+		BillingAddress: addressToColumn(i.BillingAddress),
+		Currency:       i.Currency,
+		Lines:          string(lines),
+		Subtotal:       i.Subtotal,
+		DiscountAmount: i.DiscountAmount,
+		ShippingAmount: i.ShippingAmount,
+		TaxAmount:      i.TaxAmount,
+		TaxRate:        i.TaxRate,
+		TotalAmount:    i.TotalAmount,
+		PDF:            i.PDF,
+		Document:       i.Document,
+		IssuedAt:       i.IssuedAt,
+		CreatedAt:      i.CreatedAt,
+		UpdatedAt:      i.UpdatedAt,
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
@@ -9,34 +10,55 @@ import (
 
 // Order represents the database model for orders
 type Order struct {
-	ID              string `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
-	UserID          string `gorm:"type:uuid;not null;index"`
-	Status          string `gorm:"type:varchar(20);not null;index"`
-	TotalAmount     float64
-	ShippingAddress string `gorm:"type:text"`
-	BillingAddress  string `gorm:"type:text"`
-	PaymentMethod   string `gorm:"type:varchar(50)"`
-	PaymentStatus   string `gorm:"type:varchar(20)"`
-	TrackingNumber  string `gorm:"type:varchar(100)"`
-	Notes           string `gorm:"type:text"`
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
-	DeletedAt       gorm.DeletedAt `gorm:"index"`
+	ID               string `gorm:"type:uuid;primaryKey;default:uuid_generate_v7();index:idx_orders_created_at_id,priority:2"`
+	UserID           string `gorm:"type:uuid;not null;index"`
+	Status           string `gorm:"type:varchar(20);not null;index"`
+	Currency         string `gorm:"type:varchar(3);not null;default:'USD'"`
+	Subtotal         int64  `gorm:"not null;default:0"` // Amounts are in cents of Currency
+	DiscountAmount   int64  `gorm:"not null;default:0"`
+	ShippingAmount   int64  `gorm:"not null;default:0"`
+	TaxAmount        int64  `gorm:"not null;default:0"`
+	TaxRate          float64
+	TotalAmount      int64  `gorm:"not null;default:0"`
+	RefundedAmount   int64  `gorm:"not null;default:0"`
+	CouponCode       string `gorm:"type:varchar(50);index"`
+	ShippingAddress  string `gorm:"type:text"` // JSON address snapshot
+	BillingAddress   string `gorm:"type:text"` // JSON address snapshot
+	PaymentMethod    string `gorm:"type:varchar(50)"`
+	PaymentStatus    string `gorm:"type:varchar(20)"`
+	PaymentID        string `gorm:"type:varchar(100)"`
+	TrackingNumber   string `gorm:"type:varchar(100)"`
+	Notes            string `gorm:"type:text"`
+	DisputeID        string `gorm:"type:varchar(100)"`
+	DisputedAt       *time.Time
+	StockCommittedAt *time.Time
+	Version          int64     `gorm:"not null;default:1"`
+	CreatedAt        time.Time `gorm:"index:idx_orders_created_at_id,priority:1"`
+	UpdatedAt        time.Time
+	DeletedAt        gorm.DeletedAt `gorm:"index"`
 
 	Items []OrderItem `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
 }
 
 // OrderItem represents the database model for order items
 type OrderItem struct {
-	ID        string `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
-	OrderID   string `gorm:"type:uuid;not null;index"`
-	ProductID string `gorm:"type:uuid;not null;index"`
-	Quantity  int32  `gorm:"not null"`
-	Price     float64
-	Subtotal  float64
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ID            string `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
+	OrderID       string `gorm:"type:uuid;not null;index"`
+	ProductID     string `gorm:"type:uuid;not null;index"`
+	VariantID     string `gorm:"type:varchar(100)"`
+	ProductName   string `gorm:"type:varchar(255)"`
+	VariantName   string `gorm:"type:varchar(255)"`
+	SKU           string `gorm:"type:varchar(100)"`
+	Quantity      int32  `gorm:"not null"`
+	Price         int64  `gorm:"not null;default:0"` // in cents
+	Subtotal      int64  `gorm:"not null;default:0"` // in cents
+	Status        string `gorm:"type:varchar(20);not null;default:'PENDING'"`
+	ReservationID string `gorm:"type:varchar(100)"`
+	RefundAmount  int64  `gorm:"not null;default:0"` // in cents
+	RefundedAt    *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
 }
 
 // TableName specifies the table name for Order
@@ -54,31 +76,52 @@ func (o *Order) ToDomain() *domain.Order {
 	items := make([]domain.OrderItem, len(o.Items))
 	for i, item := range o.Items {
 		items[i] = domain.OrderItem{
-			ID:        item.ID,
-			OrderID:   item.OrderID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-			Subtotal:  item.Subtotal,
-			CreatedAt: item.CreatedAt,
-			UpdatedAt: item.UpdatedAt,
+			ID:            item.ID,
+			OrderID:       item.OrderID,
+			ProductID:     item.ProductID,
+			VariantID:     item.VariantID,
+			ProductName:   item.ProductName,
+			VariantName:   item.VariantName,
+			SKU:           item.SKU,
+			Quantity:      item.Quantity,
+			Price:         item.Price,
+			Subtotal:      item.Subtotal,
+			Status:        domain.OrderItemStatus(item.Status),
+			ReservationID: item.ReservationID,
+			RefundAmount:  item.RefundAmount,
+			RefundedAt:    item.RefundedAt,
+			CreatedAt:     item.CreatedAt,
+			UpdatedAt:     item.UpdatedAt,
 		}
 	}
 
 	return &domain.Order{
-		ID:              o.ID,
-		UserID:          o.UserID,
-		Status:          domain.OrderStatus(o.Status),
-		Items:           items,
-		TotalAmount:     o.TotalAmount,
-		ShippingAddress: o.ShippingAddress,
-		BillingAddress:  o.BillingAddress,
-		PaymentMethod:   o.PaymentMethod,
-		PaymentStatus:   o.PaymentStatus,
-		TrackingNumber:  o.TrackingNumber,
-		Notes:           o.Notes,
-		CreatedAt:       o.CreatedAt,
-		UpdatedAt:       o.UpdatedAt,
+		ID:               o.ID,
+		UserID:           o.UserID,
+		Status:           domain.OrderStatus(o.Status),
+		Currency:         o.Currency,
+		Items:            items,
+		Subtotal:         o.Subtotal,
+		DiscountAmount:   o.DiscountAmount,
+		ShippingAmount:   o.ShippingAmount,
+		TaxAmount:        o.TaxAmount,
+		TaxRate:          o.TaxRate,
+		TotalAmount:      o.TotalAmount,
+		RefundedAmount:   o.RefundedAmount,
+		CouponCode:       o.CouponCode,
+		ShippingAddress:  addressFromColumn(o.ShippingAddress),
+		BillingAddress:   addressFromColumn(o.BillingAddress),
+		PaymentMethod:    o.PaymentMethod,
+		PaymentStatus:    o.PaymentStatus,
+		PaymentID:        o.PaymentID,
+		TrackingNumber:   o.TrackingNumber,
+		Notes:            o.Notes,
+		DisputeID:        o.DisputeID,
+		DisputedAt:       o.DisputedAt,
+		StockCommittedAt: o.StockCommittedAt,
+		Version:          o.Version,
+		CreatedAt:        o.CreatedAt,
+		UpdatedAt:        o.UpdatedAt,
 	}
 }
 
@@ -87,30 +130,76 @@ func FromDomain(order *domain.Order) *Order {
 	items := make([]OrderItem, len(order.Items))
 	for i, item := range order.Items {
 		items[i] = OrderItem{
-			ID:        item.ID,
-			OrderID:   item.OrderID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-			Subtotal:  item.Subtotal,
-			CreatedAt: item.CreatedAt,
-			UpdatedAt: item.UpdatedAt,
+			ID:            item.ID,
+			OrderID:       item.OrderID,
+			ProductID:     item.ProductID,
+			VariantID:     item.VariantID,
+			ProductName:   item.ProductName,
+			VariantName:   item.VariantName,
+			SKU:           item.SKU,
+			Quantity:      item.Quantity,
+			Price:         item.Price,
+			Subtotal:      item.Subtotal,
+			Status:        string(item.Status),
+			ReservationID: item.ReservationID,
+			RefundAmount:  item.RefundAmount,
+			RefundedAt:    item.RefundedAt,
+			CreatedAt:     item.CreatedAt,
+			UpdatedAt:     item.UpdatedAt,
 		}
 	}
 
 	return &Order{
-		ID:              order.ID,
-		UserID:          order.UserID,
-		Status:          string(order.Status),
-		Items:           items,
-		TotalAmount:     order.TotalAmount,
-		ShippingAddress: order.ShippingAddress,
-		BillingAddress:  order.BillingAddress,
-		PaymentMethod:   order.PaymentMethod,
-		PaymentStatus:   order.PaymentStatus,
-		TrackingNumber:  order.TrackingNumber,
-		Notes:           order.Notes,
-		CreatedAt:       order.CreatedAt,
-		UpdatedAt:       order.UpdatedAt,
+		ID:               order.ID,
+		UserID:           order.UserID,
+		Status:           string(order.Status),
+		Currency:         order.Currency,
+		Items:            items,
+		Subtotal:         order.Subtotal,
+		DiscountAmount:   order.DiscountAmount,
+		ShippingAmount:   order.ShippingAmount,
+		TaxAmount:        order.TaxAmount,
+		TaxRate:          order.TaxRate,
+		TotalAmount:      order.TotalAmount,
+		RefundedAmount:   order.RefundedAmount,
+		CouponCode:       order.CouponCode,
+		ShippingAddress:  addressToColumn(order.ShippingAddress),
+		BillingAddress:   addressToColumn(order.BillingAddress),
+		PaymentMethod:    order.PaymentMethod,
+		PaymentStatus:    order.PaymentStatus,
+		PaymentID:        order.PaymentID,
+		TrackingNumber:   order.TrackingNumber,
+		Notes:            order.Notes,
+		DisputeID:        order.DisputeID,
+		DisputedAt:       order.DisputedAt,
+		StockCommittedAt: order.StockCommittedAt,
+		Version:          order.Version,
+		CreatedAt:        order.CreatedAt,
+		UpdatedAt:        order.UpdatedAt,
 	}
 }
+
+// addressToColumn serializes an address snapshot for storage
+func addressToColumn(address domain.Address) string {
+	if address.IsEmpty() {
+		return ""
+	}
+	data, err := json.Marshal(address)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// addressFromColumn restores an address snapshot. Orders placed before
+// snapshots were taken only stored the address ID, which is kept as is.
+func addressFromColumn(value string) domain.Address {
+	var address domain.Address
+	if value == "" {
+		return address
+	}
+	if err := json.Unmarshal([]byte(value), &address); err != nil {
+		return domain.Address{ID: value}
+	}
+	return address
+}
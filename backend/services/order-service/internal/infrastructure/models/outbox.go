@@ -0,0 +1,115 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// OutboxEvent represents the database model for the order event outbox
+type OutboxEvent struct {
+	ID            string `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
+	AggregateID   string `gorm:"type:uuid;not null;index"`
+	EventType     string `gorm:"type:varchar(50);not null"`
+	Payload       string `gorm:"type:jsonb;not null"`
+	Attempts      int    `gorm:"not null;default:0"`
+	LastError     string `gorm:"type:text"`
+	CreatedAt     time.Time
+	PublishedAt   *time.Time `gorm:"index"`
+	NextAttemptAt *time.Time
+	FailedAt      *time.Time `gorm:"index"`
+}
+
+// TableName specifies the table name for OutboxEvent
+func (OutboxEvent) TableName() string {
+	return "order_outbox"
+}
+
+// ToDomain converts database OutboxEvent model to domain OutboxEvent
+func (e *OutboxEvent) ToDomain() *domain.OutboxEvent {
+	return &domain.OutboxEvent{
+		ID:            e.ID,
+		Type:          domain.EventType(e.EventType),
+		AggregateID:   e.AggregateID,
+		Payload:       e.Payload,
+		Attempts:      e.Attempts,
+		LastError:     e.LastError,
+		CreatedAt:     e.CreatedAt,
+		PublishedAt:   e.PublishedAt,
+		NextAttemptAt: e.NextAttemptAt,
+		FailedAt:      e.FailedAt,
+	}
+}
+
+// OrderEventItem is the item snapshot carried by order events
+type OrderEventItem struct {
+	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id,omitempty"`
+	Quantity  int32  `json:"quantity"`
+	Price     int64  `json:"price_cents"`
+	Status    string `json:"status"`
+}
+
+// OrderEventPayload is the order snapshot carried by order events
+type OrderEventPayload struct {
+	OrderID        string           `json:"order_id"`
+	UserID         string           `json:"user_id"`
+	Status         string           `json:"status"`
+	Currency       string           `json:"currency"`
+	Subtotal       int64            `json:"subtotal_cents"`
+	DiscountAmount int64            `json:"discount_amount_cents"`
+	ShippingAmount int64            `json:"shipping_amount_cents"`
+	TaxAmount      int64            `json:"tax_amount_cents"`
+	TotalAmount    int64            `json:"total_amount_cents"`
+	RefundedAmount int64            `json:"refunded_amount_cents"`
+	CouponCode     string           `json:"coupon_code,omitempty"`
+	PaymentMethod  string           `json:"payment_method"`
+	PaymentStatus  string           `json:"payment_status"`
+	TrackingNumber string           `json:"tracking_number,omitempty"`
+	Items          []OrderEventItem `json:"items"`
+	OccurredAt     time.Time        `json:"occurred_at"`
+}
+
+// NewOutboxEvent builds an outbox row holding a snapshot of the order
+func NewOutboxEvent(eventType domain.EventType, order *domain.Order) (*OutboxEvent, error) {
+	items := make([]OrderEventItem, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = OrderEventItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+			Price:     item.Price,
+			Status:    string(item.Status),
+		}
+	}
+
+	payload, err := json.Marshal(OrderEventPayload{
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		Status:         string(order.Status),
+		Currency:       order.Currency,
+		Subtotal:       order.Subtotal,
+		DiscountAmount: order.DiscountAmount,
+		ShippingAmount: order.ShippingAmount,
+		TaxAmount:      order.TaxAmount,
+		TotalAmount:    order.TotalAmount,
+		RefundedAmount: order.RefundedAmount,
+		CouponCode:     order.CouponCode,
+		PaymentMethod:  order.PaymentMethod,
+		PaymentStatus:  order.PaymentStatus,
+		TrackingNumber: order.TrackingNumber,
+		Items:          items,
+		OccurredAt:     order.UpdatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxEvent{
+		AggregateID: order.ID,
+		EventType:   string(eventType),
+		Payload:     string(payload),
+		CreatedAt:   time.Now(),
+	}, nil
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// OrderReturn represents the database model for return merchandise authorizations
+type OrderReturn struct {
+	ID              string `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
+	OrderID         string `gorm:"type:uuid;not null;index"`
+	UserID          string `gorm:"type:uuid;not null;index"`
+	Status          string `gorm:"type:varchar(20);not null;index"`
+	Reason          string `gorm:"type:text"`
+	RejectionReason string `gorm:"type:text"`
+	ReviewedBy      string `gorm:"type:varchar(100)"`
+	ReceivedBy      string `gorm:"type:varchar(100)"`
+	Currency        string `gorm:"type:varchar(3);not null;default:'USD'"`
+	RefundAmount    int64  `gorm:"not null;default:0"` // in cents
+	ReviewedAt      *time.Time
+	ReceivedAt      *time.Time
+	RefundedAt      *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+
+	Items []OrderReturnItem `gorm:"foreignKey:ReturnID;constraint:OnDelete:CASCADE"`
+}
+
+// OrderReturnItem represents the database model for lines included in a return
+type OrderReturnItem struct {
+	ID          string `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
+	ReturnID    string `gorm:"type:uuid;not null;index"`
+	OrderItemID string `gorm:"type:uuid;not null;index"`
+	ProductID   string `gorm:"type:uuid;not null"`
+	VariantID   string `gorm:"type:varchar(100)"`
+	Quantity    int32  `gorm:"not null"`
+	Restocked   bool   `gorm:"not null;default:false"`
+}
+
+// TableName specifies the table name for OrderReturn
+func (OrderReturn) TableName() string {
+	return "order_returns"
+}
+
+// TableName specifies the table name for OrderReturnItem
+func (OrderReturnItem) TableName() string {
+	return "order_return_items"
+}
+
+// ToDomain converts database OrderReturn model to domain OrderReturn
+func (r *OrderReturn) ToDomain() *domain.OrderReturn {
+	items := make([]domain.OrderReturnItem, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = domain.OrderReturnItem{
+			ID:          item.ID,
+			ReturnID:    item.ReturnID,
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			Quantity:    item.Quantity,
+			Restocked:   item.Restocked,
+		}
+	}
+
+	return &domain.OrderReturn{
+		ID:              r.ID,
+		OrderID:         r.OrderID,
+		UserID:          r.UserID,
+		Status:          domain.ReturnStatus(r.Status),
+		Reason:          r.Reason,
+		RejectionReason: r.RejectionReason,
+		ReviewedBy:      r.ReviewedBy,
+		ReceivedBy:      r.ReceivedBy,
+		Currency:        r.Currency,
+		RefundAmount:    r.RefundAmount,
+		Items:           items,
+		ReviewedAt:      r.ReviewedAt,
+		ReceivedAt:      r.ReceivedAt,
+		RefundedAt:      r.RefundedAt,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+	}
+}
+
+// ReturnFromDomain converts domain OrderReturn to database OrderReturn model
+func ReturnFromDomain(r *domain.OrderReturn) *OrderReturn {
+	items := make([]OrderReturnItem, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = OrderReturnItem{
+			ID:          item.ID,
+			ReturnID:    item.ReturnID,
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			Quantity:    item.Quantity,
+			Restocked:   item.Restocked,
+		}
+	}
+
+	return &OrderReturn{
+		ID:              r.ID,
+		OrderID:         r.OrderID,
+		UserID:          r.UserID,
+		Status:          string(r.Status),
+		Reason:          r.Reason,
+		RejectionReason: r.RejectionReason,
+		ReviewedBy:      r.ReviewedBy,
+		ReceivedBy:      r.ReceivedBy,
+		Currency:        r.Currency,
+		RefundAmount:    r.RefundAmount,
+		Items:           items,
+		ReviewedAt:      r.ReviewedAt,
+		ReceivedAt:      r.ReceivedAt,
+		RefundedAt:      r.RefundedAt,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+	}
+}
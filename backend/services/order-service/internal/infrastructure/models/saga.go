@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// CheckoutSaga represents the database model for checkout saga progress
+type CheckoutSaga struct {
+	ID              string `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
+	OrderID         string `gorm:"type:uuid;not null;uniqueIndex"`
+	State           string `gorm:"type:varchar(30);not null;index"`
+	ReservationID   string `gorm:"type:varchar(100)"`
+	PaymentIntentID string `gorm:"type:varchar(100)"`
+	FailureReason   string `gorm:"type:text"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time `gorm:"index"`
+}
+
+// TableName specifies the table name for CheckoutSaga
+func (CheckoutSaga) TableName() string {
+	return "checkout_sagas"
+}
+
+// ToDomain converts database CheckoutSaga model to domain CheckoutSaga
+func (s *CheckoutSaga) ToDomain() *domain.CheckoutSaga {
+	return &domain.CheckoutSaga{
+		ID:              s.ID,
+		OrderID:         s.OrderID,
+		State:           domain.SagaState(s.State),
+		ReservationID:   s.ReservationID,
+		PaymentIntentID: s.PaymentIntentID,
+		FailureReason:   s.FailureReason,
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
+	}
+}
+
+// SagaFromDomain converts domain CheckoutSaga to database CheckoutSaga model
+func SagaFromDomain(saga *domain.CheckoutSaga) *CheckoutSaga {
+	return &CheckoutSaga{
+		ID:              saga.ID,
+		OrderID:         saga.OrderID,
+		State:           string(saga.State),
+		ReservationID:   saga.ReservationID,
+		PaymentIntentID: saga.PaymentIntentID,
+		FailureReason:   saga.FailureReason,
+		CreatedAt:       saga.CreatedAt,
+		UpdatedAt:       saga.UpdatedAt,
+	}
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// Shipment represents the database model for order shipments
+type Shipment struct {
+	ID             string `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
+	OrderID        string `gorm:"type:uuid;not null;index"`
+	Carrier        string `gorm:"type:varchar(50);not null;uniqueIndex:idx_shipments_tracking"`
+	TrackingNumber string `gorm:"type:varchar(100);not null;uniqueIndex:idx_shipments_tracking"`
+	Status         string `gorm:"type:varchar(20);not null"`
+	ShippedAt      *time.Time
+	DeliveredAt    *time.Time
+	LastEventAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	Items []ShipmentItem `gorm:"foreignKey:ShipmentID;constraint:OnDelete:CASCADE"`
+}
+
+// ShipmentItem represents the database model for order lines packed in a shipment
+type ShipmentItem struct {
+	ID          string `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
+	ShipmentID  string `gorm:"type:uuid;not null;index"`
+	OrderItemID string `gorm:"type:uuid;not null;index"`
+	Quantity    int32  `gorm:"not null"`
+}
+
+// ShipmentTrackingEvent represents the database model for carrier tracking updates.
+// The unique index drops carrier retries of the same update.
+type ShipmentTrackingEvent struct {
+	ID          string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
+	ShipmentID  string    `gorm:"type:uuid;not null;uniqueIndex:idx_tracking_events_dedupe"`
+	Status      string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_tracking_events_dedupe"`
+	OccurredAt  time.Time `gorm:"not null;uniqueIndex:idx_tracking_events_dedupe"`
+	Location    string    `gorm:"type:varchar(255)"`
+	Description string    `gorm:"type:text"`
+	CreatedAt   time.Time
+}
+
+// TableName specifies the table name for Shipment
+func (Shipment) TableName() string {
+	return "shipments"
+}
+
+// TableName specifies the table name for ShipmentItem
+func (ShipmentItem) TableName() string {
+	return "shipment_items"
+}
+
+// TableName specifies the table name for ShipmentTrackingEvent
+func (ShipmentTrackingEvent) TableName() string {
+	return "shipment_tracking_events"
+}
+
+// ToDomain converts database Shipment model to domain Shipment
+func (s *Shipment) ToDomain() *domain.Shipment {
+	items := make([]domain.ShipmentItem, len(s.Items))
+	for i, item := range s.Items {
+		items[i] = domain.ShipmentItem{
+			ID:          item.ID,
+			ShipmentID:  item.ShipmentID,
+			OrderItemID: item.OrderItemID,
+			Quantity:    item.Quantity,
+		}
+	}
+
+	return &domain.Shipment{
+		ID:             s.ID,
+		OrderID:        s.OrderID,
+		Carrier:        s.Carrier,
+		TrackingNumber: s.TrackingNumber,
+		Status:         domain.ShipmentStatus(s.Status),
+		Items:          items,
+		ShippedAt:      s.ShippedAt,
+		DeliveredAt:    s.DeliveredAt,
+		LastEventAt:    s.LastEventAt,
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
+	}
+}
+
+// ShipmentFromDomain converts domain Shipment to database Shipment model
+func ShipmentFromDomain(s *domain.Shipment) *Shipment {
+	items := make([]ShipmentItem, len(s.Items))
+	for i, item := range s.Items {
+		items[i] = ShipmentItem{
+			ID:          item.ID,
+			ShipmentID:  item.ShipmentID,
+			OrderItemID: item.OrderItemID,
+			Quantity:    item.Quantity,
+		}
+	}
+
+	return &Shipment{
+		ID:             s.ID,
+		OrderID:        s.OrderID,
+		Carrier:        s.Carrier,
+		TrackingNumber: s.TrackingNumber,
+		Status:         string(s.Status),
+		Items:          items,
+		ShippedAt:      s.ShippedAt,
+		DeliveredAt:    s.DeliveredAt,
+		LastEventAt:    s.LastEventAt,
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
+	}
+}
+
+// TrackingEventFromDomain converts domain TrackingEvent to database ShipmentTrackingEvent model
+func TrackingEventFromDomain(e *domain.TrackingEvent) *ShipmentTrackingEvent {
+	return &ShipmentTrackingEvent{
+		ID:          e.ID,
+		ShipmentID:  e.ShipmentID,
+		Status:      string(e.Status),
+		OccurredAt:  e.OccurredAt,
+		Location:    e.Location,
+		Description: e.Description,
+		CreatedAt:   e.CreatedAt,
+	}
+}
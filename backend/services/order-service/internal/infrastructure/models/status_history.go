@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// OrderStatusHistory represents the database model for order status transitions
+type OrderStatusHistory struct {
+	ID         string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
+	OrderID    string    `gorm:"type:uuid;not null;index:idx_order_status_history_order_created"`
+	FromStatus string    `gorm:"type:varchar(20)"`
+	ToStatus   string    `gorm:"type:varchar(20);not null"`
+	ChangedBy  string    `gorm:"type:varchar(100);not null"`
+	Reason     string    `gorm:"type:text"`
+	CreatedAt  time.Time `gorm:"index:idx_order_status_history_order_created"`
+}
+
+// TableName specifies the table name for OrderStatusHistory
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
+
+// ToDomain converts database OrderStatusHistory model to domain StatusChange
+func (h *OrderStatusHistory) ToDomain() domain.StatusChange {
+	return domain.StatusChange{
+		ID:         h.ID,
+		OrderID:    h.OrderID,
+		FromStatus: domain.OrderStatus(h.FromStatus),
+		ToStatus:   domain.OrderStatus(h.ToStatus),
+		ChangedBy:  h.ChangedBy,
+		Reason:     h.Reason,
+		CreatedAt:  h.CreatedAt,
+	}
+}
+
+// StatusChangeFromDomain converts domain StatusChange to database OrderStatusHistory model
+func StatusChangeFromDomain(change domain.StatusChange) *OrderStatusHistory {
+	return &OrderStatusHistory{
+		ID:         change.ID,
+		OrderID:    change.OrderID,
+		FromStatus: string(change.FromStatus),
+		ToStatus:   string(change.ToStatus),
+		ChangedBy:  change.ChangedBy,
+		Reason:     change.Reason,
+		CreatedAt:  change.CreatedAt,
+	}
+}
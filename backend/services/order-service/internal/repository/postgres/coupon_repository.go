@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/infrastructure/models"
+	"gorm.io/gorm"
+)
+
+// CouponRepository handles coupon lookups
+type CouponRepository struct {
+	db *gorm.DB
+}
+
+// NewCouponRepository creates a new coupon repository
+func NewCouponRepository(db *gorm.DB) *CouponRepository {
+	return &CouponRepository{db: db}
+}
+
+// GetByCode retrieves a coupon by its code
+func (r *CouponRepository) GetByCode(ctx context.Context, code string) (*domain.Coupon, error) {
+	var dbCoupon models.Coupon
+	if err := r.db.WithContext(ctx).First(&dbCoupon, "code = ?", domain.NormalizeCouponCode(code)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("%w: %s", domain.ErrCouponNotFound, code)
+		}
+		return nil, fmt.Errorf("failed to get coupon: %w", err)
+	}
+	return dbCoupon.ToDomain(), nil
+}
+
+// redeemCoupon records the order's coupon redemption within the given transaction.
+// The conditional increment locks the coupon row, so concurrent orders cannot
+// exceed the global or per-user redemption limits.
+func redeemCoupon(tx *gorm.DB, order *domain.Order) error {
+	result := tx.Model(&models.Coupon{}).
+		Where("code = ? AND active AND (max_redemptions = 0 OR times_redeemed < max_redemptions)", order.CouponCode).
+		Update("times_redeemed", gorm.Expr("times_redeemed + 1"))
+	if result.Error != nil {
+		return fmt.Errorf("failed to redeem coupon: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrCouponExhausted, order.CouponCode)
+	}
+
+	var coupon models.Coupon
+	if err := tx.First(&coupon, "code = ?", order.CouponCode).Error; err != nil {
+		return fmt.Errorf("failed to get coupon: %w", err)
+	}
+
+	if coupon.PerUserLimit > 0 {
+		var used int64
+		if err := tx.Model(&models.CouponRedemption{}).
+			Where("coupon_id = ? AND user_id = ?", coupon.ID, order.UserID).
+			Count(&used).Error; err != nil {
+			return fmt.Errorf("failed to count coupon redemptions: %w", err)
+		}
+		if err := coupon.ToDomain().CheckUserLimit(used); err != nil {
+			return err
+		}
+	}
+
+	redemption := &models.CouponRedemption{
+		CouponID: coupon.ID,
+		UserID:   order.UserID,
+		OrderID:  order.ID,
+		Amount:   order.DiscountAmount,
+	}
+	if err := tx.Create(redemption).Error; err != nil {
+		return fmt.Errorf("failed to record coupon redemption: %w", err)
+	}
+	return nil
+}
+
+// releaseCoupon returns the order's coupon redemption within the given transaction
+func releaseCoupon(tx *gorm.DB, order *domain.Order) error {
+	var redemption models.CouponRedemption
+	if err := tx.First(&redemption, "order_id = ?", order.ID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return fmt.Errorf("failed to get coupon redemption: %w", err)
+	}
+
+	if err := tx.Delete(&redemption).Error; err != nil {
+		return fmt.Errorf("failed to delete coupon redemption: %w", err)
+	}
+	if err := tx.Model(&models.Coupon{}).
+		Where("id = ? AND times_redeemed > 0", redemption.CouponID).
+		Update("times_redeemed", gorm.Expr("times_redeemed - 1")).Error; err != nil {
+		return fmt.Errorf("failed to release coupon: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/infrastructure/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyRepository handles create-order idempotency key persistence
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository creates a new idempotency repository
+func NewIdempotencyRepository(db *gorm.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Claim atomically inserts the record unless the key is already taken.
+// It returns nil when the claim succeeded, or the existing record otherwise.
+func (r *IdempotencyRepository) Claim(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(models.IdempotencyKeyFromDomain(record))
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to claim idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var existing models.IdempotencyKey
+	if err := r.db.WithContext(ctx).
+		First(&existing, "user_id = ? AND key = ?", record.UserID, record.Key).Error; err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return existing.ToDomain(), nil
+}
+
+// completeIdempotencyKey links a claimed key to the order it created, within
+// the transaction creating the order. It fails if the claim is gone or was
+// completed by another request, so the order is not created twice.
+func completeIdempotencyKey(tx *gorm.DB, userID, key, orderID string) error {
+	result := tx.Model(&models.IdempotencyKey{}).
+		Where("user_id = ? AND key = ? AND (order_id IS NULL OR order_id = '')", userID, key).
+		Updates(map[string]interface{}{
+			"order_id":   orderID,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: claim on key %s was lost", domain.ErrRequestInProgress, key)
+	}
+	return nil
+}
+
+// Release removes an in-progress claim last touched before the given time,
+// so the key can be retried. Completed keys are never released.
+func (r *IdempotencyRepository) Release(ctx context.Context, userID, key string, claimedBefore time.Time) error {
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND key = ? AND (order_id IS NULL OR order_id = '')", userID, key).
+		Where("updated_at <= ?", claimedBefore).
+		Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Discard removes a key whether or not it was completed, so a request that
+// failed can be retried with it
+func (r *IdempotencyRepository) Discard(ctx context.Context, userID, key string) error {
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND key = ?", userID, key).
+		Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return fmt.Errorf("failed to discard idempotency key: %w", err)
+	}
+	return nil
+}
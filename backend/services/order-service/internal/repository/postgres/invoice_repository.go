@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/infrastructure/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// invoiceSequenceName is the sequence invoice numbers are drawn from
+const invoiceSequenceName = "invoice"
+
+// InvoiceRepository handles invoice persistence
+type InvoiceRepository struct {
+	db *gorm.DB
+}
+
+// NewInvoiceRepository creates a new invoice repository
+func NewInvoiceRepository(db *gorm.DB) *InvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+// Create numbers and stores a new invoice. The sequence row stays locked until
+// the invoice commits, so numbers are handed out in order and a rolled back
+// insert never leaves a gap. It returns domain.ErrInvoiceExists when the order
+// already has an invoice.
+func (r *InvoiceRepository) Create(ctx context.Context, invoice *domain.Invoice) error {
+	var dbInvoice *models.Invoice
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.InvoiceSequence{Name: invoiceSequenceName}).Error; err != nil {
+			return fmt.Errorf("failed to initialize invoice sequence: %w", err)
+		}
+
+		var sequence models.InvoiceSequence
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&sequence, "name = ?", invoiceSequenceName).Error; err != nil {
+			return fmt.Errorf("failed to lock invoice sequence: %w", err)
+		}
+
+		// Checked under the lock so concurrent requests cannot both issue one
+		var existing int64
+		if err := tx.Model(&models.Invoice{}).Where("order_id = ?", invoice.OrderID).Count(&existing).Error; err != nil {
+			return fmt.Errorf("failed to check existing invoice: %w", err)
+		}
+		if existing > 0 {
+			return domain.ErrInvoiceExists
+		}
+
+		sequence.LastValue++
+		if err := tx.Save(&sequence).Error; err != nil {
+			return fmt.Errorf("failed to advance invoice sequence: %w", err)
+		}
+
+		invoice.AssignNumber(sequence.LastValue)
+		dbInvoice = models.InvoiceFromDomain(invoice)
+		if err := tx.Create(dbInvoice).Error; err != nil {
+			return fmt.Errorf("failed to create invoice: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	*invoice = *dbInvoice.ToDomain()
+	return nil
+}
+
+// SaveDocuments stores the rendered documents of an invoice
+func (r *InvoiceRepository) SaveDocuments(ctx context.Context, invoice *domain.Invoice) error {
+	invoice.UpdatedAt = time.Now()
+	if err := r.db.WithContext(ctx).Model(&models.Invoice{}).
+		Where("id = ?", invoice.ID).
+		Updates(map[string]interface{}{
+			"pdf":        invoice.PDF,
+			"document":   invoice.Document,
+			"updated_at": invoice.UpdatedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to save invoice documents: %w", err)
+	}
+	return nil
+}
+
+// GetByOrderID retrieves the invoice of an order
+func (r *InvoiceRepository) GetByOrderID(ctx context.Context, orderID string) (*domain.Invoice, error) {
+	var dbInvoice models.Invoice
+	if err := r.db.WithContext(ctx).First(&dbInvoice, "order_id = ?", orderID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrInvoiceNotFound
+		}
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	return dbInvoice.ToDomain(), nil
+}
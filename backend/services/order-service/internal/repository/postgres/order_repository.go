@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -31,11 +32,29 @@ func NewOrderRepository(db *gorm.DB, redisClient *redis.Client) *OrderRepository
 	}
 }
 
-// Create creates a new order
+// Create creates a new order together with its initial status history, coupon
+// redemption and the completion of its idempotency key
 func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error {
 	dbOrder := models.FromDomain(order)
-	if err := r.db.WithContext(ctx).Create(dbOrder).Error; err != nil {
-		return fmt.Errorf("failed to create order: %w", err)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(dbOrder).Error; err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+		order.ID = dbOrder.ID
+		if order.CouponCode != "" {
+			if err := redeemCoupon(tx, order); err != nil {
+				return err
+			}
+		}
+		if key := order.IdempotencyKey(); key != "" {
+			if err := completeIdempotencyKey(tx, order.UserID, key, order.ID); err != nil {
+				return err
+			}
+		}
+		return r.savePendingChanges(tx, order)
+	})
+	if err != nil {
+		return err
 	}
 
 	// Reload to get database-generated IDs
@@ -43,7 +62,7 @@ func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error
 		return fmt.Errorf("failed to reload order: %w", err)
 	}
 
-	// Update the domain object with generated IDs
+	// Update the domain object with generated IDs; this also drops the persisted changes
 	*order = *dbOrder.ToDomain()
 
 	// Cache the order
@@ -61,7 +80,7 @@ func (r *OrderRepository) GetByID(ctx context.Context, orderID string) (*domain.
 	var dbOrder models.Order
 	if err := r.db.WithContext(ctx).Preload("Items").First(&dbOrder, "id = ?", orderID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("order not found")
+			return nil, domain.ErrOrderNotFound
 		}
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
@@ -74,12 +93,41 @@ func (r *OrderRepository) GetByID(ctx context.Context, orderID string) (*domain.
 	return order, nil
 }
 
-// Update updates an existing order
+// Update updates an existing order and its items, and appends any status
+// transitions in the same transaction. The order is only written if it is
+// still at the version it was loaded at; otherwise domain.ErrOrderConflict is
+// returned and nothing is saved.
 func (r *OrderRepository) Update(ctx context.Context, order *domain.Order) error {
 	dbOrder := models.FromDomain(order)
-	if err := r.db.WithContext(ctx).Save(dbOrder).Error; err != nil {
-		return fmt.Errorf("failed to update order: %w", err)
+	dbOrder.Version = order.Version + 1
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(dbOrder).
+			Where("version = ?", order.Version).
+			Select("*").
+			Omit("CreatedAt", "Items").
+			Updates(dbOrder)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update order: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrOrderConflict
+		}
+
+		// Items change status after creation, so upsert them rather than skipping existing rows
+		if err := tx.Save(&dbOrder.Items).Error; err != nil {
+			return fmt.Errorf("failed to update order items: %w", err)
+		}
+		return r.savePendingChanges(tx, order)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderConflict) {
+			// The cached copy is as stale as the caller's, so drop it before it reloads
+			r.invalidateOrderCache(ctx, order.ID)
+		}
+		return err
 	}
+	order.Version = dbOrder.Version
+	order.MarkPersisted()
 
 	// Invalidate cache
 	r.invalidateOrderCache(ctx, order.ID)
@@ -110,6 +158,64 @@ func (r *OrderRepository) GetUserOrders(ctx context.Context, userID string, limi
 	return orders, nil
 }
 
+// Search lists orders matching the filter, newest first, starting strictly
+// after the cursor. It uses keyset pagination over (created_at, id) so deep
+// pages cost the same as the first one.
+func (r *OrderRepository) Search(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) ([]*domain.Order, error) {
+	query := r.db.WithContext(ctx).Model(&models.Order{})
+
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		query = query.Where("status IN ?", statuses)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+	if filter.MinTotal != nil {
+		query = query.Where("total_amount >= ?", *filter.MinTotal)
+	}
+	if filter.MaxTotal != nil {
+		query = query.Where("total_amount <= ?", *filter.MaxTotal)
+	}
+	if filter.PaymentStatus != "" {
+		query = query.Where("payment_status = ?", filter.PaymentStatus)
+	}
+	if filter.ProductID != "" {
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM order_items WHERE order_items.order_id = orders.id AND order_items.product_id = ? AND order_items.deleted_at IS NULL)",
+			filter.ProductID,
+		)
+	}
+	if after != nil {
+		query = query.Where("(created_at, id) < (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	var dbOrders []models.Order
+	if err := query.
+		Preload("Items").
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&dbOrders).Error; err != nil {
+		return nil, fmt.Errorf("failed to search orders: %w", err)
+	}
+
+	orders := make([]*domain.Order, len(dbOrders))
+	for i, dbOrder := range dbOrders {
+		orders[i] = dbOrder.ToDomain()
+	}
+
+	return orders, nil
+}
+
 // GetOrdersByStatus retrieves orders by status
 func (r *OrderRepository) GetOrdersByStatus(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.Order, error) {
 	var dbOrders []models.Order
@@ -134,6 +240,99 @@ func (r *OrderRepository) GetOrdersByStatus(ctx context.Context, status domain.O
 	return orders, nil
 }
 
+// GetWithPendingRefunds retrieves orders with a cancelled or returned line
+// whose refund is still pending and that last changed before the given time
+func (r *OrderRepository) GetWithPendingRefunds(ctx context.Context, changedBefore time.Time, limit int) ([]*domain.Order, error) {
+	var dbOrders []models.Order
+	if err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("id IN (?)", r.db.Model(&models.OrderItem{}).
+			Select("order_id").
+			Where("refund_amount > 0 AND refunded_at IS NULL AND updated_at < ?", changedBefore)).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&dbOrders).Error; err != nil {
+		return nil, fmt.Errorf("failed to get orders with pending refunds: %w", err)
+	}
+
+	orders := make([]*domain.Order, len(dbOrders))
+	for i, dbOrder := range dbOrders {
+		orders[i] = dbOrder.ToDomain()
+	}
+	return orders, nil
+}
+
+// GetWithUncommittedStock retrieves confirmed orders whose stock reservation
+// was not committed yet and that last changed before the given time
+func (r *OrderRepository) GetWithUncommittedStock(ctx context.Context, changedBefore time.Time, limit int) ([]*domain.Order, error) {
+	var dbOrders []models.Order
+	if err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("status IN ?", []string{
+			string(domain.OrderStatusConfirmed),
+			string(domain.OrderStatusProcessing),
+			string(domain.OrderStatusShipped),
+			string(domain.OrderStatusDelivered),
+		}).
+		Where("stock_committed_at IS NULL AND updated_at < ?", changedBefore).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&dbOrders).Error; err != nil {
+		return nil, fmt.Errorf("failed to get orders with uncommitted stock: %w", err)
+	}
+
+	orders := make([]*domain.Order, len(dbOrders))
+	for i, dbOrder := range dbOrders {
+		orders[i] = dbOrder.ToDomain()
+	}
+	return orders, nil
+}
+
+// GetStatusHistory retrieves the status transitions of an order, oldest first
+func (r *OrderRepository) GetStatusHistory(ctx context.Context, orderID string) ([]domain.StatusChange, error) {
+	var dbHistory []models.OrderStatusHistory
+	if err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&dbHistory).Error; err != nil {
+		return nil, fmt.Errorf("failed to get order status history: %w", err)
+	}
+
+	history := make([]domain.StatusChange, len(dbHistory))
+	for i, entry := range dbHistory {
+		history[i] = entry.ToDomain()
+	}
+	return history, nil
+}
+
+// savePendingChanges writes the order's status transitions and outbox events
+// within the given transaction, so they commit or roll back with the order itself.
+// Cancelling an order also gives its coupon redemption back.
+func (r *OrderRepository) savePendingChanges(tx *gorm.DB, order *domain.Order) error {
+	for _, change := range order.PendingStatusChanges() {
+		change.OrderID = order.ID
+		if err := tx.Create(models.StatusChangeFromDomain(change)).Error; err != nil {
+			return fmt.Errorf("failed to record status change: %w", err)
+		}
+		if change.ToStatus == domain.OrderStatusCancelled && order.CouponCode != "" {
+			if err := releaseCoupon(tx, order); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, eventType := range order.PendingEvents() {
+		event, err := models.NewOutboxEvent(eventType, order)
+		if err != nil {
+			return fmt.Errorf("failed to build outbox event: %w", err)
+		}
+		if err := tx.Create(event).Error; err != nil {
+			return fmt.Errorf("failed to write outbox event: %w", err)
+		}
+	}
+	return nil
+}
+
 // cacheOrder caches an order in Redis
 func (r *OrderRepository) cacheOrder(ctx context.Context, order *domain.Order) {
 	if r.redis == nil {
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/infrastructure/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxRepository handles reading and acknowledging outbox events
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// ProcessPending locks up to limit unpublished events in creation order and hands
+// them to publish one by one. Published events are acknowledged in the same
+// transaction. Only the oldest pending event of each order is picked up, so a
+// relay instance never publishes an event while another one still holds an
+// earlier event of the same order; rows locked by another instance are skipped.
+// A failed event is retried with backoff and holds back the later events of its
+// order until then, without blocking every other order; after
+// domain.MaxOutboxAttempts it is dead-lettered and no longer picked up. The first
+// failure is returned.
+func (r *OutboxRepository) ProcessPending(ctx context.Context, limit int, publish func(ctx context.Context, event *domain.OutboxEvent) error) (int, error) {
+	published := 0
+	var publishErr error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var dbEvents []models.OutboxEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND failed_at IS NULL").
+			Where("(next_attempt_at IS NULL OR next_attempt_at <= ?)", now).
+			Where(`NOT EXISTS (SELECT 1 FROM order_outbox earlier
+				WHERE earlier.aggregate_id = order_outbox.aggregate_id AND earlier.created_at < order_outbox.created_at
+				AND earlier.published_at IS NULL AND earlier.failed_at IS NULL)`).
+			Order("created_at ASC").
+			Limit(limit).
+			Find(&dbEvents).Error; err != nil {
+			return fmt.Errorf("failed to get pending outbox events: %w", err)
+		}
+
+		for _, dbEvent := range dbEvents {
+			if err := publish(ctx, dbEvent.ToDomain()); err != nil {
+				if publishErr == nil {
+					publishErr = fmt.Errorf("failed to publish outbox event %s: %w", dbEvent.ID, err)
+				}
+				if err := recordPublishFailure(tx, &dbEvent, err, now); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := tx.Model(&models.OutboxEvent{}).
+				Where("id = ?", dbEvent.ID).
+				Updates(map[string]interface{}{
+					"attempts":     gorm.Expr("attempts + 1"),
+					"published_at": now,
+				}).Error; err != nil {
+				return fmt.Errorf("failed to acknowledge outbox event: %w", err)
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, publishErr
+}
+
+// recordPublishFailure schedules the retry of an event that failed to publish,
+// or dead-letters it once it has used up its attempts
+func recordPublishFailure(tx *gorm.DB, dbEvent *models.OutboxEvent, publishErr error, now time.Time) error {
+	attempts := dbEvent.Attempts + 1
+	updates := map[string]interface{}{
+		"attempts":   attempts,
+		"last_error": publishErr.Error(),
+	}
+	if attempts >= domain.MaxOutboxAttempts {
+		updates["failed_at"] = now
+	} else {
+		updates["next_attempt_at"] = now.Add(domain.OutboxRetryDelay(attempts))
+	}
+
+	if err := tx.Model(&models.OutboxEvent{}).Where("id = ?", dbEvent.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to record outbox event failure: %w", err)
+	}
+	return nil
+}
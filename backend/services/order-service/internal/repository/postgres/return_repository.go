@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/infrastructure/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReturnRepository handles return merchandise authorization persistence
+type ReturnRepository struct {
+	db *gorm.DB
+}
+
+// NewReturnRepository creates a new return repository
+func NewReturnRepository(db *gorm.DB) *ReturnRepository {
+	return &ReturnRepository{db: db}
+}
+
+// Create creates a new return with its items. An item can only be part of one
+// open return at a time; the order row stays locked from that check until the
+// return is created, so concurrent requests for the same items cannot both
+// open one.
+func (r *ReturnRepository) Create(ctx context.Context, orderReturn *domain.OrderReturn) error {
+	dbReturn := models.ReturnFromDomain(orderReturn)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var order models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&order, "id = ?", orderReturn.OrderID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return domain.ErrOrderNotFound
+			}
+			return fmt.Errorf("failed to lock order: %w", err)
+		}
+
+		itemIDs := make([]string, len(orderReturn.Items))
+		for i, item := range orderReturn.Items {
+			itemIDs[i] = item.OrderItemID
+		}
+		var taken []string
+		if err := tx.Model(&models.OrderReturnItem{}).
+			Joins("JOIN order_returns ON order_returns.id = order_return_items.return_id").
+			Where("order_returns.order_id = ? AND order_returns.status NOT IN ?", orderReturn.OrderID,
+				[]string{string(domain.ReturnStatusRejected), string(domain.ReturnStatusRefunded)}).
+			Where("order_return_items.order_item_id IN ?", itemIDs).
+			Pluck("order_return_items.order_item_id", &taken).Error; err != nil {
+			return fmt.Errorf("failed to check open returns: %w", err)
+		}
+		if len(taken) > 0 {
+			return fmt.Errorf("%w: item %s already has an open return", domain.ErrReturnNotAllowed, taken[0])
+		}
+
+		if err := tx.Create(dbReturn).Error; err != nil {
+			return fmt.Errorf("failed to create return: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	*orderReturn = *dbReturn.ToDomain()
+	return nil
+}
+
+// Update persists the return and the restock state of its items
+func (r *ReturnRepository) Update(ctx context.Context, orderReturn *domain.OrderReturn) error {
+	dbReturn := models.ReturnFromDomain(orderReturn)
+	if err := r.db.WithContext(ctx).Session(&gorm.Session{FullSaveAssociations: true}).Save(dbReturn).Error; err != nil {
+		return fmt.Errorf("failed to update return: %w", err)
+	}
+	return nil
+}
+
+// ReceiveLocked loads a return with its row locked and lets receive record
+// its receipt and restock its items. The return is saved with whatever receive
+// changed before the lock is released, even when receive fails part way, so
+// concurrent or retried receipts run one after the other and each sees the
+// items the previous ones restocked. The error of receive is returned after
+// the save.
+func (r *ReturnRepository) ReceiveLocked(ctx context.Context, returnID string, receive func(orderReturn *domain.OrderReturn) error) (*domain.OrderReturn, error) {
+	var orderReturn *domain.OrderReturn
+	var receiveErr error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked models.OrderReturn
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&locked, "id = ?", returnID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return domain.ErrReturnNotFound
+			}
+			return fmt.Errorf("failed to lock return: %w", err)
+		}
+
+		var dbReturn models.OrderReturn
+		if err := tx.Preload("Items").First(&dbReturn, "id = ?", returnID).Error; err != nil {
+			return fmt.Errorf("failed to get return: %w", err)
+		}
+		orderReturn = dbReturn.ToDomain()
+
+		receiveErr = receive(orderReturn)
+		if err := tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(models.ReturnFromDomain(orderReturn)).Error; err != nil {
+			return fmt.Errorf("failed to update return: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if receiveErr != nil {
+		return nil, receiveErr
+	}
+	return orderReturn, nil
+}
+
+// GetByID retrieves a return by ID
+func (r *ReturnRepository) GetByID(ctx context.Context, returnID string) (*domain.OrderReturn, error) {
+	var dbReturn models.OrderReturn
+	if err := r.db.WithContext(ctx).Preload("Items").First(&dbReturn, "id = ?", returnID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrReturnNotFound
+		}
+		return nil, fmt.Errorf("failed to get return: %w", err)
+	}
+	return dbReturn.ToDomain(), nil
+}
+
+// GetByOrderID retrieves the returns of an order, newest first
+func (r *ReturnRepository) GetByOrderID(ctx context.Context, orderID string) ([]*domain.OrderReturn, error) {
+	var dbReturns []models.OrderReturn
+	if err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("order_id = ?", orderID).
+		Order("created_at DESC").
+		Find(&dbReturns).Error; err != nil {
+		return nil, fmt.Errorf("failed to get order returns: %w", err)
+	}
+
+	returns := make([]*domain.OrderReturn, len(dbReturns))
+	for i := range dbReturns {
+		returns[i] = dbReturns[i].ToDomain()
+	}
+	return returns, nil
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/infrastructure/models"
+	"gorm.io/gorm"
+)
+
+// SagaRepository handles checkout saga persistence
+type SagaRepository struct {
+	db *gorm.DB
+}
+
+// NewSagaRepository creates a new saga repository
+func NewSagaRepository(db *gorm.DB) *SagaRepository {
+	return &SagaRepository{db: db}
+}
+
+// Create creates a new checkout saga
+func (r *SagaRepository) Create(ctx context.Context, saga *domain.CheckoutSaga) error {
+	dbSaga := models.SagaFromDomain(saga)
+	if err := r.db.WithContext(ctx).Create(dbSaga).Error; err != nil {
+		return fmt.Errorf("failed to create saga: %w", err)
+	}
+
+	saga.ID = dbSaga.ID
+	return nil
+}
+
+// Update persists the current saga state
+func (r *SagaRepository) Update(ctx context.Context, saga *domain.CheckoutSaga) error {
+	dbSaga := models.SagaFromDomain(saga)
+	if err := r.db.WithContext(ctx).Save(dbSaga).Error; err != nil {
+		return fmt.Errorf("failed to update saga: %w", err)
+	}
+	return nil
+}
+
+// GetByOrderID retrieves the saga for an order
+func (r *SagaRepository) GetByOrderID(ctx context.Context, orderID string) (*domain.CheckoutSaga, error) {
+	var dbSaga models.CheckoutSaga
+	if err := r.db.WithContext(ctx).First(&dbSaga, "order_id = ?", orderID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrSagaNotFound
+		}
+		return nil, fmt.Errorf("failed to get saga: %w", err)
+	}
+	return dbSaga.ToDomain(), nil
+}
+
+// ClaimStale claims up to limit unfinished sagas that have not progressed
+// since the given time. Claiming touches their updated_at, so they are not
+// stale again, and cannot be claimed by another instance, until the claim is
+// as old as the saga was; rows another instance is claiming are skipped.
+func (r *SagaRepository) ClaimStale(ctx context.Context, updatedBefore time.Time, limit int) ([]*domain.CheckoutSaga, error) {
+	terminal := []string{
+		string(domain.SagaStateCompleted),
+		string(domain.SagaStateCompensated),
+	}
+
+	var dbSagas []models.CheckoutSaga
+	if err := r.db.WithContext(ctx).Raw(`UPDATE checkout_sagas SET updated_at = ?
+		WHERE id IN (
+			SELECT id FROM checkout_sagas
+			WHERE state NOT IN ? AND updated_at < ?
+			ORDER BY updated_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, time.Now(), terminal, updatedBefore, limit).
+		Scan(&dbSagas).Error; err != nil {
+		return nil, fmt.Errorf("failed to claim stale sagas: %w", err)
+	}
+
+	sagas := make([]*domain.CheckoutSaga, len(dbSagas))
+	for i, dbSaga := range dbSagas {
+		sagas[i] = dbSaga.ToDomain()
+	}
+	return sagas, nil
+}
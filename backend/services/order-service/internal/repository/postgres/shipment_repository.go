@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/infrastructure/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ShipmentRepository handles shipment and carrier tracking event persistence
+type ShipmentRepository struct {
+	db *gorm.DB
+}
+
+// NewShipmentRepository creates a new shipment repository
+func NewShipmentRepository(db *gorm.DB) *ShipmentRepository {
+	return &ShipmentRepository{db: db}
+}
+
+// Create creates the shipment build packs from the order and its existing
+// shipments. The order row stays locked from loading them until the shipment is
+// created, so concurrent requests cannot pack the same units twice. The order
+// version is bumped with it, so a concurrent order update, e.g. cancelling a
+// line just packed, fails its version check and is applied again. A tracking
+// number already in use is rejected with domain.ErrShipmentNotAllowed.
+func (r *ShipmentRepository) Create(ctx context.Context, orderID string, build func(order *domain.Order, existing []*domain.Shipment) (*domain.Shipment, error)) (*domain.Shipment, error) {
+	var shipment *domain.Shipment
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&locked, "id = ?", orderID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return domain.ErrOrderNotFound
+			}
+			return fmt.Errorf("failed to lock order: %w", err)
+		}
+
+		var dbOrder models.Order
+		if err := tx.Preload("Items").First(&dbOrder, "id = ?", orderID).Error; err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+		var dbShipments []models.Shipment
+		if err := tx.Preload("Items").Where("order_id = ?", orderID).Order("created_at ASC").Find(&dbShipments).Error; err != nil {
+			return fmt.Errorf("failed to get order shipments: %w", err)
+		}
+		existing := make([]*domain.Shipment, len(dbShipments))
+		for i := range dbShipments {
+			existing[i] = dbShipments[i].ToDomain()
+		}
+
+		var err error
+		if shipment, err = build(dbOrder.ToDomain(), existing); err != nil {
+			return err
+		}
+
+		// Tracking numbers are unique across orders, which the order lock does not cover
+		dbShipment := models.ShipmentFromDomain(shipment)
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "carrier"}, {Name: "tracking_number"}},
+			DoNothing: true,
+		}).Omit(clause.Associations).Create(dbShipment)
+		if result.Error != nil {
+			return fmt.Errorf("failed to create shipment: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: tracking number %s is already in use", domain.ErrShipmentNotAllowed, shipment.TrackingNumber)
+		}
+		for i := range dbShipment.Items {
+			dbShipment.Items[i].ShipmentID = dbShipment.ID
+		}
+		if err := tx.Create(&dbShipment.Items).Error; err != nil {
+			return fmt.Errorf("failed to create shipment items: %w", err)
+		}
+		if err := tx.Model(&models.Order{}).
+			Where("id = ?", orderID).
+			UpdateColumn("version", gorm.Expr("version + 1")).Error; err != nil {
+			return fmt.Errorf("failed to update order version: %w", err)
+		}
+
+		shipment = dbShipment.ToDomain()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+// GetByTrackingNumber retrieves the shipment a carrier tracking number belongs to
+func (r *ShipmentRepository) GetByTrackingNumber(ctx context.Context, carrier, trackingNumber string) (*domain.Shipment, error) {
+	var dbShipment models.Shipment
+	if err := r.db.WithContext(ctx).
+		Preload("Items").
+		First(&dbShipment, "carrier = ? AND tracking_number = ?", carrier, trackingNumber).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrShipmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get shipment: %w", err)
+	}
+	return dbShipment.ToDomain(), nil
+}
+
+// GetByOrderID retrieves the shipments of an order, oldest first
+func (r *ShipmentRepository) GetByOrderID(ctx context.Context, orderID string) ([]*domain.Shipment, error) {
+	var dbShipments []models.Shipment
+	if err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&dbShipments).Error; err != nil {
+		return nil, fmt.Errorf("failed to get order shipments: %w", err)
+	}
+
+	shipments := make([]*domain.Shipment, len(dbShipments))
+	for i := range dbShipments {
+		shipments[i] = dbShipments[i].ToDomain()
+	}
+	return shipments, nil
+}
+
+// RecordTrackingEvent stores a carrier event and the shipment state it produced
+// in one transaction. It reports false without touching the shipment when the
+// event was already recorded.
+func (r *ShipmentRepository) RecordTrackingEvent(ctx context.Context, shipment *domain.Shipment, event *domain.TrackingEvent) (bool, error) {
+	recorded := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		dbEvent := models.TrackingEventFromDomain(event)
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(dbEvent)
+		if result.Error != nil {
+			return fmt.Errorf("failed to record tracking event: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.Shipment{}).
+			Where("id = ?", shipment.ID).
+			Updates(map[string]interface{}{
+				"status":        string(shipment.Status),
+				"shipped_at":    shipment.ShippedAt,
+				"delivered_at":  shipment.DeliveredAt,
+				"last_event_at": shipment.LastEventAt,
+				"updated_at":    shipment.UpdatedAt,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to update shipment: %w", err)
+		}
+
+		event.ID = dbEvent.ID
+		recorded = true
+		return nil
+	})
+	return recorded, err
+}
@@ -0,0 +1,328 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
+)
+
+const (
+	sagaStaleAfter       = 2 * time.Minute
+	sagaRecoveryInterval = 1 * time.Minute
+	sagaRecoveryBatch    = 100
+)
+
+// SagaRepository defines the interface for checkout saga persistence
+type SagaRepository interface {
+	Create(ctx context.Context, saga *domain.CheckoutSaga) error
+	Update(ctx context.Context, saga *domain.CheckoutSaga) error
+	GetByOrderID(ctx context.Context, orderID string) (*domain.CheckoutSaga, error)
+	ClaimStale(ctx context.Context, updatedBefore time.Time, limit int) ([]*domain.CheckoutSaga, error)
+}
+
+// ProductCatalog resolves authoritative product data from product-service
+type ProductCatalog interface {
+	GetProducts(ctx context.Context, productIDs []string) (map[string]domain.Product, error)
+}
+
+// StockReserver reserves, releases, commits and restocks stock in inventory-service
+type StockReserver interface {
+	ReserveStock(ctx context.Context, orderID string, items []domain.OrderItem, ttl time.Duration) (string, []string, error)
+	ReleaseReservation(ctx context.Context, reservationID, orderID string) error
+	CommitReservation(ctx context.Context, orderID string) error
+	RestockItem(ctx context.Context, productID, variantID string, quantity int32, reason string) error
+}
+
+// PaymentGateway creates, looks up, cancels and refunds payments in payment-service
+type PaymentGateway interface {
+	CreatePaymentIntent(ctx context.Context, order *domain.Order) (string, error)
+	GetOrderPayment(ctx context.Context, orderID string) (*domain.Payment, error)
+	CancelPayment(ctx context.Context, paymentID, reason string) error
+	RefundPayment(ctx context.Context, paymentID string, amount int64, currency, reason, idempotencyKey string) error
+}
+
+// priceItems resolves the unit price of every item from the product catalog and
+// snapshots the product name, SKU and variant so later catalog edits don't rewrite the order.
+func (uc *OrderUseCase) priceItems(ctx context.Context, items []domain.OrderItem) error {
+	productIDs := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if !seen[item.ProductID] {
+			seen[item.ProductID] = true
+			productIDs = append(productIDs, item.ProductID)
+		}
+	}
+
+	products, err := uc.products.GetProducts(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range items {
+		product, ok := products[items[i].ProductID]
+		if !ok {
+			return fmt.Errorf("%w: %s", domain.ErrProductNotFound, items[i].ProductID)
+		}
+		if err := items[i].ApplyProduct(product); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveAddresses snapshots the shipping and billing addresses from the
+// user's address book. The default address is used when no shipping address is
+// given, and billing falls back to shipping.
+func (uc *OrderUseCase) resolveAddresses(ctx context.Context, userID, shippingAddressID, billingAddressID string) (domain.Address, domain.Address, error) {
+	addresses, err := uc.addresses.ListAddresses(ctx, userID)
+	if err != nil {
+		return domain.Address{}, domain.Address{}, err
+	}
+
+	shipping, err := domain.SelectAddress(addresses, shippingAddressID)
+	if err != nil {
+		return domain.Address{}, domain.Address{}, err
+	}
+	if billingAddressID == "" || billingAddressID == shipping.ID {
+		return shipping, shipping, nil
+	}
+
+	billing, err := domain.SelectAddress(addresses, billingAddressID)
+	if err != nil {
+		return domain.Address{}, domain.Address{}, err
+	}
+	return shipping, billing, nil
+}
+
+// runCheckoutSaga reserves stock and creates a payment intent for a persisted order,
+// compensating the completed steps if any of them fails.
+func (uc *OrderUseCase) runCheckoutSaga(ctx context.Context, order *domain.Order) error {
+	saga, err := domain.NewCheckoutSaga(order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to start checkout: %w", err)
+	}
+
+	if err := uc.sagaRepo.Create(ctx, saga); err != nil {
+		logger.Errorf("Failed to create checkout saga for order %s: %v", order.ID, err)
+		// Without a saga nothing retries this; the order is otherwise
+		// cancelled as unpaid when the payment window closes
+		_ = uc.cancelOrder(ctx, order.ID, "checkout could not be started")
+		return fmt.Errorf("failed to start checkout: %w", err)
+	}
+
+	// Step 1: reserve stock
+	saga.Advance(domain.SagaStateReservingStock)
+	if err := uc.sagaRepo.Update(ctx, saga); err != nil {
+		return uc.abortSaga(ctx, saga, err)
+	}
+
+	// Hold stock slightly past the payment window so the unpaid order is
+	// cancelled, and its reservation released, before inventory expires it;
+	// once the order is confirmed the reservation is committed instead
+	reservationID, lineIDs, err := uc.inventory.ReserveStock(ctx, order.ID, order.Items, uc.paymentWindow+unpaidOrderSweepInterval)
+	if err != nil {
+		return uc.abortSaga(ctx, saga, err)
+	}
+	reservationLines := make(map[string]string, len(order.Items))
+	for i := range order.Items {
+		reservationLines[order.Items[i].ID] = lineIDs[i]
+	}
+
+	// Save the reservation lines now, since a saga resumed after a crash
+	// reloads the order and would not have them otherwise
+	err = uc.updateOrder(ctx, order, func(o *domain.Order) error {
+		for i := range o.Items {
+			o.Items[i].ReservationID = reservationLines[o.Items[i].ID]
+		}
+		return nil
+	})
+	if err != nil {
+		return uc.abortSaga(ctx, saga, fmt.Errorf("failed to save reservation lines: %w", err))
+	}
+
+	saga.RecordReservation(reservationID)
+	if err := uc.sagaRepo.Update(ctx, saga); err != nil {
+		return uc.abortSaga(ctx, saga, err)
+	}
+
+	// Step 2: create payment intent
+	saga.Advance(domain.SagaStateCreatingPayment)
+	if err := uc.sagaRepo.Update(ctx, saga); err != nil {
+		return uc.abortSaga(ctx, saga, err)
+	}
+
+	paymentIntentID, err := uc.payments.CreatePaymentIntent(ctx, order)
+	if err != nil {
+		return uc.abortSaga(ctx, saga, err)
+	}
+
+	saga.RecordPaymentIntent(paymentIntentID)
+	if err := uc.sagaRepo.Update(ctx, saga); err != nil {
+		return uc.abortSaga(ctx, saga, err)
+	}
+
+	// Step 3: attach the payment to the order
+	if err := uc.completeSaga(ctx, saga, order); err != nil {
+		return uc.abortSaga(ctx, saga, err)
+	}
+
+	return nil
+}
+
+// completeSaga attaches the payment intent to the order and finishes the saga
+func (uc *OrderUseCase) completeSaga(ctx context.Context, saga *domain.CheckoutSaga, order *domain.Order) error {
+	err := uc.updateOrder(ctx, order, func(o *domain.Order) error {
+		o.PaymentID = saga.PaymentIntentID
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("Failed to attach payment to order %s: %v", order.ID, err)
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+
+	saga.Advance(domain.SagaStateCompleted)
+	if err := uc.sagaRepo.Update(ctx, saga); err != nil {
+		logger.Errorf("Failed to complete checkout saga for order %s: %v", order.ID, err)
+		return fmt.Errorf("failed to update saga: %w", err)
+	}
+
+	return nil
+}
+
+// abortSaga compensates a failed checkout and returns the original failure
+func (uc *OrderUseCase) abortSaga(ctx context.Context, saga *domain.CheckoutSaga, cause error) error {
+	logger.Errorf("Checkout saga failed for order %s in state %s: %v", saga.OrderID, saga.State, cause)
+
+	saga.StartCompensation(cause.Error())
+	if err := uc.sagaRepo.Update(ctx, saga); err != nil {
+		logger.Errorf("Failed to record compensation for order %s: %v", saga.OrderID, err)
+	}
+
+	if err := uc.compensateSaga(ctx, saga); err != nil {
+		logger.Errorf("Compensation incomplete for order %s, will retry: %v", saga.OrderID, err)
+	}
+
+	return fmt.Errorf("checkout failed: %w", cause)
+}
+
+// compensateSaga undoes the external side effects of a checkout in reverse order.
+// Every step is idempotent, so an interrupted compensation can simply be rerun.
+func (uc *OrderUseCase) compensateSaga(ctx context.Context, saga *domain.CheckoutSaga) error {
+	// Look the payment up by order ID, which also covers a payment intent that
+	// was created but whose ID never reached the saga
+	payment, err := uc.payments.GetOrderPayment(ctx, saga.OrderID)
+	if err != nil && !errors.Is(err, domain.ErrPaymentNotFound) {
+		return err
+	}
+	if payment != nil && !payment.IsClosed() {
+		if err := uc.payments.CancelPayment(ctx, payment.ID, "checkout compensation"); err != nil {
+			return err
+		}
+	}
+
+	// Release every line by order ID, which also covers a reservation that was
+	// made but never recorded
+	if err := uc.inventory.ReleaseReservation(ctx, "", saga.OrderID); err != nil {
+		return err
+	}
+
+	if err := uc.cancelOrder(ctx, saga.OrderID, "checkout failed: "+saga.FailureReason); err != nil {
+		return err
+	}
+
+	saga.Advance(domain.SagaStateCompensated)
+	if err := uc.sagaRepo.Update(ctx, saga); err != nil {
+		return fmt.Errorf("failed to update saga: %w", err)
+	}
+
+	logger.Infof("Checkout saga compensated for order %s", saga.OrderID)
+	return nil
+}
+
+// cancelOrder cancels an order that could not be placed, unless it already is
+func (uc *OrderUseCase) cancelOrder(ctx context.Context, orderID, reason string) error {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		logger.Errorf("Failed to load order %s for cancellation: %v", orderID, err)
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	err = uc.updateOrder(ctx, order, func(o *domain.Order) error {
+		if o.Status == domain.OrderStatusCancelled {
+			return errNothingToSave
+		}
+		return o.Cancel(domain.SystemActor, reason)
+	})
+	if err != nil {
+		logger.Errorf("Failed to cancel order %s: %v", orderID, err)
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+	return nil
+}
+
+// RecoverSagas resumes or compensates checkout sagas left unfinished by a crashed instance.
+// Sagas whose forward steps all succeeded are completed; any other saga is compensated.
+// Each stale saga is claimed first, so instances running the job side by side
+// do not recover the same one.
+func (uc *OrderUseCase) RecoverSagas(ctx context.Context) error {
+	sagas, err := uc.sagaRepo.ClaimStale(ctx, time.Now().Add(-sagaStaleAfter), sagaRecoveryBatch)
+	if err != nil {
+		return fmt.Errorf("failed to claim stale sagas: %w", err)
+	}
+
+	for _, saga := range sagas {
+		if saga.HasForwardStepsDone() {
+			order, err := uc.orderRepo.GetByID(ctx, saga.OrderID)
+			if err != nil {
+				logger.Errorf("Failed to load order %s for saga recovery: %v", saga.OrderID, err)
+				continue
+			}
+			if err := uc.completeSaga(ctx, saga, order); err != nil {
+				logger.Errorf("Failed to resume checkout saga for order %s: %v", saga.OrderID, err)
+				continue
+			}
+			logger.Infof("Resumed checkout saga for order %s", saga.OrderID)
+			continue
+		}
+
+		if saga.State != domain.SagaStateCompensating {
+			saga.StartCompensation("checkout interrupted in state " + string(saga.State))
+			if err := uc.sagaRepo.Update(ctx, saga); err != nil {
+				logger.Errorf("Failed to record compensation for order %s: %v", saga.OrderID, err)
+				continue
+			}
+		}
+
+		if err := uc.compensateSaga(ctx, saga); err != nil {
+			logger.Errorf("Failed to compensate checkout saga for order %s: %v", saga.OrderID, err)
+		}
+	}
+
+	return nil
+}
+
+// StartSagaRecoveryJob starts a background job that recovers unfinished checkout sagas
+func (uc *OrderUseCase) StartSagaRecoveryJob(ctx context.Context) {
+	ticker := time.NewTicker(sagaRecoveryInterval)
+	go func() {
+		if err := uc.RecoverSagas(ctx); err != nil {
+			logger.Errorf("Failed to recover checkout sagas: %v", err)
+		}
+		for {
+			select {
+			case <-ticker.C:
+				if err := uc.RecoverSagas(ctx); err != nil {
+					logger.Errorf("Failed to recover checkout sagas: %v", err)
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	logger.Info("Started checkout saga recovery job")
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// newPlacedOrder returns a pending order of two lines that was just persisted,
+// before its checkout saga ran
+func newPlacedOrder(t *testing.T) *domain.Order {
+	t.Helper()
+
+	order := newPaidOrder(t)
+	order.PaymentID = ""
+	order.Status = domain.OrderStatusPending
+	return order
+}
+
+func TestRunCheckoutSaga(t *testing.T) {
+	tests := []struct {
+		name         string
+		reserveErr   error
+		createErr    error
+		wantErr      bool
+		wantSaga     domain.SagaState
+		wantStatus   domain.OrderStatus
+		wantPayment  string // Payment attached to the order
+		wantReleased bool
+	}{
+		{
+			name:        "every step succeeds",
+			wantSaga:    domain.SagaStateCompleted,
+			wantStatus:  domain.OrderStatusPending,
+			wantPayment: "pay-order-1",
+		},
+		{
+			name:         "stock is unavailable",
+			reserveErr:   domain.ErrStockUnavailable,
+			wantErr:      true,
+			wantSaga:     domain.SagaStateCompensated,
+			wantStatus:   domain.OrderStatusCancelled,
+			wantReleased: true,
+		},
+		{
+			name:         "payment intent cannot be created",
+			createErr:    domain.ErrPaymentNotCreated,
+			wantErr:      true,
+			wantSaga:     domain.SagaStateCompensated,
+			wantStatus:   domain.OrderStatusCancelled,
+			wantReleased: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := newPlacedOrder(t)
+			orders := newFakeOrderRepo(order)
+			sagas := newFakeSagaRepo()
+			inventory := &fakeInventory{reserveErr: tt.reserveErr}
+			payments := newFakePayments()
+			payments.createErr = tt.createErr
+			uc := NewOrderUseCase(orders, sagas, nil, nil, nil, nil, nil, nil, inventory, payments, domain.PricingPolicy{}, time.Hour)
+
+			err := uc.runCheckoutSaga(context.Background(), order)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runCheckoutSaga error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.reserveErr != nil && !errors.Is(err, tt.reserveErr) {
+				t.Errorf("error = %v, want %v", err, tt.reserveErr)
+			}
+
+			if saga := sagas.get(order.ID); saga.State != tt.wantSaga {
+				t.Errorf("saga is %s, want %s", saga.State, tt.wantSaga)
+			}
+			stored := orders.get(order.ID)
+			if stored.Status != tt.wantStatus || stored.PaymentID != tt.wantPayment {
+				t.Errorf("order is %s with payment %q, want %s with payment %q", stored.Status, stored.PaymentID, tt.wantStatus, tt.wantPayment)
+			}
+			if tt.reserveErr == nil {
+				for _, item := range stored.Items {
+					if item.ReservationID == "" {
+						t.Errorf("item %s has no reservation line", item.ID)
+					}
+				}
+			}
+			if (len(inventory.released) > 0) != tt.wantReleased {
+				t.Errorf("released reservations of %v, want released %v", inventory.released, tt.wantReleased)
+			}
+		})
+	}
+}
+
+func TestRecoverSagas(t *testing.T) {
+	stale := time.Now().Add(-2 * sagaStaleAfter)
+
+	tests := []struct {
+		name          string
+		state         domain.SagaState
+		updatedAt     time.Time
+		payment       string // Status of a payment created for the order, if any
+		releaseErr    error  // Returned by inventory when releasing stock
+		updateErr     error  // Returned when saving the order
+		wantSaga      domain.SagaState
+		wantStatus    domain.OrderStatus
+		wantPayment   string // Payment attached to the order
+		wantReleased  bool
+		wantCancelled bool // Payment cancelled in payment-service
+	}{
+		{
+			name:        "saga whose forward steps all succeeded is completed",
+			state:       domain.SagaStatePaymentCreated,
+			updatedAt:   stale,
+			payment:     domain.PaymentStatusPending,
+			wantSaga:    domain.SagaStateCompleted,
+			wantStatus:  domain.OrderStatusPending,
+			wantPayment: "pay-order-1",
+		},
+		{
+			name:         "saga interrupted while reserving stock is compensated",
+			state:        domain.SagaStateReservingStock,
+			updatedAt:    stale,
+			wantSaga:     domain.SagaStateCompensated,
+			wantStatus:   domain.OrderStatusCancelled,
+			wantReleased: true,
+		},
+		{
+			name:          "payment created but not recorded is cancelled",
+			state:         domain.SagaStateCreatingPayment,
+			updatedAt:     stale,
+			payment:       domain.PaymentStatusPending,
+			wantSaga:      domain.SagaStateCompensated,
+			wantStatus:    domain.OrderStatusCancelled,
+			wantReleased:  true,
+			wantCancelled: true,
+		},
+		{
+			name:         "interrupted compensation is finished",
+			state:        domain.SagaStateCompensating,
+			updatedAt:    stale,
+			wantSaga:     domain.SagaStateCompensated,
+			wantStatus:   domain.OrderStatusCancelled,
+			wantReleased: true,
+		},
+		{
+			name:       "compensation whose release failed is retried later",
+			state:      domain.SagaStateReservingStock,
+			updatedAt:  stale,
+			releaseErr: errors.New("inventory unavailable"),
+			wantSaga:   domain.SagaStateCompensating,
+			wantStatus: domain.OrderStatusPending,
+		},
+		{
+			name:         "compensation whose order cancellation failed is retried later",
+			state:        domain.SagaStateReservingStock,
+			updatedAt:    stale,
+			updateErr:    errors.New("database unavailable"),
+			wantSaga:     domain.SagaStateCompensating,
+			wantStatus:   domain.OrderStatusPending,
+			wantReleased: true,
+		},
+		{
+			name:       "saga still in progress is left alone",
+			state:      domain.SagaStateReservingStock,
+			updatedAt:  time.Now(),
+			wantSaga:   domain.SagaStateReservingStock,
+			wantStatus: domain.OrderStatusPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			order := newPlacedOrder(t)
+			orders := newFakeOrderRepo(order)
+			orders.updateErr = tt.updateErr
+			sagas := newFakeSagaRepo(&domain.CheckoutSaga{ID: "saga-1", OrderID: order.ID, State: tt.state, UpdatedAt: tt.updatedAt})
+			inventory := &fakeInventory{releaseErr: tt.releaseErr}
+			payments := newFakePayments()
+			if tt.payment != "" {
+				payments.set(order.ID, "pay-"+order.ID, tt.payment)
+				if tt.state == domain.SagaStatePaymentCreated {
+					saga := sagas.get(order.ID)
+					saga.PaymentIntentID = "pay-" + order.ID
+					sagas.Update(ctx, saga)
+				}
+			}
+			uc := NewOrderUseCase(orders, sagas, nil, nil, nil, nil, nil, nil, inventory, payments, domain.PricingPolicy{}, time.Hour)
+
+			// A second run right after must find the saga claimed or finished
+			for i := 0; i < 2; i++ {
+				if err := uc.RecoverSagas(ctx); err != nil {
+					t.Fatalf("RecoverSagas: %v", err)
+				}
+			}
+
+			if saga := sagas.get(order.ID); saga.State != tt.wantSaga {
+				t.Errorf("saga is %s, want %s", saga.State, tt.wantSaga)
+			}
+			stored := orders.get(order.ID)
+			if stored.Status != tt.wantStatus || stored.PaymentID != tt.wantPayment {
+				t.Errorf("order is %s with payment %q, want %s with payment %q", stored.Status, stored.PaymentID, tt.wantStatus, tt.wantPayment)
+			}
+			wantReleases := 0
+			if tt.wantReleased {
+				wantReleases = 1
+			}
+			if len(inventory.released) != wantReleases {
+				t.Errorf("reservation released %d times, want %d", len(inventory.released), wantReleases)
+			}
+			if (len(payments.cancelled) > 0) != tt.wantCancelled {
+				t.Errorf("cancelled payments %v, want cancelled %v", payments.cancelled, tt.wantCancelled)
+			}
+		})
+	}
+}
@@ -0,0 +1,758 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+var errNotImplemented = errors.New("not implemented by fake")
+
+// fakeOrderRepo keeps orders in memory. Orders are copied in and out, so a
+// use case only sees its own changes once it saved them, and updates are
+// checked against the version the order was loaded at.
+type fakeOrderRepo struct {
+	mu      sync.Mutex
+	orders  map[string]*domain.Order
+	updates int
+	// keys, when set, has the idempotency key of a created order completed,
+	// as the repository does in the same transaction
+	keys *fakeIdempotencyRepo
+	// coupons, when set, has the coupon of a created order redeemed, as the
+	// repository does in the same transaction
+	coupons *fakeCouponRepo
+	// interfere, when set, changes the stored order as another writer would,
+	// just before the next update
+	interfere func(stored *domain.Order)
+	// updateErr, when set, fails every update
+	updateErr error
+}
+
+func newFakeOrderRepo(orders ...*domain.Order) *fakeOrderRepo {
+	repo := &fakeOrderRepo{orders: make(map[string]*domain.Order)}
+	for _, order := range orders {
+		repo.orders[order.ID] = copyOrder(order)
+	}
+	return repo
+}
+
+func copyOrder(order *domain.Order) *domain.Order {
+	c := *order
+	c.Items = append([]domain.OrderItem(nil), order.Items...)
+	c.MarkPersisted()
+	return &c
+}
+
+func (r *fakeOrderRepo) Create(ctx context.Context, order *domain.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if order.ID == "" {
+		order.ID = fmt.Sprintf("order-%d", len(r.orders)+1)
+	}
+	if order.CouponCode != "" && r.coupons != nil {
+		if err := r.coupons.redeem(order); err != nil {
+			return err
+		}
+	}
+	if key := order.IdempotencyKey(); key != "" && r.keys != nil {
+		if err := r.keys.complete(order.UserID, key, order.ID); err != nil {
+			return err
+		}
+	}
+	for i := range order.Items {
+		order.Items[i].ID = fmt.Sprintf("%s-item-%d", order.ID, i+1)
+		order.Items[i].OrderID = order.ID
+	}
+	r.orders[order.ID] = copyOrder(order)
+	order.MarkPersisted()
+	return nil
+}
+
+func (r *fakeOrderRepo) GetByID(ctx context.Context, orderID string) (*domain.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, ok := r.orders[orderID]
+	if !ok {
+		return nil, domain.ErrOrderNotFound
+	}
+	return copyOrder(order), nil
+}
+
+func (r *fakeOrderRepo) Update(ctx context.Context, order *domain.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	stored, ok := r.orders[order.ID]
+	if !ok {
+		return domain.ErrOrderNotFound
+	}
+	if r.interfere != nil {
+		r.interfere(stored)
+		stored.Version++
+		r.interfere = nil
+	}
+	if stored.Version != order.Version {
+		return domain.ErrOrderConflict
+	}
+	order.Version++
+	r.orders[order.ID] = copyOrder(order)
+	r.updates++
+	order.MarkPersisted()
+	return nil
+}
+
+func (r *fakeOrderRepo) GetUserOrders(ctx context.Context, userID string, limit, offset int) ([]*domain.Order, error) {
+	return nil, errNotImplemented
+}
+
+func (r *fakeOrderRepo) GetOrdersByStatus(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []*domain.Order
+	for _, order := range r.orders {
+		if order.Status == status {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+	return orders, nil
+}
+
+// Search applies the filter and keyset the way the repository's query does
+func (r *fakeOrderRepo) Search(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) ([]*domain.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []*domain.Order
+	for _, order := range r.orders {
+		if matchesFilter(order, filter) && (after == nil || listedAfter(order, *after)) {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return listedAfter(orders[j], domain.CursorAfter(orders[i]))
+	})
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
+// listedAfter reports whether the order comes after the cursor when orders
+// are listed newest first by (created_at, id)
+func listedAfter(order *domain.Order, cursor domain.OrderCursor) bool {
+	if !order.CreatedAt.Equal(cursor.CreatedAt) {
+		return order.CreatedAt.Before(cursor.CreatedAt)
+	}
+	return order.ID < cursor.ID
+}
+
+func matchesFilter(order *domain.Order, filter domain.OrderFilter) bool {
+	if filter.UserID != "" && order.UserID != filter.UserID {
+		return false
+	}
+	if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, order.Status) {
+		return false
+	}
+	if filter.CreatedFrom != nil && order.CreatedAt.Before(*filter.CreatedFrom) {
+		return false
+	}
+	if filter.CreatedTo != nil && !order.CreatedAt.Before(*filter.CreatedTo) {
+		return false
+	}
+	if filter.MinTotal != nil && order.TotalAmount < *filter.MinTotal {
+		return false
+	}
+	if filter.MaxTotal != nil && order.TotalAmount > *filter.MaxTotal {
+		return false
+	}
+	if filter.PaymentStatus != "" && order.PaymentStatus != filter.PaymentStatus {
+		return false
+	}
+	if filter.ProductID != "" {
+		return slices.ContainsFunc(order.Items, func(item domain.OrderItem) bool {
+			return item.ProductID == filter.ProductID
+		})
+	}
+	return true
+}
+
+func (r *fakeOrderRepo) GetStatusHistory(ctx context.Context, orderID string) ([]domain.StatusChange, error) {
+	return nil, errNotImplemented
+}
+
+func (r *fakeOrderRepo) GetWithPendingRefunds(ctx context.Context, changedBefore time.Time, limit int) ([]*domain.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []*domain.Order
+	for _, order := range r.orders {
+		if len(order.PendingRefunds()) > 0 {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+	return orders, nil
+}
+
+func (r *fakeOrderRepo) GetWithUncommittedStock(ctx context.Context, changedBefore time.Time, limit int) ([]*domain.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []*domain.Order
+	for _, order := range r.orders {
+		if order.NeedsStockCommit() {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+	return orders, nil
+}
+
+// get returns the stored order, failing the lookup loudly in tests
+func (r *fakeOrderRepo) get(orderID string) *domain.Order {
+	order, err := r.GetByID(context.Background(), orderID)
+	if err != nil {
+		panic(err)
+	}
+	return order
+}
+
+// fakeIdempotencyRepo keeps create-order idempotency keys in memory
+type fakeIdempotencyRepo struct {
+	mu      sync.Mutex
+	records map[string]*domain.IdempotencyRecord // by user ID and key
+}
+
+func newFakeIdempotencyRepo(records ...*domain.IdempotencyRecord) *fakeIdempotencyRepo {
+	repo := &fakeIdempotencyRepo{records: make(map[string]*domain.IdempotencyRecord)}
+	for _, record := range records {
+		c := *record
+		repo.records[record.UserID+"/"+record.Key] = &c
+	}
+	return repo
+}
+
+func (r *fakeIdempotencyRepo) Claim(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.records[record.UserID+"/"+record.Key]; ok {
+		c := *existing
+		return &c, nil
+	}
+	c := *record
+	r.records[record.UserID+"/"+record.Key] = &c
+	return nil, nil
+}
+
+func (r *fakeIdempotencyRepo) Release(ctx context.Context, userID, key string, claimedBefore time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if record, ok := r.records[userID+"/"+key]; ok && !record.IsCompleted() && !record.UpdatedAt.After(claimedBefore) {
+		delete(r.records, userID+"/"+key)
+	}
+	return nil
+}
+
+func (r *fakeIdempotencyRepo) Discard(ctx context.Context, userID, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.records, userID+"/"+key)
+	return nil
+}
+
+// complete links a claimed key to its order, failing if the claim was lost
+func (r *fakeIdempotencyRepo) complete(userID, key, orderID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.records[userID+"/"+key]
+	if !ok || record.IsCompleted() {
+		return fmt.Errorf("%w: claim on key %s was lost", domain.ErrRequestInProgress, key)
+	}
+	record.OrderID = orderID
+	return nil
+}
+
+// get returns the record holding the key, or nil if it is free
+func (r *fakeIdempotencyRepo) get(userID, key string) *domain.IdempotencyRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.records[userID+"/"+key]
+	if !ok {
+		return nil
+	}
+	c := *record
+	return &c
+}
+
+// fakeAddressBook is user-service holding the same addresses for every user
+type fakeAddressBook []domain.Address
+
+func (b fakeAddressBook) ListAddresses(ctx context.Context, userID string) ([]domain.Address, error) {
+	return b, nil
+}
+
+// fakeCouponRepo keeps coupons by code and the redemptions of their orders
+type fakeCouponRepo struct {
+	mu          sync.Mutex
+	coupons     map[string]*domain.Coupon
+	redemptions []domain.CouponRedemption
+	// interfere, when set, changes the stored coupon as a concurrent order
+	// would, just before the next redemption
+	interfere func(stored *domain.Coupon)
+}
+
+func newFakeCouponRepo(coupons ...*domain.Coupon) *fakeCouponRepo {
+	repo := &fakeCouponRepo{coupons: make(map[string]*domain.Coupon)}
+	for _, coupon := range coupons {
+		c := *coupon
+		repo.coupons[coupon.Code] = &c
+	}
+	return repo
+}
+
+func (r *fakeCouponRepo) GetByCode(ctx context.Context, code string) (*domain.Coupon, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	coupon, ok := r.coupons[domain.NormalizeCouponCode(code)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrCouponNotFound, code)
+	}
+	c := *coupon
+	return &c, nil
+}
+
+// redeem applies the limits the repository enforces when it saves an order
+func (r *fakeCouponRepo) redeem(order *domain.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	coupon, ok := r.coupons[order.CouponCode]
+	if ok && r.interfere != nil {
+		r.interfere(coupon)
+		r.interfere = nil
+	}
+	if !ok || !coupon.Active || (coupon.MaxRedemptions > 0 && coupon.TimesRedeemed >= coupon.MaxRedemptions) {
+		return fmt.Errorf("%w: %s", domain.ErrCouponExhausted, order.CouponCode)
+	}
+	var used int64
+	for _, redemption := range r.redemptions {
+		if redemption.CouponID == coupon.ID && redemption.UserID == order.UserID {
+			used++
+		}
+	}
+	if err := coupon.CheckUserLimit(used); err != nil {
+		return err
+	}
+	coupon.TimesRedeemed++
+	r.redemptions = append(r.redemptions, domain.CouponRedemption{
+		CouponID: coupon.ID,
+		OrderID:  order.ID,
+		UserID:   order.UserID,
+		Amount:   order.DiscountAmount,
+	})
+	return nil
+}
+
+// fakeCatalog is product-service holding the given products by ID
+type fakeCatalog map[string]domain.Product
+
+func (c fakeCatalog) GetProducts(ctx context.Context, productIDs []string) (map[string]domain.Product, error) {
+	products := make(map[string]domain.Product, len(productIDs))
+	for _, id := range productIDs {
+		if product, ok := c[id]; ok {
+			products[id] = product
+		}
+	}
+	return products, nil
+}
+
+// fakePayments is payment-service holding at most one payment per order. It
+// refuses to refund payments that were not collected, as payment-service does.
+type fakePayments struct {
+	mu        sync.Mutex
+	payments  map[string]*domain.Payment // by order ID
+	refunds   map[string]int64           // amount refunded by idempotency key
+	cancelled []string                   // IDs of the payments cancelled
+	lookupErr error                      // returned by GetOrderPayment when set
+	createErr error                      // returned by CreatePaymentIntent when set
+	refundErr error                      // returned by RefundPayment when set
+}
+
+func newFakePayments() *fakePayments {
+	return &fakePayments{
+		payments: make(map[string]*domain.Payment),
+		refunds:  make(map[string]int64),
+	}
+}
+
+// set gives an order a payment in the given status
+func (p *fakePayments) set(orderID, paymentID, status string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.payments[orderID] = &domain.Payment{ID: paymentID, Status: status}
+}
+
+func (p *fakePayments) find(paymentID string) *domain.Payment {
+	for _, payment := range p.payments {
+		if payment.ID == paymentID {
+			return payment
+		}
+	}
+	return nil
+}
+
+func (p *fakePayments) CreatePaymentIntent(ctx context.Context, order *domain.Order) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.createErr != nil {
+		return "", p.createErr
+	}
+	payment := &domain.Payment{ID: "pay-" + order.ID, Status: domain.PaymentStatusPending}
+	p.payments[order.ID] = payment
+	return payment.ID, nil
+}
+
+func (p *fakePayments) GetOrderPayment(ctx context.Context, orderID string) (*domain.Payment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.lookupErr != nil {
+		return nil, p.lookupErr
+	}
+	payment, ok := p.payments[orderID]
+	if !ok {
+		return nil, domain.ErrPaymentNotFound
+	}
+	c := *payment
+	return &c, nil
+}
+
+func (p *fakePayments) CancelPayment(ctx context.Context, paymentID, reason string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	payment := p.find(paymentID)
+	if payment == nil {
+		return domain.ErrPaymentNotFound
+	}
+	if payment.Status != domain.PaymentStatusPending && payment.Status != domain.PaymentStatusAuthorized {
+		return domain.ErrPaymentNotCancellable
+	}
+	payment.Status = domain.PaymentStatusCancelled
+	p.cancelled = append(p.cancelled, paymentID)
+	return nil
+}
+
+func (p *fakePayments) RefundPayment(ctx context.Context, paymentID string, amount int64, currency, reason, idempotencyKey string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	payment := p.find(paymentID)
+	if payment == nil {
+		return domain.ErrPaymentNotFound
+	}
+	if p.refundErr != nil {
+		return p.refundErr
+	}
+	if _, ok := p.refunds[idempotencyKey]; ok {
+		return nil
+	}
+	switch payment.Status {
+	case domain.PaymentStatusSucceeded, domain.PaymentStatusCaptured, domain.PaymentStatusPartiallyRefunded:
+	default:
+		return fmt.Errorf("rpc error: code = FailedPrecondition desc = payment %s is %s", paymentID, payment.Status)
+	}
+	p.refunds[idempotencyKey] = amount
+	payment.Status = domain.PaymentStatusPartiallyRefunded
+	return nil
+}
+
+// refunded returns the total amount refunded
+func (p *fakePayments) refunded() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var total int64
+	for _, amount := range p.refunds {
+		total += amount
+	}
+	return total
+}
+
+// fakeInventory is inventory-service; it only records what was released, committed and restocked
+type fakeInventory struct {
+	mu         sync.Mutex
+	released   []string // Order IDs whose whole reservation was released
+	committed  []string // Order IDs whose reservation was committed
+	restocked  int32    // Units added back to the available stock
+	reserveErr error    // returned by ReserveStock when set
+	releaseErr error    // returned by ReleaseReservation when set
+	commitErr  error    // returned by CommitReservation when set
+	// restockErr holds the error RestockItem returns for a product, if any
+	restockErr map[string]error
+}
+
+func (i *fakeInventory) ReserveStock(ctx context.Context, orderID string, items []domain.OrderItem, ttl time.Duration) (string, []string, error) {
+	if i.reserveErr != nil {
+		return "", nil, i.reserveErr
+	}
+	reservationIDs := make([]string, len(items))
+	for n := range items {
+		reservationIDs[n] = fmt.Sprintf("res-%s-%d", orderID, n+1)
+	}
+	return "res-" + orderID, reservationIDs, nil
+}
+
+func (i *fakeInventory) ReleaseReservation(ctx context.Context, reservationID, orderID string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.releaseErr != nil {
+		return i.releaseErr
+	}
+	if reservationID == "" {
+		i.released = append(i.released, orderID)
+	}
+	return nil
+}
+
+func (i *fakeInventory) CommitReservation(ctx context.Context, orderID string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.commitErr != nil {
+		return i.commitErr
+	}
+	i.committed = append(i.committed, orderID)
+	return nil
+}
+
+func (i *fakeInventory) RestockItem(ctx context.Context, productID, variantID string, quantity int32, reason string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if err := i.restockErr[productID]; err != nil {
+		return err
+	}
+	i.restocked += quantity
+	return nil
+}
+
+// fakeSagaRepo keeps checkout sagas in memory by order ID
+type fakeSagaRepo struct {
+	mu    sync.Mutex
+	sagas map[string]*domain.CheckoutSaga
+}
+
+func newFakeSagaRepo(sagas ...*domain.CheckoutSaga) *fakeSagaRepo {
+	repo := &fakeSagaRepo{sagas: make(map[string]*domain.CheckoutSaga)}
+	for _, saga := range sagas {
+		c := *saga
+		repo.sagas[saga.OrderID] = &c
+	}
+	return repo
+}
+
+func (r *fakeSagaRepo) Create(ctx context.Context, saga *domain.CheckoutSaga) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saga.ID = "saga-" + saga.OrderID
+	c := *saga
+	r.sagas[saga.OrderID] = &c
+	return nil
+}
+
+func (r *fakeSagaRepo) Update(ctx context.Context, saga *domain.CheckoutSaga) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := *saga
+	r.sagas[saga.OrderID] = &c
+	return nil
+}
+
+func (r *fakeSagaRepo) GetByOrderID(ctx context.Context, orderID string) (*domain.CheckoutSaga, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saga, ok := r.sagas[orderID]
+	if !ok {
+		return nil, domain.ErrSagaNotFound
+	}
+	c := *saga
+	return &c, nil
+}
+
+// ClaimStale touches the sagas it returns, as the repository does
+func (r *fakeSagaRepo) ClaimStale(ctx context.Context, updatedBefore time.Time, limit int) ([]*domain.CheckoutSaga, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sagas []*domain.CheckoutSaga
+	for _, saga := range r.sagas {
+		if saga.State == domain.SagaStateCompleted || saga.State == domain.SagaStateCompensated || !saga.UpdatedAt.Before(updatedBefore) {
+			continue
+		}
+		saga.UpdatedAt = time.Now()
+		c := *saga
+		sagas = append(sagas, &c)
+	}
+	return sagas, nil
+}
+
+// get returns the stored saga, failing the lookup loudly in tests
+func (r *fakeSagaRepo) get(orderID string) *domain.CheckoutSaga {
+	saga, err := r.GetByOrderID(context.Background(), orderID)
+	if err != nil {
+		panic(err)
+	}
+	return saga
+}
+
+// fakeShipmentRepo keeps shipments in memory by order ID
+type fakeShipmentRepo struct {
+	mu        sync.Mutex
+	shipments map[string][]*domain.Shipment
+}
+
+func newFakeShipmentRepo(shipments ...*domain.Shipment) *fakeShipmentRepo {
+	repo := &fakeShipmentRepo{shipments: make(map[string][]*domain.Shipment)}
+	for _, shipment := range shipments {
+		repo.add(shipment)
+	}
+	return repo
+}
+
+// add stores a shipment as if it had been created
+func (r *fakeShipmentRepo) add(shipment *domain.Shipment) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.shipments[shipment.OrderID] = append(r.shipments[shipment.OrderID], shipment)
+}
+
+func (r *fakeShipmentRepo) Create(ctx context.Context, orderID string, build func(order *domain.Order, existing []*domain.Shipment) (*domain.Shipment, error)) (*domain.Shipment, error) {
+	return nil, errNotImplemented
+}
+
+func (r *fakeShipmentRepo) GetByTrackingNumber(ctx context.Context, carrier, trackingNumber string) (*domain.Shipment, error) {
+	return nil, errNotImplemented
+}
+
+func (r *fakeShipmentRepo) GetByOrderID(ctx context.Context, orderID string) ([]*domain.Shipment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*domain.Shipment(nil), r.shipments[orderID]...), nil
+}
+
+func (r *fakeShipmentRepo) RecordTrackingEvent(ctx context.Context, shipment *domain.Shipment, event *domain.TrackingEvent) (bool, error) {
+	return false, errNotImplemented
+}
+
+// fakeReturnRepo keeps returns in memory; ReceiveLocked runs one receipt at a
+// time, as the row lock does
+type fakeReturnRepo struct {
+	mu      sync.Mutex
+	returns map[string]*domain.OrderReturn
+}
+
+func newFakeReturnRepo(returns ...*domain.OrderReturn) *fakeReturnRepo {
+	repo := &fakeReturnRepo{returns: make(map[string]*domain.OrderReturn)}
+	for _, orderReturn := range returns {
+		repo.returns[orderReturn.ID] = copyReturn(orderReturn)
+	}
+	return repo
+}
+
+func copyReturn(orderReturn *domain.OrderReturn) *domain.OrderReturn {
+	c := *orderReturn
+	c.Items = append([]domain.OrderReturnItem(nil), orderReturn.Items...)
+	return &c
+}
+
+func (r *fakeReturnRepo) Create(ctx context.Context, orderReturn *domain.OrderReturn) error {
+	return errNotImplemented
+}
+
+func (r *fakeReturnRepo) Update(ctx context.Context, orderReturn *domain.OrderReturn) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.returns[orderReturn.ID] = copyReturn(orderReturn)
+	return nil
+}
+
+func (r *fakeReturnRepo) ReceiveLocked(ctx context.Context, returnID string, receive func(orderReturn *domain.OrderReturn) error) (*domain.OrderReturn, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.returns[returnID]
+	if !ok {
+		return nil, domain.ErrReturnNotFound
+	}
+	orderReturn := copyReturn(stored)
+	err := receive(orderReturn)
+	r.returns[returnID] = copyReturn(orderReturn)
+	if err != nil {
+		return nil, err
+	}
+	return orderReturn, nil
+}
+
+func (r *fakeReturnRepo) GetByID(ctx context.Context, returnID string) (*domain.OrderReturn, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	orderReturn, ok := r.returns[returnID]
+	if !ok {
+		return nil, domain.ErrReturnNotFound
+	}
+	return copyReturn(orderReturn), nil
+}
+
+func (r *fakeReturnRepo) GetByOrderID(ctx context.Context, orderID string) ([]*domain.OrderReturn, error) {
+	return nil, errNotImplemented
+}
+
+// fakeInvoiceRepo keeps invoices in memory, numbering them in order of creation
+type fakeInvoiceRepo struct {
+	mu       sync.Mutex
+	invoices map[string]*domain.Invoice // by order ID
+	issued   int64
+}
+
+func newFakeInvoiceRepo() *fakeInvoiceRepo {
+	return &fakeInvoiceRepo{invoices: make(map[string]*domain.Invoice)}
+}
+
+func (r *fakeInvoiceRepo) Create(ctx context.Context, invoice *domain.Invoice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.invoices[invoice.OrderID]; ok {
+		return domain.ErrInvoiceExists
+	}
+	r.issued++
+	invoice.Sequence = r.issued
+	invoice.Number = fmt.Sprintf("INV-%06d", r.issued)
+	c := *invoice
+	r.invoices[invoice.OrderID] = &c
+	return nil
+}
+
+func (r *fakeInvoiceRepo) SaveDocuments(ctx context.Context, invoice *domain.Invoice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := *invoice
+	r.invoices[invoice.OrderID] = &c
+	return nil
+}
+
+func (r *fakeInvoiceRepo) GetByOrderID(ctx context.Context, orderID string) (*domain.Invoice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	invoice, ok := r.invoices[orderID]
+	if !ok {
+		return nil, domain.ErrInvoiceNotFound
+	}
+	c := *invoice
+	return &c, nil
+}
+
+// fakeInvoiceRenderer renders every invoice as its number
+type fakeInvoiceRenderer struct{}
+
+func (fakeInvoiceRenderer) RenderPDF(invoice *domain.Invoice) ([]byte, error) {
+	return []byte(invoice.Number), nil
+}
+
+func (fakeInvoiceRenderer) RenderJSON(invoice *domain.Invoice) ([]byte, error) {
+	return []byte(invoice.Number), nil
+}
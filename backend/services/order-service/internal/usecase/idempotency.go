@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
+)
+
+// idempotencyClaimTimeout is how long an in-progress claim blocks retries before
+// it is considered abandoned by a crashed instance
+const idempotencyClaimTimeout = 5 * time.Minute
+
+// IdempotencyRepository defines the interface for create-order idempotency keys
+type IdempotencyRepository interface {
+	Claim(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error)
+	Release(ctx context.Context, userID, key string, claimedBefore time.Time) error
+	Discard(ctx context.Context, userID, key string) error
+}
+
+// claimIdempotencyKey claims the key for a new request. If the key was already
+// used for the same request it returns the order that request created, once
+// its checkout has finished. The key points at the order from the moment the
+// order is saved, so while the checkout saga still runs a replay is told the
+// request is in progress rather than given an order without its payment
+// intent. A checkout that failed frees the key for a new attempt, as the
+// failed request does itself.
+func (uc *OrderUseCase) claimIdempotencyKey(ctx context.Context, key, userID, fingerprint string) (*domain.Order, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		existing, err := uc.idempotencyRepo.Claim(ctx, domain.NewIdempotencyRecord(key, userID, fingerprint))
+		if err != nil {
+			logger.Errorf("Failed to claim idempotency key %s: %v", key, err)
+			return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+		}
+		if existing == nil {
+			return nil, nil
+		}
+
+		if !existing.Matches(fingerprint) {
+			return nil, domain.ErrIdempotencyKeyReused
+		}
+
+		if existing.IsCompleted() {
+			order, err := uc.orderRepo.GetByID(ctx, existing.OrderID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get order for idempotency key: %w", err)
+			}
+			switch {
+			case order.PaymentID != "":
+				return order, nil
+			case order.Status != domain.OrderStatusCancelled:
+				// The checkout saga has not attached the payment intent yet
+				return nil, domain.ErrRequestInProgress
+			}
+
+			// The checkout failed and its order was cancelled
+			if err := uc.idempotencyRepo.Discard(ctx, userID, key); err != nil {
+				return nil, fmt.Errorf("failed to discard idempotency key of failed checkout: %w", err)
+			}
+			continue
+		}
+
+		// The first request is still running, unless its claim was abandoned
+		staleBefore := time.Now().Add(-idempotencyClaimTimeout)
+		if existing.UpdatedAt.After(staleBefore) {
+			return nil, domain.ErrRequestInProgress
+		}
+		if err := uc.idempotencyRepo.Release(ctx, userID, key, staleBefore); err != nil {
+			return nil, fmt.Errorf("failed to release abandoned idempotency key: %w", err)
+		}
+	}
+
+	return nil, domain.ErrRequestInProgress
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// newCheckoutUseCase returns a use case placing orders of user-1 for the
+// products p1 and p2, with order-1 already placed
+func newCheckoutUseCase(t *testing.T, keys *fakeIdempotencyRepo, inventory *fakeInventory) (*OrderUseCase, *fakeOrderRepo) {
+	t.Helper()
+
+	orders := newFakeOrderRepo(newPaidOrder(t))
+	orders.keys = keys
+	addresses := fakeAddressBook{{ID: "addr-1", AddressLine1: "1 Main St", IsDefault: true}}
+	catalog := fakeCatalog{
+		"p1": {ID: "p1", Name: "Mug", Price: 1000, Status: domain.ProductStatusActive},
+		"p2": {ID: "p2", Name: "Plate", Price: 2000, Status: domain.ProductStatusActive},
+	}
+	uc := NewOrderUseCase(orders, newFakeSagaRepo(), keys, nil, nil, nil, addresses, catalog, inventory, newFakePayments(), domain.PricingPolicy{}, time.Hour)
+	return uc, orders
+}
+
+// cartItems returns a fresh cart, as each request decodes its own
+func cartItems() []domain.OrderItem {
+	return []domain.OrderItem{
+		{ProductID: "p1", Quantity: 1},
+		{ProductID: "p2", Quantity: 1},
+	}
+}
+
+func TestCreateOrderIdempotencyKey(t *testing.T) {
+	fingerprint := domain.CreateOrderFingerprint("user-1", "", "", "CREDIT_CARD", "", cartItems())
+	claim := func(fingerprint, orderID string, claimedAgo time.Duration) *domain.IdempotencyRecord {
+		record := domain.NewIdempotencyRecord("key-1", "user-1", fingerprint)
+		record.OrderID = orderID
+		record.UpdatedAt = time.Now().Add(-claimedAgo)
+		return record
+	}
+
+	tests := []struct {
+		name        string
+		stored      *domain.IdempotencyRecord // Record already holding the key, if any
+		order       func(o *domain.Order)     // Changes order-1 before the request
+		wantErr     error
+		wantOrderID string // Order returned; empty when a new order is placed
+	}{
+		{name: "new key places the order"},
+		{
+			name:        "replayed request returns the order it created",
+			stored:      claim(fingerprint, "order-1", time.Hour),
+			wantOrderID: "order-1",
+		},
+		{
+			name:    "replay while the checkout saga is running",
+			stored:  claim(fingerprint, "order-1", time.Second),
+			order:   func(o *domain.Order) { o.Status, o.PaymentID = domain.OrderStatusPending, "" },
+			wantErr: domain.ErrRequestInProgress,
+		},
+		{
+			name:   "replay of a failed checkout places the order again",
+			stored: claim(fingerprint, "order-1", time.Hour),
+			order:  func(o *domain.Order) { o.Status, o.PaymentID = domain.OrderStatusCancelled, "" },
+		},
+		{
+			name:    "key reused with another cart is rejected",
+			stored:  claim("other-cart", "order-1", time.Hour),
+			wantErr: domain.ErrIdempotencyKeyReused,
+		},
+		{
+			name:    "key held by a running request",
+			stored:  claim(fingerprint, "", time.Second),
+			wantErr: domain.ErrRequestInProgress,
+		},
+		{
+			name:   "key abandoned by a crashed request is taken over",
+			stored: claim(fingerprint, "", 2*idempotencyClaimTimeout),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := newFakeIdempotencyRepo()
+			if tt.stored != nil {
+				keys = newFakeIdempotencyRepo(tt.stored)
+			}
+			uc, orders := newCheckoutUseCase(t, keys, &fakeInventory{})
+			if tt.order != nil {
+				tt.order(orders.orders["order-1"])
+			}
+
+			order, err := uc.CreateOrder(context.Background(), "key-1", "user-1", "", "", "CREDIT_CARD", "", cartItems())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CreateOrder error = %v, want %v", err, tt.wantErr)
+				}
+				if len(orders.orders) != 1 {
+					t.Errorf("%d orders stored, want only order-1", len(orders.orders))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateOrder: %v", err)
+			}
+
+			if tt.wantOrderID != "" {
+				if order.ID != tt.wantOrderID {
+					t.Errorf("CreateOrder returned %s, want the replayed %s", order.ID, tt.wantOrderID)
+				}
+				if len(orders.orders) != 1 {
+					t.Errorf("%d orders stored, want no new order", len(orders.orders))
+				}
+				return
+			}
+			if order.ID == "order-1" {
+				t.Fatal("CreateOrder returned the existing order, want a new one")
+			}
+			if record := keys.get("user-1", "key-1"); record == nil || record.OrderID != order.ID {
+				t.Errorf("key record = %+v, want it completed with %s", record, order.ID)
+			}
+		})
+	}
+}
+
+// A request whose checkout failed frees its key, so the client's retry places
+// the order and a retry of that one replays it
+func TestCreateOrderRetryAfterFailedCheckout(t *testing.T) {
+	ctx := context.Background()
+	keys := newFakeIdempotencyRepo()
+	inventory := &fakeInventory{reserveErr: domain.ErrStockUnavailable}
+	uc, orders := newCheckoutUseCase(t, keys, inventory)
+
+	if _, err := uc.CreateOrder(ctx, "key-1", "user-1", "", "", "CREDIT_CARD", "", cartItems()); !errors.Is(err, domain.ErrStockUnavailable) {
+		t.Fatalf("CreateOrder error = %v, want %v", err, domain.ErrStockUnavailable)
+	}
+	if record := keys.get("user-1", "key-1"); record != nil {
+		t.Fatalf("key still held by %+v after the checkout failed", record)
+	}
+
+	inventory.reserveErr = nil
+	placed, err := uc.CreateOrder(ctx, "key-1", "user-1", "", "", "CREDIT_CARD", "", cartItems())
+	if err != nil {
+		t.Fatalf("retried CreateOrder: %v", err)
+	}
+	replayed, err := uc.CreateOrder(ctx, "key-1", "user-1", "", "", "CREDIT_CARD", "", cartItems())
+	if err != nil {
+		t.Fatalf("replayed CreateOrder: %v", err)
+	}
+	if replayed.ID != placed.ID {
+		t.Errorf("replay returned %s, want %s", replayed.ID, placed.ID)
+	}
+	if stored := orders.get(placed.ID); stored.Status != domain.OrderStatusPending {
+		t.Errorf("placed order is %s, want %s", stored.Status, domain.OrderStatusPending)
+	}
+	// order-1, the cancelled first attempt and the placed order
+	if len(orders.orders) != 3 {
+		t.Errorf("%d orders stored, want 3", len(orders.orders))
+	}
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
+)
+
+// InvoiceRepository defines the interface for invoice persistence
+type InvoiceRepository interface {
+	Create(ctx context.Context, invoice *domain.Invoice) error
+	SaveDocuments(ctx context.Context, invoice *domain.Invoice) error
+	GetByOrderID(ctx context.Context, orderID string) (*domain.Invoice, error)
+}
+
+// InvoiceRenderer produces the documents of an invoice
+type InvoiceRenderer interface {
+	RenderPDF(invoice *domain.Invoice) ([]byte, error)
+	RenderJSON(invoice *domain.Invoice) ([]byte, error)
+}
+
+// InvoiceService issues and renders invoices for delivered orders
+type InvoiceService struct {
+	orderRepo   OrderRepository
+	invoiceRepo InvoiceRepository
+	renderer    InvoiceRenderer
+	seller      domain.Seller
+}
+
+// NewInvoiceService creates a new invoice service
+func NewInvoiceService(orderRepo OrderRepository, invoiceRepo InvoiceRepository, renderer InvoiceRenderer, seller domain.Seller) *InvoiceService {
+	return &InvoiceService{
+		orderRepo:   orderRepo,
+		invoiceRepo: invoiceRepo,
+		renderer:    renderer,
+		seller:      seller,
+	}
+}
+
+// GetInvoice returns the invoice of an order, issuing it on first request.
+// Documents are rendered from the stored snapshot, so an invoice whose
+// rendering failed earlier is completed by the next call with the same number.
+// A userID, when given, must own the order: the invoice of another user's
+// order is reported as not found and never issued.
+func (s *InvoiceService) GetInvoice(ctx context.Context, orderID, userID string) (*domain.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetByOrderID(ctx, orderID)
+	if errors.Is(err, domain.ErrInvoiceNotFound) {
+		invoice, err = s.issueInvoice(ctx, orderID, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if userID != "" && invoice.UserID != userID {
+		return nil, domain.ErrInvoiceNotFound
+	}
+
+	if !invoice.IsRendered() {
+		if err := s.render(ctx, invoice); err != nil {
+			return nil, err
+		}
+	}
+	return invoice, nil
+}
+
+// issueInvoice snapshots the order and stores it under the next invoice
+// number. The ownership is checked first, so another user's request does not
+// use up a number.
+func (s *InvoiceService) issueInvoice(ctx context.Context, orderID, userID string) (*domain.Invoice, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		logger.Errorf("Failed to get order %s: %v", orderID, err)
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if userID != "" && order.UserID != userID {
+		return nil, domain.ErrInvoiceNotFound
+	}
+
+	invoice, err := domain.NewInvoice(order, s.seller)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.invoiceRepo.Create(ctx, invoice); err != nil {
+		if errors.Is(err, domain.ErrInvoiceExists) {
+			// Issued by a concurrent request in the meantime
+			return s.invoiceRepo.GetByOrderID(ctx, orderID)
+		}
+		logger.Errorf("Failed to issue invoice for order %s: %v", orderID, err)
+		return nil, fmt.Errorf("failed to issue invoice: %w", err)
+	}
+
+	logger.Infof("Invoice %s issued for order %s", invoice.Number, orderID)
+	return invoice, nil
+}
+
+// render produces and stores the PDF and UBL JSON documents of an invoice
+func (s *InvoiceService) render(ctx context.Context, invoice *domain.Invoice) error {
+	pdf, err := s.renderer.RenderPDF(invoice)
+	if err != nil {
+		logger.Errorf("Failed to render PDF of invoice %s: %v", invoice.Number, err)
+		return fmt.Errorf("failed to render invoice PDF: %w", err)
+	}
+	document, err := s.renderer.RenderJSON(invoice)
+	if err != nil {
+		logger.Errorf("Failed to render JSON of invoice %s: %v", invoice.Number, err)
+		return fmt.Errorf("failed to render invoice JSON: %w", err)
+	}
+
+	invoice.PDF = pdf
+	invoice.Document = document
+	if err := s.invoiceRepo.SaveDocuments(ctx, invoice); err != nil {
+		logger.Errorf("Failed to store documents of invoice %s: %v", invoice.Number, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// Each step runs against the invoices the previous ones issued
+func TestGetInvoiceOwnership(t *testing.T) {
+	order := newPaidOrder(t)
+	order.Status = domain.OrderStatusDelivered
+	invoices := newFakeInvoiceRepo()
+	service := NewInvoiceService(newFakeOrderRepo(order), invoices, fakeInvoiceRenderer{}, domain.Seller{})
+
+	steps := []struct {
+		name       string
+		userID     string
+		wantErr    error
+		wantIssued int64
+	}{
+		{name: "other user before the invoice is issued", userID: "user-2", wantErr: domain.ErrInvoiceNotFound, wantIssued: 0},
+		{name: "owner has it issued", userID: "user-1", wantIssued: 1},
+		{name: "other user once it is issued", userID: "user-2", wantErr: domain.ErrInvoiceNotFound, wantIssued: 1},
+		{name: "without a user, as for admins", wantIssued: 1},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			invoice, err := service.GetInvoice(context.Background(), order.ID, step.userID)
+			if step.wantErr != nil {
+				if !errors.Is(err, step.wantErr) {
+					t.Errorf("GetInvoice error = %v, want %v", err, step.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("GetInvoice: %v", err)
+			} else if invoice.UserID != order.UserID {
+				t.Errorf("invoice of user %s, want %s", invoice.UserID, order.UserID)
+			}
+			if invoices.issued != step.wantIssued {
+				t.Errorf("%d invoice numbers used, want %d", invoices.issued, step.wantIssued)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
+)
+
+const (
+	unpaidOrderSweepInterval = 1 * time.Minute
+	unpaidOrderSweepBatch    = 100
+)
+
+// ExpireUnpaidOrders cancels PENDING orders whose payment window has passed.
+// The payment intent is cancelled first; an order whose payment went through
+// in the meantime is confirmed instead. The cancellation releases the stock
+// reservation and writes an ORDER_CANCELLED event to the outbox.
+func (uc *OrderUseCase) ExpireUnpaidOrders(ctx context.Context) error {
+	cutoff := time.Now().Add(-uc.paymentWindow)
+	filter := domain.OrderFilter{
+		Statuses:  []domain.OrderStatus{domain.OrderStatusPending},
+		CreatedTo: &cutoff,
+	}
+
+	// Page through every overdue order so skipped ones cannot starve the rest
+	var after *domain.OrderCursor
+	for {
+		orders, err := uc.orderRepo.Search(ctx, filter, after, unpaidOrderSweepBatch)
+		if err != nil {
+			return fmt.Errorf("failed to get unpaid orders: %w", err)
+		}
+
+		for _, order := range orders {
+			if err := uc.expireUnpaidOrder(ctx, order); err != nil {
+				logger.Errorf("Failed to expire unpaid order %s: %v", order.ID, err)
+			}
+		}
+
+		if len(orders) < unpaidOrderSweepBatch {
+			return nil
+		}
+		cursor := domain.CursorAfter(orders[len(orders)-1])
+		after = &cursor
+	}
+}
+
+// expireUnpaidOrder cancels a single unpaid order and releases what it holds
+func (uc *OrderUseCase) expireUnpaidOrder(ctx context.Context, order *domain.Order) error {
+	// Orders still in checkout are left to the saga recovery job
+	saga, err := uc.sagaRepo.GetByOrderID(ctx, order.ID)
+	if err != nil && !errors.Is(err, domain.ErrSagaNotFound) {
+		return err
+	}
+	if saga != nil && saga.State != domain.SagaStateCompleted && saga.State != domain.SagaStateCompensated {
+		return nil
+	}
+
+	reason := fmt.Sprintf("payment not completed within %s", uc.paymentWindow)
+	payment, err := uc.cancelOrderPayment(ctx, order, reason)
+	if err != nil {
+		return err
+	}
+	if payment != nil && payment.IsCollected() {
+		return uc.confirmPaidOrder(ctx, order, *payment)
+	}
+
+	// Release every line by order ID before cancelling, so an order whose
+	// release failed stays pending and is released on the next sweep;
+	// inventory may already have expired the lines
+	if err := uc.inventory.ReleaseReservation(ctx, "", order.ID); err != nil {
+		return err
+	}
+
+	err = uc.updateOrder(ctx, order, func(o *domain.Order) error {
+		if o.Status != domain.OrderStatusPending {
+			return fmt.Errorf("order is %s, no longer awaiting payment", o.Status)
+		}
+		if payment != nil {
+			o.PaymentStatus = payment.Status
+		}
+		return o.Cancel(domain.SystemActor, reason)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+
+	logger.Infof("Order %s cancelled: %s", order.ID, reason)
+	return nil
+}
+
+// cancelOrderPayment cancels the payment intent of an order being cancelled
+// and returns the payment as it is left, or nil when the order has none. A
+// payment that went through in the meantime cannot be cancelled and is
+// returned collected, so the caller can refund or keep it; one still being
+// processed is an error, as its outcome is not known yet.
+func (uc *OrderUseCase) cancelOrderPayment(ctx context.Context, order *domain.Order, reason string) (*domain.Payment, error) {
+	if order.PaymentID == "" {
+		return nil, nil
+	}
+
+	err := uc.payments.CancelPayment(ctx, order.PaymentID, reason)
+	if err == nil {
+		return &domain.Payment{ID: order.PaymentID, Status: domain.PaymentStatusCancelled}, nil
+	}
+	if !errors.Is(err, domain.ErrPaymentNotCancellable) {
+		return nil, err
+	}
+
+	// The payment either went through or failed
+	payment, err := uc.payments.GetOrderPayment(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !payment.IsCollected() && !payment.IsClosed() {
+		return nil, fmt.Errorf("payment %s is %s and cannot be cancelled yet", payment.ID, payment.Status)
+	}
+	return payment, nil
+}
+
+// confirmPaidOrder confirms an overdue order whose payment went through after all
+func (uc *OrderUseCase) confirmPaidOrder(ctx context.Context, order *domain.Order, payment domain.Payment) error {
+	err := uc.updateOrder(ctx, order, func(o *domain.Order) error {
+		return o.ConfirmPayment(payment, domain.SystemActor)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+
+	if err := uc.commitStock(ctx, order); err != nil {
+		logger.Errorf("Stock of order %s left uncommitted: %v", order.ID, err)
+	}
+
+	logger.Infof("Order %s was paid before its payment window closed, confirmed", order.ID)
+	return nil
+}
+
+// StartUnpaidOrderExpiryJob starts a background job that cancels unpaid orders
+func (uc *OrderUseCase) StartUnpaidOrderExpiryJob(ctx context.Context) {
+	ticker := time.NewTicker(unpaidOrderSweepInterval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := uc.ExpireUnpaidOrders(ctx); err != nil {
+					logger.Errorf("Failed to expire unpaid orders: %v", err)
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	logger.Infof("Started unpaid order expiry job (payment window %s)", uc.paymentWindow)
+}
@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
 	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
@@ -15,22 +17,95 @@ type OrderRepository interface {
 	Update(ctx context.Context, order *domain.Order) error
 	GetUserOrders(ctx context.Context, userID string, limit, offset int) ([]*domain.Order, error)
 	GetOrdersByStatus(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.Order, error)
+	Search(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) ([]*domain.Order, error)
+	GetStatusHistory(ctx context.Context, orderID string) ([]domain.StatusChange, error)
+	GetWithPendingRefunds(ctx context.Context, changedBefore time.Time, limit int) ([]*domain.Order, error)
+	GetWithUncommittedStock(ctx context.Context, changedBefore time.Time, limit int) ([]*domain.Order, error)
+}
+
+// AddressBook looks up the saved addresses of a user
+type AddressBook interface {
+	ListAddresses(ctx context.Context, userID string) ([]domain.Address, error)
+}
+
+// CouponRepository defines the interface for coupon lookups
+type CouponRepository interface {
+	GetByCode(ctx context.Context, code string) (*domain.Coupon, error)
 }
 
 // OrderUseCase handles order business logic
 type OrderUseCase struct {
-	orderRepo OrderRepository
+	orderRepo       OrderRepository
+	sagaRepo        SagaRepository
+	idempotencyRepo IdempotencyRepository
+	couponRepo      CouponRepository
+	returnRepo      ReturnRepository
+	shipmentRepo    ShipmentRepository
+	addresses       AddressBook
+	products        ProductCatalog
+	inventory       StockReserver
+	payments        PaymentGateway
+	pricing         domain.PricingPolicy
+	paymentWindow   time.Duration
 }
 
 // NewOrderUseCase creates a new order use case
-func NewOrderUseCase(orderRepo OrderRepository) *OrderUseCase {
+func NewOrderUseCase(orderRepo OrderRepository, sagaRepo SagaRepository, idempotencyRepo IdempotencyRepository, couponRepo CouponRepository, returnRepo ReturnRepository, shipmentRepo ShipmentRepository, addresses AddressBook, products ProductCatalog, inventory StockReserver, payments PaymentGateway, pricing domain.PricingPolicy, paymentWindow time.Duration) *OrderUseCase {
 	return &OrderUseCase{
-		orderRepo: orderRepo,
+		orderRepo:       orderRepo,
+		sagaRepo:        sagaRepo,
+		idempotencyRepo: idempotencyRepo,
+		couponRepo:      couponRepo,
+		returnRepo:      returnRepo,
+		shipmentRepo:    shipmentRepo,
+		addresses:       addresses,
+		products:        products,
+		inventory:       inventory,
+		payments:        payments,
+		pricing:         pricing,
+		paymentWindow:   paymentWindow,
 	}
 }
 
-// CreateOrder creates a new order
-func (uc *OrderUseCase) CreateOrder(ctx context.Context, userID, shippingAddress, billingAddress, paymentMethod string, items []domain.OrderItem) (*domain.Order, error) {
+// orderUpdateAttempts bounds how often a change is applied again to an order
+// other writers keep changing
+const orderUpdateAttempts = 5
+
+// errNothingToSave is returned by an order change that left the order as it was
+var errNothingToSave = errors.New("nothing to save")
+
+// updateOrder applies change to the order and saves it. When another writer
+// saved the order first, the order is reloaded and the change applied again,
+// so change must only modify the order and decide from its current state. On
+// return the order holds the state the change was last applied to.
+func (uc *OrderUseCase) updateOrder(ctx context.Context, order *domain.Order, change func(o *domain.Order) error) error {
+	for attempt := 1; ; attempt++ {
+		if err := change(order); err != nil {
+			if errors.Is(err, errNothingToSave) {
+				return nil
+			}
+			return err
+		}
+
+		err := uc.orderRepo.Update(ctx, order)
+		if !errors.Is(err, domain.ErrOrderConflict) || attempt == orderUpdateAttempts {
+			return err
+		}
+
+		fresh, err := uc.orderRepo.GetByID(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+		*order = *fresh
+	}
+}
+
+// CreateOrder prices the items, applies the coupon, shipping and tax, persists
+// the order and runs the checkout saga. When an idempotency key is given, a
+// retried request returns the order created by the first one instead of
+// placing a duplicate; until the first request's checkout has finished, the
+// retry fails with domain.ErrRequestInProgress and can be retried later.
+func (uc *OrderUseCase) CreateOrder(ctx context.Context, idempotencyKey, userID, shippingAddressID, billingAddressID, paymentMethod, couponCode string, items []domain.OrderItem) (*domain.Order, error) {
 	// Validate input
 	if userID == "" {
 		return nil, fmt.Errorf("user ID is required")
@@ -39,6 +114,55 @@ func (uc *OrderUseCase) CreateOrder(ctx context.Context, userID, shippingAddress
 		return nil, fmt.Errorf("order must have at least one item")
 	}
 
+	if idempotencyKey == "" {
+		return uc.placeOrder(ctx, "", userID, shippingAddressID, billingAddressID, paymentMethod, couponCode, items)
+	}
+
+	fingerprint := domain.CreateOrderFingerprint(userID, shippingAddressID, billingAddressID, paymentMethod, couponCode, items)
+	existing, err := uc.claimIdempotencyKey(ctx, idempotencyKey, userID, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		logger.Infof("Replaying order %s for idempotency key %s", existing.ID, idempotencyKey)
+		return existing, nil
+	}
+
+	// The key is completed together with the order, so a retry can never
+	// place a second one
+	order, err := uc.placeOrder(ctx, idempotencyKey, userID, shippingAddressID, billingAddressID, paymentMethod, couponCode, items)
+	if err != nil {
+		if errors.Is(err, domain.ErrRequestInProgress) {
+			// Another request took over the key; it is theirs to complete
+			return nil, err
+		}
+		// Free the key so the client can retry the failed request; an order
+		// it already points to has been cancelled by the failed checkout
+		if discardErr := uc.idempotencyRepo.Discard(ctx, userID, idempotencyKey); discardErr != nil {
+			logger.Errorf("Failed to discard idempotency key %s: %v", idempotencyKey, discardErr)
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// placeOrder prices the order, persists it and runs the checkout saga. A
+// non-empty idempotency key is completed in the write that creates the order.
+func (uc *OrderUseCase) placeOrder(ctx context.Context, idempotencyKey, userID, shippingAddressID, billingAddressID, paymentMethod, couponCode string, items []domain.OrderItem) (*domain.Order, error) {
+	// Snapshot the addresses from the user's address book
+	shippingAddress, billingAddress, err := uc.resolveAddresses(ctx, userID, shippingAddressID, billingAddressID)
+	if err != nil {
+		logger.Errorf("Failed to resolve addresses for user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to resolve addresses: %w", err)
+	}
+
+	// Resolve unit prices from the product catalog
+	if err := uc.priceItems(ctx, items); err != nil {
+		logger.Errorf("Failed to price order items: %v", err)
+		return nil, fmt.Errorf("failed to price order: %w", err)
+	}
+
 	// Create new order
 	order, err := domain.NewOrder(userID, shippingAddress, billingAddress, paymentMethod, items)
 	if err != nil {
@@ -46,12 +170,32 @@ func (uc *OrderUseCase) CreateOrder(ctx context.Context, userID, shippingAddress
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
 
-	// Save order to repository
+	// Apply the coupon discount, then shipping and tax on the discounted amount
+	if couponCode != "" {
+		coupon, err := uc.couponRepo.GetByCode(ctx, couponCode)
+		if err != nil {
+			logger.Errorf("Failed to get coupon %s: %v", couponCode, err)
+			return nil, fmt.Errorf("failed to apply coupon: %w", err)
+		}
+		if err := order.ApplyCoupon(coupon, time.Now()); err != nil {
+			return nil, fmt.Errorf("failed to apply coupon: %w", err)
+		}
+	}
+	order.ApplyPricing(uc.pricing)
+	order.SetIdempotencyKey(idempotencyKey)
+
+	// Save order to repository; this redeems the coupon and completes the
+	// idempotency key in the same transaction
 	if err := uc.orderRepo.Create(ctx, order); err != nil {
 		logger.Errorf("Failed to save order: %v", err)
 		return nil, fmt.Errorf("failed to save order: %w", err)
 	}
 
+	// Reserve stock and create the payment intent
+	if err := uc.runCheckoutSaga(ctx, order); err != nil {
+		return nil, err
+	}
+
 	logger.Infof("Order created successfully: %s", order.ID)
 	return order, nil
 }
@@ -67,7 +211,7 @@ func (uc *OrderUseCase) GetOrder(ctx context.Context, orderID string) (*domain.O
 }
 
 // UpdateOrderStatus updates the order status with validation
-func (uc *OrderUseCase) UpdateOrderStatus(ctx context.Context, orderID string, newStatus domain.OrderStatus) error {
+func (uc *OrderUseCase) UpdateOrderStatus(ctx context.Context, orderID string, newStatus domain.OrderStatus, changedBy, reason string) error {
 	// Get current order
 	order, err := uc.orderRepo.GetByID(ctx, orderID)
 	if err != nil {
@@ -76,40 +220,80 @@ func (uc *OrderUseCase) UpdateOrderStatus(ctx context.Context, orderID string, n
 	}
 
 	// Validate and update status
-	if err := order.UpdateStatus(newStatus); err != nil {
-		logger.Errorf("Invalid status transition for order %s: %v", orderID, err)
-		return fmt.Errorf("invalid status transition: %w", err)
-	}
-
-	// Save updated order
-	if err := uc.orderRepo.Update(ctx, order); err != nil {
+	err = uc.updateOrder(ctx, order, func(o *domain.Order) error {
+		if err := o.UpdateStatus(newStatus, changedBy, reason); err != nil {
+			return fmt.Errorf("invalid status transition: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
 		logger.Errorf("Failed to update order %s: %v", orderID, err)
 		return fmt.Errorf("failed to update order: %w", err)
 	}
 
+	// A confirmed order is paid for, so its stock is no longer only held
+	if newStatus == domain.OrderStatusConfirmed {
+		if err := uc.commitStock(ctx, order); err != nil {
+			logger.Errorf("Stock of order %s left uncommitted: %v", orderID, err)
+		}
+	}
+
 	logger.Infof("Order %s status updated to %s", orderID, newStatus)
 	return nil
 }
 
-// CancelOrder cancels an order
-func (uc *OrderUseCase) CancelOrder(ctx context.Context, orderID string) error {
+// CancelOrder cancels an order, the way a failed checkout is compensated: the
+// payment intent is cancelled, or refunded in full once it was collected, and
+// the stock reservation is released, or restocked once it was committed. A refund that fails stays pending and is
+// retried by the pending refund job.
+func (uc *OrderUseCase) CancelOrder(ctx context.Context, orderID, cancelledBy, reason string) error {
 	// Get current order
 	order, err := uc.orderRepo.GetByID(ctx, orderID)
 	if err != nil {
 		logger.Errorf("Failed to get order %s: %v", orderID, err)
 		return fmt.Errorf("failed to get order: %w", err)
 	}
+	if err := order.CanTransitionTo(domain.OrderStatusCancelled); err != nil {
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	payment, err := uc.cancelOrderPayment(ctx, order, reason)
+	if err != nil {
+		logger.Errorf("Failed to cancel payment of order %s: %v", orderID, err)
+		return fmt.Errorf("failed to cancel order payment: %w", err)
+	}
 
-	// Cancel the order
-	if err := order.Cancel(); err != nil {
+	// Cancel the order; a collected payment is owed back line by line
+	collected := payment != nil && payment.IsCollected()
+	var unshipped []domain.OrderItem
+	err = uc.updateOrder(ctx, order, func(o *domain.Order) error {
+		unshipped = unshippedItems(o)
+		if payment != nil {
+			o.PaymentStatus = payment.Status
+		}
+		if collected {
+			_, err := o.CancelPaid(cancelledBy, reason)
+			return err
+		}
+		return o.Cancel(cancelledBy, reason)
+	})
+	if err != nil {
 		logger.Errorf("Failed to cancel order %s: %v", orderID, err)
 		return fmt.Errorf("failed to cancel order: %w", err)
 	}
 
-	// Save updated order
-	if err := uc.orderRepo.Update(ctx, order); err != nil {
-		logger.Errorf("Failed to update cancelled order %s: %v", orderID, err)
-		return fmt.Errorf("failed to update order: %w", err)
+	// Release every line by order ID; inventory may already have released
+	// some. Stock committed when the order was confirmed is restocked instead.
+	if order.StockCommittedAt != nil {
+		uc.restockItems(ctx, order, unshipped, "cancelled order "+order.ID)
+	} else if err := uc.inventory.ReleaseReservation(ctx, "", order.ID); err != nil {
+		logger.Errorf("Failed to release reservation for cancelled order %s: %v", orderID, err)
+	}
+
+	if collected {
+		if err := uc.refundPendingItems(ctx, order, reason); err != nil {
+			logger.Errorf("Refund for cancelled order %s left pending: %v", orderID, err)
+		}
 	}
 
 	logger.Infof("Order %s cancelled successfully", orderID)
@@ -136,13 +320,13 @@ func (uc *OrderUseCase) UpdateTrackingNumber(ctx context.Context, orderID, track
 	}
 
 	// Set tracking number
-	if err := order.SetTrackingNumber(trackingNumber); err != nil {
-		logger.Errorf("Failed to set tracking number for order %s: %v", orderID, err)
-		return fmt.Errorf("failed to set tracking number: %w", err)
-	}
-
-	// Save updated order
-	if err := uc.orderRepo.Update(ctx, order); err != nil {
+	err = uc.updateOrder(ctx, order, func(o *domain.Order) error {
+		if err := o.SetTrackingNumber(trackingNumber); err != nil {
+			return fmt.Errorf("failed to set tracking number: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
 		logger.Errorf("Failed to update order %s with tracking number: %v", orderID, err)
 		return fmt.Errorf("failed to update order: %w", err)
 	}
@@ -151,6 +335,40 @@ func (uc *OrderUseCase) UpdateTrackingNumber(ctx context.Context, orderID, track
 	return nil
 }
 
+// FlagOrderDisputed flags an order whose payment the customer's bank is
+// disputing. The payment service retries it until it succeeds, so a repeated
+// call reports the order as already flagged instead of flagging it again.
+func (uc *OrderUseCase) FlagOrderDisputed(ctx context.Context, orderID, paymentID, disputeID, reason string) (*domain.Order, bool, error) {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		logger.Errorf("Failed to get order %s: %v", orderID, err)
+		return nil, false, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	alreadyFlagged := false
+	err = uc.updateOrder(ctx, order, func(o *domain.Order) error {
+		changed, err := o.FlagDisputed(paymentID, disputeID, time.Now())
+		if err != nil {
+			return err
+		}
+		if !changed {
+			alreadyFlagged = true
+			return errNothingToSave
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("Failed to flag order %s as disputed: %v", orderID, err)
+		return nil, false, fmt.Errorf("failed to update order: %w", err)
+	}
+	if alreadyFlagged {
+		return order, true, nil
+	}
+
+	logger.Infof("Order %s flagged as disputed by dispute %s of payment %s: %s", orderID, disputeID, paymentID, reason)
+	return order, false, nil
+}
+
 // GetOrdersByStatus retrieves orders by status
 func (uc *OrderUseCase) GetOrdersByStatus(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.Order, error) {
 	orders, err := uc.orderRepo.GetOrdersByStatus(ctx, status, limit, offset)
@@ -160,3 +378,133 @@ func (uc *OrderUseCase) GetOrdersByStatus(ctx context.Context, status domain.Ord
 	}
 	return orders, nil
 }
+
+// SearchOrders lists orders matching the filter for admins, newest first. The
+// returned cursor fetches the next page and is empty once there are no more.
+func (uc *OrderUseCase) SearchOrders(ctx context.Context, filter domain.OrderFilter, cursor string, limit int) ([]*domain.Order, string, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 {
+		limit = domain.DefaultSearchLimit
+	}
+	if limit > domain.MaxSearchLimit {
+		limit = domain.MaxSearchLimit
+	}
+
+	var after *domain.OrderCursor
+	if cursor != "" {
+		decoded, err := domain.DecodeOrderCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = decoded
+	}
+
+	// Fetch one extra order to learn whether another page exists
+	orders, err := uc.orderRepo.Search(ctx, filter, after, limit+1)
+	if err != nil {
+		logger.Errorf("Failed to search orders: %v", err)
+		return nil, "", fmt.Errorf("failed to search orders: %w", err)
+	}
+
+	nextCursor := ""
+	if len(orders) > limit {
+		orders = orders[:limit]
+		nextCursor = domain.CursorAfter(orders[limit-1]).Encode()
+	}
+	return orders, nextCursor, nil
+}
+
+// GetOrderStatusHistory retrieves the status transitions of an order
+func (uc *OrderUseCase) GetOrderStatusHistory(ctx context.Context, orderID string) ([]domain.StatusChange, error) {
+	history, err := uc.orderRepo.GetStatusHistory(ctx, orderID)
+	if err != nil {
+		logger.Errorf("Failed to get status history for order %s: %v", orderID, err)
+		return nil, fmt.Errorf("failed to get order status history: %w", err)
+	}
+	return history, nil
+}
+
+// CancelOrderItem cancels one line of an order, releases its stock reservation
+// and refunds the amount the order total dropped by. A line packed into a
+// shipment cannot be cancelled. The refund is saved as pending with the
+// cancellation, so one that fails is retried by the pending refund job.
+func (uc *OrderUseCase) CancelOrderItem(ctx context.Context, orderID, itemID, cancelledBy, reason string) (*domain.Order, int64, error) {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		logger.Errorf("Failed to get order %s: %v", orderID, err)
+		return nil, 0, fmt.Errorf("failed to get order: %w", err)
+	}
+	if err := uc.checkItemUnpacked(ctx, orderID, itemID); err != nil {
+		return nil, 0, err
+	}
+
+	// Cancelling the last line cancels the order, and with it the payment intent
+	var payment *domain.Payment
+	if isLastActiveItem(order, itemID) {
+		if payment, err = uc.cancelOrderPayment(ctx, order, reason); err != nil {
+			logger.Errorf("Failed to cancel payment of order %s: %v", orderID, err)
+			return nil, 0, fmt.Errorf("failed to cancel order payment: %w", err)
+		}
+	}
+
+	var item *domain.OrderItem
+	var refund int64
+	err = uc.updateOrder(ctx, order, func(o *domain.Order) error {
+		// Creating a shipment bumps the order version, so a line packed since
+		// the check above is caught when the change is applied again
+		if err := uc.checkItemUnpacked(ctx, orderID, itemID); err != nil {
+			return err
+		}
+		if item, refund, err = o.CancelItem(itemID, cancelledBy, reason); err != nil {
+			return fmt.Errorf("failed to cancel order item: %w", err)
+		}
+		if payment != nil && o.Status == domain.OrderStatusCancelled {
+			o.PaymentStatus = payment.Status
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("Failed to cancel item %s of order %s: %v", itemID, orderID, err)
+		return nil, 0, err
+	}
+
+	// Only this line's stock is given back; the rest of the order keeps its stock
+	if order.StockCommittedAt != nil {
+		uc.restockItems(ctx, order, []domain.OrderItem{*item}, "cancelled item "+item.ID)
+	} else if item.ReservationID != "" {
+		if err := uc.inventory.ReleaseReservation(ctx, item.ReservationID, orderID); err != nil {
+			logger.Errorf("Failed to release reservation %s for order %s: %v", item.ReservationID, orderID, err)
+		}
+	}
+
+	if err := uc.refundPendingItems(ctx, order, reason); err != nil {
+		logger.Errorf("Refund for item %s of order %s left pending: %v", itemID, orderID, err)
+	}
+
+	logger.Infof("Item %s of order %s cancelled, refund %s %s", itemID, orderID, domain.FormatCents(refund), order.Currency)
+	return order, refund, nil
+}
+
+// checkItemUnpacked returns domain.ErrItemNotCancellable if the line is
+// packed into one of the order's shipments
+func (uc *OrderUseCase) checkItemUnpacked(ctx context.Context, orderID, itemID string) error {
+	shipments, err := uc.shipmentRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		logger.Errorf("Failed to get shipments for order %s: %v", orderID, err)
+		return fmt.Errorf("failed to get order shipments: %w", err)
+	}
+	return domain.CheckItemUnpacked(shipments, itemID)
+}
+
+// isLastActiveItem reports whether the item is the only line of the order that
+// still counts towards its total
+func isLastActiveItem(order *domain.Order, itemID string) bool {
+	for i := range order.Items {
+		if order.Items[i].IsActive() != (order.Items[i].ID == itemID) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,501 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+func TestCancelOrder(t *testing.T) {
+	tests := []struct {
+		name              string
+		orderStatus       domain.OrderStatus
+		paymentStatus     string
+		wantErr           bool
+		wantStatus        domain.OrderStatus
+		wantPaymentStatus string
+		wantCancelled     bool  // Payment intent cancelled in payment-service
+		wantRefunded      int64 // Refunded by payment-service
+	}{
+		{
+			name:              "unpaid order cancels its payment intent",
+			orderStatus:       domain.OrderStatusPending,
+			paymentStatus:     domain.PaymentStatusPending,
+			wantStatus:        domain.OrderStatusCancelled,
+			wantPaymentStatus: domain.PaymentStatusCancelled,
+			wantCancelled:     true,
+		},
+		{
+			name:              "authorized payment is voided",
+			orderStatus:       domain.OrderStatusConfirmed,
+			paymentStatus:     domain.PaymentStatusAuthorized,
+			wantStatus:        domain.OrderStatusCancelled,
+			wantPaymentStatus: domain.PaymentStatusCancelled,
+			wantCancelled:     true,
+		},
+		{
+			name:              "paid order is refunded in full",
+			orderStatus:       domain.OrderStatusConfirmed,
+			paymentStatus:     domain.PaymentStatusSucceeded,
+			wantStatus:        domain.OrderStatusCancelled,
+			wantPaymentStatus: domain.PaymentStatusSucceeded,
+			wantRefunded:      3000,
+		},
+		{
+			name:          "payment being processed leaves the order as it is",
+			orderStatus:   domain.OrderStatusPending,
+			paymentStatus: domain.PaymentStatusProcessing,
+			wantErr:       true,
+			wantStatus:    domain.OrderStatusPending,
+		},
+		{
+			name:          "delivered order cannot be cancelled",
+			orderStatus:   domain.OrderStatusDelivered,
+			paymentStatus: domain.PaymentStatusSucceeded,
+			wantErr:       true,
+			wantStatus:    domain.OrderStatusDelivered,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := newPaidOrder(t)
+			order.Status = tt.orderStatus
+			orders := newFakeOrderRepo(order)
+			payments := newFakePayments()
+			payments.set(order.ID, order.PaymentID, tt.paymentStatus)
+			inventory := &fakeInventory{}
+			uc := NewOrderUseCase(orders, nil, nil, nil, nil, nil, nil, nil, inventory, payments, domain.PricingPolicy{}, time.Hour)
+
+			err := uc.CancelOrder(context.Background(), order.ID, "user-1", "changed my mind")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CancelOrder error = %v, want error %v", err, tt.wantErr)
+			}
+
+			stored := orders.get(order.ID)
+			if stored.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", stored.Status, tt.wantStatus)
+			}
+			if tt.wantErr {
+				if orders.updates != 0 || len(inventory.released) != 0 {
+					t.Errorf("failed cancellation saved the order %d times and released %v", orders.updates, inventory.released)
+				}
+				return
+			}
+
+			if stored.PaymentStatus != tt.wantPaymentStatus {
+				t.Errorf("PaymentStatus = %s, want %s", stored.PaymentStatus, tt.wantPaymentStatus)
+			}
+			if (len(payments.cancelled) > 0) != tt.wantCancelled {
+				t.Errorf("cancelled payments %v, want cancelled %v", payments.cancelled, tt.wantCancelled)
+			}
+			if payments.refunded() != tt.wantRefunded || stored.RefundedAmount != tt.wantRefunded {
+				t.Errorf("refunded %d by payment-service and %d on the order, want %d", payments.refunded(), stored.RefundedAmount, tt.wantRefunded)
+			}
+			if len(stored.PendingRefunds()) != 0 {
+				t.Errorf("refunds left pending: %v", stored.PendingRefunds())
+			}
+			if len(inventory.released) != 1 || inventory.released[0] != order.ID {
+				t.Errorf("released reservations of %v, want %s", inventory.released, order.ID)
+			}
+		})
+	}
+}
+
+// A change saved over by another writer is applied again to the fresh order,
+// keeping both writers' changes
+func TestUpdateOrderConflict(t *testing.T) {
+	tests := []struct {
+		name      string
+		interfere func(stored *domain.Order)
+		run       func(ctx context.Context, uc *OrderUseCase) error
+		check     func(t *testing.T, stored *domain.Order, payments *fakePayments)
+	}{
+		{
+			name:      "status change keeps the other writer's tracking number",
+			interfere: func(stored *domain.Order) { stored.TrackingNumber = "1Z999" },
+			run: func(ctx context.Context, uc *OrderUseCase) error {
+				return uc.UpdateOrderStatus(ctx, "order-1", domain.OrderStatusProcessing, "admin", "packing")
+			},
+			check: func(t *testing.T, stored *domain.Order, payments *fakePayments) {
+				if stored.Status != domain.OrderStatusProcessing || stored.TrackingNumber != "1Z999" {
+					t.Errorf("order is %s with tracking number %q, want PROCESSING with 1Z999", stored.Status, stored.TrackingNumber)
+				}
+			},
+		},
+		{
+			name: "refund recorded by the other writer is not recorded twice",
+			interfere: func(stored *domain.Order) {
+				if err := stored.RecordItemRefund("item-1", 1000); err != nil {
+					panic(err)
+				}
+			},
+			run: func(ctx context.Context, uc *OrderUseCase) error {
+				return uc.RetryPendingRefunds(ctx)
+			},
+			check: func(t *testing.T, stored *domain.Order, payments *fakePayments) {
+				if stored.RefundedAmount != 1000 || payments.refunded() != 1000 {
+					t.Errorf("refunded %d on the order and %d by payment-service, want 1000", stored.RefundedAmount, payments.refunded())
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			order := newPaidOrder(t)
+			if _, _, err := order.CancelItem("item-1", "user-1", "changed my mind"); err != nil {
+				t.Fatalf("CancelItem: %v", err)
+			}
+			orders := newFakeOrderRepo(order)
+			orders.interfere = tt.interfere
+			payments := newFakePayments()
+			payments.set(order.ID, order.PaymentID, domain.PaymentStatusSucceeded)
+			uc := NewOrderUseCase(orders, nil, nil, nil, nil, nil, nil, nil, nil, payments, domain.PricingPolicy{}, time.Hour)
+
+			if err := tt.run(ctx, uc); err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			tt.check(t, orders.get(order.ID), payments)
+		})
+	}
+}
+
+// A line packed into a shipment is not cancelled, even when it is packed
+// between loading the order and saving the cancellation
+func TestCancelOrderItemPacked(t *testing.T) {
+	tests := []struct {
+		name       string
+		concurrent bool // The shipment is created while the item is being cancelled
+	}{
+		{name: "line already packed"},
+		{name: "line packed concurrently", concurrent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := newPaidOrder(t)
+			order.Status = domain.OrderStatusProcessing
+			orders := newFakeOrderRepo(order)
+			shipment := &domain.Shipment{
+				ID:      "shipment-1",
+				OrderID: order.ID,
+				Items:   []domain.ShipmentItem{{OrderItemID: "item-1", Quantity: 1}},
+			}
+			shipments := newFakeShipmentRepo()
+			if tt.concurrent {
+				// Creating the shipment bumps the order version
+				orders.interfere = func(stored *domain.Order) { shipments.add(shipment) }
+			} else {
+				shipments.add(shipment)
+			}
+			payments := newFakePayments()
+			payments.set(order.ID, order.PaymentID, domain.PaymentStatusSucceeded)
+			uc := NewOrderUseCase(orders, nil, nil, nil, nil, shipments, nil, nil, &fakeInventory{}, payments, domain.PricingPolicy{}, time.Hour)
+
+			_, _, err := uc.CancelOrderItem(context.Background(), order.ID, "item-1", "user-1", "changed my mind")
+			if !errors.Is(err, domain.ErrItemNotCancellable) {
+				t.Fatalf("CancelOrderItem error = %v, want %v", err, domain.ErrItemNotCancellable)
+			}
+
+			item, _ := orders.get(order.ID).FindItem("item-1")
+			if item.Status != domain.OrderItemStatusPending || item.HasPendingRefund() {
+				t.Errorf("item is %s with refund %d, want it left pending", item.Status, item.RefundAmount)
+			}
+		})
+	}
+}
+
+// newSearchOrder returns an order of user-1 created minutes after base
+func newSearchOrder(id string, base time.Time, minutes int) *domain.Order {
+	return &domain.Order{
+		ID:            id,
+		UserID:        "user-1",
+		Status:        domain.OrderStatusConfirmed,
+		PaymentStatus: domain.PaymentStatusSucceeded,
+		TotalAmount:   1000,
+		Items:         []domain.OrderItem{{ID: id + "-item-1", ProductID: "product-1", Quantity: 1}},
+		CreatedAt:     base.Add(time.Duration(minutes) * time.Minute),
+	}
+}
+
+// Walking the pages returns every order once, newest first, each page
+// starting strictly after the last order of the previous one
+func TestSearchOrdersPaging(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	orders := newFakeOrderRepo(
+		newSearchOrder("order-a", base, 0),
+		newSearchOrder("order-b", base, 1),
+		// Three orders placed in the same instant are ordered by ID
+		newSearchOrder("order-c", base, 2),
+		newSearchOrder("order-d", base, 2),
+		newSearchOrder("order-e", base, 2),
+		newSearchOrder("order-f", base, 3),
+		newSearchOrder("order-g", base, 4),
+	)
+	uc := NewOrderUseCase(orders, nil, nil, nil, nil, nil, nil, nil, nil, nil, domain.PricingPolicy{}, time.Hour)
+	want := []string{"order-g", "order-f", "order-e", "order-d", "order-c", "order-b", "order-a"}
+
+	var got []string
+	cursor := ""
+	for page := 0; page < len(want); page++ {
+		results, next, err := uc.SearchOrders(context.Background(), domain.OrderFilter{}, cursor, 2)
+		if err != nil {
+			t.Fatalf("SearchOrders page %d: %v", page, err)
+		}
+		for _, order := range results {
+			got = append(got, order.ID)
+		}
+		if page == 0 {
+			// Orders placed after the first page must not shift later pages
+			orders.orders["order-h"] = copyOrder(newSearchOrder("order-h", base, 5))
+		}
+		if next == "" {
+			break
+		}
+		decoded, err := domain.DecodeOrderCursor(next)
+		if err != nil {
+			t.Fatalf("page %d cursor %q does not decode: %v", page, next, err)
+		}
+		if last := results[len(results)-1]; decoded.ID != last.ID || !decoded.CreatedAt.Equal(last.CreatedAt) {
+			t.Fatalf("page %d cursor points at %s %s, want the last order %s %s", page, decoded.ID, decoded.CreatedAt, last.ID, last.CreatedAt)
+		}
+		cursor = next
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("pages listed %v, want %v", got, want)
+	}
+}
+
+func TestSearchOrders(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	from := base.Add(time.Minute)
+	to := base.Add(10 * time.Minute)
+	minTotal, maxTotal := int64(500), int64(2000)
+
+	seed := func() []*domain.Order {
+		other := newSearchOrder("order-other-user", base, 3)
+		other.UserID = "user-2"
+		cheap := newSearchOrder("order-cheap", base, 4)
+		cheap.TotalAmount = 100
+		expensive := newSearchOrder("order-expensive", base, 4)
+		expensive.TotalAmount = 5000
+		pending := newSearchOrder("order-pending", base, 5)
+		pending.Status = domain.OrderStatusPending
+		pending.PaymentStatus = domain.PaymentStatusPending
+		otherProduct := newSearchOrder("order-other-product", base, 6)
+		otherProduct.Items[0].ProductID = "product-2"
+		return []*domain.Order{
+			newSearchOrder("order-too-early", base, 0),
+			newSearchOrder("order-match-1", base, 2),
+			other, cheap, expensive, pending, otherProduct,
+			newSearchOrder("order-match-2", base, 7),
+			newSearchOrder("order-too-late", base, 10),
+		}
+	}
+	many := func(n int) []*domain.Order {
+		orders := make([]*domain.Order, n)
+		for i := range orders {
+			orders[i] = newSearchOrder(fmt.Sprintf("order-%03d", i), base, i)
+		}
+		return orders
+	}
+	token := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name      string
+		orders    []*domain.Order
+		filter    domain.OrderFilter
+		cursor    string
+		limit     int
+		wantIDs   []string // Checked when set
+		wantCount int
+		wantMore  bool // A next cursor is returned
+		wantErr   error
+	}{
+		{
+			name:   "combined filters",
+			orders: seed(),
+			filter: domain.OrderFilter{
+				UserID:        "user-1",
+				Statuses:      []domain.OrderStatus{domain.OrderStatusConfirmed},
+				CreatedFrom:   &from,
+				CreatedTo:     &to,
+				MinTotal:      &minTotal,
+				MaxTotal:      &maxTotal,
+				PaymentStatus: domain.PaymentStatusSucceeded,
+				ProductID:     "product-1",
+			},
+			limit:     10,
+			wantIDs:   []string{"order-match-2", "order-match-1"},
+			wantCount: 2,
+		},
+		{name: "no limit uses the default", orders: many(domain.DefaultSearchLimit + 5), wantCount: domain.DefaultSearchLimit, wantMore: true},
+		{name: "negative limit uses the default", orders: many(domain.DefaultSearchLimit + 5), limit: -1, wantCount: domain.DefaultSearchLimit, wantMore: true},
+		{name: "limit is capped", orders: many(domain.MaxSearchLimit + 5), limit: 1000, wantCount: domain.MaxSearchLimit, wantMore: true},
+		{name: "last page has no cursor", orders: many(3), limit: 3, wantCount: 3},
+		{name: "cursor that is not base64", orders: many(3), cursor: "not a cursor!", wantErr: domain.ErrInvalidCursor},
+		{name: "cursor without an order ID", orders: many(3), cursor: token("2026-03-01T12:00:00Z|"), wantErr: domain.ErrInvalidCursor},
+		{name: "cursor without a separator", orders: many(3), cursor: token("2026-03-01T12:00:00Z"), wantErr: domain.ErrInvalidCursor},
+		{name: "cursor with a tampered time", orders: many(3), cursor: token("yesterday|order-001"), wantErr: domain.ErrInvalidCursor},
+		{
+			name:    "inverted date range",
+			orders:  many(3),
+			filter:  domain.OrderFilter{CreatedFrom: &to, CreatedTo: &from},
+			wantErr: domain.ErrInvalidFilter,
+		},
+		{
+			name:    "inverted total range",
+			orders:  many(3),
+			filter:  domain.OrderFilter{MinTotal: &maxTotal, MaxTotal: &minTotal},
+			wantErr: domain.ErrInvalidFilter,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewOrderUseCase(newFakeOrderRepo(tt.orders...), nil, nil, nil, nil, nil, nil, nil, nil, nil, domain.PricingPolicy{}, time.Hour)
+
+			results, next, err := uc.SearchOrders(context.Background(), tt.filter, tt.cursor, tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SearchOrders error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(results) != tt.wantCount {
+				t.Errorf("SearchOrders returned %d orders, want %d", len(results), tt.wantCount)
+			}
+			if (next != "") != tt.wantMore {
+				t.Errorf("next cursor = %q, want one %v", next, tt.wantMore)
+			}
+			if tt.wantIDs != nil {
+				var ids []string
+				for _, order := range results {
+					ids = append(ids, order.ID)
+				}
+				if !slices.Equal(ids, tt.wantIDs) {
+					t.Errorf("SearchOrders returned %v, want %v", ids, tt.wantIDs)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateOrderCoupon(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		coupon       domain.Coupon
+		code         string                      // Code the order is placed with
+		redeemed     bool                        // user-1 already redeemed the coupon
+		interfere    func(stored *domain.Coupon) // A concurrent order changing the coupon
+		wantErr      error
+		wantDiscount int64
+	}{
+		{
+			name:         "percentage coupon",
+			coupon:       domain.Coupon{Type: domain.CouponTypePercentage, Value: 1000},
+			wantDiscount: 300,
+		},
+		{
+			name:         "code is matched case-insensitively",
+			coupon:       domain.Coupon{Type: domain.CouponTypeFixedAmount, Value: 500},
+			code:         " save ",
+			wantDiscount: 500,
+		},
+		{
+			name:    "unknown coupon",
+			coupon:  domain.Coupon{Type: domain.CouponTypeFixedAmount, Value: 500},
+			code:    "OTHER",
+			wantErr: domain.ErrCouponNotFound,
+		},
+		{
+			name:    "expired coupon",
+			coupon:  domain.Coupon{Type: domain.CouponTypeFixedAmount, Value: 500, ExpiresAt: &expired},
+			wantErr: domain.ErrCouponInvalid,
+		},
+		{
+			name:    "minimum spend not met",
+			coupon:  domain.Coupon{Type: domain.CouponTypeFixedAmount, Value: 500, MinOrderAmount: 5000},
+			wantErr: domain.ErrCouponInvalid,
+		},
+		{
+			name:    "coupon used up",
+			coupon:  domain.Coupon{Type: domain.CouponTypeFixedAmount, Value: 500, MaxRedemptions: 10, TimesRedeemed: 10},
+			wantErr: domain.ErrCouponExhausted,
+		},
+		{
+			name:      "last redemption taken by a concurrent order",
+			coupon:    domain.Coupon{Type: domain.CouponTypeFixedAmount, Value: 500, MaxRedemptions: 10, TimesRedeemed: 9},
+			interfere: func(stored *domain.Coupon) { stored.TimesRedeemed++ },
+			wantErr:   domain.ErrCouponExhausted,
+		},
+		{
+			name:     "per-user limit reached",
+			coupon:   domain.Coupon{Type: domain.CouponTypeFixedAmount, Value: 500, PerUserLimit: 1},
+			redeemed: true,
+			wantErr:  domain.ErrCouponExhausted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coupon := tt.coupon
+			coupon.ID, coupon.Code, coupon.Active = "coupon-1", "SAVE", true
+			coupons := newFakeCouponRepo(&coupon)
+			coupons.interfere = tt.interfere
+			if tt.redeemed {
+				coupons.redemptions = append(coupons.redemptions, domain.CouponRedemption{CouponID: "coupon-1", OrderID: "order-0", UserID: "user-1"})
+			}
+			orders := newFakeOrderRepo()
+			orders.coupons = coupons
+			catalog := fakeCatalog{
+				"p1": {ID: "p1", Name: "Mug", Price: 1000, Status: domain.ProductStatusActive},
+				"p2": {ID: "p2", Name: "Plate", Price: 2000, Status: domain.ProductStatusActive},
+			}
+			uc := NewOrderUseCase(orders, newFakeSagaRepo(), nil, coupons, nil, nil, fakeAddressBook{{ID: "addr-1", IsDefault: true}}, catalog, &fakeInventory{}, newFakePayments(), domain.PricingPolicy{}, time.Hour)
+			code := tt.code
+			if code == "" {
+				code = "SAVE"
+			}
+
+			order, err := uc.CreateOrder(context.Background(), "", "user-1", "", "", "CREDIT_CARD", code, cartItems())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateOrder error = %v, want %v", err, tt.wantErr)
+			}
+			stored, _ := coupons.GetByCode(context.Background(), "SAVE")
+			if tt.wantErr != nil {
+				if len(orders.orders) != 0 {
+					t.Errorf("rejected coupon left %d orders", len(orders.orders))
+				}
+				if stored.TimesRedeemed != coupon.TimesRedeemed && tt.interfere == nil {
+					t.Errorf("TimesRedeemed = %d after a rejected order, want %d", stored.TimesRedeemed, coupon.TimesRedeemed)
+				}
+				return
+			}
+
+			if order.CouponCode != "SAVE" || order.DiscountAmount != tt.wantDiscount || order.TotalAmount != 3000-tt.wantDiscount {
+				t.Errorf("order has coupon %q, discount %d and total %d, want SAVE, %d and %d", order.CouponCode, order.DiscountAmount, order.TotalAmount, tt.wantDiscount, 3000-tt.wantDiscount)
+			}
+			if stored.TimesRedeemed != 1 {
+				t.Errorf("TimesRedeemed = %d, want 1", stored.TimesRedeemed)
+			}
+			want := domain.CouponRedemption{CouponID: "coupon-1", OrderID: order.ID, UserID: "user-1", Amount: tt.wantDiscount}
+			if len(coupons.redemptions) != 1 || coupons.redemptions[0] != want {
+				t.Errorf("redemptions = %+v, want [%+v]", coupons.redemptions, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
+)
+
+const (
+	outboxRelayInterval = 2 * time.Second
+	outboxRelayBatch    = 100
+)
+
+// OutboxRepository defines the interface for reading pending outbox events
+type OutboxRepository interface {
+	ProcessPending(ctx context.Context, limit int, publish func(ctx context.Context, event *domain.OutboxEvent) error) (int, error)
+}
+
+// EventPublisher defines the interface for publishing events to the message broker
+type EventPublisher interface {
+	Publish(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// OutboxRelay publishes order events written to the transactional outbox.
+// An event is only acknowledged after the broker accepted it, so delivery is at-least-once.
+type OutboxRelay struct {
+	outboxRepo OutboxRepository
+	publisher  EventPublisher
+}
+
+// NewOutboxRelay creates a new outbox relay
+func NewOutboxRelay(outboxRepo OutboxRepository, publisher EventPublisher) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo: outboxRepo,
+		publisher:  publisher,
+	}
+}
+
+// RelayPending publishes pending outbox events until none are left or a batch
+// has a failure. A batch holds one event per order, so the later events of an
+// order go out with the following batches.
+func (r *OutboxRelay) RelayPending(ctx context.Context) error {
+	for {
+		published, err := r.outboxRepo.ProcessPending(ctx, outboxRelayBatch, r.publisher.Publish)
+		if err != nil {
+			return err
+		}
+		if published > 0 {
+			logger.Infof("Published %d order events", published)
+		}
+		if published == 0 {
+			return nil
+		}
+	}
+}
+
+// Start starts a background job that relays outbox events
+func (r *OutboxRelay) Start(ctx context.Context) {
+	ticker := time.NewTicker(outboxRelayInterval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.RelayPending(ctx); err != nil {
+					logger.Errorf("Failed to relay order events: %v", err)
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	logger.Info("Started order event outbox relay")
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
+)
+
+const (
+	pendingRefundRetryInterval = 1 * time.Minute
+	pendingRefundRetryBatch    = 100
+	// pendingRefundRetryAfter leaves a fresh refund to the request that owes it
+	pendingRefundRetryAfter = 1 * time.Minute
+)
+
+// refundPendingItems issues the refunds owed for cancelled and returned lines
+// and records them on the order. Each line is refunded under its own
+// idempotency key, so a refund retried after its outcome was lost is only
+// issued once. A line whose payment cannot be refunded yet, e.g. one still
+// pending or only authorized, stays pending and is refunded once the payment
+// is collected; only a payment that failed or was cancelled has nothing to refund.
+func (uc *OrderUseCase) refundPendingItems(ctx context.Context, order *domain.Order, reason string) error {
+	var refundErr error
+	refunded := make(map[string]int64)
+	for _, item := range order.PendingRefunds() {
+		amount := item.RefundAmount
+		if order.PaymentID == "" {
+			amount = 0
+		} else if err := uc.payments.RefundPayment(ctx, order.PaymentID, item.RefundAmount, order.Currency, reason, item.RefundKey()); err != nil {
+			if !uc.paymentNeverCollected(ctx, order) {
+				logger.Errorf("Failed to refund %s %s for item %s of order %s: %v", domain.FormatCents(item.RefundAmount), order.Currency, item.ID, order.ID, err)
+				refundErr = fmt.Errorf("failed to refund payment: %w", err)
+				break
+			}
+			logger.Infof("Payment %s for order %s was never collected, nothing to refund", order.PaymentID, order.ID)
+			amount = 0
+		}
+
+		refunded[item.ID] = amount
+	}
+
+	// Save the refunds issued so far even if a later one failed
+	if len(refunded) > 0 {
+		err := uc.updateOrder(ctx, order, func(o *domain.Order) error {
+			recorded := 0
+			for itemID, amount := range refunded {
+				item, err := o.FindItem(itemID)
+				if err != nil {
+					return err
+				}
+				// Another writer may have recorded the same refund first
+				if !item.HasPendingRefund() {
+					continue
+				}
+				if err := o.RecordItemRefund(itemID, amount); err != nil {
+					return err
+				}
+				recorded++
+			}
+			if recorded == 0 {
+				return errNothingToSave
+			}
+			return nil
+		})
+		if err != nil {
+			logger.Errorf("Failed to record refunds for order %s: %v", order.ID, err)
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+	}
+	return refundErr
+}
+
+// paymentNeverCollected reports whether the order's payment failed or was
+// cancelled, so no funds were taken. It is false whenever that is not
+// certain, including when payment-service cannot be reached.
+func (uc *OrderUseCase) paymentNeverCollected(ctx context.Context, order *domain.Order) bool {
+	payment, err := uc.payments.GetOrderPayment(ctx, order.ID)
+	if err != nil {
+		logger.Errorf("Failed to get payment for order %s: %v", order.ID, err)
+		return false
+	}
+	return payment.ID == order.PaymentID && payment.IsClosed()
+}
+
+// RetryPendingRefunds issues the refunds of cancelled and returned lines that
+// could not be refunded when the line changed
+func (uc *OrderUseCase) RetryPendingRefunds(ctx context.Context) error {
+	orders, err := uc.orderRepo.GetWithPendingRefunds(ctx, time.Now().Add(-pendingRefundRetryAfter), pendingRefundRetryBatch)
+	if err != nil {
+		return fmt.Errorf("failed to get orders with pending refunds: %w", err)
+	}
+
+	for _, order := range orders {
+		if err := uc.refundPendingItems(ctx, order, "refund retry"); err != nil {
+			logger.Errorf("Failed to retry pending refunds for order %s: %v", order.ID, err)
+			continue
+		}
+		logger.Infof("Pending refunds issued for order %s", order.ID)
+	}
+	return nil
+}
+
+// StartPendingRefundJob starts a background job that retries pending refunds
+func (uc *OrderUseCase) StartPendingRefundJob(ctx context.Context) {
+	ticker := time.NewTicker(pendingRefundRetryInterval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := uc.RetryPendingRefunds(ctx); err != nil {
+					logger.Errorf("Failed to retry pending refunds: %v", err)
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	logger.Info("Started pending refund job")
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// newPaidOrder returns a confirmed order of two lines, 10.00 and 20.00, paid
+// with payment pay-order-1
+func newPaidOrder(t *testing.T) *domain.Order {
+	t.Helper()
+
+	order, err := domain.NewOrder("user-1", domain.Address{AddressLine1: "1 Main St"}, domain.Address{}, "CREDIT_CARD", []domain.OrderItem{
+		{ID: "item-1", ProductID: "p1", Quantity: 1, Price: 1000},
+		{ID: "item-2", ProductID: "p2", Quantity: 1, Price: 2000},
+	})
+	if err != nil {
+		t.Fatalf("NewOrder: %v", err)
+	}
+	order.ID = "order-1"
+	order.PaymentID = "pay-order-1"
+	order.Status = domain.OrderStatusConfirmed
+	order.MarkPersisted()
+	return order
+}
+
+func TestRefundPendingItems(t *testing.T) {
+	tests := []struct {
+		name          string
+		paymentStatus string
+		lookupErr     error
+		wantErr       bool
+		wantPending   bool
+		wantRefunded  int64 // Recorded on the order and refunded by payment-service
+	}{
+		{name: "collected payment is refunded", paymentStatus: domain.PaymentStatusSucceeded, wantRefunded: 1000},
+		{name: "captured payment is refunded", paymentStatus: domain.PaymentStatusCaptured, wantRefunded: 1000},
+		{name: "pending payment is refunded once collected", paymentStatus: domain.PaymentStatusPending, wantErr: true, wantPending: true},
+		{name: "authorized payment is refunded once captured", paymentStatus: domain.PaymentStatusAuthorized, wantErr: true, wantPending: true},
+		{name: "cancelled payment has nothing to refund", paymentStatus: domain.PaymentStatusCancelled},
+		{name: "failed payment has nothing to refund", paymentStatus: domain.PaymentStatusFailed},
+		{
+			name:          "payment that cannot be looked up stays pending",
+			paymentStatus: domain.PaymentStatusPending,
+			lookupErr:     errors.New("payment-service unavailable"),
+			wantErr:       true,
+			wantPending:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := newPaidOrder(t)
+			if _, _, err := order.CancelItem("item-1", "user-1", "changed my mind"); err != nil {
+				t.Fatalf("CancelItem: %v", err)
+			}
+			orders := newFakeOrderRepo(order)
+			payments := newFakePayments()
+			payments.set(order.ID, order.PaymentID, tt.paymentStatus)
+			payments.lookupErr = tt.lookupErr
+			uc := NewOrderUseCase(orders, nil, nil, nil, nil, nil, nil, nil, nil, payments, domain.PricingPolicy{}, time.Hour)
+
+			err := uc.refundPendingItems(context.Background(), order, "changed my mind")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("refundPendingItems error = %v, want error %v", err, tt.wantErr)
+			}
+
+			stored := orders.get(order.ID)
+			item, _ := stored.FindItem("item-1")
+			if item.HasPendingRefund() != tt.wantPending {
+				t.Errorf("item refund pending = %v, want %v", item.HasPendingRefund(), tt.wantPending)
+			}
+			if stored.RefundedAmount != tt.wantRefunded {
+				t.Errorf("RefundedAmount = %d, want %d", stored.RefundedAmount, tt.wantRefunded)
+			}
+			if payments.refunded() != tt.wantRefunded {
+				t.Errorf("payment-service refunded %d, want %d", payments.refunded(), tt.wantRefunded)
+			}
+		})
+	}
+}
+
+// A line cancelled before the payment was collected is refunded by the retry
+// job once it is, and only once
+func TestRetryPendingRefundsAfterCapture(t *testing.T) {
+	ctx := context.Background()
+	order := newPaidOrder(t)
+	order.Status = domain.OrderStatusPending
+	orders := newFakeOrderRepo(order)
+	payments := newFakePayments()
+	payments.set(order.ID, order.PaymentID, domain.PaymentStatusPending)
+	uc := NewOrderUseCase(orders, nil, nil, nil, nil, newFakeShipmentRepo(), nil, nil, nil, payments, domain.PricingPolicy{}, time.Hour)
+
+	if _, refund, err := uc.CancelOrderItem(ctx, order.ID, "item-1", "user-1", "changed my mind"); err != nil || refund != 1000 {
+		t.Fatalf("CancelOrderItem = %d, %v, want a refund of 1000", refund, err)
+	}
+	if err := uc.RetryPendingRefunds(ctx); err != nil {
+		t.Fatalf("RetryPendingRefunds: %v", err)
+	}
+	if item, _ := orders.get(order.ID).FindItem("item-1"); !item.HasPendingRefund() {
+		t.Fatal("refund settled while the payment was still pending")
+	}
+
+	// The customer pays the original total
+	payments.set(order.ID, order.PaymentID, domain.PaymentStatusSucceeded)
+	for i := 0; i < 2; i++ {
+		if err := uc.RetryPendingRefunds(ctx); err != nil {
+			t.Fatalf("RetryPendingRefunds: %v", err)
+		}
+	}
+
+	stored := orders.get(order.ID)
+	if item, _ := stored.FindItem("item-1"); item.HasPendingRefund() {
+		t.Error("refund still pending after the payment was collected")
+	}
+	if stored.RefundedAmount != 1000 || payments.refunded() != 1000 {
+		t.Errorf("refunded %d on the order and %d by payment-service, want 1000", stored.RefundedAmount, payments.refunded())
+	}
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
+)
+
+// ReturnRepository defines the interface for return merchandise authorization persistence
+type ReturnRepository interface {
+	Create(ctx context.Context, orderReturn *domain.OrderReturn) error // domain.ErrReturnNotAllowed if an item is in another open return
+	Update(ctx context.Context, orderReturn *domain.OrderReturn) error
+	// ReceiveLocked saves the return receive changed, running receive with the return locked
+	ReceiveLocked(ctx context.Context, returnID string, receive func(orderReturn *domain.OrderReturn) error) (*domain.OrderReturn, error)
+	GetByID(ctx context.Context, returnID string) (*domain.OrderReturn, error)
+	GetByOrderID(ctx context.Context, orderID string) ([]*domain.OrderReturn, error)
+}
+
+// RequestReturn opens a return for delivered lines of a customer's order
+func (uc *OrderUseCase) RequestReturn(ctx context.Context, orderID, userID string, itemIDs []string, reason string) (*domain.OrderReturn, error) {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		logger.Errorf("Failed to get order %s: %v", orderID, err)
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	orderReturn, err := domain.NewOrderReturn(order, userID, itemIDs, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request return: %w", err)
+	}
+
+	// The repository rejects items that are part of another open return
+	if err := uc.returnRepo.Create(ctx, orderReturn); err != nil {
+		logger.Errorf("Failed to save return for order %s: %v", orderID, err)
+		return nil, fmt.Errorf("failed to save return: %w", err)
+	}
+
+	logger.Infof("Return %s requested for order %s", orderReturn.ID, orderID)
+	return orderReturn, nil
+}
+
+// ApproveReturn authorizes the customer to send the items back
+func (uc *OrderUseCase) ApproveReturn(ctx context.Context, returnID, approvedBy string) (*domain.OrderReturn, error) {
+	orderReturn, err := uc.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := orderReturn.Approve(approvedBy); err != nil {
+		return nil, err
+	}
+
+	if err := uc.returnRepo.Update(ctx, orderReturn); err != nil {
+		logger.Errorf("Failed to approve return %s: %v", returnID, err)
+		return nil, err
+	}
+
+	logger.Infof("Return %s approved by %s", returnID, approvedBy)
+	return orderReturn, nil
+}
+
+// RejectReturn declines a requested return
+func (uc *OrderUseCase) RejectReturn(ctx context.Context, returnID, rejectedBy, reason string) (*domain.OrderReturn, error) {
+	orderReturn, err := uc.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := orderReturn.Reject(rejectedBy, reason); err != nil {
+		return nil, err
+	}
+
+	if err := uc.returnRepo.Update(ctx, orderReturn); err != nil {
+		logger.Errorf("Failed to reject return %s: %v", returnID, err)
+		return nil, err
+	}
+
+	logger.Infof("Return %s rejected by %s", returnID, rejectedBy)
+	return orderReturn, nil
+}
+
+// ReceiveReturn records the arrival of the returned items and adds them back to
+// stock. Calling it again for a received return retries any failed restock.
+// The return stays locked while its items are restocked, so concurrent or
+// retried calls never add the same item back twice.
+func (uc *OrderUseCase) ReceiveReturn(ctx context.Context, returnID, receivedBy string) (*domain.OrderReturn, error) {
+	orderReturn, err := uc.returnRepo.ReceiveLocked(ctx, returnID, func(r *domain.OrderReturn) error {
+		if r.Status != domain.ReturnStatusReceived {
+			if err := r.MarkReceived(receivedBy); err != nil {
+				return err
+			}
+		}
+
+		// Restock item by item, saving progress so a retry never adds stock twice
+		for i := range r.Items {
+			item := &r.Items[i]
+			if item.Restocked {
+				continue
+			}
+			if err := uc.inventory.RestockItem(ctx, item.ProductID, item.VariantID, item.Quantity, "return "+r.ID); err != nil {
+				logger.Errorf("Failed to restock item %s of return %s: %v", item.OrderItemID, returnID, err)
+				return fmt.Errorf("failed to restock returned items: %w", err)
+			}
+			item.Restocked = true
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("Failed to receive return %s: %v", returnID, err)
+		return nil, err
+	}
+
+	logger.Infof("Return %s received by %s and restocked", returnID, receivedBy)
+	return orderReturn, nil
+}
+
+// RefundReturn marks the returned lines on the order, refunds the amount the
+// order total dropped by and closes the return. The refunds are saved as
+// pending with the lines and issued per line under the line's idempotency
+// key, so retrying an interrupted refund never refunds twice.
+func (uc *OrderUseCase) RefundReturn(ctx context.Context, returnID, refundedBy string) (*domain.OrderReturn, error) {
+	orderReturn, err := uc.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+	if orderReturn.Status != domain.ReturnStatusReceived || !orderReturn.IsRestocked() {
+		return nil, fmt.Errorf("%w: return must be received and restocked before it is refunded", domain.ErrInvalidReturnTransition)
+	}
+
+	order, err := uc.orderRepo.GetByID(ctx, orderReturn.OrderID)
+	if err != nil {
+		logger.Errorf("Failed to get order %s: %v", orderReturn.OrderID, err)
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	// Lines already marked returned by an earlier, interrupted attempt are skipped
+	err = uc.updateOrder(ctx, order, func(o *domain.Order) error {
+		returned := false
+		for _, returnItem := range orderReturn.Items {
+			item, err := o.FindItem(returnItem.OrderItemID)
+			if err != nil {
+				return err
+			}
+			if item.Status == domain.OrderItemStatusReturned {
+				continue
+			}
+
+			if _, _, err := o.ReturnItem(returnItem.OrderItemID, refundedBy, orderReturn.Reason); err != nil {
+				return fmt.Errorf("failed to return order item: %w", err)
+			}
+			returned = true
+		}
+		if !returned {
+			return errNothingToSave
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("Failed to update order %s: %v", order.ID, err)
+		return nil, err
+	}
+
+	if err := uc.refundPendingItems(ctx, order, "return "+orderReturn.ID); err != nil {
+		return nil, err
+	}
+
+	if err := orderReturn.MarkRefunded(order); err != nil {
+		return nil, err
+	}
+	if err := uc.returnRepo.Update(ctx, orderReturn); err != nil {
+		logger.Errorf("Failed to close return %s: %v", returnID, err)
+		return nil, err
+	}
+
+	logger.Infof("Return %s refunded %s %s", returnID, domain.FormatCents(orderReturn.RefundAmount), orderReturn.Currency)
+	return orderReturn, nil
+}
+
+// GetReturn retrieves a return by ID
+func (uc *OrderUseCase) GetReturn(ctx context.Context, returnID string) (*domain.OrderReturn, error) {
+	return uc.returnRepo.GetByID(ctx, returnID)
+}
+
+// GetOrderReturns retrieves the returns of an order
+func (uc *OrderUseCase) GetOrderReturns(ctx context.Context, orderID string) ([]*domain.OrderReturn, error) {
+	returns, err := uc.returnRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		logger.Errorf("Failed to get returns for order %s: %v", orderID, err)
+		return nil, err
+	}
+	return returns, nil
+}
@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+// Receiving a return again, whether retried or concurrent, restocks each
+// item only once
+func TestReceiveReturnRepeated(t *testing.T) {
+	ctx := context.Background()
+	returns := newFakeReturnRepo(&domain.OrderReturn{
+		ID:      "return-1",
+		OrderID: "order-1",
+		Status:  domain.ReturnStatusApproved,
+		Items: []domain.OrderReturnItem{
+			{OrderItemID: "item-1", ProductID: "p1", Quantity: 1},
+			{OrderItemID: "item-2", ProductID: "p2", Quantity: 2},
+		},
+	})
+	inventory := &fakeInventory{}
+	uc := NewOrderUseCase(nil, nil, nil, nil, returns, nil, nil, nil, inventory, nil, domain.PricingPolicy{}, time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := uc.ReceiveReturn(ctx, "return-1", "warehouse"); err != nil {
+				t.Errorf("ReceiveReturn: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, err := uc.ReceiveReturn(ctx, "return-1", "warehouse"); err != nil {
+		t.Fatalf("ReceiveReturn: %v", err)
+	}
+
+	if inventory.restocked != 3 {
+		t.Errorf("restocked %d units, want 3", inventory.restocked)
+	}
+	stored, _ := returns.GetByID(ctx, "return-1")
+	if stored.Status != domain.ReturnStatusReceived || !stored.IsRestocked() {
+		t.Errorf("return is %s, restocked %v, want RECEIVED and restocked", stored.Status, stored.IsRestocked())
+	}
+}
+
+// A restock failing part way keeps the lines already restocked, and the
+// retry restocks only the rest
+func TestReceiveReturnRestocksPerLine(t *testing.T) {
+	ctx := context.Background()
+	returns := newFakeReturnRepo(&domain.OrderReturn{
+		ID:      "return-1",
+		OrderID: "order-1",
+		Status:  domain.ReturnStatusApproved,
+		Items: []domain.OrderReturnItem{
+			{OrderItemID: "item-1", ProductID: "p1", Quantity: 1},
+			{OrderItemID: "item-2", ProductID: "p2", Quantity: 2},
+		},
+	})
+	inventory := &fakeInventory{restockErr: map[string]error{"p2": errors.New("inventory-service unavailable")}}
+	uc := NewOrderUseCase(nil, nil, nil, nil, returns, nil, nil, nil, inventory, nil, domain.PricingPolicy{}, time.Hour)
+
+	if _, err := uc.ReceiveReturn(ctx, "return-1", "warehouse"); err == nil {
+		t.Fatal("ReceiveReturn succeeded with a failing restock, want an error")
+	}
+	stored, _ := returns.GetByID(ctx, "return-1")
+	if stored.Status != domain.ReturnStatusReceived || !stored.Items[0].Restocked || stored.Items[1].Restocked {
+		t.Fatalf("return is %s with lines restocked %v and %v, want RECEIVED with only the first restocked",
+			stored.Status, stored.Items[0].Restocked, stored.Items[1].Restocked)
+	}
+
+	inventory.restockErr = nil
+	if _, err := uc.ReceiveReturn(ctx, "return-1", "warehouse"); err != nil {
+		t.Fatalf("ReceiveReturn retry: %v", err)
+	}
+	if inventory.restocked != 3 {
+		t.Errorf("restocked %d units, want 3", inventory.restocked)
+	}
+}
+
+// newReceivedReturn returns a delivered order-1 paid with pay-order-1 and its
+// return return-1 of the given lines, received and restocked
+func newReceivedReturn(t *testing.T, itemIDs ...string) (*domain.Order, *domain.OrderReturn) {
+	t.Helper()
+
+	order := newPaidOrder(t)
+	order.Status = domain.OrderStatusDelivered
+	for i := range order.Items {
+		order.Items[i].Status = domain.OrderItemStatusFulfilled
+	}
+	orderReturn, err := domain.NewOrderReturn(order, "user-1", itemIDs, "damaged")
+	if err != nil {
+		t.Fatalf("NewOrderReturn: %v", err)
+	}
+	orderReturn.ID = "return-1"
+	orderReturn.Status = domain.ReturnStatusReceived
+	for i := range orderReturn.Items {
+		orderReturn.Items[i].Restocked = true
+	}
+	return order, orderReturn
+}
+
+func TestRefundReturn(t *testing.T) {
+	tests := []struct {
+		name         string
+		itemIDs      []string
+		restocked    bool
+		wantErr      error
+		wantRefunds  map[string]int64 // By idempotency key
+		wantStatus   domain.OrderStatus
+		wantReturned []string
+	}{
+		{
+			name:         "each line is refunded under its own key",
+			itemIDs:      []string{"item-1", "item-2"},
+			restocked:    true,
+			wantRefunds:  map[string]int64{"order-item-item-1": 1000, "order-item-item-2": 2000},
+			wantStatus:   domain.OrderStatusRefunded,
+			wantReturned: []string{"item-1", "item-2"},
+		},
+		{
+			name:         "returning some lines keeps the order delivered",
+			itemIDs:      []string{"item-2"},
+			restocked:    true,
+			wantRefunds:  map[string]int64{"order-item-item-2": 2000},
+			wantStatus:   domain.OrderStatusDelivered,
+			wantReturned: []string{"item-2"},
+		},
+		{
+			name:       "return not restocked yet",
+			itemIDs:    []string{"item-1"},
+			wantErr:    domain.ErrInvalidReturnTransition,
+			wantStatus: domain.OrderStatusDelivered,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			order, orderReturn := newReceivedReturn(t, tt.itemIDs...)
+			if !tt.restocked {
+				orderReturn.Items[0].Restocked = false
+			}
+			orders := newFakeOrderRepo(order)
+			returns := newFakeReturnRepo(orderReturn)
+			payments := newFakePayments()
+			payments.set(order.ID, order.PaymentID, domain.PaymentStatusSucceeded)
+			uc := NewOrderUseCase(orders, nil, nil, nil, returns, nil, nil, nil, &fakeInventory{}, payments, domain.PricingPolicy{}, time.Hour)
+
+			_, err := uc.RefundReturn(ctx, "return-1", "admin")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RefundReturn error = %v, want %v", err, tt.wantErr)
+			}
+
+			stored := orders.get(order.ID)
+			if stored.Status != tt.wantStatus {
+				t.Errorf("order is %s, want %s", stored.Status, tt.wantStatus)
+			}
+			var returned []string
+			for _, item := range stored.Items {
+				if item.Status == domain.OrderItemStatusReturned {
+					returned = append(returned, item.ID)
+				}
+			}
+			if !slices.Equal(returned, tt.wantReturned) {
+				t.Errorf("returned lines %v, want %v", returned, tt.wantReturned)
+			}
+			if tt.wantErr != nil {
+				if len(payments.refunds) != 0 {
+					t.Errorf("refunds %v for a rejected return", payments.refunds)
+				}
+				return
+			}
+
+			if !maps.Equal(payments.refunds, tt.wantRefunds) {
+				t.Errorf("refunds = %v, want %v", payments.refunds, tt.wantRefunds)
+			}
+			closed, _ := returns.GetByID(ctx, "return-1")
+			if closed.Status != domain.ReturnStatusRefunded || closed.RefundAmount != payments.refunded() {
+				t.Errorf("return is %s with refund %d, want %s with %d", closed.Status, closed.RefundAmount, domain.ReturnStatusRefunded, payments.refunded())
+			}
+		})
+	}
+}
+
+// A refund failing part way leaves the return open with the lines' refunds
+// pending; the retry refunds each line once and closes the return
+func TestRefundReturnRetried(t *testing.T) {
+	ctx := context.Background()
+	order, orderReturn := newReceivedReturn(t, "item-1", "item-2")
+	orders := newFakeOrderRepo(order)
+	returns := newFakeReturnRepo(orderReturn)
+	payments := newFakePayments()
+	payments.set(order.ID, order.PaymentID, domain.PaymentStatusSucceeded)
+	payments.refundErr = errors.New("payment-service unavailable")
+	uc := NewOrderUseCase(orders, nil, nil, nil, returns, nil, nil, nil, &fakeInventory{}, payments, domain.PricingPolicy{}, time.Hour)
+
+	if _, err := uc.RefundReturn(ctx, "return-1", "admin"); err == nil {
+		t.Fatal("RefundReturn succeeded with a failing refund, want an error")
+	}
+	if pending := orders.get(order.ID).PendingRefunds(); len(pending) != 2 {
+		t.Fatalf("%d refunds pending after the failed attempt, want 2", len(pending))
+	}
+	if open, _ := returns.GetByID(ctx, "return-1"); open.Status != domain.ReturnStatusReceived {
+		t.Fatalf("return is %s after the failed refund, want %s", open.Status, domain.ReturnStatusReceived)
+	}
+
+	payments.refundErr = nil
+	if _, err := uc.RefundReturn(ctx, "return-1", "admin"); err != nil {
+		t.Fatalf("RefundReturn retry: %v", err)
+	}
+	// A closed return is not refunded again
+	if _, err := uc.RefundReturn(ctx, "return-1", "admin"); !errors.Is(err, domain.ErrInvalidReturnTransition) {
+		t.Errorf("RefundReturn of a refunded return error = %v, want %v", err, domain.ErrInvalidReturnTransition)
+	}
+
+	want := map[string]int64{"order-item-item-1": 1000, "order-item-item-2": 2000}
+	if !maps.Equal(payments.refunds, want) {
+		t.Errorf("refunds = %v, want %v", payments.refunds, want)
+	}
+	stored := orders.get(order.ID)
+	if stored.RefundedAmount != 3000 || len(stored.PendingRefunds()) != 0 {
+		t.Errorf("order refunded %d with %d refunds pending, want 3000 and none", stored.RefundedAmount, len(stored.PendingRefunds()))
+	}
+	closed, _ := returns.GetByID(ctx, "return-1")
+	if closed.Status != domain.ReturnStatusRefunded || closed.RefundAmount != 3000 {
+		t.Errorf("return is %s with refund %d, want %s with 3000", closed.Status, closed.RefundAmount, domain.ReturnStatusRefunded)
+	}
+}
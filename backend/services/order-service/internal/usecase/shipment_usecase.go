@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
+)
+
+// carrierActor is recorded as the author of status changes driven by carrier tracking
+const carrierActor = "carrier"
+
+// ShipmentRepository defines the interface for shipment persistence
+type ShipmentRepository interface {
+	// Create saves the shipment build returns, running build with the order locked
+	Create(ctx context.Context, orderID string, build func(order *domain.Order, existing []*domain.Shipment) (*domain.Shipment, error)) (*domain.Shipment, error)
+	GetByTrackingNumber(ctx context.Context, carrier, trackingNumber string) (*domain.Shipment, error)
+	GetByOrderID(ctx context.Context, orderID string) ([]*domain.Shipment, error)
+	RecordTrackingEvent(ctx context.Context, shipment *domain.Shipment, event *domain.TrackingEvent) (bool, error)
+}
+
+// CreateShipment packs lines of an order into a new shipment. Starting to ship a
+// confirmed order moves it to PROCESSING.
+func (uc *OrderUseCase) CreateShipment(ctx context.Context, orderID, carrier, trackingNumber string, items []domain.ShipmentItem, createdBy string) (*domain.Shipment, error) {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		logger.Errorf("Failed to get order %s: %v", orderID, err)
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	// The lines left to ship are checked against the other shipments with the order locked
+	shipment, err := uc.shipmentRepo.Create(ctx, orderID, func(locked *domain.Order, existing []*domain.Shipment) (*domain.Shipment, error) {
+		shipment, err := domain.NewShipment(locked, existing, carrier, trackingNumber, items)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create shipment: %w", err)
+		}
+		return shipment, nil
+	})
+	if err != nil {
+		logger.Errorf("Failed to save shipment for order %s: %v", orderID, err)
+		return nil, err
+	}
+
+	err = uc.updateOrder(ctx, order, func(o *domain.Order) error {
+		if o.Status != domain.OrderStatusConfirmed {
+			return errNothingToSave
+		}
+		return o.UpdateStatus(domain.OrderStatusProcessing, createdBy, "shipment created")
+	})
+	if err != nil {
+		logger.Errorf("Failed to update order %s: %v", orderID, err)
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+
+	logger.Infof("Shipment %s created for order %s (%s %s)", shipment.ID, orderID, shipment.Carrier, shipment.TrackingNumber)
+	return shipment, nil
+}
+
+// IngestTrackingEvent applies a carrier tracking update to its shipment and
+// advances the order once every shipment has shipped or been delivered. It
+// reports whether the event had already been ingested; the order is synced
+// either way so a retried event repairs an interrupted earlier attempt.
+func (uc *OrderUseCase) IngestTrackingEvent(ctx context.Context, event domain.TrackingEvent) (*domain.Shipment, *domain.Order, bool, error) {
+	event.Carrier = domain.NormalizeCarrier(event.Carrier)
+	event.TrackingNumber = strings.TrimSpace(event.TrackingNumber)
+	// The event time is part of what identifies a carrier retry of the same update
+	if event.OccurredAt.IsZero() {
+		return nil, nil, false, fmt.Errorf("%w: tracking event has no timestamp", domain.ErrInvalidTrackingEvent)
+	}
+	event.CreatedAt = time.Now()
+
+	shipment, err := uc.shipmentRepo.GetByTrackingNumber(ctx, event.Carrier, event.TrackingNumber)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	event.ShipmentID = shipment.ID
+
+	shipment.ApplyTrackingEvent(event)
+	recorded, err := uc.shipmentRepo.RecordTrackingEvent(ctx, shipment, &event)
+	if err != nil {
+		logger.Errorf("Failed to record tracking event for shipment %s: %v", shipment.ID, err)
+		return nil, nil, false, err
+	}
+	if !recorded {
+		// Reload so the response reflects the stored state rather than the replay
+		if shipment, err = uc.shipmentRepo.GetByTrackingNumber(ctx, event.Carrier, event.TrackingNumber); err != nil {
+			return nil, nil, false, err
+		}
+	}
+
+	order, err := uc.syncOrderShipments(ctx, shipment.OrderID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	logger.Infof("Tracking event %s ingested for shipment %s (duplicate: %t)", event.Status, shipment.ID, !recorded)
+	return shipment, order, !recorded, nil
+}
+
+// GetOrderShipments retrieves the shipments of an order
+func (uc *OrderUseCase) GetOrderShipments(ctx context.Context, orderID string) ([]*domain.Shipment, error) {
+	shipments, err := uc.shipmentRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		logger.Errorf("Failed to get shipments for order %s: %v", orderID, err)
+		return nil, err
+	}
+	return shipments, nil
+}
+
+// syncOrderShipments moves the order to SHIPPED or DELIVERED when its shipments allow it
+func (uc *OrderUseCase) syncOrderShipments(ctx context.Context, orderID string) (*domain.Order, error) {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		logger.Errorf("Failed to get order %s: %v", orderID, err)
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	shipments, err := uc.shipmentRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	err = uc.updateOrder(ctx, order, func(o *domain.Order) error {
+		if changed, err = o.SyncShipments(shipments, carrierActor); err != nil {
+			return err
+		}
+		if !changed {
+			return errNothingToSave
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("Failed to sync order %s with its shipments: %v", orderID, err)
+		return nil, err
+	}
+	if !changed {
+		return order, nil
+	}
+
+	logger.Infof("Order %s moved to %s by carrier tracking", orderID, order.Status)
+	return order, nil
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/pkg/logger"
+)
+
+const (
+	stockCommitRetryInterval = 1 * time.Minute
+	stockCommitRetryBatch    = 100
+	// stockCommitRetryAfter leaves a fresh confirmation to the request that made it
+	stockCommitRetryAfter = 1 * time.Minute
+)
+
+// commitStock commits the stock reservation of a confirmed order and records
+// it on the order. The reservation is held with a TTL, so until it is
+// committed inventory would expire it and put sold stock back on sale; an
+// order whose commit fails is retried by the stock commit job.
+func (uc *OrderUseCase) commitStock(ctx context.Context, order *domain.Order) error {
+	if !order.NeedsStockCommit() {
+		return nil
+	}
+
+	if err := uc.inventory.CommitReservation(ctx, order.ID); err != nil {
+		return err
+	}
+
+	err := uc.updateOrder(ctx, order, func(o *domain.Order) error {
+		// Another writer may have recorded the commit first
+		if o.StockCommittedAt != nil {
+			return errNothingToSave
+		}
+		o.MarkStockCommitted(time.Now())
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+	return nil
+}
+
+// restockItems adds the units of cancelled lines back to the available
+// stock once their reservation was committed, as releasing it no longer frees
+// anything
+func (uc *OrderUseCase) restockItems(ctx context.Context, order *domain.Order, items []domain.OrderItem, reason string) {
+	for _, item := range items {
+		if err := uc.inventory.RestockItem(ctx, item.ProductID, item.VariantID, item.Quantity, reason); err != nil {
+			logger.Errorf("Failed to restock item %s of order %s: %v", item.ID, order.ID, err)
+		}
+	}
+}
+
+// unshippedItems returns copies of the lines of an order that did not ship
+// yet, whose stock is still in the warehouse
+func unshippedItems(order *domain.Order) []domain.OrderItem {
+	var items []domain.OrderItem
+	for _, item := range order.Items {
+		if item.Status == domain.OrderItemStatusPending {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// RetryStockCommits commits the stock reservations of confirmed orders that
+// could not be committed when the order was confirmed
+func (uc *OrderUseCase) RetryStockCommits(ctx context.Context) error {
+	orders, err := uc.orderRepo.GetWithUncommittedStock(ctx, time.Now().Add(-stockCommitRetryAfter), stockCommitRetryBatch)
+	if err != nil {
+		return fmt.Errorf("failed to get orders with uncommitted stock: %w", err)
+	}
+
+	for _, order := range orders {
+		if err := uc.commitStock(ctx, order); err != nil {
+			logger.Errorf("Failed to commit stock of order %s: %v", order.ID, err)
+			continue
+		}
+		logger.Infof("Stock of order %s committed", order.ID)
+	}
+	return nil
+}
+
+// StartStockCommitJob starts a background job that retries stock commits
+func (uc *OrderUseCase) StartStockCommitJob(ctx context.Context) {
+	ticker := time.NewTicker(stockCommitRetryInterval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := uc.RetryStockCommits(ctx); err != nil {
+					logger.Errorf("Failed to retry stock commits: %v", err)
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	logger.Info("Started stock commit job")
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/order-service/internal/domain"
+)
+
+func TestConfirmedOrderCommitsStock(t *testing.T) {
+	tests := []struct {
+		name    string
+		confirm func(uc *OrderUseCase, order *domain.Order) error
+	}{
+		{
+			name: "order confirmed once paid",
+			confirm: func(uc *OrderUseCase, order *domain.Order) error {
+				return uc.UpdateOrderStatus(context.Background(), order.ID, domain.OrderStatusConfirmed, "payment-service", "payment succeeded")
+			},
+		},
+		{
+			name: "overdue order found paid",
+			confirm: func(uc *OrderUseCase, order *domain.Order) error {
+				return uc.expireUnpaidOrder(context.Background(), order)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := newPaidOrder(t)
+			order.Status = domain.OrderStatusPending
+			orders := newFakeOrderRepo(order)
+			payments := newFakePayments()
+			payments.set(order.ID, order.PaymentID, domain.PaymentStatusSucceeded)
+			inventory := &fakeInventory{}
+			uc := NewOrderUseCase(orders, newFakeSagaRepo(), nil, nil, nil, nil, nil, nil, inventory, payments, domain.PricingPolicy{}, time.Hour)
+
+			if err := tt.confirm(uc, orders.get(order.ID)); err != nil {
+				t.Fatalf("confirm: %v", err)
+			}
+
+			stored := orders.get(order.ID)
+			if stored.Status != domain.OrderStatusConfirmed {
+				t.Fatalf("Status = %s, want %s", stored.Status, domain.OrderStatusConfirmed)
+			}
+			if len(inventory.committed) != 1 || inventory.committed[0] != order.ID {
+				t.Errorf("committed reservations of %v, want %s", inventory.committed, order.ID)
+			}
+			if stored.StockCommittedAt == nil {
+				t.Error("order does not record the committed stock")
+			}
+		})
+	}
+}
+
+func TestRetryStockCommits(t *testing.T) {
+	ctx := context.Background()
+	order := newPaidOrder(t)
+	order.Status = domain.OrderStatusPending
+	orders := newFakeOrderRepo(order)
+	inventory := &fakeInventory{commitErr: errors.New("inventory unavailable")}
+	uc := NewOrderUseCase(orders, nil, nil, nil, nil, nil, nil, nil, inventory, newFakePayments(), domain.PricingPolicy{}, time.Hour)
+
+	// The order is confirmed even though its stock cannot be committed yet
+	if err := uc.UpdateOrderStatus(ctx, order.ID, domain.OrderStatusConfirmed, "payment-service", "payment succeeded"); err != nil {
+		t.Fatalf("UpdateOrderStatus: %v", err)
+	}
+	if !orders.get(order.ID).NeedsStockCommit() {
+		t.Fatal("stock recorded as committed although inventory failed")
+	}
+
+	inventory.commitErr = nil
+	if err := uc.RetryStockCommits(ctx); err != nil {
+		t.Fatalf("RetryStockCommits: %v", err)
+	}
+	if len(inventory.committed) != 1 {
+		t.Errorf("reservation committed %d times, want 1", len(inventory.committed))
+	}
+
+	// A committed order is not committed again
+	if err := uc.RetryStockCommits(ctx); err != nil {
+		t.Fatalf("RetryStockCommits: %v", err)
+	}
+	if len(inventory.committed) != 1 {
+		t.Errorf("reservation committed %d times, want 1", len(inventory.committed))
+	}
+}
+
+func TestCancelOrderRestocksCommittedStock(t *testing.T) {
+	order := newPaidOrder(t)
+	order.MarkStockCommitted(time.Now())
+	orders := newFakeOrderRepo(order)
+	payments := newFakePayments()
+	payments.set(order.ID, order.PaymentID, domain.PaymentStatusSucceeded)
+	inventory := &fakeInventory{}
+	uc := NewOrderUseCase(orders, nil, nil, nil, nil, nil, nil, nil, inventory, payments, domain.PricingPolicy{}, time.Hour)
+
+	if err := uc.CancelOrder(context.Background(), order.ID, "user-1", "changed my mind"); err != nil {
+		t.Fatalf("CancelOrder: %v", err)
+	}
+
+	// Both lines hold one unit whose reservation was already committed
+	if inventory.restocked != 2 {
+		t.Errorf("restocked %d units, want 2", inventory.restocked)
+	}
+	if len(inventory.released) != 0 {
+		t.Errorf("released reservations of %v, want none", inventory.released)
+	}
+}
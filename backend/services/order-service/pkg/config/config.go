@@ -4,10 +4,17 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// maxPaymentWindow keeps the stock reservation of an unpaid order, which
+// outlives the payment window by one expiry sweep, within the 60 minutes
+// inventory-service holds a reservation for at most
+const maxPaymentWindow = 59 * time.Minute
+
 type Config struct {
 	// Database
 	DBHost      string
@@ -25,11 +32,30 @@ type Config struct {
 	RedisPassword string
 	RedisURL      string
 
+	// Kafka
+	KafkaBrokers string
+	KafkaTopic   string
+
+	// Pricing; fees are configured as decimal amounts of Currency
+	Currency              string
+	ShippingFee           float64
+	FreeShippingThreshold float64
+	TaxRate               float64
+
+	// Unpaid PENDING orders are cancelled once this window has passed
+	PaymentWindow time.Duration
+
+	// Seller printed on invoices
+	InvoiceSellerName    string
+	InvoiceSellerAddress string
+	InvoiceSellerTaxID   string
+
 	HTTPPort             string
 	GRPCPort             string
 	ProductServiceAddr   string
 	InventoryServiceAddr string
 	PaymentServiceAddr   string
+	UserServiceAddr      string
 }
 
 func LoadConfig() *Config {
@@ -51,11 +77,31 @@ func LoadConfig() *Config {
 		RedisPort:     getEnv("REDIS_PORT", "6379"),
 		RedisPassword: getEnv("REDIS_PASSWORD", ""),
 
+		KafkaBrokers: getEnv("KAFKA_BROKERS", "localhost:9092"),
+		KafkaTopic:   getEnv("KAFKA_TOPIC", "ecommerce-events"),
+
+		Currency:              getEnv("CURRENCY", "USD"),
+		ShippingFee:           getEnvAsFloat("SHIPPING_FEE", 5.99),
+		FreeShippingThreshold: getEnvAsFloat("FREE_SHIPPING_THRESHOLD", 50),
+		TaxRate:               getEnvAsFloat("TAX_RATE", 0.08),
+
+		PaymentWindow: getEnvAsDuration("PAYMENT_WINDOW", 15*time.Minute),
+
+		InvoiceSellerName:    getEnv("INVOICE_SELLER_NAME", "Ecommerce Rec"),
+		InvoiceSellerAddress: getEnv("INVOICE_SELLER_ADDRESS", ""),
+		InvoiceSellerTaxID:   getEnv("INVOICE_SELLER_TAX_ID", ""),
+
 		HTTPPort:             getEnv("HTTP_PORT", "3004"),
 		GRPCPort:             getEnv("GRPC_PORT", "50053"),
 		ProductServiceAddr:   getEnv("PRODUCT_SERVICE_ADDR", "localhost:50051"),
 		InventoryServiceAddr: getEnv("INVENTORY_SERVICE_ADDR", "localhost:50052"),
 		PaymentServiceAddr:   getEnv("PAYMENT_SERVICE_ADDR", "localhost:50055"),
+		UserServiceAddr:      getEnv("USER_SERVICE_ADDR", "localhost:5001"),
+	}
+
+	if cfg.PaymentWindow > maxPaymentWindow {
+		log.Printf("PAYMENT_WINDOW %s is longer than inventory holds stock, using %s", cfg.PaymentWindow, maxPaymentWindow)
+		cfg.PaymentWindow = maxPaymentWindow
 	}
 
 	// Build composite URLs
@@ -74,3 +120,21 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil && duration > 0 {
+			return duration
+		}
+	}
+	return defaultValue
+}
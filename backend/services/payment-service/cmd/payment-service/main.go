@@ -1,14 +1,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"net/http"
+	"strings"
+	"time"
 
 	pb "github.com/cqchien/ecomerce-rec/backend/proto"
 	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/delivery/grpc"
 	httpDelivery "github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/delivery/http"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
 	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/infrastructure/database"
-	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/infrastructure/models"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/infrastructure/exchange"
+	grpcClient "github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/infrastructure/grpc"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/infrastructure/kafka"
 	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/infrastructure/payment"
 	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/infrastructure/redis"
 	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/repository/postgres"
@@ -34,7 +41,7 @@ func main() {
 	log.Info("Connected to PostgreSQL")
 
 	// Auto-migrate models
-	if err := db.AutoMigrate(&models.Payment{}); err != nil {
+	if err := database.RunMigrations(db); err != nil {
 		log.Fatal("Failed to migrate database", "error", err)
 	}
 	log.Info("Database migration completed")
@@ -43,23 +50,143 @@ func main() {
 	redisClient := redis.NewRedisClient(cfg.RedisURL)
 	log.Info("Connected to Redis", "client", redisClient != nil)
 
-	// Initialize Stripe provider
-	stripeProvider := payment.NewStripeProvider(cfg.StripeSecretKey)
+	// Initialize payment providers and route payments to them
+	routes, err := payment.ParseRoutes(cfg.PaymentRoutes)
+	if err != nil {
+		log.Fatal("Invalid PAYMENT_ROUTES", "error", err)
+	}
+	providers := payment.NewRegistry(routes)
+
+	stripeProvider := payment.NewStripeProvider(cfg.StripeSecretKey, cfg.StripeAPIBase)
+	providers.Register("stripe", stripeProvider)
 	log.Info("Stripe provider initialized")
 
-	// Initialize repository
+	if cfg.PayPalClientID != "" {
+		providers.Register("paypal", payment.NewPayPalProvider(cfg.PayPalClientID, cfg.PayPalClientSecret,
+			cfg.PayPalAPIBase, cfg.PayPalReturnURL, cfg.PayPalCancelURL))
+		log.Info("PayPal provider initialized", "api_base", cfg.PayPalAPIBase)
+	} else {
+		log.Warn("PAYPAL_CLIENT_ID not set, PayPal payments disabled")
+	}
+
+	// The mock provider accepts every payment, so it is only available when asked for
+	if cfg.PaymentMockOutcome != "" {
+		mockOutcome, err := payment.ParseMockOutcome(cfg.PaymentMockOutcome)
+		if err != nil {
+			log.Fatal("Invalid PAYMENT_MOCK_OUTCOME", "error", err)
+		}
+		providers.Register("mock", payment.NewMockProvider(mockOutcome, time.Duration(cfg.PaymentMockTimeoutMs)*time.Millisecond))
+		log.Warn("Mock payment provider enabled", "outcome", mockOutcome)
+	}
+
+	if err := providers.SetVault(cfg.PaymentVaultProvider); err != nil {
+		log.Fatal("Invalid PAYMENT_VAULT_PROVIDER", "error", err)
+	}
+	if err := providers.Validate(); err != nil {
+		log.Warn("Payment routes use unavailable providers", "error", err)
+	}
+	log.Info("Payment routes configured", "routes", cfg.PaymentRoutes, "vault", cfg.PaymentVaultProvider)
+
+	// Snapshot the exchange rate into the settlement currency on every payment
+	var rates usecase.ExchangeRateSource
+	if cfg.SettlementCurrency != "" {
+		staticRates, err := exchange.NewStaticRates(cfg.SettlementCurrency, cfg.ExchangeRates)
+		if err != nil {
+			log.Fatal("Invalid EXCHANGE_RATES", "error", err)
+		}
+		rates = staticRates
+		log.Info("Exchange rates configured", "settlement_currency", cfg.SettlementCurrency, "rates", cfg.ExchangeRates)
+	}
+
+	// Initialize repositories
 	paymentRepo := postgres.NewPaymentRepository(db)
+	paymentMethodRepo := postgres.NewPaymentMethodRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	reconciliationRepo := postgres.NewReconciliationRepository(db)
+	disputeRepo := postgres.NewDisputeRepository(db)
+
+	// Connect to the order service to flag disputed orders and read shipping addresses
+	orderClient, err := grpcClient.NewOrderClient(cfg.OrderServiceGRPC)
+	if err != nil {
+		log.Fatal("Failed to create order service client", "error", err)
+	}
+	defer orderClient.Close()
+
+	// Assess the fraud risk of payments before they are charged
+	riskThresholds, err := domain.ParseAmountThresholds(cfg.RiskAmountThresholds)
+	if err != nil {
+		log.Fatal("Invalid RISK_AMOUNT_THRESHOLDS", "error", err)
+	}
+	riskRules := domain.RiskRules{
+		ReviewScore:      cfg.RiskReviewScore,
+		DenyScore:        cfg.RiskDenyScore,
+		MaxUserPayments:  cfg.RiskMaxUserPayments,
+		MaxCardPayments:  cfg.RiskMaxCardPayments,
+		AmountThresholds: riskThresholds,
+		NewAccountAge:    time.Duration(cfg.RiskNewAccountHours) * time.Hour,
+	}
+	var userAccounts usecase.UserAccounts
+	if cfg.RiskNewAccountHours > 0 {
+		userClient, err := grpcClient.NewUserClient(cfg.UserServiceGRPC)
+		if err != nil {
+			log.Fatal("Failed to create user service client", "error", err)
+		}
+		defer userClient.Close()
+		userAccounts = userClient
+	}
+	riskEvaluator := usecase.NewRuleRiskEvaluator(riskRules, time.Duration(cfg.RiskVelocityWindowMinutes)*time.Minute,
+		paymentRepo, paymentMethodRepo, orderClient, userAccounts)
+	log.Info("Risk assessment configured", "review_score", cfg.RiskReviewScore, "deny_score", cfg.RiskDenyScore)
+
+	// Initialize Kafka publisher for payment events
+	kafkaPublisher := kafka.NewPublisher(strings.Split(cfg.KafkaBrokers, ","), cfg.KafkaTopic)
+	defer kafkaPublisher.Close()
 
 	// Initialize use case
-	paymentUseCase := usecase.NewPaymentUseCase(paymentRepo, stripeProvider)
+	authorizationValidity := time.Duration(cfg.AuthorizationValidityHours) * time.Hour
+	paymentUseCase := usecase.NewPaymentUseCase(paymentRepo, paymentMethodRepo, disputeRepo, providers, orderClient, rates, riskEvaluator, authorizationValidity)
+
+	// Release authorizations that were neither captured nor voided in time
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	paymentUseCase.StartAuthorizationExpiryJob(ctx, log)
+
+	// Flag the orders of disputes the order service missed
+	paymentUseCase.StartDisputeJob(ctx, log)
+
+	// Start relay publishing payment events from the outbox
+	outboxRelay := usecase.NewOutboxRelay(outboxRepo, kafkaPublisher)
+	outboxRelay.Start(ctx, log)
+
+	// Reconcile payments against provider settlement data once a day has settled
+	settlementSources := map[string]usecase.SettlementSource{"stripe": stripeProvider}
+	if cfg.ReconciliationFixturePath != "" {
+		fixture, err := payment.NewFixtureSettlementSource(cfg.ReconciliationFixturePath)
+		if err != nil {
+			log.Fatal("Invalid RECONCILIATION_FIXTURE_PATH", "error", err)
+		}
+		settlementSources[cfg.ReconciliationFixtureProvider] = fixture
+		log.Info("Using settlement fixture", "provider", cfg.ReconciliationFixtureProvider, "path", cfg.ReconciliationFixturePath)
+	}
+	reconciler := usecase.NewReconciler(reconciliationRepo, settlementSources, time.Duration(cfg.ReconciliationLagHours)*time.Hour)
+	reconciler.Start(ctx, log)
 
 	// Initialize gRPC handler
-	paymentHandler := grpc.NewPaymentHandler(paymentUseCase)
+	paymentHandler := grpc.NewPaymentHandler(paymentUseCase, reconciler)
 	log.Info("Payment handler initialized", "handler", paymentHandler != nil)
 
-	// Start HTTP server for health checks
+	// Stripe webhooks settle asynchronous payments such as 3D Secure
+	var stripeWebhook http.Handler
+	if cfg.StripeWebhookSecret != "" {
+		stripeWebhook = httpDelivery.NewStripeWebhookHandler(cfg.StripeWebhookSecret, paymentUseCase, log)
+		log.Info("Stripe webhook endpoint enabled", "path", "/webhooks/stripe")
+	} else {
+		log.Warn("STRIPE_WEBHOOK_SECRET not set, Stripe webhook endpoint disabled")
+	}
+
+	// Start HTTP server for health checks and webhooks
 	go func() {
-		httpServer := httpDelivery.NewServer(cfg.Port)
+		httpServer := httpDelivery.NewServer(cfg.Port, stripeWebhook)
 		log.Info("HTTP server listening", "port", cfg.Port)
 		if err := httpServer.Start(); err != nil {
 			log.Fatal("Failed to start HTTP server", "error", err)
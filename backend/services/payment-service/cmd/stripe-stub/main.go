@@ -0,0 +1,30 @@
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/infrastructure/stripestub"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/pkg/logger"
+)
+
+// Runs the in-memory Stripe stub. Point the payment service at it with
+// STRIPE_API_BASE=http://localhost:12111 to process payments and refunds offline.
+// Set STRIPE_STUB_WEBHOOK_URL (e.g. http://localhost:3006/webhooks/stripe) and
+// STRIPE_WEBHOOK_SECRET to have it deliver signed webhook events.
+func main() {
+	log := logger.New("stripe-stub", "info")
+
+	port := os.Getenv("STRIPE_STUB_PORT")
+	if port == "" {
+		port = "12111"
+	}
+
+	webhookURL := os.Getenv("STRIPE_STUB_WEBHOOK_URL")
+	server := stripestub.NewServer(webhookURL, os.Getenv("STRIPE_WEBHOOK_SECRET"))
+
+	log.Info("Stripe stub listening", "port", port, "webhook_url", webhookURL)
+	if err := http.ListenAndServe(":"+port, server); err != nil {
+		log.Fatal("Failed to serve", "error", err)
+	}
+}
@@ -3,8 +3,11 @@ module github.com/cqchien/ecomerce-rec/backend/services/payment-service
 go 1.24.0
 
 require (
+	github.com/cqchien/ecomerce-rec/backend/proto v0.0.0-20260114142745-91a742ef485f
 	github.com/go-redis/redis/v8 v8.11.5
-	github.com/google/uuid v1.6.0
+	github.com/jackc/pgx/v5 v5.4.3
+	github.com/segmentio/kafka-go v0.4.47
+	github.com/stripe/stripe-go/v76 v76.25.0
 	google.golang.org/grpc v1.78.0
 	gorm.io/driver/postgres v1.5.4
 	gorm.io/gorm v1.25.5
@@ -12,14 +15,13 @@ require (
 
 require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
-	github.com/cqchien/ecomerce-rec/backend/proto v0.0.0-20260114142745-91a742ef485f // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
-	github.com/jackc/pgx/v5 v5.4.3 // indirect
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
-	github.com/stripe/stripe-go/v76 v76.25.0 // indirect
+	github.com/klauspost/compress v1.15.9 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
 	golang.org/x/crypto v0.44.0 // indirect
 	golang.org/x/net v0.47.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
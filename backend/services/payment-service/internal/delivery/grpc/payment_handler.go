@@ -2,6 +2,8 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	pb "github.com/cqchien/ecomerce-rec/backend/proto"
 	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
@@ -13,27 +15,30 @@ import (
 // PaymentHandler handles gRPC requests for payments
 type PaymentHandler struct {
 	pb.UnimplementedPaymentServiceServer
-	useCase *usecase.PaymentUseCase
+	useCase    *usecase.PaymentUseCase
+	reconciler *usecase.Reconciler
 }
 
 // NewPaymentHandler creates a new payment gRPC handler
-func NewPaymentHandler(useCase *usecase.PaymentUseCase) *PaymentHandler {
+func NewPaymentHandler(useCase *usecase.PaymentUseCase, reconciler *usecase.Reconciler) *PaymentHandler {
 	return &PaymentHandler{
-		useCase: useCase,
+		useCase:    useCase,
+		reconciler: reconciler,
 	}
 }
 
 // CreatePaymentIntent creates a new payment intent
 func (h *PaymentHandler) CreatePaymentIntent(ctx context.Context, req *pb.CreatePaymentIntentRequest) (*pb.CreatePaymentIntentResponse, error) {
-	// Convert amount from cents to float
-	amount := float64(req.Amount.AmountCents) / 100.0
+	// Convert amount from minor units of its currency to float
+	amount := domain.FromMinorUnits(req.Amount.AmountCents, req.Amount.Currency)
 
 	// Map payment method type
 	method := mapProtoMethodToDomain(req.Method)
 
-	payment, err := h.useCase.CreatePayment(ctx, req.OrderId, req.UserId, amount, req.Amount.Currency, method)
+	// A saved payment method of the user is charged when payment_method_id is set
+	payment, err := h.useCase.CreatePayment(ctx, req.OrderId, req.UserId, amount, req.Amount.Currency, method, req.PaymentMethodId)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, paymentError(err)
 	}
 
 	return &pb.CreatePaymentIntentResponse{
@@ -47,7 +52,7 @@ func (h *PaymentHandler) CreatePaymentIntent(ctx context.Context, req *pb.Create
 func (h *PaymentHandler) ConfirmPayment(ctx context.Context, req *pb.ConfirmPaymentRequest) (*pb.ConfirmPaymentResponse, error) {
 	payment, err := h.useCase.ProcessPayment(ctx, req.PaymentIntentId)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, paymentError(err)
 	}
 
 	return &pb.ConfirmPaymentResponse{
@@ -55,12 +60,53 @@ func (h *PaymentHandler) ConfirmPayment(ctx context.Context, req *pb.ConfirmPaym
 	}, nil
 }
 
-// CancelPayment cancels a payment
+// AuthorizePayment places a hold for a payment without capturing it
+func (h *PaymentHandler) AuthorizePayment(ctx context.Context, req *pb.AuthorizePaymentRequest) (*pb.AuthorizePaymentResponse, error) {
+	payment, err := h.useCase.AuthorizePayment(ctx, req.PaymentIntentId)
+	if err != nil {
+		return nil, paymentError(err)
+	}
+
+	return &pb.AuthorizePaymentResponse{
+		Payment: mapDomainPaymentToProto(payment),
+	}, nil
+}
+
+// CapturePayment captures part or all of an authorized payment
+func (h *PaymentHandler) CapturePayment(ctx context.Context, req *pb.CapturePaymentRequest) (*pb.CapturePaymentResponse, error) {
+	// No amount captures the full authorization
+	amount, currency, err := h.amountFromProto(ctx, req.PaymentId, req.Amount)
+	if err != nil {
+		return nil, paymentError(err)
+	}
+
+	payment, err := h.useCase.CapturePayment(ctx, req.PaymentId, amount, currency)
+	if err != nil {
+		return nil, paymentError(err)
+	}
+
+	return &pb.CapturePaymentResponse{
+		Payment: mapDomainPaymentToProto(payment),
+	}, nil
+}
+
+// VoidPayment releases an authorized payment
+func (h *PaymentHandler) VoidPayment(ctx context.Context, req *pb.VoidPaymentRequest) (*pb.VoidPaymentResponse, error) {
+	payment, err := h.useCase.VoidPayment(ctx, req.PaymentId)
+	if err != nil {
+		return nil, paymentError(err)
+	}
+
+	return &pb.VoidPaymentResponse{
+		Payment: mapDomainPaymentToProto(payment),
+	}, nil
+}
+
+// CancelPayment cancels a payment that has not collected funds; repeated calls return the cancelled payment
 func (h *PaymentHandler) CancelPayment(ctx context.Context, req *pb.CancelPaymentRequest) (*pb.CancelPaymentResponse, error) {
-	// TODO: Implement cancel in usecase
-	payment, err := h.useCase.GetPayment(ctx, req.PaymentId)
+	payment, err := h.useCase.CancelPayment(ctx, req.PaymentId, req.Reason)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		return nil, paymentError(err)
 	}
 
 	return &pb.CancelPaymentResponse{
@@ -70,9 +116,15 @@ func (h *PaymentHandler) CancelPayment(ctx context.Context, req *pb.CancelPaymen
 
 // RefundPayment refunds a payment
 func (h *PaymentHandler) RefundPayment(ctx context.Context, req *pb.RefundPaymentRequest) (*pb.RefundPaymentResponse, error) {
-	err := h.useCase.RefundPayment(ctx, req.PaymentId)
+	// No amount refunds what is left of the payment
+	amount, currency, err := h.amountFromProto(ctx, req.PaymentId, req.Amount)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, paymentError(err)
+	}
+
+	refund, err := h.useCase.RefundPayment(ctx, req.PaymentId, amount, currency, req.Reason, req.IdempotencyKey)
+	if err != nil {
+		return nil, paymentError(err)
 	}
 
 	payment, err := h.useCase.GetPayment(ctx, req.PaymentId)
@@ -82,15 +134,26 @@ func (h *PaymentHandler) RefundPayment(ctx context.Context, req *pb.RefundPaymen
 
 	return &pb.RefundPaymentResponse{
 		Payment:  mapDomainPaymentToProto(payment),
-		RefundId: "refund_" + payment.ID, // In production, this would be from Stripe
+		RefundId: refund.ProviderRefundID,
 	}, nil
 }
 
-// GetPaymentStatus gets payment status
+// GetPaymentStatus gets payment status, by payment ID or by order ID
 func (h *PaymentHandler) GetPaymentStatus(ctx context.Context, req *pb.GetPaymentStatusRequest) (*pb.GetPaymentStatusResponse, error) {
-	payment, err := h.useCase.GetPayment(ctx, req.PaymentId)
+	var payment *domain.Payment
+	var err error
+	switch {
+	case req.PaymentId != "":
+		payment, err = h.useCase.GetPayment(ctx, req.PaymentId)
+	case req.OrderId != "":
+		payment, err = h.useCase.GetPaymentByOrderID(ctx, req.OrderId)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "payment_id or order_id is required")
+	}
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		// Only a payment that does not exist is NotFound; callers compensating
+		// a checkout take that to mean there is nothing to cancel
+		return nil, paymentError(err)
 	}
 
 	return &pb.GetPaymentStatusResponse{
@@ -98,28 +161,174 @@ func (h *PaymentHandler) GetPaymentStatus(ctx context.Context, req *pb.GetPaymen
 	}, nil
 }
 
-// GetPaymentMethods gets payment methods for a user (stub implementation)
+// GetPaymentMethods gets the saved payment methods of a user
 func (h *PaymentHandler) GetPaymentMethods(ctx context.Context, req *pb.GetPaymentMethodsRequest) (*pb.GetPaymentMethodsResponse, error) {
-	// TODO: Implement payment methods repository
+	methods, err := h.useCase.ListPaymentMethods(ctx, req.UserId)
+	if err != nil {
+		return nil, paymentError(err)
+	}
+
+	pbMethods := make([]*pb.PaymentMethod, len(methods))
+	for i := range methods {
+		pbMethods[i] = mapDomainSavedMethodToProto(&methods[i])
+	}
 	return &pb.GetPaymentMethodsResponse{
-		PaymentMethods: []*pb.PaymentMethod{},
+		PaymentMethods: pbMethods,
 	}, nil
 }
 
-// AddPaymentMethod adds a payment method for a user (stub implementation)
+// AddPaymentMethod saves a tokenized card for a user
 func (h *PaymentHandler) AddPaymentMethod(ctx context.Context, req *pb.AddPaymentMethodRequest) (*pb.AddPaymentMethodResponse, error) {
-	// TODO: Implement payment methods repository
-	return nil, status.Error(codes.Unimplemented, "AddPaymentMethod not yet implemented")
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	method, err := h.useCase.AddPaymentMethod(ctx, req.UserId, mapProtoMethodToDomain(req.Type), req.Token, req.SetAsDefault)
+	if err != nil {
+		return nil, paymentError(err)
+	}
+
+	return &pb.AddPaymentMethodResponse{
+		PaymentMethod: mapDomainSavedMethodToProto(method),
+	}, nil
 }
 
-// RemovePaymentMethod removes a payment method (stub implementation)
+// RemovePaymentMethod removes a saved payment method of a user
 func (h *PaymentHandler) RemovePaymentMethod(ctx context.Context, req *pb.RemovePaymentMethodRequest) (*pb.RemovePaymentMethodResponse, error) {
-	// TODO: Implement payment methods repository
-	return nil, status.Error(codes.Unimplemented, "RemovePaymentMethod not yet implemented")
+	if err := h.useCase.RemovePaymentMethod(ctx, req.UserId, req.PaymentMethodId); err != nil {
+		return nil, paymentError(err)
+	}
+
+	return &pb.RemovePaymentMethodResponse{
+		Success: true,
+	}, nil
+}
+
+// GetReconciliationReport returns a reconciliation report by ID, by provider
+// and day, or the provider's latest one
+func (h *PaymentHandler) GetReconciliationReport(ctx context.Context, req *pb.GetReconciliationReportRequest) (*pb.GetReconciliationReportResponse, error) {
+	if req.ReportId == "" && req.Provider == "" {
+		return nil, status.Error(codes.InvalidArgument, "report_id or provider is required")
+	}
+
+	var day time.Time
+	if req.Date != "" {
+		var err error
+		if day, err = time.Parse(time.DateOnly, req.Date); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "date must be formatted as YYYY-MM-DD")
+		}
+	}
+
+	report, err := h.reconciler.GetReport(ctx, req.ReportId, req.Provider, day)
+	if err != nil {
+		return nil, paymentError(err)
+	}
+
+	return &pb.GetReconciliationReportResponse{
+		Report: mapDomainReportToProto(report),
+	}, nil
+}
+
+// ListDisputes lists disputes, those with the nearest evidence deadline first
+func (h *PaymentHandler) ListDisputes(ctx context.Context, req *pb.ListDisputesRequest) (*pb.ListDisputesResponse, error) {
+	filter := domain.DisputeFilter{
+		Status:    mapProtoDisputeStatusToDomain(req.Status),
+		PaymentID: req.PaymentId,
+		OrderID:   req.OrderId,
+	}
+	if req.DueBefore != nil {
+		dueBefore := time.Unix(req.DueBefore.Seconds, int64(req.DueBefore.Nanos))
+		filter.DueBefore = &dueBefore
+	}
+
+	disputes, err := h.useCase.ListDisputes(ctx, filter, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, paymentError(err)
+	}
+
+	pbDisputes := make([]*pb.Dispute, len(disputes))
+	for i := range disputes {
+		pbDisputes[i] = mapDomainDisputeToProto(&disputes[i])
+	}
+	return &pb.ListDisputesResponse{
+		Disputes: pbDisputes,
+	}, nil
+}
+
+// SubmitDisputeEvidence submits the merchant's evidence for a dispute
+func (h *PaymentHandler) SubmitDisputeEvidence(ctx context.Context, req *pb.SubmitDisputeEvidenceRequest) (*pb.SubmitDisputeEvidenceResponse, error) {
+	if req.DisputeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "dispute_id is required")
+	}
+
+	dispute, err := h.useCase.SubmitDisputeEvidence(ctx, req.DisputeId, mapProtoEvidenceToDomain(req.Evidence))
+	if err != nil {
+		return nil, paymentError(err)
+	}
+
+	return &pb.SubmitDisputeEvidenceResponse{
+		Dispute: mapDomainDisputeToProto(dispute),
+	}, nil
 }
 
 // Helper functions to map between proto and domain types
 
+// amountFromProto converts an optional amount for an operation on a payment
+// from minor units. An amount without a currency is in the payment currency,
+// which decides the conversion; the currency is returned as given.
+func (h *PaymentHandler) amountFromProto(ctx context.Context, paymentID string, money *pb.Money) (float64, string, error) {
+	if money == nil {
+		return 0, "", nil
+	}
+	currency := money.Currency
+	if currency == "" {
+		payment, err := h.useCase.GetPayment(ctx, paymentID)
+		if err != nil {
+			return 0, "", err
+		}
+		currency = payment.Currency
+	}
+	return domain.FromMinorUnits(money.AmountCents, currency), money.Currency, nil
+}
+
+// mapAmountToProto converts an amount to minor units of its currency
+func mapAmountToProto(amount float64, currency string) *pb.Money {
+	return &pb.Money{AmountCents: domain.ToMinorUnits(amount, currency), Currency: currency}
+}
+
+// paymentError maps domain errors to gRPC status errors
+func paymentError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrPaymentNotFound), errors.Is(err, domain.ErrPaymentMethodNotFound),
+		errors.Is(err, domain.ErrReconciliationReportNotFound), errors.Is(err, domain.ErrDisputeNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrRefundNotAllowed), errors.Is(err, domain.ErrCaptureNotAllowed),
+		errors.Is(err, domain.ErrVoidNotAllowed), errors.Is(err, domain.ErrCancelNotAllowed),
+		errors.Is(err, domain.ErrAuthorizationExpired),
+		errors.Is(err, domain.ErrPaymentAlreadyProcessed), errors.Is(err, domain.ErrPaymentFailed),
+		errors.Is(err, domain.ErrPaymentMethodExpired), errors.Is(err, domain.ErrNoPaymentProvider),
+		errors.Is(err, domain.ErrEvidenceNotAllowed), errors.Is(err, domain.ErrEvidenceDeadlinePassed),
+		errors.Is(err, domain.ErrExchangeRateUnavailable), errors.Is(err, domain.ErrPaymentRiskDenied):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrActivePaymentExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, domain.ErrPaymentConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, domain.ErrPaymentOutcomeUnknown):
+		// Safe to retry: the retry replays the same provider attempt
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, domain.ErrInvalidAmount), errors.Is(err, domain.ErrCurrencyMismatch),
+		errors.Is(err, domain.ErrInvalidPaymentMethod), errors.Is(err, domain.ErrInvalidPaymentToken),
+		errors.Is(err, domain.ErrRawCardNumberNotAllowed), errors.Is(err, domain.ErrEmptyEvidence),
+		errors.Is(err, domain.ErrUnsupportedCurrency):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
 func mapProtoMethodToDomain(method pb.PaymentMethodType) domain.PaymentMethod {
 	switch method {
 	case pb.PaymentMethodType_CREDIT_CARD:
@@ -147,23 +356,65 @@ func mapDomainStatusToProto(status domain.PaymentStatus) pb.PaymentStatus {
 		return pb.PaymentStatus_CANCELLED
 	case domain.PaymentStatusRefunded:
 		return pb.PaymentStatus_REFUNDED
+	case domain.PaymentStatusPartiallyRefunded:
+		return pb.PaymentStatus_PARTIALLY_REFUNDED
+	case domain.PaymentStatusAuthorized:
+		return pb.PaymentStatus_AUTHORIZED
+	case domain.PaymentStatusCaptured:
+		return pb.PaymentStatus_CAPTURED
 	default:
 		return pb.PaymentStatus_PENDING
 	}
 }
 
 func mapDomainPaymentToProto(payment *domain.Payment) *pb.Payment {
+	var authorizationExpiresAt *pb.Timestamp
+	if payment.AuthorizationExpiresAt != nil {
+		authorizationExpiresAt = &pb.Timestamp{Seconds: payment.AuthorizationExpiresAt.Unix(), Nanos: int32(payment.AuthorizationExpiresAt.Nanosecond())}
+	}
+
+	var settlementAmount *pb.Money
+	if payment.SettlementCurrency != "" {
+		settlementAmount = mapAmountToProto(payment.SettlementAmount(), payment.SettlementCurrency)
+	}
+
 	return &pb.Payment{
-		Id:            payment.ID,
-		OrderId:       payment.OrderID,
-		UserId:        payment.UserID,
-		Amount:        &pb.Money{AmountCents: int64(payment.Amount * 100), Currency: payment.Currency},
-		Status:        mapDomainStatusToProto(payment.Status),
-		Method:        mapDomainMethodToProto(payment.Method),
-		TransactionId: payment.ProviderID,
-		ErrorMessage:  payment.FailureReason,
-		CreatedAt:     &pb.Timestamp{Seconds: payment.CreatedAt.Unix(), Nanos: int32(payment.CreatedAt.Nanosecond())},
-		UpdatedAt:     &pb.Timestamp{Seconds: payment.UpdatedAt.Unix(), Nanos: int32(payment.UpdatedAt.Nanosecond())},
+		Id:             payment.ID,
+		OrderId:        payment.OrderID,
+		UserId:         payment.UserID,
+		Amount:         mapAmountToProto(payment.Amount, payment.Currency),
+		RefundedAmount: mapAmountToProto(payment.RefundedAmount, payment.Currency),
+		CapturedAmount: mapAmountToProto(payment.CapturedAmount, payment.Currency),
+		Status:         mapDomainStatusToProto(payment.Status),
+		Method:         mapDomainMethodToProto(payment.Method),
+		TransactionId:  payment.ProviderID,
+		ErrorMessage:   payment.FailureReason,
+		Provider:       payment.Provider,
+		ApprovalUrl:    payment.ApprovalURL(),
+		CreatedAt:      &pb.Timestamp{Seconds: payment.CreatedAt.Unix(), Nanos: int32(payment.CreatedAt.Nanosecond())},
+		UpdatedAt:      &pb.Timestamp{Seconds: payment.UpdatedAt.Unix(), Nanos: int32(payment.UpdatedAt.Nanosecond())},
+
+		AuthorizationExpiresAt: authorizationExpiresAt,
+		SettlementAmount:       settlementAmount,
+		ExchangeRate:           payment.ExchangeRate,
+		ExchangeRateAt:         mapOptionalTimeToProto(payment.ExchangeRateAt),
+		RiskScore:              int32(payment.RiskScore),
+		RiskDecision:           string(payment.RiskDecision),
+		RiskReasons:            payment.RiskReasons,
+	}
+}
+
+func mapDomainSavedMethodToProto(method *domain.SavedPaymentMethod) *pb.PaymentMethod {
+	return &pb.PaymentMethod{
+		Id:        method.ID,
+		UserId:    method.UserID,
+		Type:      mapDomainMethodToProto(method.Type),
+		Last4:     method.Last4,
+		Brand:     method.Brand,
+		ExpMonth:  int32(method.ExpMonth),
+		ExpYear:   int32(method.ExpYear),
+		IsDefault: method.IsDefault,
+		CreatedAt: &pb.Timestamp{Seconds: method.CreatedAt.Unix(), Nanos: int32(method.CreatedAt.Nanosecond())},
 	}
 }
 
@@ -179,3 +430,138 @@ func mapDomainMethodToProto(method domain.PaymentMethod) pb.PaymentMethodType {
 		return pb.PaymentMethodType_CREDIT_CARD
 	}
 }
+
+func mapDomainReportToProto(report *domain.ReconciliationReport) *pb.ReconciliationReport {
+	discrepancies := make([]*pb.ReconciliationDiscrepancy, len(report.Discrepancies))
+	for i, d := range report.Discrepancies {
+		discrepancies[i] = &pb.ReconciliationDiscrepancy{
+			Id:                    d.ID,
+			Type:                  mapDomainDiscrepancyTypeToProto(d.Type),
+			PaymentId:             d.PaymentID,
+			ProviderPaymentId:     d.ProviderPaymentID,
+			ProviderTransactionId: d.ProviderTransactionID,
+			ExpectedAmount:        mapAmountToProto(d.ExpectedAmount, d.Currency),
+			ActualAmount:          mapAmountToProto(d.ActualAmount, d.Currency),
+			Details:               d.Details,
+		}
+	}
+
+	return &pb.ReconciliationReport{
+		Id:               report.ID,
+		Provider:         report.Provider,
+		PeriodStart:      &pb.Timestamp{Seconds: report.PeriodStart.Unix(), Nanos: int32(report.PeriodStart.Nanosecond())},
+		PeriodEnd:        &pb.Timestamp{Seconds: report.PeriodEnd.Unix(), Nanos: int32(report.PeriodEnd.Nanosecond())},
+		TransactionCount: int32(report.TransactionCount),
+		PaymentCount:     int32(report.PaymentCount),
+		Discrepancies:    discrepancies,
+		CreatedAt:        &pb.Timestamp{Seconds: report.CreatedAt.Unix(), Nanos: int32(report.CreatedAt.Nanosecond())},
+	}
+}
+
+func mapDomainDiscrepancyTypeToProto(discrepancyType domain.DiscrepancyType) pb.DiscrepancyType {
+	switch discrepancyType {
+	case domain.DiscrepancyAmountMismatch:
+		return pb.DiscrepancyType_AMOUNT_MISMATCH
+	case domain.DiscrepancyMissingCharge:
+		return pb.DiscrepancyType_MISSING_CHARGE
+	case domain.DiscrepancyUnmatchedCharge:
+		return pb.DiscrepancyType_UNMATCHED_CHARGE
+	case domain.DiscrepancyOrphanedRefund:
+		return pb.DiscrepancyType_ORPHANED_REFUND
+	default:
+		return pb.DiscrepancyType_DISCREPANCY_TYPE_UNSPECIFIED
+	}
+}
+
+func mapDomainDisputeToProto(dispute *domain.Dispute) *pb.Dispute {
+	return &pb.Dispute{
+		Id:                  dispute.ID,
+		PaymentId:           dispute.PaymentID,
+		OrderId:             dispute.OrderID,
+		Provider:            dispute.Provider,
+		ProviderDisputeId:   dispute.ProviderDisputeID,
+		Status:              mapDomainDisputeStatusToProto(dispute.Status),
+		Reason:              dispute.Reason,
+		Amount:              mapAmountToProto(dispute.Amount, dispute.Currency),
+		EvidenceDueBy:       mapOptionalTimeToProto(dispute.EvidenceDueBy),
+		EvidenceSubmittedAt: mapOptionalTimeToProto(dispute.EvidenceSubmittedAt),
+		Evidence:            mapDomainEvidenceToProto(dispute.Evidence),
+		OrderFlaggedAt:      mapOptionalTimeToProto(dispute.OrderFlaggedAt),
+		OpenedAt:            &pb.Timestamp{Seconds: dispute.OpenedAt.Unix(), Nanos: int32(dispute.OpenedAt.Nanosecond())},
+		ClosedAt:            mapOptionalTimeToProto(dispute.ClosedAt),
+		UpdatedAt:           &pb.Timestamp{Seconds: dispute.UpdatedAt.Unix(), Nanos: int32(dispute.UpdatedAt.Nanosecond())},
+	}
+}
+
+func mapOptionalTimeToProto(t *time.Time) *pb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return &pb.Timestamp{Seconds: t.Unix(), Nanos: int32(t.Nanosecond())}
+}
+
+func mapDomainDisputeStatusToProto(disputeStatus domain.DisputeStatus) pb.DisputeStatus {
+	switch disputeStatus {
+	case domain.DisputeStatusNeedsResponse:
+		return pb.DisputeStatus_NEEDS_RESPONSE
+	case domain.DisputeStatusUnderReview:
+		return pb.DisputeStatus_UNDER_REVIEW
+	case domain.DisputeStatusWon:
+		return pb.DisputeStatus_WON
+	case domain.DisputeStatusLost:
+		return pb.DisputeStatus_LOST
+	default:
+		return pb.DisputeStatus_DISPUTE_STATUS_UNSPECIFIED
+	}
+}
+
+// mapProtoDisputeStatusToDomain returns an empty status, matching every
+// dispute, for DISPUTE_STATUS_UNSPECIFIED
+func mapProtoDisputeStatusToDomain(disputeStatus pb.DisputeStatus) domain.DisputeStatus {
+	switch disputeStatus {
+	case pb.DisputeStatus_NEEDS_RESPONSE:
+		return domain.DisputeStatusNeedsResponse
+	case pb.DisputeStatus_UNDER_REVIEW:
+		return domain.DisputeStatusUnderReview
+	case pb.DisputeStatus_WON:
+		return domain.DisputeStatusWon
+	case pb.DisputeStatus_LOST:
+		return domain.DisputeStatusLost
+	default:
+		return ""
+	}
+}
+
+func mapDomainEvidenceToProto(evidence domain.DisputeEvidence) *pb.DisputeEvidence {
+	return &pb.DisputeEvidence{
+		ProductDescription:       evidence.ProductDescription,
+		CustomerName:             evidence.CustomerName,
+		CustomerEmail:            evidence.CustomerEmail,
+		CustomerPurchaseIp:       evidence.CustomerPurchaseIP,
+		BillingAddress:           evidence.BillingAddress,
+		ShippingAddress:          evidence.ShippingAddress,
+		ShippingCarrier:          evidence.ShippingCarrier,
+		ShippingTrackingNumber:   evidence.ShippingTrackingNumber,
+		ShippingDate:             evidence.ShippingDate,
+		RefundPolicyDisclosure:   evidence.RefundPolicyDisclosure,
+		RefundRefusalExplanation: evidence.RefundRefusalExplanation,
+		UncategorizedText:        evidence.UncategorizedText,
+	}
+}
+
+func mapProtoEvidenceToDomain(evidence *pb.DisputeEvidence) domain.DisputeEvidence {
+	return domain.DisputeEvidence{
+		ProductDescription:       evidence.GetProductDescription(),
+		CustomerName:             evidence.GetCustomerName(),
+		CustomerEmail:            evidence.GetCustomerEmail(),
+		CustomerPurchaseIP:       evidence.GetCustomerPurchaseIp(),
+		BillingAddress:           evidence.GetBillingAddress(),
+		ShippingAddress:          evidence.GetShippingAddress(),
+		ShippingCarrier:          evidence.GetShippingCarrier(),
+		ShippingTrackingNumber:   evidence.GetShippingTrackingNumber(),
+		ShippingDate:             evidence.GetShippingDate(),
+		RefundPolicyDisclosure:   evidence.GetRefundPolicyDisclosure(),
+		RefundRefusalExplanation: evidence.GetRefundRefusalExplanation(),
+		UncategorizedText:        evidence.GetUncategorizedText(),
+	}
+}
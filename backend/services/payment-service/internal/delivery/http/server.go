@@ -6,12 +6,14 @@ import (
 )
 
 type Server struct {
-	port string
+	port          string
+	stripeWebhook http.Handler
 }
 
-// NewServer creates a new HTTP server for health checks
-func NewServer(port string) *Server {
-	return &Server{port: port}
+// NewServer creates a new HTTP server for health checks and, when
+// stripeWebhook is not nil, the Stripe webhook endpoint
+func NewServer(port string, stripeWebhook http.Handler) *Server {
+	return &Server{port: port, stripeWebhook: stripeWebhook}
 }
 
 // Start starts the HTTP server
@@ -19,6 +21,9 @@ func (s *Server) Start() error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", s.healthCheck)
 	mux.HandleFunc("/ready", s.readyCheck)
+	if s.stripeWebhook != nil {
+		mux.Handle("/webhooks/stripe", s.stripeWebhook)
+	}
 
 	server := &http.Server{
 		Addr:    ":" + s.port,
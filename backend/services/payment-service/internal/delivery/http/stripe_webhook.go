@@ -0,0 +1,203 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/pkg/logger"
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+// maxWebhookBodyBytes caps the webhook payload size; Stripe events are well below it
+const maxWebhookBodyBytes = 64 * 1024
+
+// ProviderEventHandler applies provider events to payments
+type ProviderEventHandler interface {
+	HandleProviderEvent(ctx context.Context, event *domain.ProviderEvent) (bool, error)
+}
+
+// StripeWebhookHandler receives Stripe webhook events, verifies their
+// signature and hands the supported ones to the payment use case
+type StripeWebhookHandler struct {
+	secret  string
+	handler ProviderEventHandler
+	log     logger.Logger
+}
+
+// NewStripeWebhookHandler creates a webhook handler that verifies events with
+// the endpoint's signing secret
+func NewStripeWebhookHandler(secret string, handler ProviderEventHandler, log logger.Logger) *StripeWebhookHandler {
+	return &StripeWebhookHandler{
+		secret:  secret,
+		handler: handler,
+		log:     log,
+	}
+}
+
+// ServeHTTP handles a webhook delivery. Stripe retries anything but a 2xx,
+// so only failures worth retrying answer with a 5xx.
+func (h *StripeWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+		return
+	}
+
+	event, err := webhook.ConstructEventWithOptions(payload, r.Header.Get("Stripe-Signature"), h.secret,
+		webhook.ConstructEventOptions{IgnoreAPIVersionMismatch: true})
+	if err != nil {
+		h.log.Warn("Rejected Stripe webhook", "error", err)
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid signature"})
+		return
+	}
+
+	providerEvent, err := toProviderEvent(&event)
+	if err != nil {
+		h.log.Error("Failed to decode Stripe webhook", "event_id", event.ID, "type", event.Type, "error", err)
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "malformed event"})
+		return
+	}
+	if providerEvent == nil {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ignored"})
+		return
+	}
+
+	duplicate, err := h.handler.HandleProviderEvent(r.Context(), providerEvent)
+	switch {
+	case errors.Is(err, domain.ErrPaymentNotFound):
+		// Not one of ours, e.g. a payment made outside this service
+		h.log.Warn("Stripe webhook for unknown payment", "event_id", event.ID, "type", event.Type)
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ignored"})
+	case err != nil:
+		h.log.Error("Failed to handle Stripe webhook", "event_id", event.ID, "type", event.Type, "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to process event"})
+	case duplicate:
+		writeJSON(w, http.StatusOK, map[string]string{"status": "duplicate"})
+	default:
+		h.log.Info("Stripe webhook processed", "event_id", event.ID, "type", event.Type)
+		writeJSON(w, http.StatusOK, map[string]string{"status": "processed"})
+	}
+}
+
+// toProviderEvent translates the supported Stripe events. It returns nil for
+// event types the payment service does not act on.
+func toProviderEvent(event *stripe.Event) (*domain.ProviderEvent, error) {
+	providerEvent := &domain.ProviderEvent{
+		ID:         event.ID,
+		OccurredAt: time.Unix(event.Created, 0),
+	}
+
+	switch event.Type {
+	case stripe.EventTypePaymentIntentSucceeded, stripe.EventTypePaymentIntentPaymentFailed,
+		stripe.EventTypePaymentIntentAmountCapturableUpdated, stripe.EventTypePaymentIntentCanceled:
+		var pi stripe.PaymentIntent
+		if err := json.Unmarshal(event.Data.Raw, &pi); err != nil {
+			return nil, err
+		}
+		switch event.Type {
+		case stripe.EventTypePaymentIntentSucceeded:
+			providerEvent.Type = domain.ProviderEventPaymentSucceeded
+			providerEvent.AmountReceived = domain.FromMinorUnits(pi.AmountReceived, string(pi.Currency))
+		case stripe.EventTypePaymentIntentPaymentFailed:
+			providerEvent.Type = domain.ProviderEventPaymentFailed
+			providerEvent.FailureReason = "payment failed"
+			if pi.LastPaymentError != nil && pi.LastPaymentError.Msg != "" {
+				providerEvent.FailureReason = pi.LastPaymentError.Msg
+			}
+		case stripe.EventTypePaymentIntentAmountCapturableUpdated:
+			// Fired when a manual capture payment intent is authorized
+			if pi.Status != stripe.PaymentIntentStatusRequiresCapture {
+				return nil, nil
+			}
+			providerEvent.Type = domain.ProviderEventPaymentAuthorized
+		case stripe.EventTypePaymentIntentCanceled:
+			providerEvent.Type = domain.ProviderEventPaymentVoided
+			providerEvent.FailureReason = "cancelled at provider"
+			if pi.CancellationReason != "" {
+				providerEvent.FailureReason = "cancelled at provider: " + string(pi.CancellationReason)
+			}
+		}
+		providerEvent.ProviderPaymentID = pi.ID
+		providerEvent.PaymentID = pi.Metadata["payment_id"]
+		providerEvent.Status = string(pi.Status)
+
+	case stripe.EventTypeChargeRefunded:
+		var charge stripe.Charge
+		if err := json.Unmarshal(event.Data.Raw, &charge); err != nil {
+			return nil, err
+		}
+		providerEvent.Type = domain.ProviderEventChargeRefunded
+		if charge.PaymentIntent != nil {
+			providerEvent.ProviderPaymentID = charge.PaymentIntent.ID
+		}
+		providerEvent.PaymentID = charge.Metadata["payment_id"]
+		providerEvent.AmountRefunded = domain.FromMinorUnits(charge.AmountRefunded, string(charge.Currency))
+
+	case stripe.EventTypeChargeDisputeCreated, stripe.EventTypeChargeDisputeUpdated, stripe.EventTypeChargeDisputeClosed:
+		var dispute stripe.Dispute
+		if err := json.Unmarshal(event.Data.Raw, &dispute); err != nil {
+			return nil, err
+		}
+		switch event.Type {
+		case stripe.EventTypeChargeDisputeCreated:
+			providerEvent.Type = domain.ProviderEventDisputeCreated
+		case stripe.EventTypeChargeDisputeUpdated:
+			providerEvent.Type = domain.ProviderEventDisputeUpdated
+		case stripe.EventTypeChargeDisputeClosed:
+			providerEvent.Type = domain.ProviderEventDisputeClosed
+		}
+		if dispute.PaymentIntent != nil {
+			providerEvent.ProviderPaymentID = dispute.PaymentIntent.ID
+		}
+		providerEvent.DisputeID = dispute.ID
+		providerEvent.DisputeStatus = disputeStatus(dispute.Status)
+		providerEvent.DisputeReason = string(dispute.Reason)
+		providerEvent.DisputeAmount = domain.FromMinorUnits(dispute.Amount, string(dispute.Currency))
+		providerEvent.DisputeCurrency = strings.ToUpper(string(dispute.Currency))
+		if dispute.EvidenceDetails != nil && dispute.EvidenceDetails.DueBy > 0 {
+			dueBy := time.Unix(dispute.EvidenceDetails.DueBy, 0)
+			providerEvent.EvidenceDueBy = &dueBy
+		}
+		providerEvent.Status = string(dispute.Status)
+
+	default:
+		return nil, nil
+	}
+
+	return providerEvent, nil
+}
+
+// disputeStatus maps a Stripe dispute status to ours. Inquiries (warning_*)
+// follow the same steps as chargebacks; one closed without becoming a
+// chargeback leaves the funds with the merchant, like a won dispute.
+func disputeStatus(status stripe.DisputeStatus) domain.DisputeStatus {
+	switch status {
+	case stripe.DisputeStatusUnderReview, stripe.DisputeStatusWarningUnderReview:
+		return domain.DisputeStatusUnderReview
+	case stripe.DisputeStatusWon, stripe.DisputeStatusWarningClosed:
+		return domain.DisputeStatusWon
+	case stripe.DisputeStatusLost:
+		return domain.DisputeStatusLost
+	default:
+		return domain.DisputeStatusNeedsResponse
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
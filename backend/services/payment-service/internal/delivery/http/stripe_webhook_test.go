@@ -0,0 +1,126 @@
+package http
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/pkg/logger"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+const testWebhookSecret = "whsec_test"
+
+// fakeEventHandler is the payment use case; it reports events it already
+// handled as duplicates
+type fakeEventHandler struct {
+	handled map[string]int // Times each event was passed in, by ID
+}
+
+func (h *fakeEventHandler) HandleProviderEvent(ctx context.Context, event *domain.ProviderEvent) (bool, error) {
+	h.handled[event.ID]++
+	return h.handled[event.ID] > 1, nil
+}
+
+// paymentIntentEvent returns the payload of a payment_intent.succeeded event
+func paymentIntentEvent(eventID, paymentIntentID string) []byte {
+	return []byte(fmt.Sprintf(`{"id":%q,"object":"event","type":"payment_intent.succeeded","created":1767225600,`+
+		`"data":{"object":{"id":%q,"object":"payment_intent","status":"succeeded","amount_received":2500,"currency":"usd"}}}`,
+		eventID, paymentIntentID))
+}
+
+// signature returns the Stripe-Signature header of a payload signed at the given time
+func signature(payload []byte, secret string, at time.Time) string {
+	return fmt.Sprintf("t=%d,v1=%s", at.Unix(), hex.EncodeToString(webhook.ComputeSignature(at, payload, secret)))
+}
+
+func TestStripeWebhookHandler(t *testing.T) {
+	handler := &fakeEventHandler{handled: make(map[string]int)}
+	webhookHandler := NewStripeWebhookHandler(testWebhookSecret, handler, logger.New("payment-service", "error"))
+	now := time.Now()
+	payload := paymentIntentEvent("evt_1", "pi_1")
+
+	// Each delivery runs against the events the previous ones left handled
+	deliveries := []struct {
+		name        string
+		payload     []byte
+		signature   string
+		wantCode    int
+		wantStatus  string // Status in the response body of accepted deliveries
+		wantHandled int    // Times the event was handed to the use case so far
+	}{
+		{
+			name:     "missing signature",
+			payload:  payload,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:      "signed with another secret",
+			payload:   payload,
+			signature: signature(payload, "whsec_other", now),
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "payload changed after signing",
+			payload:   paymentIntentEvent("evt_1", "pi_2"),
+			signature: signature(payload, testWebhookSecret, now),
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "signature too old to be replayed",
+			payload:   payload,
+			signature: signature(payload, testWebhookSecret, now.Add(-time.Hour)),
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:        "signed event is processed",
+			payload:     payload,
+			signature:   signature(payload, testWebhookSecret, now),
+			wantCode:    http.StatusOK,
+			wantStatus:  "processed",
+			wantHandled: 1,
+		},
+		{
+			name:        "redelivered event is acknowledged as a duplicate",
+			payload:     payload,
+			signature:   signature(payload, testWebhookSecret, now.Add(time.Second)),
+			wantCode:    http.StatusOK,
+			wantStatus:  "duplicate",
+			wantHandled: 2,
+		},
+	}
+
+	for _, delivery := range deliveries {
+		t.Run(delivery.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/stripe", strings.NewReader(string(delivery.payload)))
+			if delivery.signature != "" {
+				req.Header.Set("Stripe-Signature", delivery.signature)
+			}
+			rec := httptest.NewRecorder()
+			webhookHandler.ServeHTTP(rec, req)
+
+			if rec.Code != delivery.wantCode {
+				t.Fatalf("status code = %d, want %d: %s", rec.Code, delivery.wantCode, rec.Body.String())
+			}
+			if delivery.wantStatus != "" {
+				var body map[string]string
+				if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+					t.Fatalf("decode response: %v", err)
+				}
+				if body["status"] != delivery.wantStatus {
+					t.Errorf("status = %q, want %q", body["status"], delivery.wantStatus)
+				}
+			}
+			if handler.handled["evt_1"] != delivery.wantHandled {
+				t.Errorf("event handled %d times, want %d", handler.handled["evt_1"], delivery.wantHandled)
+			}
+		})
+	}
+}
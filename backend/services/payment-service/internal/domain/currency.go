@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupportedCurrency     = errors.New("unsupported currency")
+	ErrExchangeRateUnavailable = errors.New("no exchange rate into the settlement currency")
+)
+
+// defaultCurrencyExponent is the exponent of most currencies
+const defaultCurrencyExponent = 2
+
+// currencyExponents is the currency registry: the supported currencies and
+// the number of minor unit digits of each
+var currencyExponents = map[string]int{
+	// Zero-decimal currencies: amounts are whole units
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "JPY": 0, "KMF": 0, "KRW": 0, "MGA": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+
+	// Three-decimal currencies
+	"BHD": 3, "JOD": 3, "KWD": 3, "OMR": 3, "TND": 3,
+
+	// Two-decimal currencies
+	"AED": 2, "AUD": 2, "BRL": 2, "CAD": 2, "CHF": 2, "CNY": 2, "CZK": 2, "DKK": 2,
+	"EUR": 2, "GBP": 2, "HKD": 2, "HUF": 2, "IDR": 2, "ILS": 2, "INR": 2, "MXN": 2,
+	"MYR": 2, "NOK": 2, "NZD": 2, "PHP": 2, "PLN": 2, "RON": 2, "SAR": 2, "SEK": 2,
+	"SGD": 2, "THB": 2, "TRY": 2, "TWD": 2, "USD": 2, "ZAR": 2,
+}
+
+// Currency is an ISO 4217 currency of the registry. Amounts are kept in major
+// units (e.g. 12.34 USD); providers and the API exchange them in minor units
+// (1234 cents), so the exponent decides the conversion.
+type Currency struct {
+	Code     string
+	Exponent int // Number of minor unit digits: 2 for USD, 0 for JPY, 3 for KWD
+}
+
+// LookupCurrency returns the registered currency of a code, in any case
+//
+// Returns:
+//   - error: ErrUnsupportedCurrency if the currency is not registered
+func LookupCurrency(code string) (Currency, error) {
+	code = strings.ToUpper(code)
+	exponent, ok := currencyExponents[code]
+	if !ok {
+		return Currency{}, ErrUnsupportedCurrency
+	}
+	return Currency{Code: code, Exponent: exponent}, nil
+}
+
+// CurrencyOf returns the registered currency of a code. Unknown codes, e.g.
+// of payments made before the registry, are treated as two-decimal currencies.
+func CurrencyOf(code string) Currency {
+	if currency, err := LookupCurrency(code); err == nil {
+		return currency
+	}
+	return Currency{Code: strings.ToUpper(code), Exponent: defaultCurrencyExponent}
+}
+
+// ToMinor converts an amount in major units to minor units
+func (c Currency) ToMinor(amount float64) int64 {
+	return int64(math.Round(amount * math.Pow10(c.Exponent)))
+}
+
+// FromMinor converts an amount in minor units to major units
+func (c Currency) FromMinor(amount int64) float64 {
+	return float64(amount) / math.Pow10(c.Exponent)
+}
+
+// Round rounds an amount to whole minor units, so repeated partial refunds
+// cannot drift from the payment amount
+func (c Currency) Round(amount float64) float64 {
+	return c.FromMinor(c.ToMinor(amount))
+}
+
+// Format formats an amount with exactly the currency's decimals, e.g. "12.30" or "1230"
+func (c Currency) Format(amount float64) string {
+	return strconv.FormatFloat(c.Round(amount), 'f', c.Exponent, 64)
+}
+
+// ToMinorUnits converts an amount in major units of a currency to minor units
+func ToMinorUnits(amount float64, currency string) int64 {
+	return CurrencyOf(currency).ToMinor(amount)
+}
+
+// FromMinorUnits converts an amount in minor units of a currency to major units
+func FromMinorUnits(amount int64, currency string) float64 {
+	return CurrencyOf(currency).FromMinor(amount)
+}
+
+// ExchangeRate is a snapshot of the rate converting a presentment currency,
+// the one the customer pays in, into the settlement currency the merchant is
+// paid out in
+type ExchangeRate struct {
+	From   string    `json:"from"`   // Presentment currency
+	To     string    `json:"to"`     // Settlement currency
+	Rate   float64   `json:"rate"`   // Units of To per unit of From
+	Source string    `json:"source"` // Where the rate came from, e.g. static
+	AsOf   time.Time `json:"as_of"`
+}
+
+// Convert converts an amount in the presentment currency into the settlement currency
+func (r *ExchangeRate) Convert(amount float64) float64 {
+	return CurrencyOf(r.To).Round(amount * r.Rate)
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "testing"
+
+func TestToMinorUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		want     int64
+	}{
+		{name: "two decimals", amount: 25.50, currency: "USD", want: 2550},
+		{name: "lower case code", amount: 25.50, currency: "usd", want: 2550},
+		{name: "float error rounds to the nearest cent", amount: 0.1 + 0.2, currency: "USD", want: 30},
+		{name: "half cent rounds away from zero", amount: 0.125, currency: "EUR", want: 13},
+		{name: "zero decimals", amount: 1500, currency: "JPY", want: 1500},
+		{name: "three decimals", amount: 1.234, currency: "KWD", want: 1234},
+		{name: "unknown currency uses two decimals", amount: 9.99, currency: "XYZ", want: 999},
+		{name: "zero", amount: 0, currency: "USD", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToMinorUnits(tt.amount, tt.currency); got != tt.want {
+				t.Errorf("ToMinorUnits(%v, %q) = %d, want %d", tt.amount, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
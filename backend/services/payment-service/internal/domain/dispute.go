@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// DisputeStatus represents the state of a dispute opened by the customer's bank
+type DisputeStatus string
+
+const (
+	// DisputeStatusNeedsResponse: evidence can be submitted until the deadline
+	DisputeStatusNeedsResponse DisputeStatus = "NEEDS_RESPONSE"
+	// DisputeStatusUnderReview: evidence was submitted and the bank is deciding
+	DisputeStatusUnderReview DisputeStatus = "UNDER_REVIEW"
+	// DisputeStatusWon: the bank decided for the merchant, the funds stay collected
+	DisputeStatusWon DisputeStatus = "WON"
+	// DisputeStatusLost: the bank decided for the customer, the funds are returned
+	DisputeStatusLost DisputeStatus = "LOST"
+)
+
+var (
+	ErrDisputeNotFound        = errors.New("dispute not found")
+	ErrEvidenceNotAllowed     = errors.New("evidence can only be submitted while a dispute needs a response")
+	ErrEvidenceDeadlinePassed = errors.New("dispute evidence deadline has passed")
+	ErrEmptyEvidence          = errors.New("dispute evidence is empty")
+)
+
+// stage orders the statuses, so a late delivery of an older provider event
+// cannot move a dispute back
+func (s DisputeStatus) stage() int {
+	switch s {
+	case DisputeStatusNeedsResponse:
+		return 0
+	case DisputeStatusUnderReview:
+		return 1
+	case DisputeStatusWon, DisputeStatusLost:
+		return 2
+	default:
+		return -1
+	}
+}
+
+// DisputeEvidence is the merchant's case against a dispute. All fields are
+// free text; the provider decides which ones the bank gets to see.
+type DisputeEvidence struct {
+	ProductDescription       string `json:"product_description,omitempty"`
+	CustomerName             string `json:"customer_name,omitempty"`
+	CustomerEmail            string `json:"customer_email,omitempty"`
+	CustomerPurchaseIP       string `json:"customer_purchase_ip,omitempty"`
+	BillingAddress           string `json:"billing_address,omitempty"`
+	ShippingAddress          string `json:"shipping_address,omitempty"`
+	ShippingCarrier          string `json:"shipping_carrier,omitempty"`
+	ShippingTrackingNumber   string `json:"shipping_tracking_number,omitempty"`
+	ShippingDate             string `json:"shipping_date,omitempty"`
+	RefundPolicyDisclosure   string `json:"refund_policy_disclosure,omitempty"`
+	RefundRefusalExplanation string `json:"refund_refusal_explanation,omitempty"`
+	UncategorizedText        string `json:"uncategorized_text,omitempty"`
+}
+
+// IsEmpty reports whether no evidence was given
+func (e DisputeEvidence) IsEmpty() bool {
+	return e == DisputeEvidence{}
+}
+
+// Dispute is a chargeback, or an inquiry that may become one, opened by the
+// customer's bank against a payment. Disputes are opened and decided at the
+// provider and reach us as provider events; the merchant's part is to submit
+// evidence before the deadline.
+type Dispute struct {
+	ID                  string          `json:"id"`
+	PaymentID           string          `json:"payment_id"`
+	OrderID             string          `json:"order_id"`
+	Provider            string          `json:"provider"`
+	ProviderDisputeID   string          `json:"provider_dispute_id"` // e.g. Stripe dp_...
+	Status              DisputeStatus   `json:"status"`
+	ProviderStatus      string          `json:"provider_status"` // Raw provider status, e.g. warning_needs_response
+	Reason              string          `json:"reason"`          // Provider reason, e.g. fraudulent
+	Amount              float64         `json:"amount"`          // Amount the customer's bank is reclaiming
+	Currency            string          `json:"currency"`
+	EvidenceDueBy       *time.Time      `json:"evidence_due_by,omitempty"`
+	Evidence            DisputeEvidence `json:"evidence"`
+	EvidenceSubmittedAt *time.Time      `json:"evidence_submitted_at,omitempty"`
+	OrderFlaggedAt      *time.Time      `json:"order_flagged_at,omitempty"` // When the order service flagged the order
+	OpenedAt            time.Time       `json:"opened_at"`
+	ClosedAt            *time.Time      `json:"closed_at,omitempty"`
+	CreatedAt           time.Time       `json:"created_at"`
+	UpdatedAt           time.Time       `json:"updated_at"`
+}
+
+// DisputeFilter narrows a dispute listing; zero fields match every dispute
+type DisputeFilter struct {
+	Status    DisputeStatus
+	PaymentID string
+	OrderID   string
+	DueBefore *time.Time // Only disputes whose evidence is due before this time
+}
+
+// NewDispute creates the dispute a provider event reports for a payment.
+// Disputes are usually first seen with their opening event, but a missed one
+// is created from whichever event arrives first.
+func NewDispute(payment *Payment, event *ProviderEvent) *Dispute {
+	now := time.Now()
+	dispute := &Dispute{
+		PaymentID:         payment.ID,
+		OrderID:           payment.OrderID,
+		Provider:          payment.Provider,
+		ProviderDisputeID: event.DisputeID,
+		Status:            event.DisputeStatus,
+		ProviderStatus:    event.Status,
+		Reason:            event.DisputeReason,
+		Amount:            event.DisputeAmount,
+		Currency:          event.DisputeCurrency,
+		EvidenceDueBy:     event.EvidenceDueBy,
+		OpenedAt:          event.OccurredAt,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if dispute.Amount == 0 {
+		dispute.Amount = payment.SettledAmount()
+	}
+	if dispute.Currency == "" {
+		dispute.Currency = payment.Currency
+	}
+	if dispute.IsClosed() {
+		dispute.ClosedAt = &event.OccurredAt
+	}
+	return dispute
+}
+
+// ApplyProviderEvent catches up with the state the provider reports for the
+// dispute. Events reporting an earlier stage than the dispute has reached are
+// ignored. It reports whether the dispute changed.
+func (d *Dispute) ApplyProviderEvent(event *ProviderEvent) bool {
+	if event.DisputeStatus.stage() < d.Status.stage() {
+		return false
+	}
+
+	changed := false
+	if event.DisputeStatus != d.Status || event.Status != d.ProviderStatus {
+		d.Status = event.DisputeStatus
+		d.ProviderStatus = event.Status
+		changed = true
+	}
+	if event.EvidenceDueBy != nil && (d.EvidenceDueBy == nil || !event.EvidenceDueBy.Equal(*d.EvidenceDueBy)) {
+		d.EvidenceDueBy = event.EvidenceDueBy
+		changed = true
+	}
+	if currency := CurrencyOf(d.Currency); event.DisputeAmount > 0 && currency.Round(event.DisputeAmount) != currency.Round(d.Amount) {
+		d.Amount = event.DisputeAmount
+		changed = true
+	}
+	if d.IsClosed() && d.ClosedAt == nil {
+		d.ClosedAt = &event.OccurredAt
+		changed = true
+	}
+
+	if changed {
+		d.UpdatedAt = time.Now()
+	}
+	return changed
+}
+
+// IsClosed reports whether the bank has decided the dispute
+func (d *Dispute) IsClosed() bool {
+	return d.Status == DisputeStatusWon || d.Status == DisputeStatusLost
+}
+
+// SubmitEvidence records the merchant's evidence and moves the dispute to
+// UNDER_REVIEW. Evidence is accepted once, before the deadline.
+//
+// Returns:
+//   - error: ErrEmptyEvidence if no evidence is given, ErrEvidenceNotAllowed
+//     if the dispute does not need a response, ErrEvidenceDeadlinePassed if
+//     the deadline has passed
+func (d *Dispute) SubmitEvidence(evidence DisputeEvidence, at time.Time) error {
+	if evidence.IsEmpty() {
+		return ErrEmptyEvidence
+	}
+	if d.Status != DisputeStatusNeedsResponse {
+		return ErrEvidenceNotAllowed
+	}
+	if d.EvidenceDueBy != nil && !at.Before(*d.EvidenceDueBy) {
+		return ErrEvidenceDeadlinePassed
+	}
+
+	d.Evidence = evidence
+	d.EvidenceSubmittedAt = &at
+	d.Status = DisputeStatusUnderReview
+	d.UpdatedAt = time.Now()
+	return nil
+}
+
+// NeedsOrderFlag reports whether the order service has yet to flag the order
+func (d *Dispute) NeedsOrderFlag() bool {
+	return d.OrderFlaggedAt == nil
+}
+
+// MarkOrderFlagged records that the order service flagged the order
+func (d *Dispute) MarkOrderFlagged(at time.Time) {
+	d.OrderFlaggedAt = &at
+	d.UpdatedAt = time.Now()
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// EventType identifies a payment domain event.
+// Values match the event types defined by event-service.
+type EventType string
+
+const (
+	EventTypePaymentCancelled EventType = "PAYMENT_CANCELLED"
+	EventTypePaymentDisputed  EventType = "PAYMENT_DISPUTED"
+)
+
+// OutboxEvent is a payment domain event stored in the transactional outbox
+// until it has been published to the message broker
+type OutboxEvent struct {
+	ID            string
+	Type          EventType
+	AggregateID   string
+	Payload       string // JSON snapshot of the payment at the time of the event
+	Attempts      int
+	LastError     string
+	CreatedAt     time.Time
+	PublishedAt   *time.Time
+	NextAttemptAt *time.Time // When a failed event is retried
+	FailedAt      *time.Time // When the event was dead-lettered after MaxOutboxAttempts
+}
+
+const (
+	// MaxOutboxAttempts is how often publishing an event is tried before it is
+	// dead-lettered; the backoff spreads the attempts over about 40 minutes
+	MaxOutboxAttempts = 15
+
+	outboxRetryBaseDelay = 2 * time.Second
+	outboxRetryMaxDelay  = 5 * time.Minute
+)
+
+// OutboxRetryDelay returns how long to wait before publishing an event again
+// after the given number of failed attempts
+func OutboxRetryDelay(attempts int) time.Duration {
+	delay := outboxRetryBaseDelay
+	for i := 1; i < attempts && delay < outboxRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > outboxRetryMaxDelay {
+		return outboxRetryMaxDelay
+	}
+	return delay
+}
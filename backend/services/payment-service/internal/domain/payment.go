@@ -2,6 +2,8 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -15,8 +17,21 @@ const (
 	PaymentStatusFailed     PaymentStatus = "FAILED"
 	PaymentStatusRefunded   PaymentStatus = "REFUNDED"
 	PaymentStatusCancelled  PaymentStatus = "CANCELLED"
+
+	// PaymentStatusPartiallyRefunded means part of a completed payment has been
+	// refunded; further refunds are allowed up to the remaining amount
+	PaymentStatusPartiallyRefunded PaymentStatus = "PARTIALLY_REFUNDED"
+
+	// PaymentStatusAuthorized means the funds are held on the customer's card
+	// but not collected; the hold is captured or voided before it expires
+	PaymentStatusAuthorized PaymentStatus = "AUTHORIZED"
+	// PaymentStatusCaptured means an authorization was captured, in full or in part
+	PaymentStatusCaptured PaymentStatus = "CAPTURED"
 )
 
+// AuthorizationExpiredReason is recorded on authorizations released by the expiry job
+const AuthorizationExpiredReason = "authorization expired"
+
 // PaymentMethod represents the method used for payment
 type PaymentMethod string
 
@@ -34,6 +49,17 @@ var (
 	ErrInvalidPaymentMethod    = errors.New("invalid payment method")
 	ErrPaymentAlreadyProcessed = errors.New("payment already processed")
 	ErrRefundNotAllowed        = errors.New("refund not allowed for this payment")
+	ErrPaymentPending          = errors.New("payment awaiting customer action")
+	ErrCaptureNotAllowed       = errors.New("capture not allowed for this payment")
+	ErrVoidNotAllowed          = errors.New("void not allowed for this payment")
+	ErrCancelNotAllowed        = errors.New("cancel not allowed for this payment")
+	ErrAuthorizationExpired    = errors.New("payment authorization expired")
+	ErrNoPaymentProvider       = errors.New("no payment provider for this payment method and currency")
+	ErrActivePaymentExists     = errors.New("order already has an active payment")
+	ErrPaymentOutcomeUnknown   = errors.New("payment provider outcome unknown; retry the same payment")
+	// ErrPaymentConflict is returned when a payment was changed by another
+	// writer since it was loaded; nothing was saved
+	ErrPaymentConflict = errors.New("payment was changed concurrently")
 )
 
 // Payment represents a payment transaction
@@ -42,14 +68,53 @@ type Payment struct {
 	OrderID          string        `json:"order_id"`
 	UserID           string        `json:"user_id"`
 	Amount           float64       `json:"amount"`
+	RefundedAmount   float64       `json:"refunded_amount"` // Cumulative amount refunded
 	Currency         string        `json:"currency"`
 	Status           PaymentStatus `json:"status"`
 	Method           PaymentMethod `json:"method"`
+	Provider         string        `json:"provider"`          // Name of the payment provider the payment is routed to (e.g., stripe, paypal)
 	ProviderID       string        `json:"provider_id"`       // External payment provider ID (e.g., Stripe charge ID)
 	ProviderResponse string        `json:"provider_response"` // Raw response from payment provider
 	FailureReason    string        `json:"failure_reason,omitempty"`
-	CreatedAt        time.Time     `json:"created_at"`
-	UpdatedAt        time.Time     `json:"updated_at"`
+	Attempts         int           `json:"attempts"`              // Number of times the payment was sent to the provider; part of its idempotency keys
+	DisputedAt       *time.Time    `json:"disputed_at,omitempty"` // Set when the customer's bank opens a dispute
+
+	// Set when the provider reports funds collected for a payment that was
+	// already cancelled; the money is owed back to the customer
+	CollectedAfterCancelAt *time.Time `json:"collected_after_cancel_at,omitempty"`
+
+	// Exchange rate snapshot taken when the payment was created, converting
+	// Currency, the presentment currency, into the merchant's settlement currency
+	SettlementCurrency string     `json:"settlement_currency,omitempty"`
+	ExchangeRate       float64    `json:"exchange_rate,omitempty"`
+	ExchangeRateSource string     `json:"exchange_rate_source,omitempty"`
+	ExchangeRateAt     *time.Time `json:"exchange_rate_at,omitempty"`
+
+	// Fraud risk assessment of the latest attempt, taken before it reached the provider
+	RiskScore      int          `json:"risk_score"`
+	RiskDecision   RiskDecision `json:"risk_decision,omitempty"`
+	RiskReasons    []string     `json:"risk_reasons,omitempty"`
+	RiskAssessedAt *time.Time   `json:"risk_assessed_at,omitempty"`
+
+	// Saved payment method charged, if the payment was created with one
+	PaymentMethodID    string `json:"payment_method_id,omitempty"`
+	ProviderCustomerID string `json:"provider_customer_id,omitempty"`
+	ProviderMethodID   string `json:"provider_method_id,omitempty"`
+
+	// Two-phase payments: the authorization hold and what was captured of it
+	AuthorizedAt           *time.Time `json:"authorized_at,omitempty"`
+	AuthorizationExpiresAt *time.Time `json:"authorization_expires_at,omitempty"`
+	CapturedAmount         float64    `json:"captured_amount"`
+	CapturedAt             *time.Time `json:"captured_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   int64     `json:"-"` // Incremented on every save; a save of an older version is rejected
+
+	// Domain events raised since the payment was loaded, written to the outbox on save
+	events []EventType
+	// Whether an attempt was started since the payment was loaded, logged on save
+	attemptStarted bool
 }
 
 // NewPayment creates a new payment with validation.
@@ -58,16 +123,22 @@ type Payment struct {
 // Parameters:
 //   - orderID: The ID of the order this payment is for (as string)
 //   - userID: The ID of the user making the payment (as string)
-//   - amount: The payment amount (must be > 0)
-//   - currency: The currency code (e.g., "USD", "EUR")
+//   - amount: The payment amount (must be > 0), rounded to the currency's minor units
+//   - currency: The currency code (e.g., "USD", "JPY"), which must be in the currency registry
 //   - method: The payment method (CREDIT_CARD, STRIPE, etc.)
 //
 // Returns:
 //   - *Payment: The newly created payment with status set to PENDING
-//   - error: ErrInvalidAmount if amount <= 0, ErrInvalidPaymentMethod if method is empty
+//   - error: ErrInvalidAmount if amount <= 0, ErrUnsupportedCurrency if the
+//     currency is not registered, ErrInvalidPaymentMethod if method is empty
 //
 // Note: ID generation is handled by the repository layer using PostgreSQL's uuid_generate_v7().
 func NewPayment(orderID, userID string, amount float64, currency string, method PaymentMethod) (*Payment, error) {
+	registered, err := LookupCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	amount = registered.Round(amount)
 	if amount <= 0 {
 		return nil, ErrInvalidAmount
 	}
@@ -81,7 +152,7 @@ func NewPayment(orderID, userID string, amount float64, currency string, method
 		OrderID:   orderID,
 		UserID:    userID,
 		Amount:    amount,
-		Currency:  currency,
+		Currency:  registered.Code,
 		Status:    PaymentStatusPending,
 		Method:    method,
 		CreatedAt: now,
@@ -89,16 +160,118 @@ func NewPayment(orderID, userID string, amount float64, currency string, method
 	}, nil
 }
 
-// MarkAsProcessing marks the payment as processing
+// SetExchangeRate records the rate converting the payment into the settlement
+// currency
+//
+// Returns:
+//   - error: ErrCurrencyMismatch if the rate is not from the payment currency
+func (p *Payment) SetExchangeRate(rate *ExchangeRate) error {
+	if !strings.EqualFold(rate.From, p.Currency) {
+		return ErrCurrencyMismatch
+	}
+	asOf := rate.AsOf
+	p.SettlementCurrency = strings.ToUpper(rate.To)
+	p.ExchangeRate = rate.Rate
+	p.ExchangeRateSource = rate.Source
+	p.ExchangeRateAt = &asOf
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// SettlementAmount converts the collected amount into the settlement currency
+// at the rate of the snapshot. It returns 0 if the payment has no snapshot.
+func (p *Payment) SettlementAmount() float64 {
+	if p.SettlementCurrency == "" {
+		return 0
+	}
+	rate := ExchangeRate{From: p.Currency, To: p.SettlementCurrency, Rate: p.ExchangeRate}
+	return rate.Convert(p.SettledAmount())
+}
+
+// UseSavedMethod makes the payment charge a saved payment method of its user
+//
+// Returns:
+//   - error: ErrPaymentMethodNotFound if the method belongs to another user,
+//     ErrPaymentMethodExpired if the card has expired
+func (p *Payment) UseSavedMethod(method *SavedPaymentMethod) error {
+	if method.UserID != p.UserID {
+		return ErrPaymentMethodNotFound
+	}
+	if method.IsExpired(time.Now()) {
+		return ErrPaymentMethodExpired
+	}
+	p.Method = method.Type
+	p.Provider = method.Provider
+	p.PaymentMethodID = method.ID
+	p.ProviderCustomerID = method.ProviderCustomerID
+	p.ProviderMethodID = method.ProviderMethodID
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// MarkAsProcessing starts a new attempt to charge a pending payment, or to
+// retry a failed one. Each attempt gets its own idempotency keys, and the
+// provider payment of the failed attempt is forgotten.
 func (p *Payment) MarkAsProcessing() error {
-	if p.Status != PaymentStatusPending {
+	if !p.CanStartAttempt() {
 		return ErrPaymentAlreadyProcessed
 	}
 	p.Status = PaymentStatusProcessing
+	p.Attempts++
+	p.attemptStarted = true
+	p.ProviderID = ""
+	p.ProviderResponse = ""
+	p.FailureReason = ""
 	p.UpdatedAt = time.Now()
 	return nil
 }
 
+// CanStartAttempt reports whether the payment can be sent to the provider in
+// a new attempt: it is pending, or its last attempt failed
+func (p *Payment) CanStartAttempt() bool {
+	return p.Status == PaymentStatusPending || p.Status == PaymentStatusFailed
+}
+
+// IdempotencyKey returns the key for a provider operation of the current
+// attempt. Retrying an attempt whose response was lost sends the same key,
+// so the provider replays the original result instead of charging again.
+func (p *Payment) IdempotencyKey(operation string) string {
+	return fmt.Sprintf("%s-%s-%d", operation, p.ID, p.Attempts)
+}
+
+// IsActive reports whether the payment still counts as the order's payment.
+// An order has at most one active payment; failed and cancelled ones can be
+// followed by a new payment.
+func (p *Payment) IsActive() bool {
+	return p.Status != PaymentStatusFailed && p.Status != PaymentStatusCancelled
+}
+
+// Matches reports whether another payment request is for the same user,
+// amount and currency, i.e. a retry of the request that created this payment
+func (p *Payment) Matches(other *Payment) bool {
+	return p.OrderID == other.OrderID && p.UserID == other.UserID &&
+		p.round(p.Amount) == p.round(other.Amount) && strings.EqualFold(p.Currency, other.Currency)
+}
+
+// IsAwaitingAction reports whether the payment was handed to the provider and
+// waits for the customer, e.g. 3D Secure or PayPal approval. Processing such
+// a payment again checks with the provider instead of starting over.
+func (p *Payment) IsAwaitingAction() bool {
+	return p.Status == PaymentStatusProcessing && p.ProviderID != ""
+}
+
+// ApprovalURL returns the provider page where the customer approves a payment
+// awaiting action, such as a PayPal order, or "" if there is none
+func (p *Payment) ApprovalURL() string {
+	if !p.IsAwaitingAction() {
+		return ""
+	}
+	if strings.HasPrefix(p.ProviderResponse, "https://") || strings.HasPrefix(p.ProviderResponse, "http://") {
+		return p.ProviderResponse
+	}
+	return ""
+}
+
 // MarkAsCompleted marks the payment as completed
 func (p *Payment) MarkAsCompleted(providerID, providerResponse string) {
 	p.Status = PaymentStatusCompleted
@@ -107,6 +280,177 @@ func (p *Payment) MarkAsCompleted(providerID, providerResponse string) {
 	p.UpdatedAt = time.Now()
 }
 
+// MarkAsAwaitingAction records the provider payment while the customer
+// completes an asynchronous step such as 3D Secure. The payment stays
+// PROCESSING until a provider event settles it.
+func (p *Payment) MarkAsAwaitingAction(providerID, providerResponse string) {
+	p.ProviderID = providerID
+	p.ProviderResponse = providerResponse
+	p.UpdatedAt = time.Now()
+}
+
+// MarkAsAuthorized records a successful authorization of the full amount,
+// held until expiresAt
+func (p *Payment) MarkAsAuthorized(providerID, providerResponse string, expiresAt time.Time) {
+	now := time.Now()
+	p.Status = PaymentStatusAuthorized
+	p.ProviderID = providerID
+	p.ProviderResponse = providerResponse
+	p.FailureReason = ""
+	p.AuthorizedAt = &now
+	p.AuthorizationExpiresAt = &expiresAt
+	p.UpdatedAt = now
+}
+
+// IsAuthorizationExpired reports whether the authorization hold has lapsed at the given time
+func (p *Payment) IsAuthorizationExpired(at time.Time) bool {
+	return p.AuthorizationExpiresAt != nil && !at.Before(*p.AuthorizationExpiresAt)
+}
+
+// Capture collects part or all of an authorized payment; an amount of 0
+// captures the full authorization. Whatever is not captured is released.
+//
+// Returns:
+//   - error: ErrCaptureNotAllowed if the payment is not authorized,
+//     ErrAuthorizationExpired if the hold has lapsed, ErrInvalidAmount if the
+//     amount is negative or exceeds the authorized amount
+func (p *Payment) Capture(amount float64) error {
+	if p.Status != PaymentStatusAuthorized {
+		return ErrCaptureNotAllowed
+	}
+	if p.IsAuthorizationExpired(time.Now()) {
+		return ErrAuthorizationExpired
+	}
+	if amount == 0 {
+		amount = p.Amount
+	}
+	amount = p.round(amount)
+	if amount <= 0 || amount > p.Amount {
+		return ErrInvalidAmount
+	}
+
+	now := time.Now()
+	p.Status = PaymentStatusCaptured
+	p.CapturedAmount = amount
+	p.CapturedAt = &now
+	p.UpdatedAt = now
+	return nil
+}
+
+// Void releases an authorization without collecting any funds
+func (p *Payment) Void() error {
+	if p.Status != PaymentStatusAuthorized {
+		return ErrVoidNotAllowed
+	}
+	p.markCancelled()
+	return nil
+}
+
+// ExpireAuthorization releases an authorization whose hold has lapsed
+func (p *Payment) ExpireAuthorization() error {
+	if err := p.Void(); err != nil {
+		return err
+	}
+	p.FailureReason = AuthorizationExpiredReason
+	return nil
+}
+
+// SettleSucceeded applies a provider report that the payment went through.
+// A failed attempt can still succeed when the customer retries on the same
+// provider payment, and an authorization captured at the provider (e.g. from
+// the dashboard) becomes CAPTURED for the amount received. A cancelled payment
+// stays CANCELLED and is marked as collected after cancellation, so it can be
+// refunded. It reports whether the payment changed.
+func (p *Payment) SettleSucceeded(providerID, providerResponse string, amountReceived float64) bool {
+	switch p.Status {
+	case PaymentStatusCancelled:
+		if p.CollectedAfterCancelAt != nil {
+			return false
+		}
+		now := time.Now()
+		p.ProviderResponse = providerResponse
+		p.CollectedAfterCancelAt = &now
+		p.UpdatedAt = now
+		return true
+	case PaymentStatusPending, PaymentStatusProcessing, PaymentStatusFailed:
+		p.MarkAsCompleted(providerID, providerResponse)
+		p.FailureReason = ""
+		return true
+	case PaymentStatusAuthorized:
+		if amountReceived <= 0 || amountReceived > p.Amount {
+			amountReceived = p.Amount
+		}
+		now := time.Now()
+		p.Status = PaymentStatusCaptured
+		p.ProviderResponse = providerResponse
+		p.CapturedAmount = p.round(amountReceived)
+		p.CapturedAt = &now
+		p.UpdatedAt = now
+		return true
+	default:
+		return false
+	}
+}
+
+// SettleAuthorized applies a provider report that the payment was authorized,
+// e.g. once the customer completed 3D Secure. It reports whether the payment changed.
+func (p *Payment) SettleAuthorized(providerID, providerResponse string, expiresAt time.Time) bool {
+	switch p.Status {
+	case PaymentStatusPending, PaymentStatusProcessing, PaymentStatusFailed:
+		p.MarkAsAuthorized(providerID, providerResponse, expiresAt)
+		return true
+	default:
+		return false
+	}
+}
+
+// SettleVoided applies a provider report that an authorization was released,
+// for instance because the provider cancelled it when the hold lapsed. It
+// reports whether the payment changed.
+func (p *Payment) SettleVoided(reason string) bool {
+	if p.Void() != nil {
+		return false
+	}
+	p.FailureReason = reason
+	return true
+}
+
+// SettleFailed applies a provider report that the payment failed. Payments
+// that already completed are left alone. It reports whether the payment changed.
+func (p *Payment) SettleFailed(reason string) bool {
+	if p.Status != PaymentStatusPending && p.Status != PaymentStatusProcessing {
+		return false
+	}
+	p.MarkAsFailed(reason)
+	return true
+}
+
+// SyncRefundedAmount catches up with the cumulative amount the provider has
+// refunded, including refunds issued outside this service. It reports
+// whether the payment changed.
+func (p *Payment) SyncRefundedAmount(total float64) bool {
+	total = p.round(total)
+	if total <= p.RefundedAmount || total > p.SettledAmount() {
+		return false
+	}
+	if !p.CanRefund() {
+		return false
+	}
+	return p.Refund(total-p.RefundedAmount) == nil
+}
+
+// MarkDisputed records that a dispute was opened against the payment and
+// raises PAYMENT_DISPUTED. It reports whether the payment changed.
+func (p *Payment) MarkDisputed(at time.Time) bool {
+	if p.DisputedAt != nil {
+		return false
+	}
+	p.DisputedAt = &at
+	p.UpdatedAt = time.Now()
+	p.recordEvent(EventTypePaymentDisputed)
+	return true
+}
+
 // MarkAsFailed marks the payment as failed
 func (p *Payment) MarkAsFailed(reason string) {
 	p.Status = PaymentStatusFailed
@@ -116,25 +460,98 @@ func (p *Payment) MarkAsFailed(reason string) {
 
 // CanRefund checks if the payment can be refunded
 func (p *Payment) CanRefund() bool {
-	return p.Status == PaymentStatusCompleted
+	switch p.Status {
+	case PaymentStatusCompleted, PaymentStatusCaptured, PaymentStatusPartiallyRefunded:
+		return p.RefundableAmount() > 0
+	default:
+		return false
+	}
+}
+
+// SettledAmount returns the amount actually collected: the captured amount
+// for two-phase payments, the full amount otherwise
+func (p *Payment) SettledAmount() float64 {
+	if p.CapturedAt != nil {
+		return p.CapturedAmount
+	}
+	return p.Amount
+}
+
+// RefundableAmount returns the part of the collected amount not refunded yet
+func (p *Payment) RefundableAmount() float64 {
+	return p.round(p.SettledAmount() - p.RefundedAmount)
 }
 
-// Refund processes a refund for the payment
-func (p *Payment) Refund() error {
+// Refund records a refund of the given amount. The payment becomes
+// PARTIALLY_REFUNDED, or REFUNDED once nothing is left to refund.
+//
+// Returns:
+//   - error: ErrRefundNotAllowed if the payment is not completed or captured, or already
+//     fully refunded, ErrInvalidAmount if amount is not positive or exceeds
+//     the refundable amount
+func (p *Payment) Refund(amount float64) error {
 	if !p.CanRefund() {
 		return ErrRefundNotAllowed
 	}
-	p.Status = PaymentStatusRefunded
+	amount = p.round(amount)
+	if amount <= 0 || amount > p.RefundableAmount() {
+		return ErrInvalidAmount
+	}
+
+	p.RefundedAmount = p.round(p.RefundedAmount + amount)
+	if p.RefundableAmount() == 0 {
+		p.Status = PaymentStatusRefunded
+	} else {
+		p.Status = PaymentStatusPartiallyRefunded
+	}
 	p.UpdatedAt = time.Now()
 	return nil
 }
 
-// Cancel cancels a pending payment
-func (p *Payment) Cancel() error {
-	if p.Status != PaymentStatusPending {
-		return ErrPaymentAlreadyProcessed
+// Cancel cancels a payment that has not collected any funds: one still
+// pending, waiting for the customer at the provider, or authorized. Money that
+// was already collected has to be refunded instead.
+//
+// Returns:
+//   - error: ErrCancelNotAllowed for payments in any other state
+func (p *Payment) Cancel(reason string) error {
+	if p.Status != PaymentStatusPending && p.Status != PaymentStatusAuthorized && !p.IsAwaitingAction() {
+		return ErrCancelNotAllowed
 	}
+	p.markCancelled()
+	p.FailureReason = reason
+	return nil
+}
+
+// markCancelled moves the payment to CANCELLED and raises PAYMENT_CANCELLED
+func (p *Payment) markCancelled() {
 	p.Status = PaymentStatusCancelled
 	p.UpdatedAt = time.Now()
-	return nil
+	p.recordEvent(EventTypePaymentCancelled)
+}
+
+func (p *Payment) recordEvent(eventType EventType) {
+	p.events = append(p.events, eventType)
+}
+
+// PendingEvents returns the domain events not yet written to the outbox
+func (p *Payment) PendingEvents() []EventType {
+	return p.events
+}
+
+// StartedAttempt reports whether an attempt was started since the payment
+// was loaded, to be logged for the risk velocity rules
+func (p *Payment) StartedAttempt() bool {
+	return p.attemptStarted
+}
+
+// MarkPersisted clears the events and the started attempt once they have been saved
+func (p *Payment) MarkPersisted() {
+	p.events = nil
+	p.attemptStarted = false
+}
+
+// round rounds an amount to whole minor units of the payment currency
+func (p *Payment) round(amount float64) float64 {
+	return CurrencyOf(p.Currency).Round(amount)
 }
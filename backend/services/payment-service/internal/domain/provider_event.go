@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+// ProviderEventType identifies an asynchronous notification from the payment provider
+type ProviderEventType string
+
+const (
+	ProviderEventPaymentSucceeded  ProviderEventType = "PAYMENT_SUCCEEDED"
+	ProviderEventPaymentFailed     ProviderEventType = "PAYMENT_FAILED"
+	ProviderEventChargeRefunded    ProviderEventType = "CHARGE_REFUNDED"
+	ProviderEventDisputeCreated    ProviderEventType = "DISPUTE_CREATED"
+	ProviderEventDisputeUpdated    ProviderEventType = "DISPUTE_UPDATED"
+	ProviderEventDisputeClosed     ProviderEventType = "DISPUTE_CLOSED"
+	ProviderEventPaymentAuthorized ProviderEventType = "PAYMENT_AUTHORIZED"
+	ProviderEventPaymentVoided     ProviderEventType = "PAYMENT_VOIDED"
+)
+
+// ProviderEvent is a provider webhook translated into the fields the payment
+// transitions need. Events are delivered at least once and possibly out of
+// order, so applying one must be idempotent.
+type ProviderEvent struct {
+	ID                string // Provider event ID, used for deduplication
+	Type              ProviderEventType
+	ProviderPaymentID string // e.g. Stripe payment intent ID
+	PaymentID         string // Our payment ID from the provider metadata, if present
+	Status            string // Raw provider status, stored as the provider response
+	FailureReason     string
+	AmountReceived    float64 // Amount collected, for succeeded payments
+	AmountRefunded    float64 // Cumulative amount refunded at the provider
+	OccurredAt        time.Time
+
+	// Disputes
+	DisputeID       string // Provider dispute ID
+	DisputeStatus   DisputeStatus
+	DisputeReason   string
+	DisputeAmount   float64
+	DisputeCurrency string
+	EvidenceDueBy   *time.Time
+
+	// AuthorizationExpiresAt is when the hold of an authorized payment lapses;
+	// filled in by the use case, since providers do not report it
+	AuthorizationExpiresAt time.Time
+}
+
+// IsDispute reports whether the event is about a dispute of the payment
+func (e *ProviderEvent) IsDispute() bool {
+	switch e.Type {
+	case ProviderEventDisputeCreated, ProviderEventDisputeUpdated, ProviderEventDisputeClosed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Apply performs the transition the event describes on the payment and
+// reports whether the payment changed
+func (e *ProviderEvent) Apply(payment *Payment) bool {
+	switch e.Type {
+	case ProviderEventPaymentSucceeded:
+		return payment.SettleSucceeded(e.ProviderPaymentID, e.Status, e.AmountReceived)
+	case ProviderEventPaymentAuthorized:
+		return payment.SettleAuthorized(e.ProviderPaymentID, e.Status, e.AuthorizationExpiresAt)
+	case ProviderEventPaymentVoided:
+		return payment.SettleVoided(e.FailureReason)
+	case ProviderEventPaymentFailed:
+		return payment.SettleFailed(e.FailureReason)
+	case ProviderEventChargeRefunded:
+		return payment.SyncRefundedAmount(e.AmountRefunded)
+	case ProviderEventDisputeCreated, ProviderEventDisputeUpdated, ProviderEventDisputeClosed:
+		return payment.MarkDisputed(e.OccurredAt)
+	default:
+		return false
+	}
+}
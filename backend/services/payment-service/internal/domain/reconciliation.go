@@ -0,0 +1,245 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrReconciliationReportNotFound is returned when no report matches a query
+var ErrReconciliationReportNotFound = errors.New("reconciliation report not found")
+
+// BalanceTransactionType is the kind of money movement a provider settled
+type BalanceTransactionType string
+
+const (
+	BalanceTransactionCharge BalanceTransactionType = "charge"
+	BalanceTransactionRefund BalanceTransactionType = "refund"
+)
+
+// BalanceTransaction is a charge or refund as settled by the payment provider.
+// Amounts are positive for both kinds.
+type BalanceTransaction struct {
+	ID                string                 `json:"id"` // Provider balance transaction ID, e.g. Stripe txn_...
+	Type              BalanceTransactionType `json:"type"`
+	ProviderPaymentID string                 `json:"provider_payment_id"` // Matched against Payment.ProviderID
+	ProviderRefundID  string                 `json:"provider_refund_id,omitempty"`
+	Amount            float64                `json:"amount"`
+	Currency          string                 `json:"currency"`
+	CreatedAt         time.Time              `json:"created_at"`
+}
+
+// DiscrepancyType classifies a difference between our records and the provider's
+type DiscrepancyType string
+
+const (
+	// DiscrepancyAmountMismatch: the provider settled another amount or
+	// currency than the payment or refund records, including charges for
+	// payments that never collected
+	DiscrepancyAmountMismatch DiscrepancyType = "AMOUNT_MISMATCH"
+	// DiscrepancyMissingCharge: a payment collected funds but the provider has no charge for it
+	DiscrepancyMissingCharge DiscrepancyType = "MISSING_CHARGE"
+	// DiscrepancyUnmatchedCharge: the provider charged for a payment we do not know
+	DiscrepancyUnmatchedCharge DiscrepancyType = "UNMATCHED_CHARGE"
+	// DiscrepancyOrphanedRefund: the provider refunded money we have no refund record for
+	DiscrepancyOrphanedRefund DiscrepancyType = "ORPHANED_REFUND"
+)
+
+// Discrepancy is a single finding of a reconciliation run
+type Discrepancy struct {
+	ID                    string          `json:"id"`
+	ReportID              string          `json:"report_id"`
+	Type                  DiscrepancyType `json:"type"`
+	PaymentID             string          `json:"payment_id,omitempty"` // Empty when no payment matches
+	ProviderPaymentID     string          `json:"provider_payment_id"`
+	ProviderTransactionID string          `json:"provider_transaction_id,omitempty"` // Empty for missing charges
+	ExpectedAmount        float64         `json:"expected_amount"`                   // What our records say
+	ActualAmount          float64         `json:"actual_amount"`                     // What the provider settled
+	Currency              string          `json:"currency"`
+	Details               string          `json:"details"`
+	CreatedAt             time.Time       `json:"created_at"`
+}
+
+// ReconciliationReport is the outcome of reconciling one provider's
+// settlement data for a period against the payments created in it
+type ReconciliationReport struct {
+	ID               string        `json:"id"`
+	Provider         string        `json:"provider"`
+	PeriodStart      time.Time     `json:"period_start"`
+	PeriodEnd        time.Time     `json:"period_end"` // Exclusive
+	TransactionCount int           `json:"transaction_count"`
+	PaymentCount     int           `json:"payment_count"`
+	Discrepancies    []Discrepancy `json:"discrepancies"`
+	CreatedAt        time.Time     `json:"created_at"`
+}
+
+// IsClean reports whether the run found nothing to look into
+func (r *ReconciliationReport) IsClean() bool {
+	return len(r.Discrepancies) == 0
+}
+
+// Reconcile compares the provider's balance transactions with our payments and
+// refunds and returns a report of the differences.
+//
+// Payments are in scope when they belong to the provider and were created in
+// [from, to). Transactions may reach past to, so payments created just before
+// the end of the period are matched with charges settled shortly after it.
+// Payments created outside the period, and transactions of them, are left to
+// the run covering their own period.
+//
+// Parameters:
+//   - payments: The payments in scope, plus any payment a transaction refers to
+//   - refunds: Our refunds whose provider refund ID appears in the transactions
+//   - transactions: The provider's charges and refunds
+func Reconcile(provider string, from, to time.Time, payments []Payment, refunds []Refund, transactions []BalanceTransaction) *ReconciliationReport {
+	report := &ReconciliationReport{
+		Provider:    provider,
+		PeriodStart: from,
+		PeriodEnd:   to,
+		CreatedAt:   time.Now(),
+	}
+	inPeriod := func(t time.Time) bool { return !t.Before(from) && t.Before(to) }
+
+	paymentsByProviderID := make(map[string]*Payment, len(payments))
+	for i := range payments {
+		if payments[i].ProviderID != "" {
+			paymentsByProviderID[payments[i].ProviderID] = &payments[i]
+		}
+	}
+	refundsByProviderID := make(map[string]*Refund, len(refunds))
+	for i := range refunds {
+		refundsByProviderID[refunds[i].ProviderRefundID] = &refunds[i]
+	}
+
+	charges := make(map[string][]BalanceTransaction)
+	for _, txn := range transactions {
+		payment := paymentsByProviderID[txn.ProviderPaymentID]
+		switch txn.Type {
+		case BalanceTransactionCharge:
+			if payment != nil {
+				charges[txn.ProviderPaymentID] = append(charges[txn.ProviderPaymentID], txn)
+				continue
+			}
+			if !inPeriod(txn.CreatedAt) {
+				continue
+			}
+			report.TransactionCount++
+			report.add(Discrepancy{
+				Type:                  DiscrepancyUnmatchedCharge,
+				ProviderPaymentID:     txn.ProviderPaymentID,
+				ProviderTransactionID: txn.ID,
+				ActualAmount:          txn.Amount,
+				Currency:              strings.ToUpper(txn.Currency),
+				Details:               "provider charge does not belong to any payment",
+			})
+
+		case BalanceTransactionRefund:
+			if !inPeriod(txn.CreatedAt) {
+				continue
+			}
+			report.TransactionCount++
+			refund := refundsByProviderID[txn.ProviderRefundID]
+			if refund == nil || txn.ProviderRefundID == "" {
+				discrepancy := Discrepancy{
+					Type:                  DiscrepancyOrphanedRefund,
+					ProviderPaymentID:     txn.ProviderPaymentID,
+					ProviderTransactionID: txn.ID,
+					ActualAmount:          txn.Amount,
+					Currency:              strings.ToUpper(txn.Currency),
+					Details:               fmt.Sprintf("provider refund %s has no refund record", txn.ProviderRefundID),
+				}
+				if payment != nil {
+					discrepancy.PaymentID = payment.ID
+				}
+				report.add(discrepancy)
+				continue
+			}
+			if !sameAmount(refund.Amount, refund.Currency, txn.Amount, txn.Currency) {
+				report.add(Discrepancy{
+					Type:                  DiscrepancyAmountMismatch,
+					PaymentID:             refund.PaymentID,
+					ProviderPaymentID:     txn.ProviderPaymentID,
+					ProviderTransactionID: txn.ID,
+					ExpectedAmount:        refund.Amount,
+					ActualAmount:          txn.Amount,
+					Currency:              strings.ToUpper(txn.Currency),
+					Details:               fmt.Sprintf("refund %s recorded as %s %s", refund.ID, CurrencyOf(refund.Currency).Format(refund.Amount), refund.Currency),
+				})
+			}
+		}
+	}
+
+	for i := range payments {
+		payment := &payments[i]
+		if payment.Provider != provider || !inPeriod(payment.CreatedAt) {
+			continue
+		}
+		report.PaymentCount++
+
+		expected := collectedAmount(payment)
+		paymentCharges := charges[payment.ProviderID]
+		report.TransactionCount += len(paymentCharges)
+		if len(paymentCharges) == 0 {
+			if expected > 0 {
+				report.add(Discrepancy{
+					Type:              DiscrepancyMissingCharge,
+					PaymentID:         payment.ID,
+					ProviderPaymentID: payment.ProviderID,
+					ExpectedAmount:    expected,
+					Currency:          strings.ToUpper(payment.Currency),
+					Details:           fmt.Sprintf("payment is %s but the provider settled no charge", payment.Status),
+				})
+			}
+			continue
+		}
+
+		var charged float64
+		currency := strings.ToUpper(paymentCharges[0].Currency)
+		mixed := false
+		for _, charge := range paymentCharges {
+			charged += charge.Amount
+			mixed = mixed || !strings.EqualFold(charge.Currency, currency)
+		}
+		charged = CurrencyOf(currency).Round(charged)
+		if mixed || !sameAmount(expected, payment.Currency, charged, currency) {
+			report.add(Discrepancy{
+				Type:                  DiscrepancyAmountMismatch,
+				PaymentID:             payment.ID,
+				ProviderPaymentID:     payment.ProviderID,
+				ProviderTransactionID: paymentCharges[0].ID,
+				ExpectedAmount:        expected,
+				ActualAmount:          charged,
+				Currency:              currency,
+				Details: fmt.Sprintf("payment is %s with %s %s collected; provider settled %d charge(s)",
+					payment.Status, CurrencyOf(payment.Currency).Format(expected), payment.Currency, len(paymentCharges)),
+			})
+		}
+	}
+
+	return report
+}
+
+// add appends a discrepancy found by the run
+func (r *ReconciliationReport) add(discrepancy Discrepancy) {
+	discrepancy.CreatedAt = r.CreatedAt
+	r.Discrepancies = append(r.Discrepancies, discrepancy)
+}
+
+// collectedAmount returns what the provider should have charged for the
+// payment: its settled amount once it collected funds, nothing otherwise.
+// Refunds do not reduce it; they are settled as transactions of their own.
+func collectedAmount(p *Payment) float64 {
+	switch p.Status {
+	case PaymentStatusCompleted, PaymentStatusCaptured, PaymentStatusPartiallyRefunded, PaymentStatusRefunded:
+		return p.SettledAmount()
+	default:
+		return 0
+	}
+}
+
+// sameAmount compares two amounts to the minor unit, and their currencies
+func sameAmount(a float64, currencyA string, b float64, currencyB string) bool {
+	currency := CurrencyOf(currencyA)
+	return currency.Round(a) == currency.Round(b) && strings.EqualFold(currencyA, currencyB)
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconcile(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	inPeriod := from.Add(time.Hour)
+
+	completed := Payment{
+		ID:         "pay_1",
+		Provider:   "stripe",
+		ProviderID: "pi_1",
+		Status:     PaymentStatusCompleted,
+		Amount:     25.50,
+		Currency:   "USD",
+		CreatedAt:  inPeriod,
+	}
+	charge := BalanceTransaction{
+		ID:                "txn_1",
+		Type:              BalanceTransactionCharge,
+		ProviderPaymentID: "pi_1",
+		Amount:            25.50,
+		Currency:          "usd",
+		CreatedAt:         inPeriod,
+	}
+	refund := Refund{ID: "ref_1", PaymentID: "pay_1", ProviderRefundID: "re_1", Amount: 5, Currency: "USD"}
+	refundTxn := BalanceTransaction{
+		ID:                "txn_2",
+		Type:              BalanceTransactionRefund,
+		ProviderPaymentID: "pi_1",
+		ProviderRefundID:  "re_1",
+		Amount:            5,
+		Currency:          "usd",
+		CreatedAt:         inPeriod,
+	}
+
+	with := func(p Payment, change func(*Payment)) Payment {
+		change(&p)
+		return p
+	}
+	withTxn := func(txn BalanceTransaction, change func(*BalanceTransaction)) BalanceTransaction {
+		change(&txn)
+		return txn
+	}
+
+	tests := []struct {
+		name          string
+		payments      []Payment
+		refunds       []Refund
+		transactions  []BalanceTransaction
+		want          []DiscrepancyType
+		wantPayments  int
+		wantTxnsCount int
+	}{
+		{
+			name:          "matching charge and refund",
+			payments:      []Payment{completed},
+			refunds:       []Refund{refund},
+			transactions:  []BalanceTransaction{charge, refundTxn},
+			wantPayments:  1,
+			wantTxnsCount: 2,
+		},
+		{
+			name:          "charge settled after the period still matches",
+			payments:      []Payment{completed},
+			transactions:  []BalanceTransaction{withTxn(charge, func(c *BalanceTransaction) { c.CreatedAt = to.Add(time.Hour) })},
+			wantPayments:  1,
+			wantTxnsCount: 1,
+		},
+		{
+			name:          "charge for another amount",
+			payments:      []Payment{completed},
+			transactions:  []BalanceTransaction{withTxn(charge, func(c *BalanceTransaction) { c.Amount = 25.49 })},
+			want:          []DiscrepancyType{DiscrepancyAmountMismatch},
+			wantPayments:  1,
+			wantTxnsCount: 1,
+		},
+		{
+			name:          "charge in another currency",
+			payments:      []Payment{completed},
+			transactions:  []BalanceTransaction{withTxn(charge, func(c *BalanceTransaction) { c.Currency = "eur" })},
+			want:          []DiscrepancyType{DiscrepancyAmountMismatch},
+			wantPayments:  1,
+			wantTxnsCount: 1,
+		},
+		{
+			name:          "captured payment is expected at its captured amount",
+			payments:      []Payment{with(completed, func(p *Payment) { p.Status = PaymentStatusCaptured; p.CapturedAmount = 20; p.CapturedAt = &inPeriod })},
+			transactions:  []BalanceTransaction{withTxn(charge, func(c *BalanceTransaction) { c.Amount = 20 })},
+			wantPayments:  1,
+			wantTxnsCount: 1,
+		},
+		{
+			name:          "collected payment without a charge",
+			payments:      []Payment{completed},
+			want:          []DiscrepancyType{DiscrepancyMissingCharge},
+			wantPayments:  1,
+			wantTxnsCount: 0,
+		},
+		{
+			name:          "failed payment without a charge",
+			payments:      []Payment{with(completed, func(p *Payment) { p.Status = PaymentStatusFailed })},
+			wantPayments:  1,
+			wantTxnsCount: 0,
+		},
+		{
+			name:          "charge for a payment that never collected",
+			payments:      []Payment{with(completed, func(p *Payment) { p.Status = PaymentStatusFailed })},
+			transactions:  []BalanceTransaction{charge},
+			want:          []DiscrepancyType{DiscrepancyAmountMismatch},
+			wantPayments:  1,
+			wantTxnsCount: 1,
+		},
+		{
+			name:          "charge for an unknown payment",
+			transactions:  []BalanceTransaction{withTxn(charge, func(c *BalanceTransaction) { c.ProviderPaymentID = "pi_unknown" })},
+			want:          []DiscrepancyType{DiscrepancyUnmatchedCharge},
+			wantTxnsCount: 1,
+		},
+		{
+			name:         "unknown charge outside the period is left to its own run",
+			transactions: []BalanceTransaction{withTxn(charge, func(c *BalanceTransaction) { c.ProviderPaymentID = "pi_unknown"; c.CreatedAt = to })},
+		},
+		{
+			name:          "refund without a refund record",
+			payments:      []Payment{completed},
+			transactions:  []BalanceTransaction{charge, refundTxn},
+			want:          []DiscrepancyType{DiscrepancyOrphanedRefund},
+			wantPayments:  1,
+			wantTxnsCount: 2,
+		},
+		{
+			name:          "refund for another amount",
+			payments:      []Payment{completed},
+			refunds:       []Refund{refund},
+			transactions:  []BalanceTransaction{charge, withTxn(refundTxn, func(r *BalanceTransaction) { r.Amount = 6 })},
+			want:          []DiscrepancyType{DiscrepancyAmountMismatch},
+			wantPayments:  1,
+			wantTxnsCount: 2,
+		},
+		{
+			name: "payments of other providers and periods are out of scope",
+			payments: []Payment{
+				with(completed, func(p *Payment) { p.Provider = "paypal" }),
+				with(completed, func(p *Payment) { p.CreatedAt = from.Add(-time.Hour) }),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Reconcile("stripe", from, to, tt.payments, tt.refunds, tt.transactions)
+
+			if report.PaymentCount != tt.wantPayments {
+				t.Errorf("PaymentCount = %d, want %d", report.PaymentCount, tt.wantPayments)
+			}
+			if report.TransactionCount != tt.wantTxnsCount {
+				t.Errorf("TransactionCount = %d, want %d", report.TransactionCount, tt.wantTxnsCount)
+			}
+			if len(report.Discrepancies) != len(tt.want) {
+				t.Fatalf("got %d discrepancies %+v, want %v", len(report.Discrepancies), report.Discrepancies, tt.want)
+			}
+			for i, discrepancy := range report.Discrepancies {
+				if discrepancy.Type != tt.want[i] {
+					t.Errorf("discrepancy %d is %s, want %s", i, discrepancy.Type, tt.want[i])
+				}
+			}
+			if report.IsClean() != (len(tt.want) == 0) {
+				t.Errorf("IsClean() = %v with %d discrepancies", report.IsClean(), len(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RefundReason is the reason code reported to the payment provider
+type RefundReason string
+
+const (
+	RefundReasonDuplicate           RefundReason = "duplicate"
+	RefundReasonFraudulent          RefundReason = "fraudulent"
+	RefundReasonRequestedByCustomer RefundReason = "requested_by_customer"
+)
+
+var (
+	// ErrCurrencyMismatch is returned when an amount is given in another currency than the payment
+	ErrCurrencyMismatch = errors.New("currency does not match payment")
+	ErrRefundNotFound   = errors.New("refund not found")
+)
+
+// Refund is a single refund issued against a payment
+type Refund struct {
+	ID               string       `json:"id"`
+	PaymentID        string       `json:"payment_id"`
+	ProviderRefundID string       `json:"provider_refund_id"` // External refund ID (e.g., Stripe re_...)
+	Amount           float64      `json:"amount"`
+	Currency         string       `json:"currency"`
+	Reason           RefundReason `json:"reason"`
+	Note             string       `json:"note,omitempty"` // Free-text reason given by the caller
+	IdempotencyKey   string       `json:"idempotency_key"`
+	CreatedAt        time.Time    `json:"created_at"`
+}
+
+// ParseRefundReason maps a caller supplied reason to a reason code. Text that
+// is not a known code is kept as a note and refunded as requested by customer.
+func ParseRefundReason(reason string) (RefundReason, string) {
+	switch code := RefundReason(strings.ToLower(strings.TrimSpace(reason))); code {
+	case RefundReasonDuplicate, RefundReasonFraudulent, RefundReasonRequestedByCustomer:
+		return code, ""
+	default:
+		return RefundReasonRequestedByCustomer, strings.TrimSpace(reason)
+	}
+}
+
+// NewRefund validates a refund of the given amount against the payment.
+// An amount of zero refunds everything not refunded yet.
+//
+// A refund the caller keys itself takes its idempotency key from the caller's
+// key, see RequestedRefundKey. Otherwise the key is derived from the payment,
+// the amount refunded so far and the amount of this refund, so retrying a
+// refund whose outcome was lost reuses the key and the provider does not
+// refund twice.
+func NewRefund(payment *Payment, amount float64, reason, requestKey string) (*Refund, error) {
+	if !payment.CanRefund() {
+		return nil, ErrRefundNotAllowed
+	}
+	if amount == 0 {
+		amount = payment.RefundableAmount()
+	}
+	amount = payment.round(amount)
+	if amount <= 0 || amount > payment.RefundableAmount() {
+		return nil, ErrInvalidAmount
+	}
+
+	idempotencyKey := RequestedRefundKey(payment.ID, requestKey)
+	if requestKey == "" {
+		idempotencyKey = fmt.Sprintf("refund-%s-%d-%d", payment.ID,
+			ToMinorUnits(payment.RefundedAmount, payment.Currency), ToMinorUnits(amount, payment.Currency))
+	}
+
+	code, note := ParseRefundReason(reason)
+	return &Refund{
+		PaymentID:      payment.ID,
+		Amount:         amount,
+		Currency:       payment.Currency,
+		Reason:         code,
+		Note:           note,
+		IdempotencyKey: idempotencyKey,
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+// RequestedRefundKey returns the idempotency key of a refund of the payment
+// keyed by the caller. It stays the same however much was refunded since, so
+// a caller retrying the request gets the first refund back.
+func RequestedRefundKey(paymentID, requestKey string) string {
+	return fmt.Sprintf("refund-%s-request-%s", paymentID, requestKey)
+}
@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RiskDecision is the outcome of a fraud risk assessment of a payment
+type RiskDecision string
+
+const (
+	// RiskDecisionAllow: the payment is charged
+	RiskDecisionAllow RiskDecision = "ALLOW"
+	// RiskDecisionReview: the payment is charged and flagged for manual review
+	RiskDecisionReview RiskDecision = "REVIEW"
+	// RiskDecisionDeny: the payment fails without reaching the provider
+	RiskDecisionDeny RiskDecision = "DENY"
+)
+
+// Points each risk rule adds to the score when it matches. The scores
+// deciding on a payment are configured relative to them.
+const (
+	riskVelocityPoints   = 40
+	riskAmountPoints     = 30
+	riskCountryPoints    = 30
+	riskNewAccountPoints = 20
+)
+
+// ErrPaymentRiskDenied is returned for payments the risk assessment denied
+var ErrPaymentRiskDenied = errors.New("payment declined by risk assessment")
+
+// RiskAssessment is the fraud risk assessment of a payment attempt: a score
+// and the reasons that made it up
+type RiskAssessment struct {
+	Score      int          `json:"score"` // 0 for no risk; the rules' points add up
+	Decision   RiskDecision `json:"decision"`
+	Reasons    []string     `json:"reasons"`
+	AssessedAt time.Time    `json:"assessed_at"`
+}
+
+// RiskSignals are the facts about a payment the risk rules look at. Signals
+// that could not be determined are left zero and their rules do not apply.
+type RiskSignals struct {
+	UserPayments     int           // Payment attempts of the user started within the velocity window, this one and retries included
+	CardPayments     int           // Payment attempts with the same saved card started within the velocity window, this one and retries included
+	Amount           float64       // Amount compared with the threshold
+	AmountCurrency   string        // Currency of Amount
+	CardCountry      string        // ISO country of the card issuer or, failing that, the billing address
+	ShippingCountry  string        // ISO country of the order's shipping address
+	AccountCreatedAt *time.Time    // When the user signed up
+	VelocityWindow   time.Duration // Window UserPayments and CardPayments were counted in
+}
+
+// RiskRules are the limits of the risk rules and the scores deciding on a
+// payment. Each rule that matches adds its points; a rule with a zero limit
+// is off, and so is a decision with a zero score.
+type RiskRules struct {
+	ReviewScore int // Payments scoring at least this are reviewed
+	DenyScore   int // Payments scoring at least this are denied
+
+	MaxUserPayments  int                // Payment attempts a user may start within the velocity window
+	MaxCardPayments  int                // Payment attempts a saved card may start within the velocity window
+	AmountThresholds map[string]float64 // Amount above which a payment is large, by currency
+	NewAccountAge    time.Duration      // Accounts younger than this are new
+}
+
+// ParseAmountThresholds parses amount thresholds written as "CUR=amount"
+// entries separated by semicolons, e.g. "USD=1000;JPY=150000"
+func ParseAmountThresholds(spec string) (map[string]float64, error) {
+	thresholds := make(map[string]float64)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		code, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid amount threshold %q: want CURRENCY=amount", entry)
+		}
+		currency, err := LookupCurrency(strings.TrimSpace(code))
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount threshold %q: %w", entry, err)
+		}
+		amount, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || amount <= 0 {
+			return nil, fmt.Errorf("invalid amount threshold %q: amount must be a positive number", entry)
+		}
+		thresholds[currency.Code] = amount
+	}
+	return thresholds, nil
+}
+
+// Assess scores the signals of a payment and decides on it
+func (r RiskRules) Assess(signals RiskSignals, at time.Time) *RiskAssessment {
+	assessment := &RiskAssessment{Reasons: []string{}, AssessedAt: at}
+	add := func(points int, reason string) {
+		assessment.Score += points
+		assessment.Reasons = append(assessment.Reasons, reason)
+	}
+
+	if r.MaxUserPayments > 0 && signals.UserPayments > r.MaxUserPayments {
+		add(riskVelocityPoints, fmt.Sprintf("user made %d payment attempts in %s", signals.UserPayments, signals.VelocityWindow))
+	}
+	if r.MaxCardPayments > 0 && signals.CardPayments > r.MaxCardPayments {
+		add(riskVelocityPoints, fmt.Sprintf("card was used for %d payment attempts in %s", signals.CardPayments, signals.VelocityWindow))
+	}
+	if threshold, ok := r.AmountThresholds[strings.ToUpper(signals.AmountCurrency)]; ok && threshold > 0 && signals.Amount > threshold {
+		currency := CurrencyOf(signals.AmountCurrency)
+		add(riskAmountPoints, fmt.Sprintf("amount %s %s is above %s", currency.Format(signals.Amount), currency.Code, currency.Format(threshold)))
+	}
+	if signals.CardCountry != "" && signals.ShippingCountry != "" && !strings.EqualFold(signals.CardCountry, signals.ShippingCountry) {
+		add(riskCountryPoints, fmt.Sprintf("card country %s differs from shipping country %s",
+			strings.ToUpper(signals.CardCountry), strings.ToUpper(signals.ShippingCountry)))
+	}
+	if r.NewAccountAge > 0 && signals.AccountCreatedAt != nil && at.Sub(*signals.AccountCreatedAt) < r.NewAccountAge {
+		add(riskNewAccountPoints, fmt.Sprintf("account was created %s ago", at.Sub(*signals.AccountCreatedAt).Round(time.Minute)))
+	}
+
+	switch {
+	case r.DenyScore > 0 && assessment.Score >= r.DenyScore:
+		assessment.Decision = RiskDecisionDeny
+	case r.ReviewScore > 0 && assessment.Score >= r.ReviewScore:
+		assessment.Decision = RiskDecisionReview
+	default:
+		assessment.Decision = RiskDecisionAllow
+	}
+	return assessment
+}
+
+// SetRiskAssessment records the risk assessment of the current attempt. A
+// denied payment fails without reaching the provider.
+//
+// Returns:
+//   - error: ErrPaymentRiskDenied if the assessment denied the payment
+func (p *Payment) SetRiskAssessment(assessment *RiskAssessment) error {
+	assessedAt := assessment.AssessedAt
+	p.RiskScore = assessment.Score
+	p.RiskDecision = assessment.Decision
+	p.RiskReasons = assessment.Reasons
+	p.RiskAssessedAt = &assessedAt
+	p.UpdatedAt = time.Now()
+
+	if assessment.Decision == RiskDecisionDeny {
+		p.MarkAsFailed(ErrPaymentRiskDenied.Error())
+		return ErrPaymentRiskDenied
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRiskRulesAssess(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	newAccount := at.Add(-2 * time.Hour)
+	oldAccount := at.Add(-30 * 24 * time.Hour)
+
+	rules := RiskRules{
+		ReviewScore:      30,
+		DenyScore:        70,
+		MaxUserPayments:  3,
+		MaxCardPayments:  2,
+		AmountThresholds: map[string]float64{"USD": 1000, "JPY": 150000},
+		NewAccountAge:    24 * time.Hour,
+	}
+
+	tests := []struct {
+		name         string
+		rules        RiskRules
+		signals      RiskSignals
+		wantScore    int
+		wantDecision RiskDecision
+		wantReasons  []string
+	}{
+		{
+			name:         "no signals",
+			rules:        rules,
+			wantDecision: RiskDecisionAllow,
+			wantReasons:  []string{},
+		},
+		{
+			name:  "within every limit",
+			rules: rules,
+			signals: RiskSignals{
+				UserPayments:     3,
+				CardPayments:     2,
+				Amount:           1000,
+				AmountCurrency:   "USD",
+				CardCountry:      "us",
+				ShippingCountry:  "US",
+				AccountCreatedAt: &oldAccount,
+				VelocityWindow:   time.Hour,
+			},
+			wantDecision: RiskDecisionAllow,
+			wantReasons:  []string{},
+		},
+		{
+			name:         "user velocity is reviewed",
+			rules:        rules,
+			signals:      RiskSignals{UserPayments: 4, VelocityWindow: time.Hour},
+			wantScore:    riskVelocityPoints,
+			wantDecision: RiskDecisionReview,
+			wantReasons:  []string{"user made 4 payment attempts in 1h0m0s"},
+		},
+		{
+			name:         "large amount in a currency without minor units",
+			rules:        rules,
+			signals:      RiskSignals{Amount: 150001, AmountCurrency: "jpy"},
+			wantScore:    riskAmountPoints,
+			wantDecision: RiskDecisionReview,
+			wantReasons:  []string{"amount 150001 JPY is above 150000"},
+		},
+		{
+			name:         "amount in a currency without a threshold",
+			rules:        rules,
+			signals:      RiskSignals{Amount: 5000, AmountCurrency: "EUR"},
+			wantDecision: RiskDecisionAllow,
+			wantReasons:  []string{},
+		},
+		{
+			name:         "new account alone is allowed",
+			rules:        rules,
+			signals:      RiskSignals{AccountCreatedAt: &newAccount},
+			wantScore:    riskNewAccountPoints,
+			wantDecision: RiskDecisionAllow,
+			wantReasons:  []string{"account was created 2h0m0s ago"},
+		},
+		{
+			name:  "velocity on user and card with a foreign card is denied",
+			rules: rules,
+			signals: RiskSignals{
+				UserPayments:    5,
+				CardPayments:    3,
+				CardCountry:     "de",
+				ShippingCountry: "us",
+				VelocityWindow:  time.Hour,
+			},
+			wantScore:    2*riskVelocityPoints + riskCountryPoints,
+			wantDecision: RiskDecisionDeny,
+			wantReasons: []string{
+				"user made 5 payment attempts in 1h0m0s",
+				"card was used for 3 payment attempts in 1h0m0s",
+				"card country DE differs from shipping country US",
+			},
+		},
+		{
+			name:         "unknown shipping country skips the country rule",
+			rules:        rules,
+			signals:      RiskSignals{CardCountry: "DE"},
+			wantDecision: RiskDecisionAllow,
+			wantReasons:  []string{},
+		},
+		{
+			name:  "zero limits and scores turn rules and decisions off",
+			rules: RiskRules{},
+			signals: RiskSignals{
+				UserPayments:     50,
+				CardPayments:     50,
+				Amount:           1e6,
+				AmountCurrency:   "USD",
+				CardCountry:      "DE",
+				ShippingCountry:  "US",
+				AccountCreatedAt: &newAccount,
+			},
+			wantScore:    riskCountryPoints,
+			wantDecision: RiskDecisionAllow,
+			wantReasons:  []string{"card country DE differs from shipping country US"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assessment := tt.rules.Assess(tt.signals, at)
+
+			if assessment.Score != tt.wantScore {
+				t.Errorf("Score = %d, want %d", assessment.Score, tt.wantScore)
+			}
+			if assessment.Decision != tt.wantDecision {
+				t.Errorf("Decision = %s, want %s", assessment.Decision, tt.wantDecision)
+			}
+			if !reflect.DeepEqual(assessment.Reasons, tt.wantReasons) {
+				t.Errorf("Reasons = %q, want %q", assessment.Reasons, tt.wantReasons)
+			}
+			if !assessment.AssessedAt.Equal(at) {
+				t.Errorf("AssessedAt = %v, want %v", assessment.AssessedAt, at)
+			}
+		})
+	}
+}
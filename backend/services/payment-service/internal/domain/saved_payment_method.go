@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrPaymentMethodNotFound   = errors.New("payment method not found")
+	ErrPaymentMethodExpired    = errors.New("payment method expired")
+	ErrInvalidPaymentToken     = errors.New("invalid payment method token")
+	ErrRawCardNumberNotAllowed = errors.New("raw card numbers must not be sent; tokenize the card with the payment provider")
+)
+
+// CardDetails are the displayable details of a tokenized card, as reported by
+// the payment provider. They never include the full card number.
+type CardDetails struct {
+	Provider         string // Name of the provider storing the card, e.g. stripe
+	ProviderMethodID string // Provider token for the card, e.g. Stripe pm_...
+	Brand            string // visa, mastercard, ...
+	Last4            string
+	ExpMonth         int
+	ExpYear          int
+	Country          string // ISO country of the card issuer, if the provider reports it
+}
+
+// SavedPaymentMethod is a card a user stored for later payments. Only the
+// provider's token and display details are kept; the card number stays with
+// the provider.
+type SavedPaymentMethod struct {
+	ID                 string        `json:"id"`
+	UserID             string        `json:"user_id"`
+	Type               PaymentMethod `json:"type"`
+	Provider           string        `json:"provider"`             // Provider storing the card; payments with the method go to it
+	ProviderCustomerID string        `json:"provider_customer_id"` // e.g. Stripe customer ID the card is attached to
+	ProviderMethodID   string        `json:"provider_method_id"`   // e.g. Stripe payment method ID
+	Brand              string        `json:"brand"`
+	Last4              string        `json:"last4"`
+	ExpMonth           int           `json:"exp_month"`
+	ExpYear            int           `json:"exp_year"`
+	Country            string        `json:"country,omitempty"` // ISO country of the card issuer
+	IsDefault          bool          `json:"is_default"`
+	CreatedAt          time.Time     `json:"created_at"`
+	UpdatedAt          time.Time     `json:"updated_at"`
+}
+
+// NewSavedPaymentMethod creates a saved card from the details the provider
+// reported when the token was attached to the customer.
+//
+// Returns:
+//   - error: ErrInvalidPaymentMethod if the type is not a card,
+//     ErrInvalidPaymentToken if the details are incomplete,
+//     ErrPaymentMethodExpired if the card has already expired
+func NewSavedPaymentMethod(userID string, methodType PaymentMethod, customerID string, card CardDetails, isDefault bool) (*SavedPaymentMethod, error) {
+	if methodType != PaymentMethodCreditCard && methodType != PaymentMethodDebitCard {
+		return nil, ErrInvalidPaymentMethod
+	}
+	if card.ProviderMethodID == "" || customerID == "" || !isDigits(card.Last4, 4) {
+		return nil, ErrInvalidPaymentToken
+	}
+	if card.ExpMonth < 1 || card.ExpMonth > 12 || card.ExpYear < 2000 {
+		return nil, ErrInvalidPaymentToken
+	}
+
+	now := time.Now()
+	method := &SavedPaymentMethod{
+		UserID:             userID,
+		Type:               methodType,
+		Provider:           card.Provider,
+		ProviderCustomerID: customerID,
+		ProviderMethodID:   card.ProviderMethodID,
+		Brand:              card.Brand,
+		Last4:              card.Last4,
+		ExpMonth:           card.ExpMonth,
+		ExpYear:            card.ExpYear,
+		Country:            strings.ToUpper(card.Country),
+		IsDefault:          isDefault,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if method.IsExpired(now) {
+		return nil, ErrPaymentMethodExpired
+	}
+	return method, nil
+}
+
+// IsExpired reports whether the card has expired at the given time. Cards are
+// valid through the last day of their expiry month.
+func (m *SavedPaymentMethod) IsExpired(at time.Time) bool {
+	validUntil := time.Date(m.ExpYear, time.Month(m.ExpMonth)+1, 1, 0, 0, 0, 0, time.UTC)
+	return !at.Before(validUntil)
+}
+
+// ValidatePaymentToken rejects tokens that are empty or look like a raw card
+// number, so a PAN can never reach the provider call or the database
+func ValidatePaymentToken(token string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ErrInvalidPaymentToken
+	}
+	if LooksLikeCardNumber(token) {
+		return ErrRawCardNumberNotAllowed
+	}
+	return nil
+}
+
+// LooksLikeCardNumber reports whether s, ignoring spaces and dashes, is a
+// 12 to 19 digit number that passes the Luhn check
+func LooksLikeCardNumber(s string) bool {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(s)
+	if len(digits) < 12 || len(digits) > 19 || !isDigits(digits, len(digits)) {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// isDigits reports whether s is exactly n ASCII digits
+func isDigits(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "testing"
+
+func TestLooksLikeCardNumber(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "visa test card", input: "4242424242424242", want: true},
+		{name: "spaced", input: "4242 4242 4242 4242", want: true},
+		{name: "dashed", input: "4242-4242-4242-4242", want: true},
+		{name: "amex length", input: "378282246310005", want: true},
+		{name: "12 digits", input: "424242424242", want: true},
+		{name: "19 digits", input: "6011000990139424009", want: true},
+		{name: "failed checksum", input: "4242424242424241", want: false},
+		{name: "too short", input: "42424242424", want: false},
+		{name: "too long", input: "42424242424242424242", want: false},
+		{name: "letters", input: "4242424242424a42", want: false},
+		{name: "provider token", input: "pm_card_visa", want: false},
+		{name: "empty", input: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LooksLikeCardNumber(tt.input); got != tt.want {
+				t.Errorf("LooksLikeCardNumber(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
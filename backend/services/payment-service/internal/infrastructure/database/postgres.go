@@ -33,7 +33,39 @@ func ConnectPostgres(dsn string) (*gorm.DB, error) {
 
 // RunMigrations runs database migrations
 func RunMigrations(db *gorm.DB) error {
+	if err := dropOrderUniqueIndex(db); err != nil {
+		return err
+	}
 	return db.AutoMigrate(
 		&models.Payment{},
+		&models.PaymentAttempt{},
+		&models.Refund{},
+		&models.ProviderEvent{},
+		&models.SavedPaymentMethod{},
+		&models.OutboxEvent{},
+		&models.ReconciliationReport{},
+		&models.ReconciliationDiscrepancy{},
+		&models.Dispute{},
 	)
 }
+
+// dropOrderUniqueIndex drops the unique index that once allowed only one
+// payment per order, failed or not. It shares its name with the plain order_id
+// index that replaces it, so AutoMigrate would keep it; only one active payment
+// per order is now enforced, by idx_payments_active_order. Safe to rerun.
+func dropOrderUniqueIndex(db *gorm.DB) error {
+	var unique bool
+	if err := db.Raw(
+		`SELECT EXISTS (SELECT 1 FROM pg_indexes
+		 WHERE schemaname = current_schema() AND indexname = 'idx_payments_order_id' AND indexdef LIKE 'CREATE UNIQUE INDEX%')`,
+	).Scan(&unique).Error; err != nil {
+		return fmt.Errorf("failed to inspect payments order index: %w", err)
+	}
+	if !unique {
+		return nil
+	}
+	if err := db.Exec(`DROP INDEX idx_payments_order_id`).Error; err != nil {
+		return fmt.Errorf("failed to drop payments order unique index: %w", err)
+	}
+	return nil
+}
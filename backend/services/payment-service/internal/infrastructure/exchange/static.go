@@ -0,0 +1,84 @@
+// Package exchange provides the exchange rates that convert payments into the
+// merchant's settlement currency.
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// StaticRates serves fixed exchange rates into the settlement currency, as
+// configured. Payments record them with source "static" and the time the
+// rates were loaded.
+type StaticRates struct {
+	settlement string
+	rates      map[string]float64 // Settlement currency units per unit of each currency
+	loadedAt   time.Time
+}
+
+// NewStaticRates parses rates written as "CUR=rate" entries separated by
+// semicolons, e.g. "EUR=1.08;JPY=0.0067". Each rate is the price of one unit
+// of the currency in the settlement currency.
+func NewStaticRates(settlementCurrency, spec string) (*StaticRates, error) {
+	settlement, err := domain.LookupCurrency(settlementCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid settlement currency %q: %w", settlementCurrency, err)
+	}
+
+	rates := make(map[string]float64)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		code, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid exchange rate %q: want CURRENCY=rate", entry)
+		}
+		currency, err := domain.LookupCurrency(strings.TrimSpace(code))
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange rate %q: %w", entry, err)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate %q: rate must be a positive number", entry)
+		}
+		rates[currency.Code] = rate
+	}
+
+	return &StaticRates{
+		settlement: settlement.Code,
+		rates:      rates,
+		loadedAt:   time.Now(),
+	}, nil
+}
+
+// SettlementRate returns the rate converting a currency into the settlement
+// currency; the settlement currency itself converts at 1
+//
+// Returns:
+//   - error: ErrExchangeRateUnavailable if no rate is configured for the currency
+func (s *StaticRates) SettlementRate(ctx context.Context, currency string) (*domain.ExchangeRate, error) {
+	currency = strings.ToUpper(currency)
+	rate := 1.0
+	if currency != s.settlement {
+		var ok bool
+		if rate, ok = s.rates[currency]; !ok {
+			return nil, fmt.Errorf("%w: %s to %s", domain.ErrExchangeRateUnavailable, currency, s.settlement)
+		}
+	}
+
+	return &domain.ExchangeRate{
+		From:   currency,
+		To:     s.settlement,
+		Rate:   rate,
+		Source: "static",
+		AsOf:   s.loadedAt,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/cqchien/ecomerce-rec/backend/proto"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// OrderClient calls the order service
+type OrderClient struct {
+	conn   *grpc.ClientConn
+	client pb.OrderServiceClient
+}
+
+// NewOrderClient creates an order service client. The connection is made
+// lazily, so the payment service starts while the order service is down.
+func NewOrderClient(addr string) (*OrderClient, error) {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to order service: %w", err)
+	}
+	return &OrderClient{
+		conn:   conn,
+		client: pb.NewOrderServiceClient(conn),
+	}, nil
+}
+
+// FlagOrderDisputed asks the order service to flag the order of a disputed
+// payment. The order service ignores repeated calls for the same dispute.
+func (c *OrderClient) FlagOrderDisputed(ctx context.Context, dispute *domain.Dispute) error {
+	if _, err := c.client.FlagOrderDisputed(ctx, &pb.FlagOrderDisputedRequest{
+		OrderId:   dispute.OrderID,
+		PaymentId: dispute.PaymentID,
+		DisputeId: dispute.ID,
+		Reason:    dispute.Reason,
+	}); err != nil {
+		return fmt.Errorf("failed to flag order %s: %w", dispute.OrderID, err)
+	}
+	return nil
+}
+
+// GetOrderCountries returns the countries of an order's shipping and billing
+// addresses; a country is "" if the order has no such address
+func (c *OrderClient) GetOrderCountries(ctx context.Context, orderID string) (string, string, error) {
+	resp, err := c.client.GetOrder(ctx, &pb.GetOrderRequest{Id: orderID})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get order %s: %w", orderID, err)
+	}
+	order := resp.GetOrder()
+	return order.GetShippingAddress().GetCountry(), order.GetBillingAddress().GetCountry(), nil
+}
+
+// Close closes the connection
+func (c *OrderClient) Close() error {
+	return c.conn.Close()
+}
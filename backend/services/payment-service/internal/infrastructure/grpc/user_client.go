@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/cqchien/ecomerce-rec/backend/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// UserClient calls the user service
+type UserClient struct {
+	conn   *grpc.ClientConn
+	client pb.UserServiceClient
+}
+
+// NewUserClient creates a user service client. The connection is made
+// lazily, so the payment service starts while the user service is down.
+func NewUserClient(addr string) (*UserClient, error) {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to user service: %w", err)
+	}
+	return &UserClient{
+		conn:   conn,
+		client: pb.NewUserServiceClient(conn),
+	}, nil
+}
+
+// GetAccountCreatedAt returns when a user signed up, or nil if the user
+// service does not report it
+func (c *UserClient) GetAccountCreatedAt(ctx context.Context, userID string) (*time.Time, error) {
+	resp, err := c.client.GetProfile(ctx, &pb.GetProfileRequest{UserId: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", userID, err)
+	}
+	createdAt := resp.GetProfile().GetCreatedAt()
+	if createdAt == nil || createdAt.Seconds == 0 {
+		return nil, nil
+	}
+	at := time.Unix(createdAt.Seconds, int64(createdAt.Nanos))
+	return &at, nil
+}
+
+// Close closes the connection
+func (c *UserClient) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// Dispute represents the GORM model for disputes opened against payments
+type Dispute struct {
+	ID                  string     `gorm:"type:uuid;primary_key;default:uuid_generate_v7()"`
+	PaymentID           string     `gorm:"type:uuid;not null;index"`
+	OrderID             string     `gorm:"type:uuid;not null;index"`
+	Provider            string     `gorm:"type:varchar(50)"`
+	ProviderDisputeID   string     `gorm:"type:varchar(255);not null;uniqueIndex"`
+	Status              string     `gorm:"type:varchar(20);not null;index"`
+	ProviderStatus      string     `gorm:"type:varchar(50)"`
+	Reason              string     `gorm:"type:varchar(100)"`
+	Amount              float64    `gorm:"type:decimal(12,3);not null"`
+	Currency            string     `gorm:"type:varchar(3);not null"`
+	EvidenceDueBy       *time.Time `gorm:"index"`
+	Evidence            string     `gorm:"type:jsonb;not null;default:'{}'"` // JSON of domain.DisputeEvidence
+	EvidenceSubmittedAt *time.Time
+	OrderFlaggedAt      *time.Time
+	OpenedAt            time.Time `gorm:"not null"`
+	ClosedAt            *time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// TableName specifies the table name for Dispute
+func (Dispute) TableName() string {
+	return "payment_disputes"
+}
+
+// ToDomain converts GORM Dispute to domain Dispute
+func (d *Dispute) ToDomain() *domain.Dispute {
+	dispute := &domain.Dispute{
+		ID:                  d.ID,
+		PaymentID:           d.PaymentID,
+		OrderID:             d.OrderID,
+		Provider:            d.Provider,
+		ProviderDisputeID:   d.ProviderDisputeID,
+		Status:              domain.DisputeStatus(d.Status),
+		ProviderStatus:      d.ProviderStatus,
+		Reason:              d.Reason,
+		Amount:              d.Amount,
+		Currency:            d.Currency,
+		EvidenceDueBy:       d.EvidenceDueBy,
+		EvidenceSubmittedAt: d.EvidenceSubmittedAt,
+		OrderFlaggedAt:      d.OrderFlaggedAt,
+		OpenedAt:            d.OpenedAt,
+		ClosedAt:            d.ClosedAt,
+		CreatedAt:           d.CreatedAt,
+		UpdatedAt:           d.UpdatedAt,
+	}
+	_ = json.Unmarshal([]byte(d.Evidence), &dispute.Evidence)
+	return dispute
+}
+
+// FromDomain converts domain Dispute to GORM Dispute
+func (d *Dispute) FromDomain(dispute *domain.Dispute) error {
+	evidence, err := json.Marshal(dispute.Evidence)
+	if err != nil {
+		return err
+	}
+
+	d.ID = dispute.ID
+	d.PaymentID = dispute.PaymentID
+	d.OrderID = dispute.OrderID
+	d.Provider = dispute.Provider
+	d.ProviderDisputeID = dispute.ProviderDisputeID
+	d.Status = string(dispute.Status)
+	d.ProviderStatus = dispute.ProviderStatus
+	d.Reason = dispute.Reason
+	d.Amount = dispute.Amount
+	d.Currency = dispute.Currency
+	d.EvidenceDueBy = dispute.EvidenceDueBy
+	d.Evidence = string(evidence)
+	d.EvidenceSubmittedAt = dispute.EvidenceSubmittedAt
+	d.OrderFlaggedAt = dispute.OrderFlaggedAt
+	d.OpenedAt = dispute.OpenedAt
+	d.ClosedAt = dispute.ClosedAt
+	d.CreatedAt = dispute.CreatedAt
+	d.UpdatedAt = dispute.UpdatedAt
+	return nil
+}
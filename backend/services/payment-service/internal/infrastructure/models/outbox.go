@@ -0,0 +1,103 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// OutboxEvent represents the database model for the payment event outbox
+type OutboxEvent struct {
+	ID            string `gorm:"type:uuid;primaryKey;default:uuid_generate_v7()"`
+	AggregateID   string `gorm:"type:uuid;not null;index"`
+	EventType     string `gorm:"type:varchar(50);not null"`
+	Payload       string `gorm:"type:jsonb;not null"`
+	Attempts      int    `gorm:"not null;default:0"`
+	LastError     string `gorm:"type:text"`
+	CreatedAt     time.Time
+	PublishedAt   *time.Time `gorm:"index"`
+	NextAttemptAt *time.Time
+	FailedAt      *time.Time `gorm:"index"`
+}
+
+// TableName specifies the table name for OutboxEvent
+func (OutboxEvent) TableName() string {
+	return "payment_outbox"
+}
+
+// ToDomain converts database OutboxEvent model to domain OutboxEvent
+func (e *OutboxEvent) ToDomain() *domain.OutboxEvent {
+	return &domain.OutboxEvent{
+		ID:            e.ID,
+		Type:          domain.EventType(e.EventType),
+		AggregateID:   e.AggregateID,
+		Payload:       e.Payload,
+		Attempts:      e.Attempts,
+		LastError:     e.LastError,
+		CreatedAt:     e.CreatedAt,
+		PublishedAt:   e.PublishedAt,
+		NextAttemptAt: e.NextAttemptAt,
+		FailedAt:      e.FailedAt,
+	}
+}
+
+// PaymentEventPayload is the payment snapshot carried by payment events
+type PaymentEventPayload struct {
+	PaymentID      string    `json:"payment_id"`
+	OrderID        string    `json:"order_id"`
+	UserID         string    `json:"user_id"`
+	Status         string    `json:"status"`
+	Method         string    `json:"method"`
+	Provider       string    `json:"provider,omitempty"`
+	Currency       string    `json:"currency"`
+	Amount         int64     `json:"amount_cents"` // Amounts are in minor units of the currency, e.g. yen for JPY
+	CapturedAmount int64     `json:"captured_amount_cents"`
+	RefundedAmount int64     `json:"refunded_amount_cents"`
+	Reason         string    `json:"reason,omitempty"`
+	OccurredAt     time.Time `json:"occurred_at"`
+
+	// Exchange rate snapshot, if the payment has one
+	SettlementCurrency string  `json:"settlement_currency,omitempty"`
+	SettlementAmount   int64   `json:"settlement_amount_cents,omitempty"`
+	ExchangeRate       float64 `json:"exchange_rate,omitempty"`
+
+	// Risk assessment of the latest attempt, if there was one
+	RiskScore    int    `json:"risk_score,omitempty"`
+	RiskDecision string `json:"risk_decision,omitempty"`
+}
+
+// NewOutboxEvent builds an outbox row holding a snapshot of the payment
+func NewOutboxEvent(eventType domain.EventType, payment *domain.Payment) (*OutboxEvent, error) {
+	payload, err := json.Marshal(PaymentEventPayload{
+		PaymentID:      payment.ID,
+		OrderID:        payment.OrderID,
+		UserID:         payment.UserID,
+		Status:         string(payment.Status),
+		Method:         string(payment.Method),
+		Provider:       payment.Provider,
+		Currency:       payment.Currency,
+		Amount:         domain.ToMinorUnits(payment.Amount, payment.Currency),
+		CapturedAmount: domain.ToMinorUnits(payment.CapturedAmount, payment.Currency),
+		RefundedAmount: domain.ToMinorUnits(payment.RefundedAmount, payment.Currency),
+		Reason:         payment.FailureReason,
+		OccurredAt:     payment.UpdatedAt,
+
+		SettlementCurrency: payment.SettlementCurrency,
+		SettlementAmount:   domain.ToMinorUnits(payment.SettlementAmount(), payment.SettlementCurrency),
+		ExchangeRate:       payment.ExchangeRate,
+
+		RiskScore:    payment.RiskScore,
+		RiskDecision: string(payment.RiskDecision),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxEvent{
+		AggregateID: payment.ID,
+		EventType:   string(eventType),
+		Payload:     string(payload),
+		CreatedAt:   time.Now(),
+	}, nil
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
@@ -9,19 +10,40 @@ import (
 
 // Payment represents the GORM model for payments
 type Payment struct {
-	ID               string  `gorm:"type:uuid;primary_key;default:uuid_generate_v7()"`
-	OrderID          string  `gorm:"type:uuid;not null;uniqueIndex"`
-	UserID           string  `gorm:"type:uuid;not null;index"`
-	Amount           float64 `gorm:"type:decimal(10,2);not null"`
-	Currency         string  `gorm:"type:varchar(3);not null"`
-	Status           string  `gorm:"type:varchar(50);not null;index"`
-	Method           string  `gorm:"type:varchar(50);not null"`
-	ProviderID       string  `gorm:"type:varchar(255);index"`
-	ProviderResponse string  `gorm:"type:text"`
-	FailureReason    string  `gorm:"type:text"`
-	CreatedAt        time.Time
-	UpdatedAt        time.Time
-	DeletedAt        gorm.DeletedAt `gorm:"index"`
+	ID                     string  `gorm:"type:uuid;primary_key;default:uuid_generate_v7()"`
+	OrderID                string  `gorm:"type:uuid;not null;index;uniqueIndex:idx_payments_active_order,where:status <> 'FAILED' AND status <> 'CANCELLED' AND deleted_at IS NULL"`
+	UserID                 string  `gorm:"type:uuid;not null;index"`
+	Amount                 float64 `gorm:"type:decimal(12,3);not null"`
+	RefundedAmount         float64 `gorm:"type:decimal(12,3);not null;default:0"`
+	Currency               string  `gorm:"type:varchar(3);not null"`
+	Status                 string  `gorm:"type:varchar(50);not null;index"`
+	Method                 string  `gorm:"type:varchar(50);not null"`
+	Provider               string  `gorm:"type:varchar(50)"`
+	ProviderID             string  `gorm:"type:varchar(255);index"`
+	ProviderResponse       string  `gorm:"type:text"`
+	FailureReason          string  `gorm:"type:text"`
+	Attempts               int     `gorm:"not null;default:0"`
+	DisputedAt             *time.Time
+	CollectedAfterCancelAt *time.Time
+	SettlementCurrency     string  `gorm:"type:varchar(3)"`
+	ExchangeRate           float64 `gorm:"type:decimal(20,10);not null;default:0"`
+	ExchangeRateSource     string  `gorm:"type:varchar(50)"`
+	ExchangeRateAt         *time.Time
+	RiskScore              int    `gorm:"not null;default:0"`
+	RiskDecision           string `gorm:"type:varchar(10);index"`
+	RiskReasons            string `gorm:"type:jsonb;not null;default:'[]'"` // JSON array of reasons
+	RiskAssessedAt         *time.Time
+	PaymentMethodID        *string `gorm:"type:uuid;index"`
+	ProviderCustomerID     string  `gorm:"type:varchar(255)"`
+	ProviderMethodID       string  `gorm:"type:varchar(255)"`
+	AuthorizedAt           *time.Time
+	AuthorizationExpiresAt *time.Time `gorm:"index"`
+	CapturedAmount         float64    `gorm:"type:decimal(12,3);not null;default:0"`
+	CapturedAt             *time.Time
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+	Version                int64          `gorm:"not null;default:0"`
+	DeletedAt              gorm.DeletedAt `gorm:"index"`
 }
 
 // TableName specifies the table name for Payment
@@ -31,20 +53,44 @@ func (Payment) TableName() string {
 
 // ToDomain converts GORM Payment to domain Payment
 func (p *Payment) ToDomain() *domain.Payment {
-	return &domain.Payment{
-		ID:               p.ID,
-		OrderID:          p.OrderID,
-		UserID:           p.UserID,
-		Amount:           p.Amount,
-		Currency:         p.Currency,
-		Status:           domain.PaymentStatus(p.Status),
-		Method:           domain.PaymentMethod(p.Method),
-		ProviderID:       p.ProviderID,
-		ProviderResponse: p.ProviderResponse,
-		FailureReason:    p.FailureReason,
-		CreatedAt:        p.CreatedAt,
-		UpdatedAt:        p.UpdatedAt,
+	payment := &domain.Payment{
+		ID:                     p.ID,
+		OrderID:                p.OrderID,
+		UserID:                 p.UserID,
+		Amount:                 p.Amount,
+		RefundedAmount:         p.RefundedAmount,
+		Currency:               p.Currency,
+		Status:                 domain.PaymentStatus(p.Status),
+		Method:                 domain.PaymentMethod(p.Method),
+		Provider:               p.Provider,
+		ProviderID:             p.ProviderID,
+		ProviderResponse:       p.ProviderResponse,
+		FailureReason:          p.FailureReason,
+		Attempts:               p.Attempts,
+		DisputedAt:             p.DisputedAt,
+		CollectedAfterCancelAt: p.CollectedAfterCancelAt,
+		SettlementCurrency:     p.SettlementCurrency,
+		ExchangeRate:           p.ExchangeRate,
+		ExchangeRateSource:     p.ExchangeRateSource,
+		ExchangeRateAt:         p.ExchangeRateAt,
+		RiskScore:              p.RiskScore,
+		RiskDecision:           domain.RiskDecision(p.RiskDecision),
+		RiskAssessedAt:         p.RiskAssessedAt,
+		ProviderCustomerID:     p.ProviderCustomerID,
+		ProviderMethodID:       p.ProviderMethodID,
+		AuthorizedAt:           p.AuthorizedAt,
+		AuthorizationExpiresAt: p.AuthorizationExpiresAt,
+		CapturedAmount:         p.CapturedAmount,
+		CapturedAt:             p.CapturedAt,
+		CreatedAt:              p.CreatedAt,
+		UpdatedAt:              p.UpdatedAt,
+		Version:                p.Version,
 	}
+	if p.PaymentMethodID != nil {
+		payment.PaymentMethodID = *p.PaymentMethodID
+	}
+	_ = json.Unmarshal([]byte(p.RiskReasons), &payment.RiskReasons)
+	return payment
 }
 
 // FromDomain converts domain Payment to GORM Payment
@@ -53,12 +99,41 @@ func (p *Payment) FromDomain(domainPayment *domain.Payment) {
 	p.OrderID = domainPayment.OrderID
 	p.UserID = domainPayment.UserID
 	p.Amount = domainPayment.Amount
+	p.RefundedAmount = domainPayment.RefundedAmount
 	p.Currency = domainPayment.Currency
 	p.Status = string(domainPayment.Status)
 	p.Method = string(domainPayment.Method)
+	p.Provider = domainPayment.Provider
 	p.ProviderID = domainPayment.ProviderID
 	p.ProviderResponse = domainPayment.ProviderResponse
 	p.FailureReason = domainPayment.FailureReason
+	p.Attempts = domainPayment.Attempts
+	p.DisputedAt = domainPayment.DisputedAt
+	p.CollectedAfterCancelAt = domainPayment.CollectedAfterCancelAt
+	p.SettlementCurrency = domainPayment.SettlementCurrency
+	p.ExchangeRate = domainPayment.ExchangeRate
+	p.ExchangeRateSource = domainPayment.ExchangeRateSource
+	p.ExchangeRateAt = domainPayment.ExchangeRateAt
+	p.RiskScore = domainPayment.RiskScore
+	p.RiskDecision = string(domainPayment.RiskDecision)
+	reasons := domainPayment.RiskReasons
+	if reasons == nil {
+		reasons = []string{}
+	}
+	encodedReasons, _ := json.Marshal(reasons)
+	p.RiskReasons = string(encodedReasons)
+	p.RiskAssessedAt = domainPayment.RiskAssessedAt
+	p.PaymentMethodID = nil
+	if domainPayment.PaymentMethodID != "" {
+		p.PaymentMethodID = &domainPayment.PaymentMethodID
+	}
+	p.ProviderCustomerID = domainPayment.ProviderCustomerID
+	p.ProviderMethodID = domainPayment.ProviderMethodID
+	p.AuthorizedAt = domainPayment.AuthorizedAt
+	p.AuthorizationExpiresAt = domainPayment.AuthorizationExpiresAt
+	p.CapturedAmount = domainPayment.CapturedAmount
+	p.CapturedAt = domainPayment.CapturedAt
 	p.CreatedAt = domainPayment.CreatedAt
 	p.UpdatedAt = domainPayment.UpdatedAt
+	p.Version = domainPayment.Version
 }
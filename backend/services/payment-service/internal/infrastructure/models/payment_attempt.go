@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// PaymentAttempt represents the GORM model for the log of attempts to charge
+// payments, which the risk velocity rules count
+type PaymentAttempt struct {
+	ID              string    `gorm:"type:uuid;primary_key;default:uuid_generate_v7()"`
+	PaymentID       string    `gorm:"type:uuid;not null;index"`
+	UserID          string    `gorm:"type:uuid;not null;index:idx_payment_attempts_user,priority:1"`
+	PaymentMethodID *string   `gorm:"type:uuid;index:idx_payment_attempts_method,priority:1"`
+	Attempt         int       `gorm:"not null"`
+	CreatedAt       time.Time `gorm:"index:idx_payment_attempts_user,priority:2;index:idx_payment_attempts_method,priority:2"`
+}
+
+// TableName specifies the table name for PaymentAttempt
+func (PaymentAttempt) TableName() string {
+	return "payment_attempts"
+}
+
+// NewPaymentAttempt logs the attempt a payment has just started
+func NewPaymentAttempt(payment *domain.Payment) *PaymentAttempt {
+	attempt := &PaymentAttempt{
+		PaymentID: payment.ID,
+		UserID:    payment.UserID,
+		Attempt:   payment.Attempts,
+		CreatedAt: payment.UpdatedAt,
+	}
+	if payment.PaymentMethodID != "" {
+		attempt.PaymentMethodID = &payment.PaymentMethodID
+	}
+	return attempt
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ProviderEvent records a provider webhook event that has been applied, so
+// redelivered events are recognised and skipped
+type ProviderEvent struct {
+	ID          string `gorm:"type:varchar(255);primary_key"`
+	Type        string `gorm:"type:varchar(50);not null"`
+	PaymentID   string `gorm:"type:uuid;index"`
+	ProcessedAt time.Time
+}
+
+// TableName specifies the table name for ProviderEvent
+func (ProviderEvent) TableName() string {
+	return "provider_events"
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// ReconciliationReport represents the GORM model for reconciliation runs.
+// A provider has at most one report per period.
+type ReconciliationReport struct {
+	ID               string    `gorm:"type:uuid;primary_key;default:uuid_generate_v7()"`
+	Provider         string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_reconciliation_reports_period"`
+	PeriodStart      time.Time `gorm:"not null;uniqueIndex:idx_reconciliation_reports_period"`
+	PeriodEnd        time.Time `gorm:"not null"`
+	TransactionCount int       `gorm:"not null;default:0"`
+	PaymentCount     int       `gorm:"not null;default:0"`
+	DiscrepancyCount int       `gorm:"not null;default:0"`
+	CreatedAt        time.Time
+
+	Discrepancies []ReconciliationDiscrepancy `gorm:"foreignKey:ReportID"`
+}
+
+// TableName specifies the table name for ReconciliationReport
+func (ReconciliationReport) TableName() string {
+	return "reconciliation_reports"
+}
+
+// ToDomain converts GORM ReconciliationReport to domain ReconciliationReport
+func (r *ReconciliationReport) ToDomain() *domain.ReconciliationReport {
+	report := &domain.ReconciliationReport{
+		ID:               r.ID,
+		Provider:         r.Provider,
+		PeriodStart:      r.PeriodStart,
+		PeriodEnd:        r.PeriodEnd,
+		TransactionCount: r.TransactionCount,
+		PaymentCount:     r.PaymentCount,
+		CreatedAt:        r.CreatedAt,
+	}
+	for _, d := range r.Discrepancies {
+		report.Discrepancies = append(report.Discrepancies, *d.ToDomain())
+	}
+	return report
+}
+
+// FromDomain converts domain ReconciliationReport to GORM ReconciliationReport
+func (r *ReconciliationReport) FromDomain(report *domain.ReconciliationReport) {
+	r.ID = report.ID
+	r.Provider = report.Provider
+	r.PeriodStart = report.PeriodStart
+	r.PeriodEnd = report.PeriodEnd
+	r.TransactionCount = report.TransactionCount
+	r.PaymentCount = report.PaymentCount
+	r.DiscrepancyCount = len(report.Discrepancies)
+	r.CreatedAt = report.CreatedAt
+	r.Discrepancies = make([]ReconciliationDiscrepancy, len(report.Discrepancies))
+	for i := range report.Discrepancies {
+		r.Discrepancies[i].FromDomain(&report.Discrepancies[i])
+	}
+}
+
+// ReconciliationDiscrepancy represents the GORM model for the findings of a reconciliation run
+type ReconciliationDiscrepancy struct {
+	ID                    string  `gorm:"type:uuid;primary_key;default:uuid_generate_v7()"`
+	ReportID              string  `gorm:"type:uuid;not null;index"`
+	Type                  string  `gorm:"type:varchar(50);not null;index"`
+	PaymentID             *string `gorm:"type:uuid;index"`
+	ProviderPaymentID     string  `gorm:"type:varchar(255);index"`
+	ProviderTransactionID string  `gorm:"type:varchar(255)"`
+	ExpectedAmount        float64 `gorm:"type:decimal(12,3);not null;default:0"`
+	ActualAmount          float64 `gorm:"type:decimal(12,3);not null;default:0"`
+	Currency              string  `gorm:"type:varchar(10)"`
+	Details               string  `gorm:"type:text"`
+	CreatedAt             time.Time
+}
+
+// TableName specifies the table name for ReconciliationDiscrepancy
+func (ReconciliationDiscrepancy) TableName() string {
+	return "reconciliation_discrepancies"
+}
+
+// ToDomain converts GORM ReconciliationDiscrepancy to domain Discrepancy
+func (d *ReconciliationDiscrepancy) ToDomain() *domain.Discrepancy {
+	discrepancy := &domain.Discrepancy{
+		ID:                    d.ID,
+		ReportID:              d.ReportID,
+		Type:                  domain.DiscrepancyType(d.Type),
+		ProviderPaymentID:     d.ProviderPaymentID,
+		ProviderTransactionID: d.ProviderTransactionID,
+		ExpectedAmount:        d.ExpectedAmount,
+		ActualAmount:          d.ActualAmount,
+		Currency:              d.Currency,
+		Details:               d.Details,
+		CreatedAt:             d.CreatedAt,
+	}
+	if d.PaymentID != nil {
+		discrepancy.PaymentID = *d.PaymentID
+	}
+	return discrepancy
+}
+
+// FromDomain converts domain Discrepancy to GORM ReconciliationDiscrepancy
+func (d *ReconciliationDiscrepancy) FromDomain(discrepancy *domain.Discrepancy) {
+	d.ID = discrepancy.ID
+	d.ReportID = discrepancy.ReportID
+	d.Type = string(discrepancy.Type)
+	d.PaymentID = nil
+	if discrepancy.PaymentID != "" {
+		d.PaymentID = &discrepancy.PaymentID
+	}
+	d.ProviderPaymentID = discrepancy.ProviderPaymentID
+	d.ProviderTransactionID = discrepancy.ProviderTransactionID
+	d.ExpectedAmount = discrepancy.ExpectedAmount
+	d.ActualAmount = discrepancy.ActualAmount
+	d.Currency = discrepancy.Currency
+	d.Details = discrepancy.Details
+	d.CreatedAt = discrepancy.CreatedAt
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// Refund represents the GORM model for refunds issued against payments
+type Refund struct {
+	ID               string  `gorm:"type:uuid;primary_key;default:uuid_generate_v7()"`
+	PaymentID        string  `gorm:"type:uuid;not null;index"`
+	ProviderRefundID string  `gorm:"type:varchar(255);index"`
+	Amount           float64 `gorm:"type:decimal(12,3);not null"`
+	Currency         string  `gorm:"type:varchar(3);not null"`
+	Reason           string  `gorm:"type:varchar(50);not null"`
+	Note             string  `gorm:"type:text"`
+	IdempotencyKey   string  `gorm:"type:varchar(255);not null;uniqueIndex"`
+	CreatedAt        time.Time
+}
+
+// TableName specifies the table name for Refund
+func (Refund) TableName() string {
+	return "payment_refunds"
+}
+
+// ToDomain converts GORM Refund to domain Refund
+func (r *Refund) ToDomain() *domain.Refund {
+	return &domain.Refund{
+		ID:               r.ID,
+		PaymentID:        r.PaymentID,
+		ProviderRefundID: r.ProviderRefundID,
+		Amount:           r.Amount,
+		Currency:         r.Currency,
+		Reason:           domain.RefundReason(r.Reason),
+		Note:             r.Note,
+		IdempotencyKey:   r.IdempotencyKey,
+		CreatedAt:        r.CreatedAt,
+	}
+}
+
+// FromDomain converts domain Refund to GORM Refund
+func (r *Refund) FromDomain(domainRefund *domain.Refund) {
+	r.ID = domainRefund.ID
+	r.PaymentID = domainRefund.PaymentID
+	r.ProviderRefundID = domainRefund.ProviderRefundID
+	r.Amount = domainRefund.Amount
+	r.Currency = domainRefund.Currency
+	r.Reason = string(domainRefund.Reason)
+	r.Note = domainRefund.Note
+	r.IdempotencyKey = domainRefund.IdempotencyKey
+	r.CreatedAt = domainRefund.CreatedAt
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+	"gorm.io/gorm"
+)
+
+// SavedPaymentMethod represents the GORM model for tokenized payment methods.
+// There is deliberately no column for a card number.
+type SavedPaymentMethod struct {
+	ID                 string `gorm:"type:uuid;primary_key;default:uuid_generate_v7()"`
+	UserID             string `gorm:"type:uuid;not null;index;uniqueIndex:idx_payment_methods_user_provider_method"`
+	Type               string `gorm:"type:varchar(50);not null"`
+	Provider           string `gorm:"type:varchar(50)"`
+	ProviderCustomerID string `gorm:"type:varchar(255);not null"`
+	ProviderMethodID   string `gorm:"type:varchar(255);not null;uniqueIndex:idx_payment_methods_user_provider_method"`
+	Brand              string `gorm:"type:varchar(50)"`
+	Last4              string `gorm:"type:varchar(4);not null"`
+	ExpMonth           int    `gorm:"not null"`
+	ExpYear            int    `gorm:"not null"`
+	Country            string `gorm:"type:varchar(2)"`
+	IsDefault          bool   `gorm:"not null;default:false"`
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	DeletedAt          gorm.DeletedAt `gorm:"index"`
+}
+
+// TableName specifies the table name for SavedPaymentMethod
+func (SavedPaymentMethod) TableName() string {
+	return "payment_methods"
+}
+
+// ToDomain converts GORM SavedPaymentMethod to domain SavedPaymentMethod
+func (m *SavedPaymentMethod) ToDomain() *domain.SavedPaymentMethod {
+	return &domain.SavedPaymentMethod{
+		ID:                 m.ID,
+		UserID:             m.UserID,
+		Type:               domain.PaymentMethod(m.Type),
+		Provider:           m.Provider,
+		ProviderCustomerID: m.ProviderCustomerID,
+		ProviderMethodID:   m.ProviderMethodID,
+		Brand:              m.Brand,
+		Last4:              m.Last4,
+		ExpMonth:           m.ExpMonth,
+		ExpYear:            m.ExpYear,
+		Country:            m.Country,
+		IsDefault:          m.IsDefault,
+		CreatedAt:          m.CreatedAt,
+		UpdatedAt:          m.UpdatedAt,
+	}
+}
+
+// FromDomain converts domain SavedPaymentMethod to GORM SavedPaymentMethod
+func (m *SavedPaymentMethod) FromDomain(method *domain.SavedPaymentMethod) {
+	m.ID = method.ID
+	m.UserID = method.UserID
+	m.Type = string(method.Type)
+	m.Provider = method.Provider
+	m.ProviderCustomerID = method.ProviderCustomerID
+	m.ProviderMethodID = method.ProviderMethodID
+	m.Brand = method.Brand
+	m.Last4 = method.Last4
+	m.ExpMonth = method.ExpMonth
+	m.ExpYear = method.ExpYear
+	m.Country = method.Country
+	m.IsDefault = method.IsDefault
+	m.CreatedAt = method.CreatedAt
+	m.UpdatedAt = method.UpdatedAt
+}
@@ -0,0 +1,153 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// MockOutcome is the result the mock provider gives every payment
+type MockOutcome string
+
+const (
+	MockOutcomeSucceed MockOutcome = "succeed"
+	MockOutcomeDecline MockOutcome = "decline"
+	MockOutcomeTimeout MockOutcome = "timeout"
+)
+
+// ErrMockDeclined is returned by the mock provider when set to decline
+var ErrMockDeclined = errors.New("mock provider: card declined")
+
+// ParseMockOutcome parses a mock outcome name, case-insensitively
+func ParseMockOutcome(s string) (MockOutcome, error) {
+	switch outcome := MockOutcome(strings.ToLower(strings.TrimSpace(s))); outcome {
+	case MockOutcomeSucceed, MockOutcomeDecline, MockOutcomeTimeout:
+		return outcome, nil
+	default:
+		return "", fmt.Errorf("unknown mock payment outcome %q: want succeed, decline or timeout", s)
+	}
+}
+
+// MockProvider is an in-process payment provider for local development and
+// integration tests. It never talks to the network, derives every ID from the
+// payment ID so runs are reproducible, and gives all payments the same
+// configurable outcome.
+type MockProvider struct {
+	mu      sync.RWMutex
+	outcome MockOutcome
+	timeout time.Duration // How long a timing out call hangs before failing
+}
+
+// NewMockProvider creates a mock provider with the given outcome. Timing out
+// calls hang for the timeout, or until their context is done if sooner.
+func NewMockProvider(outcome MockOutcome, timeout time.Duration) *MockProvider {
+	return &MockProvider{
+		outcome: outcome,
+		timeout: timeout,
+	}
+}
+
+// SetOutcome changes the outcome of subsequent calls
+func (p *MockProvider) SetOutcome(outcome MockOutcome) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.outcome = outcome
+}
+
+// Outcome returns the current outcome
+func (p *MockProvider) Outcome() MockOutcome {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.outcome
+}
+
+// ProcessPayment charges the payment according to the configured outcome
+func (p *MockProvider) ProcessPayment(ctx context.Context, payment *domain.Payment) (string, string, error) {
+	providerID := "mock_pi_" + payment.ID
+	if err := p.result(ctx); err != nil {
+		return providerID, "declined", err
+	}
+	return providerID, "succeeded", nil
+}
+
+// AuthorizePayment authorizes the payment according to the configured outcome
+func (p *MockProvider) AuthorizePayment(ctx context.Context, payment *domain.Payment) (string, string, error) {
+	providerID := "mock_pi_" + payment.ID
+	if err := p.result(ctx); err != nil {
+		return providerID, "declined", err
+	}
+	return providerID, "requires_capture", nil
+}
+
+// CapturePayment captures an authorized payment according to the configured outcome
+func (p *MockProvider) CapturePayment(ctx context.Context, payment *domain.Payment) (string, error) {
+	if err := p.result(ctx); err != nil {
+		return "", err
+	}
+	return "succeeded", nil
+}
+
+// VoidPayment voids an authorized payment according to the configured outcome
+func (p *MockProvider) VoidPayment(ctx context.Context, payment *domain.Payment) error {
+	return p.result(ctx)
+}
+
+// RefundPayment refunds a payment according to the configured outcome
+func (p *MockProvider) RefundPayment(ctx context.Context, payment *domain.Payment, refund *domain.Refund) (string, error) {
+	if err := p.result(ctx); err != nil {
+		return "", err
+	}
+	return "mock_re_" + refund.IdempotencyKey, nil
+}
+
+// SubmitDisputeEvidence accepts dispute evidence according to the configured outcome
+func (p *MockProvider) SubmitDisputeEvidence(ctx context.Context, dispute *domain.Dispute) error {
+	return p.result(ctx)
+}
+
+// CreateCustomer returns a customer ID derived from the user ID
+func (p *MockProvider) CreateCustomer(ctx context.Context, userID string) (string, error) {
+	return "mock_cus_" + userID, nil
+}
+
+// AttachPaymentMethod accepts any token as a Visa card ending in 4242
+func (p *MockProvider) AttachPaymentMethod(ctx context.Context, customerID, token string) (*domain.CardDetails, error) {
+	return &domain.CardDetails{
+		ProviderMethodID: "mock_pm_" + token,
+		Brand:            "visa",
+		Last4:            "4242",
+		ExpMonth:         12,
+		ExpYear:          time.Now().Year() + 5,
+		Country:          "US",
+	}, nil
+}
+
+// DetachPaymentMethod accepts every saved method
+func (p *MockProvider) DetachPaymentMethod(ctx context.Context, providerMethodID string) error {
+	return nil
+}
+
+// result returns the error of the configured outcome, hanging first when the
+// outcome is a timeout. Like a real lost response, a timeout leaves the
+// outcome unknown.
+func (p *MockProvider) result(ctx context.Context) error {
+	switch p.Outcome() {
+	case MockOutcomeDecline:
+		return ErrMockDeclined
+	case MockOutcomeTimeout:
+		timer := time.NewTimer(p.timeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		return fmt.Errorf("mock provider: %w: %w", domain.ErrPaymentOutcomeUnknown, context.DeadlineExceeded)
+	default:
+		return nil
+	}
+}
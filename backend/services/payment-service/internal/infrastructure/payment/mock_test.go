@@ -0,0 +1,114 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+func TestParseMockOutcome(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    MockOutcome
+		wantErr bool
+	}{
+		{in: "succeed", want: MockOutcomeSucceed},
+		{in: " Decline ", want: MockOutcomeDecline},
+		{in: "TIMEOUT", want: MockOutcomeTimeout},
+		{in: "", wantErr: true},
+		{in: "refund", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseMockOutcome(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMockOutcome(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMockOutcome(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMockProvider(t *testing.T) {
+	tests := []struct {
+		outcome      MockOutcome
+		wantErr      error
+		wantResponse string // Response of ProcessPayment
+	}{
+		{outcome: MockOutcomeSucceed, wantResponse: "succeeded"},
+		{outcome: MockOutcomeDecline, wantErr: ErrMockDeclined, wantResponse: "declined"},
+		{outcome: MockOutcomeTimeout, wantErr: domain.ErrPaymentOutcomeUnknown, wantResponse: "declined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.outcome), func(t *testing.T) {
+			provider := NewMockProvider(tt.outcome, time.Millisecond)
+			ctx := context.Background()
+			payment := &domain.Payment{ID: "pay-1", Amount: 25, Currency: "USD"}
+			refund := &domain.Refund{PaymentID: "pay-1", Amount: 5, IdempotencyKey: "refund-1"}
+
+			providerID, response, err := provider.ProcessPayment(ctx, payment)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ProcessPayment error = %v, want %v", err, tt.wantErr)
+			}
+			if providerID != "mock_pi_pay-1" || response != tt.wantResponse {
+				t.Errorf("ProcessPayment = %q, %q, want mock_pi_pay-1, %q", providerID, response, tt.wantResponse)
+			}
+
+			if _, _, err := provider.AuthorizePayment(ctx, payment); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AuthorizePayment error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := provider.CapturePayment(ctx, payment); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CapturePayment error = %v, want %v", err, tt.wantErr)
+			}
+			if err := provider.VoidPayment(ctx, payment); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VoidPayment error = %v, want %v", err, tt.wantErr)
+			}
+			refundID, err := provider.RefundPayment(ctx, payment, refund)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RefundPayment error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && refundID != "mock_re_refund-1" {
+				t.Errorf("RefundPayment = %q, want mock_re_refund-1", refundID)
+			}
+			if err := provider.SubmitDisputeEvidence(ctx, &domain.Dispute{}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("SubmitDisputeEvidence error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestMockProviderTimeoutEndsWithContext checks a timing out call gives up
+// when its context is done instead of hanging for the whole timeout
+func TestMockProviderTimeoutEndsWithContext(t *testing.T) {
+	provider := NewMockProvider(MockOutcomeTimeout, time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := provider.ProcessPayment(ctx, &domain.Payment{ID: "pay-1"})
+	if !errors.Is(err, domain.ErrPaymentOutcomeUnknown) {
+		t.Fatalf("ProcessPayment error = %v, want %v", err, domain.ErrPaymentOutcomeUnknown)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ProcessPayment returned after %s, want it to end with the context", elapsed)
+	}
+}
+
+func TestMockProviderSetOutcome(t *testing.T) {
+	provider := NewMockProvider(MockOutcomeSucceed, time.Millisecond)
+	provider.SetOutcome(MockOutcomeDecline)
+
+	if provider.Outcome() != MockOutcomeDecline {
+		t.Errorf("Outcome = %q, want %q", provider.Outcome(), MockOutcomeDecline)
+	}
+	if _, _, err := provider.ProcessPayment(context.Background(), &domain.Payment{ID: "pay-1"}); !errors.Is(err, ErrMockDeclined) {
+		t.Errorf("ProcessPayment error = %v, want %v", err, ErrMockDeclined)
+	}
+}
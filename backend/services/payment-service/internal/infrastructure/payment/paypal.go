@@ -0,0 +1,423 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// PayPal order intents
+const (
+	paypalIntentCapture   = "CAPTURE"
+	paypalIntentAuthorize = "AUTHORIZE"
+)
+
+// PayPalProvider implements payment processing with the PayPal Orders v2 API.
+// A PayPal payment needs the buyer's approval: the first call creates an
+// order and reports domain.ErrPaymentPending with the approval link as the
+// provider response. Once the buyer approved it on PayPal, processing or
+// authorizing the payment again captures or authorizes the order.
+type PayPalProvider struct {
+	clientID     string
+	clientSecret string
+	apiBase      string
+	returnURL    string // Where PayPal sends the buyer after approving
+	cancelURL    string // Where PayPal sends the buyer after cancelling
+	httpClient   *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	tokenExpiry time.Time
+}
+
+// NewPayPalProvider creates a new PayPal payment provider. apiBase is the
+// REST API URL, e.g. https://api-m.sandbox.paypal.com for the sandbox.
+func NewPayPalProvider(clientID, clientSecret, apiBase, returnURL, cancelURL string) *PayPalProvider {
+	return &PayPalProvider{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		apiBase:      strings.TrimRight(apiBase, "/"),
+		returnURL:    returnURL,
+		cancelURL:    cancelURL,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// paypalAmount is a PayPal money value
+type paypalAmount struct {
+	CurrencyCode string `json:"currency_code"`
+	Value        string `json:"value"`
+}
+
+// paypalOrder is the part of a PayPal order the provider reads
+type paypalOrder struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	Intent        string `json:"intent"`
+	PurchaseUnits []struct {
+		Payments struct {
+			Authorizations []paypalPaymentRecord `json:"authorizations"`
+			Captures       []paypalPaymentRecord `json:"captures"`
+		} `json:"payments"`
+	} `json:"purchase_units"`
+	Links []struct {
+		Href string `json:"href"`
+		Rel  string `json:"rel"`
+	} `json:"links"`
+}
+
+// paypalPaymentRecord is an authorization, capture or refund of an order
+type paypalPaymentRecord struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// paypalError is an error response of the PayPal API
+type paypalError struct {
+	StatusCode int
+	Name       string `json:"name"`
+	Message    string `json:"message"`
+	Details    []struct {
+		Issue string `json:"issue"`
+	} `json:"details"`
+}
+
+func (e *paypalError) Error() string {
+	return fmt.Sprintf("paypal: %d %s: %s", e.StatusCode, e.Name, e.Message)
+}
+
+// hasIssue reports whether the error details name the issue
+func (e *paypalError) hasIssue(issue string) bool {
+	for _, d := range e.Details {
+		if d.Issue == issue {
+			return true
+		}
+	}
+	return false
+}
+
+// ProcessPayment creates an order that captures on approval, or captures the
+// order once the buyer approved it
+func (p *PayPalProvider) ProcessPayment(ctx context.Context, payment *domain.Payment) (string, string, error) {
+	return p.completeOrder(ctx, payment, paypalIntentCapture)
+}
+
+// AuthorizePayment creates an order that authorizes on approval, or
+// authorizes the order once the buyer approved it
+func (p *PayPalProvider) AuthorizePayment(ctx context.Context, payment *domain.Payment) (string, string, error) {
+	return p.completeOrder(ctx, payment, paypalIntentAuthorize)
+}
+
+// CapturePayment captures the payment's CapturedAmount from the order's
+// authorization; it is the final capture, so PayPal releases the rest
+func (p *PayPalProvider) CapturePayment(ctx context.Context, payment *domain.Payment) (string, error) {
+	order, err := p.getOrder(ctx, payment.ProviderID)
+	if err != nil {
+		return "", err
+	}
+	authorization, ok := order.authorization()
+	if !ok {
+		return "", fmt.Errorf("paypal order %s has no authorization", order.ID)
+	}
+
+	body := map[string]interface{}{
+		"amount":        p.amount(payment.CapturedAmount, payment.Currency),
+		"final_capture": true,
+	}
+	var capture paypalPaymentRecord
+	if err := p.do(ctx, http.MethodPost, "/v2/payments/authorizations/"+authorization.ID+"/capture", payment.IdempotencyKey("capture"), body, &capture); err != nil {
+		return "", fmt.Errorf("paypal capture failed: %w", err)
+	}
+	switch capture.Status {
+	case "COMPLETED", "PENDING":
+		return capture.Status, nil
+	default:
+		return capture.Status, fmt.Errorf("capture not successful: %s", capture.Status)
+	}
+}
+
+// VoidPayment voids the order's authorization, releasing the hold. An order
+// the buyer has not paid yet holds no funds and is left to expire at PayPal.
+func (p *PayPalProvider) VoidPayment(ctx context.Context, payment *domain.Payment) error {
+	order, err := p.getOrder(ctx, payment.ProviderID)
+	if err != nil {
+		return err
+	}
+	authorization, ok := order.authorization()
+	if !ok {
+		switch order.Status {
+		case "CREATED", "SAVED", "PAYER_ACTION_REQUIRED", "APPROVED":
+			return nil
+		}
+		return fmt.Errorf("paypal order %s has no authorization", order.ID)
+	}
+	if authorization.Status == "VOIDED" {
+		return nil
+	}
+
+	if err := p.do(ctx, http.MethodPost, "/v2/payments/authorizations/"+authorization.ID+"/void", payment.IdempotencyKey("void"), nil, nil); err != nil {
+		return fmt.Errorf("paypal void failed: %w", err)
+	}
+	return nil
+}
+
+// RefundPayment refunds part or all of the order's capture and returns the
+// PayPal refund ID
+func (p *PayPalProvider) RefundPayment(ctx context.Context, payment *domain.Payment, r *domain.Refund) (string, error) {
+	order, err := p.getOrder(ctx, payment.ProviderID)
+	if err != nil {
+		return "", err
+	}
+	capture, ok := order.capture()
+	if !ok {
+		return "", fmt.Errorf("paypal order %s has no capture", order.ID)
+	}
+
+	body := map[string]interface{}{
+		"amount":    p.amount(r.Amount, payment.Currency),
+		"custom_id": payment.ID,
+	}
+	if r.Note != "" {
+		body["note_to_payer"] = r.Note
+	}
+	var refund paypalPaymentRecord
+	if err := p.do(ctx, http.MethodPost, "/v2/payments/captures/"+capture.ID+"/refund", r.IdempotencyKey, body, &refund); err != nil {
+		return "", fmt.Errorf("paypal refund failed: %w", err)
+	}
+	switch refund.Status {
+	case "CANCELLED", "FAILED":
+		return refund.ID, fmt.Errorf("refund not successful: %s", refund.Status)
+	}
+	return refund.ID, nil
+}
+
+// completeOrder creates the payment's order on the first call and completes
+// it with the given intent once the buyer approved it
+func (p *PayPalProvider) completeOrder(ctx context.Context, payment *domain.Payment, intent string) (string, string, error) {
+	var order *paypalOrder
+	var err error
+	if payment.ProviderID == "" {
+		order, err = p.createOrder(ctx, payment, intent)
+	} else {
+		order, err = p.getOrder(ctx, payment.ProviderID)
+	}
+	if err != nil {
+		return payment.ProviderID, "", err
+	}
+
+	if order.Status == "APPROVED" {
+		path := "/v2/checkout/orders/" + order.ID + "/capture"
+		if intent == paypalIntentAuthorize {
+			path = "/v2/checkout/orders/" + order.ID + "/authorize"
+		}
+		completed := &paypalOrder{}
+		err := p.do(ctx, http.MethodPost, path, payment.IdempotencyKey(strings.ToLower(intent)+"-order"), map[string]interface{}{}, completed)
+		var perr *paypalError
+		if errors.As(err, &perr) && perr.hasIssue("ORDER_NOT_APPROVED") {
+			return order.ID, order.approveLink(), domain.ErrPaymentPending
+		}
+		if err != nil {
+			return order.ID, "", fmt.Errorf("paypal payment failed: %w", err)
+		}
+		order = completed
+	}
+
+	switch order.Status {
+	case "CREATED", "SAVED", "PAYER_ACTION_REQUIRED":
+		// Waiting for the buyer to approve the order on PayPal
+		return order.ID, order.approveLink(), domain.ErrPaymentPending
+	case "COMPLETED":
+		record, ok := order.capture()
+		if intent == paypalIntentAuthorize {
+			record, ok = order.authorization()
+		}
+		if !ok {
+			return order.ID, order.Status, fmt.Errorf("paypal order %s completed without a payment", order.ID)
+		}
+		switch record.Status {
+		case "COMPLETED", "CREATED", "CAPTURED":
+			return order.ID, record.Status, nil
+		case "PENDING":
+			return order.ID, record.Status, domain.ErrPaymentPending
+		default:
+			return order.ID, record.Status, fmt.Errorf("payment not successful: %s", record.Status)
+		}
+	default:
+		return order.ID, order.Status, fmt.Errorf("payment not successful: %s", order.Status)
+	}
+}
+
+// createOrder creates a PayPal order for the payment
+func (p *PayPalProvider) createOrder(ctx context.Context, payment *domain.Payment, intent string) (*paypalOrder, error) {
+	body := map[string]interface{}{
+		"intent": intent,
+		"purchase_units": []map[string]interface{}{{
+			"reference_id": payment.OrderID,
+			"custom_id":    payment.ID,
+			"amount":       p.amount(payment.Amount, payment.Currency),
+		}},
+		"application_context": map[string]string{
+			"return_url":  p.returnURL,
+			"cancel_url":  p.cancelURL,
+			"user_action": "PAY_NOW",
+		},
+	}
+	order := &paypalOrder{}
+	if err := p.do(ctx, http.MethodPost, "/v2/checkout/orders", payment.IdempotencyKey("order"), body, order); err != nil {
+		return nil, fmt.Errorf("paypal order creation failed: %w", err)
+	}
+	return order, nil
+}
+
+// getOrder fetches a PayPal order
+func (p *PayPalProvider) getOrder(ctx context.Context, orderID string) (*paypalOrder, error) {
+	order := &paypalOrder{}
+	if err := p.do(ctx, http.MethodGet, "/v2/checkout/orders/"+url.PathEscape(orderID), "", nil, order); err != nil {
+		return nil, fmt.Errorf("paypal order lookup failed: %w", err)
+	}
+	return order, nil
+}
+
+// approveLink returns the URL the buyer approves the order at
+func (o *paypalOrder) approveLink() string {
+	for _, link := range o.Links {
+		if link.Rel == "approve" || link.Rel == "payer-action" {
+			return link.Href
+		}
+	}
+	return o.Status
+}
+
+// authorization returns the order's latest authorization
+func (o *paypalOrder) authorization() (paypalPaymentRecord, bool) {
+	for _, unit := range o.PurchaseUnits {
+		if n := len(unit.Payments.Authorizations); n > 0 {
+			return unit.Payments.Authorizations[n-1], true
+		}
+	}
+	return paypalPaymentRecord{}, false
+}
+
+// capture returns the order's latest capture
+func (o *paypalOrder) capture() (paypalPaymentRecord, bool) {
+	for _, unit := range o.PurchaseUnits {
+		if n := len(unit.Payments.Captures); n > 0 {
+			return unit.Payments.Captures[n-1], true
+		}
+	}
+	return paypalPaymentRecord{}, false
+}
+
+// paypalWholeUnitCurrencies are currencies PayPal takes without decimals,
+// although they have minor units
+var paypalWholeUnitCurrencies = map[string]bool{"HUF": true, "TWD": true}
+
+// amount formats an amount the way PayPal expects it: with as many decimals
+// as the currency has, e.g. "12.30" USD or "1230" JPY
+func (p *PayPalProvider) amount(amount float64, currency string) paypalAmount {
+	c := domain.CurrencyOf(currency)
+	if paypalWholeUnitCurrencies[c.Code] {
+		c.Exponent = 0
+	}
+	return paypalAmount{
+		CurrencyCode: c.Code,
+		Value:        c.Format(amount),
+	}
+}
+
+// do sends an authenticated API request. A non-empty requestID makes the
+// request idempotent, so retries do not create a second order or refund.
+func (p *PayPalProvider) do(ctx context.Context, method, path, requestID string, body, out interface{}) error {
+	token, err := p.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.apiBase+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Prefer", "return=representation")
+	if requestID != "" {
+		req.Header.Set("PayPal-Request-Id", requestID)
+	}
+
+	return p.send(req, out)
+}
+
+// token returns a cached OAuth access token, fetching a new one shortly
+// before the current one expires
+func (p *PayPalProvider) token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.accessToken != "" && time.Now().Before(p.tokenExpiry) {
+		return p.accessToken, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.apiBase+"/v1/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(p.clientID, p.clientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := p.send(req, &resp); err != nil {
+		return "", fmt.Errorf("paypal authentication failed: %w", err)
+	}
+
+	p.accessToken = resp.AccessToken
+	p.tokenExpiry = time.Now().Add(time.Duration(resp.ExpiresIn)*time.Second - time.Minute)
+	return p.accessToken, nil
+}
+
+// send performs the request and decodes the response into out, or the
+// error response into a *paypalError. Network failures, rate limiting and
+// PayPal server errors are marked with domain.ErrPaymentOutcomeUnknown, since
+// PayPal may have carried out the request.
+func (p *PayPalProvider) send(req *http.Request, out interface{}) error {
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrPaymentOutcomeUnknown, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		perr := &paypalError{StatusCode: resp.StatusCode}
+		_ = json.NewDecoder(resp.Body).Decode(perr)
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%w: %w", domain.ErrPaymentOutcomeUnknown, perr)
+		}
+		return perr
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
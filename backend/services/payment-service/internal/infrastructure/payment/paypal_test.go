@@ -0,0 +1,120 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// newPayPalStub serves a PayPal token and the order PAYPAL-1 with the given
+// JSON body and status; posts to the order's capture endpoint get the
+// capture response
+func newPayPalStub(t *testing.T, orderStatus int, order string, captureStatus int, capture string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, ok := r.BasicAuth(); !ok || id != "client" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token", "expires_in": 3600})
+	})
+	mux.HandleFunc("/v2/checkout/orders/PAYPAL-1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(orderStatus)
+		_, _ = w.Write([]byte(order))
+	})
+	mux.HandleFunc("/v2/checkout/orders/PAYPAL-1/capture", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PayPal-Request-Id") != "capture-order-pay-1-1" {
+			t.Errorf("capture PayPal-Request-Id = %q, want capture-order-pay-1-1", r.Header.Get("PayPal-Request-Id"))
+		}
+		w.WriteHeader(captureStatus)
+		_, _ = w.Write([]byte(capture))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestPayPalProviderProcessPayment(t *testing.T) {
+	const (
+		created   = `{"id":"PAYPAL-1","status":"PAYER_ACTION_REQUIRED","links":[{"rel":"payer-action","href":"https://paypal.test/approve"}]}`
+		approved  = `{"id":"PAYPAL-1","status":"APPROVED","links":[{"rel":"approve","href":"https://paypal.test/approve"}]}`
+		completed = `{"id":"PAYPAL-1","status":"COMPLETED","purchase_units":[{"payments":{"captures":[{"id":"CAP-1","status":"COMPLETED"}]}}]}`
+		pending   = `{"id":"PAYPAL-1","status":"COMPLETED","purchase_units":[{"payments":{"captures":[{"id":"CAP-1","status":"PENDING"}]}}]}`
+		declined  = `{"id":"PAYPAL-1","status":"COMPLETED","purchase_units":[{"payments":{"captures":[{"id":"CAP-1","status":"DECLINED"}]}}]}`
+	)
+
+	tests := []struct {
+		name          string
+		orderStatus   int
+		order         string
+		captureStatus int
+		capture       string
+		wantResponse  string
+		wantErr       error
+		wantFailure   bool // A final error that is neither pending nor unknown
+	}{
+		{name: "awaiting buyer approval", orderStatus: 200, order: created, wantResponse: "https://paypal.test/approve", wantErr: domain.ErrPaymentPending},
+		{name: "approved order is captured", orderStatus: 200, order: approved, captureStatus: 201, capture: completed, wantResponse: "COMPLETED"},
+		{
+			name:          "approval withdrawn before the capture",
+			orderStatus:   200,
+			order:         approved,
+			captureStatus: 422,
+			capture:       `{"name":"UNPROCESSABLE_ENTITY","details":[{"issue":"ORDER_NOT_APPROVED"}]}`,
+			wantResponse:  "https://paypal.test/approve",
+			wantErr:       domain.ErrPaymentPending,
+		},
+		{name: "capture still pending", orderStatus: 200, order: pending, wantResponse: "PENDING", wantErr: domain.ErrPaymentPending},
+		{name: "capture declined", orderStatus: 200, order: declined, wantResponse: "DECLINED", wantFailure: true},
+		{name: "voided order", orderStatus: 200, order: `{"id":"PAYPAL-1","status":"VOIDED"}`, wantResponse: "VOIDED", wantFailure: true},
+		{name: "server error leaves the outcome unknown", orderStatus: 503, order: `{"name":"SERVICE_UNAVAILABLE"}`, wantErr: domain.ErrPaymentOutcomeUnknown},
+		{name: "client error is final", orderStatus: 404, order: `{"name":"RESOURCE_NOT_FOUND"}`, wantFailure: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newPayPalStub(t, tt.orderStatus, tt.order, tt.captureStatus, tt.capture)
+			defer stub.Close()
+			provider := NewPayPalProvider("client", "secret", stub.URL+"/", "", "")
+			payment := &domain.Payment{ID: "pay-1", ProviderID: "PAYPAL-1", Amount: 25, Currency: "USD", Attempts: 1}
+
+			_, response, err := provider.ProcessPayment(context.Background(), payment)
+			if tt.wantFailure {
+				if err == nil || errors.Is(err, domain.ErrPaymentPending) || errors.Is(err, domain.ErrPaymentOutcomeUnknown) {
+					t.Fatalf("ProcessPayment error = %v, want a final failure", err)
+				}
+			} else if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ProcessPayment error = %v, want %v", err, tt.wantErr)
+			}
+			if response != tt.wantResponse {
+				t.Errorf("ProcessPayment response = %q, want %q", response, tt.wantResponse)
+			}
+		})
+	}
+}
+
+func TestPayPalProviderAmount(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     paypalAmount
+	}{
+		{amount: 12.3, currency: "usd", want: paypalAmount{CurrencyCode: "USD", Value: "12.30"}},
+		{amount: 1230, currency: "JPY", want: paypalAmount{CurrencyCode: "JPY", Value: "1230"}},
+		{amount: 1500.4, currency: "HUF", want: paypalAmount{CurrencyCode: "HUF", Value: "1500"}},
+	}
+
+	provider := NewPayPalProvider("client", "secret", "http://paypal.invalid", "", "")
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			if got := provider.amount(tt.amount, tt.currency); got != tt.want {
+				t.Errorf("amount(%v, %s) = %+v, want %+v", tt.amount, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
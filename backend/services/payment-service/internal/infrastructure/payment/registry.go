@@ -0,0 +1,252 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// Gateway is a payment provider able to move money for a payment
+type Gateway interface {
+	ProcessPayment(ctx context.Context, payment *domain.Payment) (string, string, error)
+	AuthorizePayment(ctx context.Context, payment *domain.Payment) (string, string, error)
+	CapturePayment(ctx context.Context, payment *domain.Payment) (string, error)
+	VoidPayment(ctx context.Context, payment *domain.Payment) error
+	RefundPayment(ctx context.Context, payment *domain.Payment, refund *domain.Refund) (string, error)
+}
+
+// Vault is a payment provider that stores cards for later payments
+type Vault interface {
+	CreateCustomer(ctx context.Context, userID string) (string, error)
+	AttachPaymentMethod(ctx context.Context, customerID, token string) (*domain.CardDetails, error)
+	DetachPaymentMethod(ctx context.Context, providerMethodID string) error
+}
+
+// DisputeResponder is a payment provider that takes evidence against disputes
+type DisputeResponder interface {
+	SubmitDisputeEvidence(ctx context.Context, dispute *domain.Dispute) error
+}
+
+// Route sends payments of a method, optionally limited to some currencies,
+// to a named provider. Method "*" matches every method.
+type Route struct {
+	Method     domain.PaymentMethod
+	Currencies []string // Upper-case ISO codes; empty matches every currency
+	Provider   string
+}
+
+// matches reports whether the route applies to the method and currency
+func (r Route) matches(method domain.PaymentMethod, currency string) bool {
+	if r.Method != "*" && r.Method != method {
+		return false
+	}
+	if len(r.Currencies) == 0 {
+		return true
+	}
+	for _, c := range r.Currencies {
+		if strings.EqualFold(c, currency) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRoutes parses routes written as "METHOD[:CUR,CUR]=provider" entries
+// separated by semicolons, e.g. "PAYPAL:USD,EUR=paypal;*=stripe". Routes are
+// tried in order, so more specific ones go first.
+func ParseRoutes(spec string) ([]Route, error) {
+	var routes []Route
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		match, provider, ok := strings.Cut(entry, "=")
+		provider = strings.TrimSpace(provider)
+		if !ok || provider == "" {
+			return nil, fmt.Errorf("invalid payment route %q: want METHOD[:CURRENCIES]=provider", entry)
+		}
+
+		method, currencies, _ := strings.Cut(match, ":")
+		route := Route{
+			Method:   domain.PaymentMethod(strings.ToUpper(strings.TrimSpace(method))),
+			Provider: provider,
+		}
+		if route.Method == "" {
+			return nil, fmt.Errorf("invalid payment route %q: missing payment method", entry)
+		}
+		for _, c := range strings.Split(currencies, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				route.Currencies = append(route.Currencies, strings.ToUpper(c))
+			}
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// Registry routes payment operations to the registered providers. A payment
+// is routed once, when it is created, and its provider name is stored on it,
+// so later captures and refunds reach the provider that took the payment even
+// if the routes change.
+type Registry struct {
+	gateways  map[string]Gateway
+	routes    []Route
+	vault     Vault
+	vaultName string
+}
+
+// NewRegistry creates an empty registry using the given routes
+func NewRegistry(routes []Route) *Registry {
+	return &Registry{
+		gateways: make(map[string]Gateway),
+		routes:   routes,
+	}
+}
+
+// Register adds a provider under a name used by the routes
+func (r *Registry) Register(name string, gateway Gateway) {
+	r.gateways[name] = gateway
+}
+
+// SetVault chooses the registered provider that stores saved payment
+// methods; payments charging a saved method are routed to it
+func (r *Registry) SetVault(name string) error {
+	vault, ok := r.gateways[name].(Vault)
+	if !ok {
+		return fmt.Errorf("payment provider %q is not registered or cannot store payment methods", name)
+	}
+	r.vault = vault
+	r.vaultName = name
+	return nil
+}
+
+// Validate checks that every route points at a registered provider
+func (r *Registry) Validate() error {
+	for _, route := range r.routes {
+		if _, ok := r.gateways[route.Provider]; !ok {
+			return fmt.Errorf("payment route for %s uses unknown provider %q", route.Method, route.Provider)
+		}
+	}
+	return nil
+}
+
+// Route returns the name of the provider for a payment method and currency.
+// The first matching route decides; if its provider is not registered, e.g.
+// PayPal without credentials, the payment is rejected rather than sent to a
+// provider that cannot handle the method.
+func (r *Registry) Route(method domain.PaymentMethod, currency string) (string, error) {
+	for _, route := range r.routes {
+		if !route.matches(method, currency) {
+			continue
+		}
+		if _, ok := r.gateways[route.Provider]; !ok {
+			return "", fmt.Errorf("%w: provider %q is not available", domain.ErrNoPaymentProvider, route.Provider)
+		}
+		return route.Provider, nil
+	}
+	return "", domain.ErrNoPaymentProvider
+}
+
+// gateway returns the provider of a payment. Payments created before routing
+// existed have no provider name and are routed by method and currency.
+func (r *Registry) gateway(payment *domain.Payment) (Gateway, error) {
+	name := payment.Provider
+	if name == "" {
+		var err error
+		if name, err = r.Route(payment.Method, payment.Currency); err != nil {
+			return nil, err
+		}
+	}
+	gateway, ok := r.gateways[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: provider %q is not registered", domain.ErrNoPaymentProvider, name)
+	}
+	return gateway, nil
+}
+
+// ProcessPayment processes a payment with its provider
+func (r *Registry) ProcessPayment(ctx context.Context, payment *domain.Payment) (string, string, error) {
+	gateway, err := r.gateway(payment)
+	if err != nil {
+		return "", "", err
+	}
+	return gateway.ProcessPayment(ctx, payment)
+}
+
+// AuthorizePayment authorizes a payment with its provider
+func (r *Registry) AuthorizePayment(ctx context.Context, payment *domain.Payment) (string, string, error) {
+	gateway, err := r.gateway(payment)
+	if err != nil {
+		return "", "", err
+	}
+	return gateway.AuthorizePayment(ctx, payment)
+}
+
+// CapturePayment captures an authorized payment with its provider
+func (r *Registry) CapturePayment(ctx context.Context, payment *domain.Payment) (string, error) {
+	gateway, err := r.gateway(payment)
+	if err != nil {
+		return "", err
+	}
+	return gateway.CapturePayment(ctx, payment)
+}
+
+// VoidPayment voids an authorized payment with its provider
+func (r *Registry) VoidPayment(ctx context.Context, payment *domain.Payment) error {
+	gateway, err := r.gateway(payment)
+	if err != nil {
+		return err
+	}
+	return gateway.VoidPayment(ctx, payment)
+}
+
+// RefundPayment refunds a payment with its provider
+func (r *Registry) RefundPayment(ctx context.Context, payment *domain.Payment, refund *domain.Refund) (string, error) {
+	gateway, err := r.gateway(payment)
+	if err != nil {
+		return "", err
+	}
+	return gateway.RefundPayment(ctx, payment, refund)
+}
+
+// SubmitDisputeEvidence submits dispute evidence to the provider of the disputed payment
+func (r *Registry) SubmitDisputeEvidence(ctx context.Context, dispute *domain.Dispute) error {
+	responder, ok := r.gateways[dispute.Provider].(DisputeResponder)
+	if !ok {
+		return fmt.Errorf("%w: provider %q does not take dispute evidence", domain.ErrNoPaymentProvider, dispute.Provider)
+	}
+	return responder.SubmitDisputeEvidence(ctx, dispute)
+}
+
+// CreateCustomer creates a customer with the vault provider
+func (r *Registry) CreateCustomer(ctx context.Context, userID string) (string, error) {
+	if r.vault == nil {
+		return "", fmt.Errorf("%w: no provider stores payment methods", domain.ErrNoPaymentProvider)
+	}
+	return r.vault.CreateCustomer(ctx, userID)
+}
+
+// AttachPaymentMethod attaches a payment method with the vault provider
+func (r *Registry) AttachPaymentMethod(ctx context.Context, customerID, token string) (*domain.CardDetails, error) {
+	if r.vault == nil {
+		return nil, fmt.Errorf("%w: no provider stores payment methods", domain.ErrNoPaymentProvider)
+	}
+	card, err := r.vault.AttachPaymentMethod(ctx, customerID, token)
+	if err != nil {
+		return nil, err
+	}
+	card.Provider = r.vaultName
+	return card, nil
+}
+
+// DetachPaymentMethod detaches a payment method with the vault provider
+func (r *Registry) DetachPaymentMethod(ctx context.Context, providerMethodID string) error {
+	if r.vault == nil {
+		return fmt.Errorf("%w: no provider stores payment methods", domain.ErrNoPaymentProvider)
+	}
+	return r.vault.DetachPaymentMethod(ctx, providerMethodID)
+}
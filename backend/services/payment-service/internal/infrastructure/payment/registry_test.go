@@ -0,0 +1,232 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+func TestParseRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []Route
+		wantErr bool
+	}{
+		{name: "empty spec", spec: "", want: nil},
+		{
+			name: "routes keep their order",
+			spec: "PAYPAL:USD,EUR=paypal;*=stripe",
+			want: []Route{
+				{Method: domain.PaymentMethodPayPal, Currencies: []string{"USD", "EUR"}, Provider: "paypal"},
+				{Method: "*", Provider: "stripe"},
+			},
+		},
+		{
+			name: "spaces, case and empty entries",
+			spec: " paypal : usd , ,eur = paypal ;; credit_card=stripe; ",
+			want: []Route{
+				{Method: domain.PaymentMethodPayPal, Currencies: []string{"USD", "EUR"}, Provider: "paypal"},
+				{Method: domain.PaymentMethodCreditCard, Provider: "stripe"},
+			},
+		},
+		{name: "missing provider", spec: "PAYPAL=", wantErr: true},
+		{name: "missing separator", spec: "PAYPAL", wantErr: true},
+		{name: "missing method", spec: ":USD=paypal", wantErr: true},
+		{name: "one bad entry fails the spec", spec: "*=stripe;PAYPAL", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routes, err := ParseRoutes(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseRoutes(%q) = %v, want an error", tt.spec, routes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRoutes(%q): %v", tt.spec, err)
+			}
+			if !reflect.DeepEqual(routes, tt.want) {
+				t.Errorf("ParseRoutes(%q) = %+v, want %+v", tt.spec, routes, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteMatches(t *testing.T) {
+	paypalUSD := Route{Method: domain.PaymentMethodPayPal, Currencies: []string{"USD", "EUR"}, Provider: "paypal"}
+	anyMethod := Route{Method: "*", Provider: "stripe"}
+
+	tests := []struct {
+		name     string
+		route    Route
+		method   domain.PaymentMethod
+		currency string
+		want     bool
+	}{
+		{name: "method and listed currency", route: paypalUSD, method: domain.PaymentMethodPayPal, currency: "EUR", want: true},
+		{name: "currency case is ignored", route: paypalUSD, method: domain.PaymentMethodPayPal, currency: "usd", want: true},
+		{name: "currency not listed", route: paypalUSD, method: domain.PaymentMethodPayPal, currency: "JPY", want: false},
+		{name: "other method", route: paypalUSD, method: domain.PaymentMethodCreditCard, currency: "USD", want: false},
+		{name: "wildcard method and no currencies", route: anyMethod, method: domain.PaymentMethodDebitCard, currency: "JPY", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.route.matches(tt.method, tt.currency); got != tt.want {
+				t.Errorf("matches(%s, %s) = %v, want %v", tt.method, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestRegistry returns a registry routing PayPal in USD to paypal, PayPal
+// otherwise to an unregistered provider and everything else to mock
+func newTestRegistry(t *testing.T) *Registry {
+	t.Helper()
+
+	routes, err := ParseRoutes("PAYPAL:USD=paypal;PAYPAL=missing;*=mock")
+	if err != nil {
+		t.Fatalf("ParseRoutes: %v", err)
+	}
+	registry := NewRegistry(routes)
+	registry.Register("paypal", NewPayPalProvider("id", "secret", "http://paypal.invalid", "", ""))
+	registry.Register("mock", NewMockProvider(MockOutcomeSucceed, time.Second))
+	return registry
+}
+
+func TestRegistryRoute(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   domain.PaymentMethod
+		currency string
+		want     string
+		wantErr  error
+	}{
+		{name: "first matching route wins", method: domain.PaymentMethodPayPal, currency: "USD", want: "paypal"},
+		{name: "wildcard route", method: domain.PaymentMethodCreditCard, currency: "EUR", want: "mock"},
+		{name: "matching route with an unregistered provider", method: domain.PaymentMethodPayPal, currency: "EUR", wantErr: domain.ErrNoPaymentProvider},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTestRegistry(t).Route(tt.method, tt.currency)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Route error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Route = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no matching route", func(t *testing.T) {
+		registry := NewRegistry([]Route{{Method: domain.PaymentMethodPayPal, Provider: "mock"}})
+		registry.Register("mock", NewMockProvider(MockOutcomeSucceed, time.Second))
+		if _, err := registry.Route(domain.PaymentMethodCreditCard, "USD"); !errors.Is(err, domain.ErrNoPaymentProvider) {
+			t.Errorf("Route error = %v, want %v", err, domain.ErrNoPaymentProvider)
+		}
+	})
+}
+
+func TestRegistryGateway(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string // Provider stored on the payment
+		method   domain.PaymentMethod
+		want     string
+		wantErr  error
+	}{
+		{name: "stored provider is used over the routes", provider: "paypal", method: domain.PaymentMethodCreditCard, want: "paypal"},
+		{name: "payment without a provider is routed", method: domain.PaymentMethodCreditCard, want: "mock"},
+		{name: "stored provider that is not registered", provider: "adyen", method: domain.PaymentMethodCreditCard, wantErr: domain.ErrNoPaymentProvider},
+		{name: "payment without a provider and no usable route", method: domain.PaymentMethodPayPal, wantErr: domain.ErrNoPaymentProvider},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newTestRegistry(t)
+			payment := &domain.Payment{ID: "pay-1", Method: tt.method, Currency: "EUR", Provider: tt.provider}
+
+			gateway, err := registry.gateway(payment)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("gateway error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if gateway != registry.gateways[tt.want] {
+				t.Errorf("gateway = %T, want the %s provider", gateway, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{name: "every provider registered", spec: "PAYPAL=paypal;*=mock"},
+		{name: "route to an unknown provider", spec: "PAYPAL=paypal;*=stripe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routes, err := ParseRoutes(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseRoutes: %v", err)
+			}
+			registry := NewRegistry(routes)
+			registry.Register("paypal", NewPayPalProvider("id", "secret", "http://paypal.invalid", "", ""))
+			registry.Register("mock", NewMockProvider(MockOutcomeSucceed, time.Second))
+
+			if err := registry.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistrySetVault(t *testing.T) {
+	tests := []struct {
+		name    string
+		vault   string
+		wantErr bool
+	}{
+		{name: "provider storing payment methods", vault: "mock"},
+		{name: "provider that cannot store payment methods", vault: "paypal", wantErr: true},
+		{name: "unknown provider", vault: "stripe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newTestRegistry(t)
+			if err := registry.SetVault(tt.vault); (err != nil) != tt.wantErr {
+				t.Fatalf("SetVault(%q) error = %v, want error %v", tt.vault, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				// A rejected vault leaves saved payment methods unavailable
+				if _, err := registry.CreateCustomer(context.Background(), "user-1"); !errors.Is(err, domain.ErrNoPaymentProvider) {
+					t.Errorf("CreateCustomer error = %v, want %v", err, domain.ErrNoPaymentProvider)
+				}
+				return
+			}
+
+			card, err := registry.AttachPaymentMethod(context.Background(), "mock_cus_user-1", "tok_1")
+			if err != nil {
+				t.Fatalf("AttachPaymentMethod: %v", err)
+			}
+			if card.Provider != tt.vault {
+				t.Errorf("card provider = %q, want %q", card.Provider, tt.vault)
+			}
+		})
+	}
+}
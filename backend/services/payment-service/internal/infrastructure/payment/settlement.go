@@ -0,0 +1,115 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+	"github.com/stripe/stripe-go/v76"
+)
+
+// ListBalanceTransactions returns the charges and refunds Stripe settled in
+// [from, to). Sources are expanded so each transaction can be matched to its
+// payment intent; fees, payouts and other balance movements are skipped.
+func (p *StripeProvider) ListBalanceTransactions(ctx context.Context, from, to time.Time) ([]domain.BalanceTransaction, error) {
+	params := &stripe.BalanceTransactionListParams{
+		CreatedRange: &stripe.RangeQueryParams{
+			GreaterThanOrEqual: from.Unix(),
+			LesserThan:         to.Unix(),
+		},
+	}
+	params.AddExpand("data.source")
+	params.Limit = stripe.Int64(100)
+	params.Context = ctx
+
+	var transactions []domain.BalanceTransaction
+	iter := p.balanceTransactions.List(params)
+	for iter.Next() {
+		if txn, ok := toBalanceTransaction(iter.BalanceTransaction()); ok {
+			transactions = append(transactions, txn)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("stripe balance transactions failed: %w", err)
+	}
+	return transactions, nil
+}
+
+// toBalanceTransaction translates a Stripe charge or refund balance
+// transaction. The balance transaction itself is in the settlement currency of
+// the account, so amounts are taken from its charge or refund, in the
+// presentment currency payments are recorded in.
+func toBalanceTransaction(bt *stripe.BalanceTransaction) (domain.BalanceTransaction, bool) {
+	txn := domain.BalanceTransaction{
+		ID:        bt.ID,
+		CreatedAt: time.Unix(bt.Created, 0),
+	}
+	if bt.Source == nil {
+		return txn, false
+	}
+
+	switch bt.Type {
+	case stripe.BalanceTransactionTypeCharge, stripe.BalanceTransactionTypePayment:
+		charge := bt.Source.Charge
+		if charge == nil || charge.PaymentIntent == nil {
+			return txn, false
+		}
+		txn.Type = domain.BalanceTransactionCharge
+		txn.ProviderPaymentID = charge.PaymentIntent.ID
+		amount := charge.AmountCaptured
+		if amount == 0 {
+			amount = charge.Amount
+		}
+		txn.Amount = domain.FromMinorUnits(amount, string(charge.Currency))
+		txn.Currency = string(charge.Currency)
+	case stripe.BalanceTransactionTypeRefund, stripe.BalanceTransactionTypePaymentRefund:
+		refund := bt.Source.Refund
+		if refund == nil {
+			return txn, false
+		}
+		txn.Type = domain.BalanceTransactionRefund
+		txn.ProviderRefundID = refund.ID
+		if refund.PaymentIntent != nil {
+			txn.ProviderPaymentID = refund.PaymentIntent.ID
+		}
+		txn.Amount = domain.FromMinorUnits(refund.Amount, string(refund.Currency))
+		txn.Currency = string(refund.Currency)
+	default:
+		return txn, false
+	}
+	return txn, true
+}
+
+// FixtureSettlementSource serves balance transactions from a JSON file, an
+// array of domain.BalanceTransaction. It stands in for a provider's
+// settlement data in local development and tests, e.g. for the mock provider.
+type FixtureSettlementSource struct {
+	transactions []domain.BalanceTransaction
+}
+
+// NewFixtureSettlementSource loads the balance transactions of a fixture file
+func NewFixtureSettlementSource(path string) (*FixtureSettlementSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settlement fixture: %w", err)
+	}
+	var transactions []domain.BalanceTransaction
+	if err := json.Unmarshal(data, &transactions); err != nil {
+		return nil, fmt.Errorf("invalid settlement fixture %s: %w", path, err)
+	}
+	return &FixtureSettlementSource{transactions: transactions}, nil
+}
+
+// ListBalanceTransactions returns the fixture transactions created in [from, to)
+func (s *FixtureSettlementSource) ListBalanceTransactions(ctx context.Context, from, to time.Time) ([]domain.BalanceTransaction, error) {
+	var transactions []domain.BalanceTransaction
+	for _, txn := range s.transactions {
+		if !txn.CreatedAt.Before(from) && txn.CreatedAt.Before(to) {
+			transactions = append(transactions, txn)
+		}
+	}
+	return transactions, nil
+}
@@ -2,63 +2,323 @@ package payment
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
 	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/balancetransaction"
+	"github.com/stripe/stripe-go/v76/customer"
+	"github.com/stripe/stripe-go/v76/dispute"
 	"github.com/stripe/stripe-go/v76/paymentintent"
+	"github.com/stripe/stripe-go/v76/paymentmethod"
+	"github.com/stripe/stripe-go/v76/refund"
 )
 
 // StripeProvider implements payment processing using Stripe
 type StripeProvider struct {
-	apiKey string
+	apiKey              string
+	paymentIntents      *paymentintent.Client
+	refunds             *refund.Client
+	customers           *customer.Client
+	paymentMethods      *paymentmethod.Client
+	balanceTransactions *balancetransaction.Client
+	disputes            *dispute.Client
 }
 
-// NewStripeProvider creates a new Stripe payment provider
-func NewStripeProvider(apiKey string) *StripeProvider {
-	stripe.Key = apiKey
+// NewStripeProvider creates a new Stripe payment provider. A non-empty apiBase
+// points the client at another Stripe compatible server, such as the local
+// stub in cmd/stripe-stub.
+func NewStripeProvider(apiKey, apiBase string) *StripeProvider {
+	config := &stripe.BackendConfig{}
+	if apiBase != "" {
+		config.URL = stripe.String(apiBase)
+	}
+	backend := stripe.GetBackendWithConfig(stripe.APIBackend, config)
+
 	return &StripeProvider{
-		apiKey: apiKey,
+		apiKey:              apiKey,
+		paymentIntents:      &paymentintent.Client{B: backend, Key: apiKey},
+		refunds:             &refund.Client{B: backend, Key: apiKey},
+		customers:           &customer.Client{B: backend, Key: apiKey},
+		paymentMethods:      &paymentmethod.Client{B: backend, Key: apiKey},
+		balanceTransactions: &balancetransaction.Client{B: backend, Key: apiKey},
+		disputes:            &dispute.Client{B: backend, Key: apiKey},
 	}
 }
 
-// ProcessPayment processes a payment using Stripe
+// ProcessPayment processes a payment using Stripe, capturing the funds right away
 func (p *StripeProvider) ProcessPayment(ctx context.Context, payment *domain.Payment) (string, string, error) {
-	// Convert amount to cents (Stripe uses smallest currency unit)
-	amountInCents := int64(payment.Amount * 100)
+	return p.confirmPaymentIntent(ctx, payment, stripe.PaymentIntentCaptureMethodAutomatic, stripe.PaymentIntentStatusSucceeded)
+}
+
+// AuthorizePayment places a hold for the payment amount on the customer's
+// card without capturing it, using a manual capture payment intent
+func (p *StripeProvider) AuthorizePayment(ctx context.Context, payment *domain.Payment) (string, string, error) {
+	return p.confirmPaymentIntent(ctx, payment, stripe.PaymentIntentCaptureMethodManual, stripe.PaymentIntentStatusRequiresCapture)
+}
 
+// CapturePayment captures the payment's CapturedAmount from its authorization;
+// Stripe releases the rest of the hold
+func (p *StripeProvider) CapturePayment(ctx context.Context, payment *domain.Payment) (string, error) {
+	amount, err := stripeAmount(payment.CapturedAmount, payment.Currency)
+	if err != nil {
+		return "", err
+	}
+	params := &stripe.PaymentIntentCaptureParams{
+		AmountToCapture: stripe.Int64(amount),
+	}
+	params.SetIdempotencyKey(payment.IdempotencyKey("capture"))
+	params.Context = ctx
+
+	pi, err := p.paymentIntents.Capture(payment.ProviderID, params)
+	if err != nil {
+		return "", stripeFailure("capture", err)
+	}
+	if pi.Status != stripe.PaymentIntentStatusSucceeded {
+		return string(pi.Status), fmt.Errorf("capture not successful: %s", pi.Status)
+	}
+	return string(pi.Status), nil
+}
+
+// VoidPayment cancels an uncaptured payment intent, releasing the hold
+func (p *StripeProvider) VoidPayment(ctx context.Context, payment *domain.Payment) error {
+	params := &stripe.PaymentIntentCancelParams{
+		CancellationReason: stripe.String(string(stripe.PaymentIntentCancellationReasonAbandoned)),
+	}
+	params.SetIdempotencyKey(payment.IdempotencyKey("void"))
+	params.Context = ctx
+
+	if _, err := p.paymentIntents.Cancel(payment.ProviderID, params); err != nil {
+		return stripeFailure("void", err)
+	}
+	return nil
+}
+
+// CreateCustomer creates the Stripe customer saved cards are attached to
+func (p *StripeProvider) CreateCustomer(ctx context.Context, userID string) (string, error) {
+	params := &stripe.CustomerParams{
+		Metadata: map[string]string{"user_id": userID},
+	}
+	params.SetIdempotencyKey("customer-" + userID)
+	params.Context = ctx
+
+	c, err := p.customers.New(params)
+	if err != nil {
+		return "", fmt.Errorf("stripe customer creation failed: %w", err)
+	}
+	return c.ID, nil
+}
+
+// AttachPaymentMethod attaches a payment method the client created with
+// Stripe.js to the customer and returns the card's display details
+func (p *StripeProvider) AttachPaymentMethod(ctx context.Context, customerID, token string) (*domain.CardDetails, error) {
+	params := &stripe.PaymentMethodAttachParams{
+		Customer: stripe.String(customerID),
+	}
+	params.Context = ctx
+
+	pm, err := p.paymentMethods.Attach(token, params)
+	if err != nil {
+		var stripeErr *stripe.Error
+		if errors.As(err, &stripeErr) && stripeErr.Type == stripe.ErrorTypeInvalidRequest {
+			return nil, fmt.Errorf("%w: %s", domain.ErrInvalidPaymentToken, stripeErr.Msg)
+		}
+		return nil, fmt.Errorf("stripe attach failed: %w", err)
+	}
+	if pm.Card == nil {
+		return nil, fmt.Errorf("%w: payment method %s is not a card", domain.ErrInvalidPaymentToken, pm.ID)
+	}
+
+	return &domain.CardDetails{
+		ProviderMethodID: pm.ID,
+		Brand:            string(pm.Card.Brand),
+		Last4:            pm.Card.Last4,
+		ExpMonth:         int(pm.Card.ExpMonth),
+		ExpYear:          int(pm.Card.ExpYear),
+		Country:          pm.Card.Country,
+	}, nil
+}
+
+// DetachPaymentMethod detaches a saved payment method from its customer
+func (p *StripeProvider) DetachPaymentMethod(ctx context.Context, providerMethodID string) error {
+	params := &stripe.PaymentMethodDetachParams{}
+	params.Context = ctx
+
+	if _, err := p.paymentMethods.Detach(providerMethodID, params); err != nil {
+		return fmt.Errorf("stripe detach failed: %w", err)
+	}
+	return nil
+}
+
+// confirmPaymentIntent creates and confirms a payment intent with the given
+// capture method and checks that it reached the expected status. A payment
+// that already has an intent, left waiting for 3D Secure, is checked instead
+// of charged twice.
+func (p *StripeProvider) confirmPaymentIntent(ctx context.Context, payment *domain.Payment, captureMethod stripe.PaymentIntentCaptureMethod, want stripe.PaymentIntentStatus) (string, string, error) {
+	if payment.ProviderID != "" {
+		getParams := &stripe.PaymentIntentParams{}
+		getParams.Context = ctx
+		pi, err := p.paymentIntents.Get(payment.ProviderID, getParams)
+		if err != nil {
+			return payment.ProviderID, "", stripeFailure("payment lookup", err)
+		}
+		return pi.ID, string(pi.Status), checkIntentStatus(pi.Status, want)
+	}
+
+	amount, err := stripeAmount(payment.Amount, payment.Currency)
+	if err != nil {
+		return "", "", err
+	}
 	params := &stripe.PaymentIntentParams{
-		Amount:   stripe.Int64(amountInCents),
-		Currency: stripe.String(payment.Currency),
+		Amount:        stripe.Int64(amount),
+		Currency:      stripe.String(payment.Currency),
+		CaptureMethod: stripe.String(string(captureMethod)),
 		Metadata: map[string]string{
 			"order_id":   payment.OrderID,
 			"user_id":    payment.UserID,
 			"payment_id": payment.ID,
 		},
 	}
+	if payment.ProviderMethodID != "" {
+		// Charge the saved card attached to the user's customer
+		params.Customer = stripe.String(payment.ProviderCustomerID)
+		params.PaymentMethod = stripe.String(payment.ProviderMethodID)
+	}
+	// Keys of the attempt make a retry after a lost response reuse the
+	// payment intent and replay its confirmation instead of charging again
+	params.SetIdempotencyKey(payment.IdempotencyKey("payment-intent"))
+	params.Context = ctx
 
-	pi, err := paymentintent.New(params)
+	pi, err := p.paymentIntents.New(params)
 	if err != nil {
-		return "", "", fmt.Errorf("stripe payment failed: %w", err)
+		return "", "", stripeFailure("payment", err)
 	}
 
 	// Confirm the payment intent
 	confirmParams := &stripe.PaymentIntentConfirmParams{}
-	confirmedPI, err := paymentintent.Confirm(pi.ID, confirmParams)
+	confirmParams.SetIdempotencyKey(payment.IdempotencyKey("confirm"))
+	confirmParams.Context = ctx
+	confirmedPI, err := p.paymentIntents.Confirm(pi.ID, confirmParams)
+	if err != nil {
+		return pi.ID, "", stripeFailure("confirmation", err)
+	}
+
+	return pi.ID, string(confirmedPI.Status), checkIntentStatus(confirmedPI.Status, want)
+}
+
+// checkIntentStatus maps a confirmed payment intent status to the result the
+// use case expects
+func checkIntentStatus(status, want stripe.PaymentIntentStatus) error {
+	switch status {
+	case want:
+		return nil
+	case stripe.PaymentIntentStatusRequiresAction, stripe.PaymentIntentStatusProcessing:
+		// A payment_intent webhook settles it
+		return domain.ErrPaymentPending
+	}
+	return fmt.Errorf("payment not successful: %s", status)
+}
+
+// RefundPayment refunds part or all of a succeeded payment intent and returns
+// the Stripe refund ID. Refunds still pending at Stripe are accepted; only
+// failed or cancelled refunds are reported as errors.
+func (p *StripeProvider) RefundPayment(ctx context.Context, payment *domain.Payment, r *domain.Refund) (string, error) {
+	amount, err := stripeAmount(r.Amount, r.Currency)
 	if err != nil {
-		return pi.ID, "", fmt.Errorf("stripe confirmation failed: %w", err)
+		return "", err
+	}
+	params := &stripe.RefundParams{
+		PaymentIntent: stripe.String(payment.ProviderID),
+		Amount:        stripe.Int64(amount),
+		Reason:        stripe.String(string(r.Reason)),
+		Metadata: map[string]string{
+			"order_id":   payment.OrderID,
+			"payment_id": payment.ID,
+		},
+	}
+	if r.Note != "" {
+		params.AddMetadata("note", r.Note)
 	}
+	params.SetIdempotencyKey(r.IdempotencyKey)
+	params.Context = ctx
 
-	if confirmedPI.Status != stripe.PaymentIntentStatusSucceeded {
-		return pi.ID, string(confirmedPI.Status), fmt.Errorf("payment not successful: %s", confirmedPI.Status)
+	stripeRefund, err := p.refunds.New(params)
+	if err != nil {
+		return "", stripeFailure("refund", err)
 	}
 
-	return pi.ID, string(confirmedPI.Status), nil
+	switch stripeRefund.Status {
+	case stripe.RefundStatusFailed, stripe.RefundStatusCanceled:
+		return stripeRefund.ID, fmt.Errorf("refund not successful: %s", stripeRefund.Status)
+	}
+	return stripeRefund.ID, nil
 }
 
-// RefundPayment processes a refund using Stripe
-func (p *StripeProvider) RefundPayment(ctx context.Context, providerID string, amount float64) error {
-	// Note: This is a simplified implementation
-	// In production, you would use stripe.Refund API
+// SubmitDisputeEvidence submits the dispute's evidence to the customer's bank.
+// Stripe accepts a single submission, so the request is keyed by dispute.
+func (p *StripeProvider) SubmitDisputeEvidence(ctx context.Context, d *domain.Dispute) error {
+	evidence := d.Evidence
+	params := &stripe.DisputeParams{
+		Evidence: &stripe.DisputeEvidenceParams{
+			ProductDescription:       optionalString(evidence.ProductDescription),
+			CustomerName:             optionalString(evidence.CustomerName),
+			CustomerEmailAddress:     optionalString(evidence.CustomerEmail),
+			CustomerPurchaseIP:       optionalString(evidence.CustomerPurchaseIP),
+			BillingAddress:           optionalString(evidence.BillingAddress),
+			ShippingAddress:          optionalString(evidence.ShippingAddress),
+			ShippingCarrier:          optionalString(evidence.ShippingCarrier),
+			ShippingTrackingNumber:   optionalString(evidence.ShippingTrackingNumber),
+			ShippingDate:             optionalString(evidence.ShippingDate),
+			RefundPolicyDisclosure:   optionalString(evidence.RefundPolicyDisclosure),
+			RefundRefusalExplanation: optionalString(evidence.RefundRefusalExplanation),
+			UncategorizedText:        optionalString(evidence.UncategorizedText),
+		},
+		Submit: stripe.Bool(true),
+	}
+	params.SetIdempotencyKey("dispute-evidence-" + d.ID)
+	params.Context = ctx
+
+	if _, err := p.disputes.Update(d.ProviderDisputeID, params); err != nil {
+		return stripeFailure("dispute evidence", err)
+	}
 	return nil
 }
+
+// optionalString leaves empty values out of a Stripe request
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return stripe.String(value)
+}
+
+// stripeFailure wraps a failed Stripe call. Declines and rejected requests
+// are final. Network failures, rate limiting, idempotency conflicts and Stripe
+// server errors leave the outcome unknown, so they are marked with
+// domain.ErrPaymentOutcomeUnknown to be retried with the same idempotency key.
+func stripeFailure(operation string, err error) error {
+	var stripeErr *stripe.Error
+	if errors.As(err, &stripeErr) {
+		switch {
+		case stripeErr.HTTPStatusCode == http.StatusConflict, stripeErr.HTTPStatusCode == http.StatusTooManyRequests,
+			stripeErr.HTTPStatusCode >= http.StatusInternalServerError:
+		default:
+			return fmt.Errorf("stripe %s failed: %w", operation, err)
+		}
+	}
+	return fmt.Errorf("%w: stripe %s failed: %w", domain.ErrPaymentOutcomeUnknown, operation, err)
+}
+
+// stripeAmount converts an amount to the minor units of its currency, as
+// Stripe expects: cents for USD, yen for JPY. Stripe only accepts amounts of
+// three-decimal currencies in steps of 10 minor units.
+func stripeAmount(amount float64, currency string) (int64, error) {
+	c := domain.CurrencyOf(currency)
+	minor := c.ToMinor(amount)
+	if c.Exponent == 3 && minor%10 != 0 {
+		return 0, fmt.Errorf("%w: stripe takes %s amounts in steps of %s", domain.ErrInvalidAmount, c.Code, c.Format(c.FromMinor(10)))
+	}
+	return minor, nil
+}
@@ -0,0 +1,104 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/infrastructure/stripestub"
+)
+
+// TestStripeProviderCaptureAndRefund authorizes a payment against the Stripe
+// stub, captures part of it and refunds the capture in steps
+func TestStripeProviderCaptureAndRefund(t *testing.T) {
+	stub := httptest.NewServer(stripestub.NewServer("", ""))
+	defer stub.Close()
+	provider := NewStripeProvider("sk_test_stub", stub.URL)
+	ctx := context.Background()
+
+	payment, err := domain.NewPayment("order-1", "user-1", 50, "USD", domain.PaymentMethodStripe)
+	if err != nil {
+		t.Fatalf("NewPayment: %v", err)
+	}
+	payment.ID = "pay-1"
+	if err := payment.MarkAsProcessing(); err != nil {
+		t.Fatalf("MarkAsProcessing: %v", err)
+	}
+
+	providerID, response, err := provider.AuthorizePayment(ctx, payment)
+	if err != nil {
+		t.Fatalf("AuthorizePayment: %v", err)
+	}
+	if response != "requires_capture" {
+		t.Fatalf("AuthorizePayment response = %q, want requires_capture", response)
+	}
+	payment.MarkAsAuthorized(providerID, response, time.Now().Add(7*24*time.Hour))
+
+	if err := payment.Capture(40); err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	if response, err := provider.CapturePayment(ctx, payment); err != nil || response != "succeeded" {
+		t.Fatalf("CapturePayment = %q, %v, want succeeded", response, err)
+	}
+
+	// Each step runs against the state the previous ones left
+	refundIDs := make(map[string]string)
+	steps := []struct {
+		name       string
+		amount     float64
+		requestKey string
+		wantSameAs string // Request key of an earlier refund the step must replay
+		wantErr    bool
+	}{
+		{name: "partial refund", amount: 15, requestKey: "order-item-1"},
+		{name: "retried refund is replayed", amount: 15, requestKey: "order-item-1", wantSameAs: "order-item-1"},
+		{name: "rest of the capture", amount: 25, requestKey: "order-item-2"},
+		{name: "refund beyond the capture", amount: 5, requestKey: "order-item-3", wantErr: true},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			// Built directly so the stub, not the domain, rejects the excess refund
+			refund := &domain.Refund{
+				PaymentID:      payment.ID,
+				Amount:         step.amount,
+				Currency:       payment.Currency,
+				Reason:         domain.RefundReasonRequestedByCustomer,
+				IdempotencyKey: domain.RequestedRefundKey(payment.ID, step.requestKey),
+			}
+
+			refundID, err := provider.RefundPayment(ctx, payment, refund)
+			if step.wantErr {
+				if err == nil {
+					t.Fatalf("RefundPayment returned %s, want an error", refundID)
+				}
+				if errors.Is(err, domain.ErrPaymentOutcomeUnknown) {
+					t.Errorf("RefundPayment error %v is retryable, want a final error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RefundPayment: %v", err)
+			}
+			if refundID == "" {
+				t.Fatal("RefundPayment returned no refund ID")
+			}
+
+			if step.wantSameAs != "" {
+				if refundID != refundIDs[step.wantSameAs] {
+					t.Errorf("refund ID = %s, want the replayed %s", refundID, refundIDs[step.wantSameAs])
+				}
+				return
+			}
+			for key, id := range refundIDs {
+				if id == refundID {
+					t.Errorf("refund ID %s was already issued for %s", refundID, key)
+				}
+			}
+			refundIDs[step.requestKey] = refundID
+		})
+	}
+}
@@ -0,0 +1,770 @@
+// Package stripestub is an in-memory stand-in for the parts of the Stripe API
+// the payment service uses, so payment and refund flows can run offline.
+//
+// It keeps state in memory, honours Idempotency-Key headers and answers with
+// Stripe shaped JSON objects and errors, which is enough for stripe-go to
+// treat it as the real API. When a webhook URL is configured it also delivers
+// signed events, so asynchronous flows such as 3D Secure and disputes can be
+// exercised.
+package stripestub
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+const (
+	// DeclinedPaymentMethod makes confirming a payment intent fail with card_declined,
+	// like Stripe's test payment method of the same name
+	DeclinedPaymentMethod = "pm_card_chargeDeclined"
+
+	// ThreeDSecurePaymentMethod leaves a confirmed payment intent in requires_action
+	// until it is authenticated or failed through the test helper endpoints
+	ThreeDSecurePaymentMethod = "pm_card_threeDSecure2Required"
+
+	// DisputedPaymentMethod charges successfully, then the charge is disputed
+	// as fraudulent, like Stripe's test payment method of the same name
+	DisputedPaymentMethod = "pm_card_createDispute"
+
+	// WinningEvidence and LosingEvidence, submitted as a dispute's
+	// uncategorized_text, close the dispute as won or lost right away
+	WinningEvidence = "winning_evidence"
+	LosingEvidence  = "losing_evidence"
+)
+
+// disputeDelay is how long after the charge a dispute opens. It gives the
+// payment service time to save the charge before hearing about the dispute.
+const disputeDelay = 2 * time.Second
+
+type paymentIntent struct {
+	ID                 string            `json:"id"`
+	Object             string            `json:"object"`
+	Amount             int64             `json:"amount"`
+	AmountReceived     int64             `json:"amount_received"`
+	AmountCapturable   int64             `json:"amount_capturable"`
+	Currency           string            `json:"currency"`
+	Status             string            `json:"status"`
+	CaptureMethod      string            `json:"capture_method"`
+	CancellationReason string            `json:"cancellation_reason,omitempty"`
+	PaymentMethod      string            `json:"payment_method,omitempty"`
+	Metadata           map[string]string `json:"metadata"`
+	Created            int64             `json:"created"`
+
+	refunded int64
+}
+
+type customer struct {
+	ID       string            `json:"id"`
+	Object   string            `json:"object"`
+	Metadata map[string]string `json:"metadata"`
+	Created  int64             `json:"created"`
+}
+
+type card struct {
+	Brand    string `json:"brand"`
+	Last4    string `json:"last4"`
+	ExpMonth int64  `json:"exp_month"`
+	ExpYear  int64  `json:"exp_year"`
+	Funding  string `json:"funding"`
+	Country  string `json:"country"`
+}
+
+type paymentMethod struct {
+	ID       string  `json:"id"`
+	Object   string  `json:"object"`
+	Type     string  `json:"type"`
+	Customer *string `json:"customer"`
+	Card     *card   `json:"card"`
+	Created  int64   `json:"created"`
+
+	testToken string // The test token it was created from, which decides how charges behave
+}
+
+// testCards are the test payment method tokens the stub accepts for attaching
+var testCards = map[string]card{
+	"pm_card_visa":            {Brand: "visa", Last4: "4242", Funding: "credit", Country: "US"},
+	"pm_card_visa_debit":      {Brand: "visa", Last4: "5556", Funding: "debit", Country: "US"},
+	"pm_card_mastercard":      {Brand: "mastercard", Last4: "4444", Funding: "credit", Country: "US"},
+	"pm_card_amex":            {Brand: "amex", Last4: "8431", Funding: "credit", Country: "US"},
+	"pm_card_gb":              {Brand: "visa", Last4: "0000", Funding: "credit", Country: "GB"},
+	DeclinedPaymentMethod:     {Brand: "visa", Last4: "0002", Funding: "credit", Country: "US"},
+	ThreeDSecurePaymentMethod: {Brand: "visa", Last4: "3155", Funding: "credit", Country: "US"},
+	DisputedPaymentMethod:     {Brand: "visa", Last4: "0259", Funding: "credit", Country: "US"},
+}
+
+type refund struct {
+	ID            string            `json:"id"`
+	Object        string            `json:"object"`
+	Amount        int64             `json:"amount"`
+	Currency      string            `json:"currency"`
+	PaymentIntent string            `json:"payment_intent"`
+	Reason        string            `json:"reason,omitempty"`
+	Status        string            `json:"status"`
+	Metadata      map[string]string `json:"metadata"`
+	Created       int64             `json:"created"`
+}
+
+type charge struct {
+	ID             string            `json:"id"`
+	Object         string            `json:"object"`
+	Amount         int64             `json:"amount"`
+	AmountCaptured int64             `json:"amount_captured"`
+	AmountRefunded int64             `json:"amount_refunded"`
+	Currency       string            `json:"currency"`
+	PaymentIntent  string            `json:"payment_intent"`
+	Refunded       bool              `json:"refunded"`
+	Metadata       map[string]string `json:"metadata"`
+	Created        int64             `json:"created"`
+}
+
+type evidenceDetails struct {
+	DueBy           int64 `json:"due_by"`
+	HasEvidence     bool  `json:"has_evidence"`
+	PastDue         bool  `json:"past_due"`
+	SubmissionCount int64 `json:"submission_count"`
+}
+
+type dispute struct {
+	ID              string            `json:"id"`
+	Object          string            `json:"object"`
+	Amount          int64             `json:"amount"`
+	Currency        string            `json:"currency"`
+	Charge          string            `json:"charge"`
+	PaymentIntent   string            `json:"payment_intent"`
+	Reason          string            `json:"reason"`
+	Status          string            `json:"status"`
+	Evidence        map[string]string `json:"evidence"`
+	EvidenceDetails evidenceDetails   `json:"evidence_details"`
+	Metadata        map[string]string `json:"metadata"`
+	Created         int64             `json:"created"`
+}
+
+// balanceTransaction is always served with its source expanded, as the
+// payment service requests it
+type balanceTransaction struct {
+	ID       string      `json:"id"`
+	Object   string      `json:"object"`
+	Amount   int64       `json:"amount"`
+	Currency string      `json:"currency"`
+	Type     string      `json:"type"`
+	Source   interface{} `json:"source"`
+	Created  int64       `json:"created"`
+}
+
+type list struct {
+	Object  string      `json:"object"`
+	Data    interface{} `json:"data"`
+	HasMore bool        `json:"has_more"`
+	URL     string      `json:"url"`
+}
+
+type event struct {
+	ID         string `json:"id"`
+	Object     string `json:"object"`
+	APIVersion string `json:"api_version"`
+	Type       string `json:"type"`
+	Created    int64  `json:"created"`
+	Data       struct {
+		Object interface{} `json:"object"`
+	} `json:"data"`
+}
+
+type stubError struct {
+	status  int
+	Type    string `json:"type"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+	Param   string `json:"param,omitempty"`
+}
+
+type cachedResponse struct {
+	status int
+	body   []byte
+}
+
+// Server serves the stub Stripe API
+type Server struct {
+	mu             sync.Mutex
+	paymentIntents map[string]*paymentIntent
+	refunds        map[string]*refund
+	customers      map[string]*customer
+	paymentMethods map[string]*paymentMethod
+	disputes       map[string]*dispute
+	balance        []*balanceTransaction // In creation order
+	idempotent     map[string]cachedResponse
+
+	webhookURL    string
+	webhookSecret string
+}
+
+// NewServer creates an empty stub server. Events are posted to webhookURL,
+// signed with webhookSecret; an empty URL disables webhook delivery.
+func NewServer(webhookURL, webhookSecret string) *Server {
+	return &Server{
+		paymentIntents: make(map[string]*paymentIntent),
+		refunds:        make(map[string]*refund),
+		customers:      make(map[string]*customer),
+		paymentMethods: make(map[string]*paymentMethod),
+		disputes:       make(map[string]*dispute),
+		idempotent:     make(map[string]cachedResponse),
+		webhookURL:     webhookURL,
+		webhookSecret:  webhookSecret,
+	}
+}
+
+// ServeHTTP routes the supported Stripe endpoints
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Replay the stored response of a retried request, as Stripe does
+	key := r.Header.Get("Idempotency-Key")
+	if key != "" && r.Method == http.MethodPost {
+		key = r.URL.Path + "|" + key
+		if cached, ok := s.idempotent[key]; ok {
+			w.Header().Set("Idempotent-Replayed", "true")
+			writeRaw(w, cached.status, cached.body)
+			return
+		}
+	}
+
+	if err := r.ParseForm(); err != nil {
+		s.respond(w, key, nil, &stubError{status: http.StatusBadRequest, Type: "invalid_request_error", Message: "malformed form body"})
+		return
+	}
+
+	object, stubErr := s.route(r)
+	s.respond(w, key, object, stubErr)
+}
+
+// route dispatches a request and returns the object or error to answer with
+func (s *Server) route(r *http.Request) (interface{}, *stubError) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "v1" {
+		return nil, notFound("unrecognized request URL " + r.URL.Path)
+	}
+
+	switch {
+	case parts[1] == "payment_intents" && len(parts) == 2 && r.Method == http.MethodPost:
+		return s.createPaymentIntent(r)
+	case parts[1] == "payment_intents" && len(parts) == 3 && r.Method == http.MethodGet:
+		return s.getPaymentIntent(parts[2])
+	case parts[1] == "payment_intents" && len(parts) == 4 && parts[3] == "confirm" && r.Method == http.MethodPost:
+		return s.confirmPaymentIntent(parts[2], r)
+	case parts[1] == "payment_intents" && len(parts) == 4 && parts[3] == "capture" && r.Method == http.MethodPost:
+		return s.capturePaymentIntent(parts[2], r)
+	case parts[1] == "payment_intents" && len(parts) == 4 && parts[3] == "cancel" && r.Method == http.MethodPost:
+		return s.cancelPaymentIntent(parts[2], r)
+	case parts[1] == "test_helpers" && len(parts) == 5 && parts[2] == "payment_intents" && r.Method == http.MethodPost:
+		return s.completeAction(parts[3], parts[4])
+	case parts[1] == "customers" && len(parts) == 2 && r.Method == http.MethodPost:
+		c := &customer{ID: newID("cus"), Object: "customer", Metadata: metadata(r), Created: time.Now().Unix()}
+		s.customers[c.ID] = c
+		return c, nil
+	case parts[1] == "payment_methods" && len(parts) == 4 && parts[3] == "attach" && r.Method == http.MethodPost:
+		return s.attachPaymentMethod(parts[2], r)
+	case parts[1] == "payment_methods" && len(parts) == 4 && parts[3] == "detach" && r.Method == http.MethodPost:
+		return s.detachPaymentMethod(parts[2])
+	case parts[1] == "refunds" && len(parts) == 2 && r.Method == http.MethodPost:
+		return s.createRefund(r)
+	case parts[1] == "refunds" && len(parts) == 3 && r.Method == http.MethodGet:
+		if re, ok := s.refunds[parts[2]]; ok {
+			return re, nil
+		}
+		return nil, notFound("No such refund: '" + parts[2] + "'")
+	case parts[1] == "balance_transactions" && len(parts) == 2 && r.Method == http.MethodGet:
+		return s.listBalanceTransactions(r)
+	case parts[1] == "disputes" && len(parts) == 3 && r.Method == http.MethodGet:
+		if dp, ok := s.disputes[parts[2]]; ok {
+			return dp, nil
+		}
+		return nil, notFound("No such dispute: '" + parts[2] + "'")
+	case parts[1] == "disputes" && len(parts) == 3 && r.Method == http.MethodPost:
+		return s.updateDispute(parts[2], r)
+	default:
+		return nil, notFound("unrecognized request URL " + r.Method + " " + r.URL.Path)
+	}
+}
+
+func (s *Server) createPaymentIntent(r *http.Request) (interface{}, *stubError) {
+	amount, err := strconv.ParseInt(r.PostForm.Get("amount"), 10, 64)
+	if err != nil || amount <= 0 {
+		return nil, invalidParam("amount", "Invalid positive integer")
+	}
+	currency := strings.ToLower(r.PostForm.Get("currency"))
+	if currency == "" {
+		return nil, invalidParam("currency", "Missing required param: currency.")
+	}
+
+	captureMethod := r.PostForm.Get("capture_method")
+	switch captureMethod {
+	case "":
+		captureMethod = "automatic"
+	case "automatic", "manual":
+	default:
+		return nil, invalidParam("capture_method", "Invalid capture_method: must be one of automatic or manual")
+	}
+
+	pi := &paymentIntent{
+		ID:            newID("pi"),
+		Object:        "payment_intent",
+		Amount:        amount,
+		Currency:      currency,
+		Status:        "requires_confirmation",
+		CaptureMethod: captureMethod,
+		PaymentMethod: r.PostForm.Get("payment_method"),
+		Metadata:      metadata(r),
+		Created:       time.Now().Unix(),
+	}
+	s.paymentIntents[pi.ID] = pi
+	return pi, nil
+}
+
+func (s *Server) getPaymentIntent(id string) (interface{}, *stubError) {
+	pi, ok := s.paymentIntents[id]
+	if !ok {
+		return nil, notFound("No such payment_intent: '" + id + "'")
+	}
+	return pi, nil
+}
+
+func (s *Server) confirmPaymentIntent(id string, r *http.Request) (interface{}, *stubError) {
+	pi, ok := s.paymentIntents[id]
+	if !ok {
+		return nil, notFound("No such payment_intent: '" + id + "'")
+	}
+	if pi.Status != "requires_confirmation" && pi.Status != "requires_payment_method" {
+		return nil, unexpectedState(pi, "cannot be confirmed")
+	}
+	if method := r.PostForm.Get("payment_method"); method != "" {
+		pi.PaymentMethod = method
+	}
+	behaviour := s.behaviour(pi)
+	if behaviour == DeclinedPaymentMethod {
+		pi.Status = "requires_payment_method"
+		return nil, &stubError{status: http.StatusPaymentRequired, Type: "card_error", Code: "card_declined", Message: "Your card was declined."}
+	}
+	if behaviour == ThreeDSecurePaymentMethod {
+		pi.Status = "requires_action"
+		return pi, nil
+	}
+
+	s.succeed(pi)
+	return pi, nil
+}
+
+// succeed completes a confirmed payment intent: automatic capture collects
+// the funds, manual capture leaves them held until captured
+func (s *Server) succeed(pi *paymentIntent) {
+	if pi.CaptureMethod == "manual" {
+		pi.Status = "requires_capture"
+		pi.AmountCapturable = pi.Amount
+		s.emit("payment_intent.amount_capturable_updated", pi)
+		return
+	}
+	pi.Status = "succeeded"
+	pi.AmountReceived = pi.Amount
+	s.recordCharge(pi)
+	s.emit("payment_intent.succeeded", pi)
+	s.disputeLater(pi)
+}
+
+// behaviour returns the test token deciding how the payment intent's charge
+// behaves, looking through saved payment methods to the token they came from
+func (s *Server) behaviour(pi *paymentIntent) string {
+	if pm, ok := s.paymentMethods[pi.PaymentMethod]; ok {
+		return pm.testToken
+	}
+	return pi.PaymentMethod
+}
+
+// attachPaymentMethod attaches a test card token to a customer. Like Stripe,
+// attaching a test token creates a new payment method object.
+func (s *Server) attachPaymentMethod(token string, r *http.Request) (interface{}, *stubError) {
+	customerID := r.PostForm.Get("customer")
+	if _, ok := s.customers[customerID]; !ok {
+		return nil, invalidParam("customer", "No such customer: '"+customerID+"'")
+	}
+	if pm, ok := s.paymentMethods[token]; ok {
+		if pm.Customer != nil && *pm.Customer != customerID {
+			return nil, invalidParam("payment_method", "The payment method you provided has already been attached to a customer.")
+		}
+		pm.Customer = &customerID
+		return pm, nil
+	}
+
+	details, ok := testCards[token]
+	if !ok {
+		return nil, &stubError{status: http.StatusNotFound, Type: "invalid_request_error", Code: "resource_missing", Param: "payment_method",
+			Message: "No such PaymentMethod: '" + token + "'"}
+	}
+	details.ExpMonth = 12
+	details.ExpYear = int64(time.Now().Year() + 3)
+
+	pm := &paymentMethod{
+		ID:        newID("pm"),
+		Object:    "payment_method",
+		Type:      "card",
+		Customer:  &customerID,
+		Card:      &details,
+		Created:   time.Now().Unix(),
+		testToken: token,
+	}
+	s.paymentMethods[pm.ID] = pm
+	return pm, nil
+}
+
+func (s *Server) detachPaymentMethod(id string) (interface{}, *stubError) {
+	pm, ok := s.paymentMethods[id]
+	if !ok {
+		return nil, notFound("No such PaymentMethod: '" + id + "'")
+	}
+	if pm.Customer == nil {
+		return nil, invalidParam("payment_method", "The payment method "+id+" is not attached to a customer.")
+	}
+	pm.Customer = nil
+	return pm, nil
+}
+
+func (s *Server) capturePaymentIntent(id string, r *http.Request) (interface{}, *stubError) {
+	pi, ok := s.paymentIntents[id]
+	if !ok {
+		return nil, notFound("No such payment_intent: '" + id + "'")
+	}
+	if pi.Status != "requires_capture" {
+		return nil, unexpectedState(pi, "cannot be captured")
+	}
+
+	amount := pi.AmountCapturable
+	if value := r.PostForm.Get("amount_to_capture"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			return nil, invalidParam("amount_to_capture", "Invalid positive integer")
+		}
+		if parsed > pi.AmountCapturable {
+			return nil, invalidParam("amount_to_capture", fmt.Sprintf("Amount to capture (%d) is greater than the capturable amount (%d)", parsed, pi.AmountCapturable))
+		}
+		amount = parsed
+	}
+
+	pi.Status = "succeeded"
+	pi.AmountReceived = amount
+	pi.AmountCapturable = 0
+	s.recordCharge(pi)
+	s.emit("payment_intent.succeeded", pi)
+	s.disputeLater(pi)
+	return pi, nil
+}
+
+func (s *Server) cancelPaymentIntent(id string, r *http.Request) (interface{}, *stubError) {
+	pi, ok := s.paymentIntents[id]
+	if !ok {
+		return nil, notFound("No such payment_intent: '" + id + "'")
+	}
+	if pi.Status == "succeeded" || pi.Status == "canceled" {
+		return nil, unexpectedState(pi, "cannot be canceled")
+	}
+
+	pi.Status = "canceled"
+	pi.AmountCapturable = 0
+	pi.CancellationReason = r.PostForm.Get("cancellation_reason")
+	s.emit("payment_intent.canceled", pi)
+	return pi, nil
+}
+
+// completeAction finishes a requires_action payment intent as the customer
+// would: "authenticate" succeeds it and "fail_authentication" fails it. Either
+// outcome is reported by webhook only, like the real 3D Secure flow.
+func (s *Server) completeAction(id, action string) (interface{}, *stubError) {
+	pi, ok := s.paymentIntents[id]
+	if !ok {
+		return nil, notFound("No such payment_intent: '" + id + "'")
+	}
+	if pi.Status != "requires_action" {
+		return nil, unexpectedState(pi, "does not require action")
+	}
+
+	switch action {
+	case "authenticate":
+		s.succeed(pi)
+	case "fail_authentication":
+		pi.Status = "requires_payment_method"
+		s.emit("payment_intent.payment_failed", map[string]interface{}{
+			"id":       pi.ID,
+			"object":   "payment_intent",
+			"status":   pi.Status,
+			"metadata": pi.Metadata,
+			"last_payment_error": map[string]string{
+				"type":    "card_error",
+				"code":    "payment_intent_authentication_failure",
+				"message": "The provided PaymentMethod has failed authentication.",
+			},
+		})
+	default:
+		return nil, notFound("unrecognized test helper action " + action)
+	}
+	return pi, nil
+}
+
+func (s *Server) createRefund(r *http.Request) (interface{}, *stubError) {
+	piID := r.PostForm.Get("payment_intent")
+	pi, ok := s.paymentIntents[piID]
+	if !ok {
+		return nil, invalidParam("payment_intent", "No such payment_intent: '"+piID+"'")
+	}
+	if pi.Status != "succeeded" {
+		return nil, &stubError{status: http.StatusBadRequest, Type: "invalid_request_error", Code: "charge_not_refundable",
+			Message: "This PaymentIntent does not have a successful charge to refund."}
+	}
+
+	remaining := pi.AmountReceived - pi.refunded
+	if remaining == 0 {
+		return nil, &stubError{status: http.StatusBadRequest, Type: "invalid_request_error", Code: "charge_already_refunded",
+			Message: "Charge has already been refunded."}
+	}
+	amount := remaining
+	if value := r.PostForm.Get("amount"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			return nil, invalidParam("amount", "Invalid positive integer")
+		}
+		if parsed > remaining {
+			return nil, invalidParam("amount", fmt.Sprintf("Refund amount (%d) is greater than unrefunded amount on charge (%d)", parsed, remaining))
+		}
+		amount = parsed
+	}
+
+	reason := r.PostForm.Get("reason")
+	switch reason {
+	case "", "duplicate", "fraudulent", "requested_by_customer":
+	default:
+		return nil, invalidParam("reason", "Invalid reason: must be one of duplicate, fraudulent, or requested_by_customer")
+	}
+
+	re := &refund{
+		ID:            newID("re"),
+		Object:        "refund",
+		Amount:        amount,
+		Currency:      pi.Currency,
+		PaymentIntent: pi.ID,
+		Reason:        reason,
+		Status:        "succeeded",
+		Metadata:      metadata(r),
+		Created:       time.Now().Unix(),
+	}
+	pi.refunded += amount
+	s.refunds[re.ID] = re
+	s.balance = append(s.balance, &balanceTransaction{
+		ID: newID("txn"), Object: "balance_transaction", Amount: -amount, Currency: pi.Currency,
+		Type: "refund", Source: re, Created: re.Created,
+	})
+	s.emit("charge.refunded", chargeOf(pi))
+	return re, nil
+}
+
+// disputeLater opens a dispute against the charge of a payment intent paid
+// with DisputedPaymentMethod, once disputeDelay has passed
+func (s *Server) disputeLater(pi *paymentIntent) {
+	if s.behaviour(pi) != DisputedPaymentMethod {
+		return
+	}
+	time.AfterFunc(disputeDelay, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		dp := &dispute{
+			ID:            newID("dp"),
+			Object:        "dispute",
+			Amount:        pi.AmountReceived,
+			Currency:      pi.Currency,
+			Charge:        chargeOf(pi).ID,
+			PaymentIntent: pi.ID,
+			Reason:        "fraudulent",
+			Status:        "needs_response",
+			Evidence:      map[string]string{},
+			Metadata:      map[string]string{},
+			Created:       time.Now().Unix(),
+		}
+		dp.EvidenceDetails.DueBy = time.Now().Add(7 * 24 * time.Hour).Unix()
+		s.disputes[dp.ID] = dp
+		s.emit("charge.dispute.created", dp)
+	})
+}
+
+// updateDispute saves the evidence[...] parameters of a dispute. With
+// submit=true the evidence goes to the bank and the dispute is under review;
+// WinningEvidence or LosingEvidence then decide it right away.
+func (s *Server) updateDispute(id string, r *http.Request) (interface{}, *stubError) {
+	dp, ok := s.disputes[id]
+	if !ok {
+		return nil, notFound("No such dispute: '" + id + "'")
+	}
+	if dp.Status != "needs_response" {
+		return nil, &stubError{status: http.StatusBadRequest, Type: "invalid_request_error",
+			Message: "This dispute is " + dp.Status + " and its evidence can no longer be updated."}
+	}
+
+	for name, v := range r.PostForm {
+		if strings.HasPrefix(name, "evidence[") && strings.HasSuffix(name, "]") && len(v) > 0 {
+			dp.Evidence[name[len("evidence["):len(name)-1]] = v[0]
+		}
+	}
+	dp.EvidenceDetails.HasEvidence = len(dp.Evidence) > 0
+	if r.PostForm.Get("submit") != "true" {
+		return dp, nil
+	}
+
+	dp.Status = "under_review"
+	dp.EvidenceDetails.SubmissionCount++
+	s.emit("charge.dispute.updated", dp)
+	switch dp.Evidence["uncategorized_text"] {
+	case WinningEvidence:
+		dp.Status = "won"
+		s.emit("charge.dispute.closed", dp)
+	case LosingEvidence:
+		dp.Status = "lost"
+		s.emit("charge.dispute.closed", dp)
+	}
+	return dp, nil
+}
+
+// chargeOf returns the charge of a succeeded payment intent
+func chargeOf(pi *paymentIntent) *charge {
+	return &charge{
+		ID:             "ch_" + strings.TrimPrefix(pi.ID, "pi_"),
+		Object:         "charge",
+		Amount:         pi.AmountReceived,
+		AmountCaptured: pi.AmountReceived,
+		AmountRefunded: pi.refunded,
+		Currency:       pi.Currency,
+		PaymentIntent:  pi.ID,
+		Refunded:       pi.refunded == pi.AmountReceived,
+		Metadata:       pi.Metadata,
+		Created:        pi.Created,
+	}
+}
+
+// recordCharge adds the balance transaction of a payment intent that just
+// collected its funds
+func (s *Server) recordCharge(pi *paymentIntent) {
+	s.balance = append(s.balance, &balanceTransaction{
+		ID: newID("txn"), Object: "balance_transaction", Amount: pi.AmountReceived, Currency: pi.Currency,
+		Type: "charge", Source: chargeOf(pi), Created: time.Now().Unix(),
+	})
+}
+
+// listBalanceTransactions lists balance transactions, newest first, filtered
+// by created[gte] and created[lt]. Everything is returned in one page.
+func (s *Server) listBalanceTransactions(r *http.Request) (interface{}, *stubError) {
+	var from, to int64 = 0, 1<<63 - 1
+	for param, bound := range map[string]*int64{"created[gte]": &from, "created[lt]": &to} {
+		if value := r.Form.Get(param); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, invalidParam(param, "Invalid integer")
+			}
+			*bound = parsed
+		}
+	}
+
+	data := []*balanceTransaction{}
+	for i := len(s.balance) - 1; i >= 0; i-- {
+		if txn := s.balance[i]; txn.Created >= from && txn.Created < to {
+			data = append(data, txn)
+		}
+	}
+	return &list{Object: "list", Data: data, URL: "/v1/balance_transactions"}, nil
+}
+
+// emit delivers a signed event to the webhook URL in the background. The
+// object is encoded right away so later changes do not leak into the event.
+func (s *Server) emit(eventType string, object interface{}) {
+	if s.webhookURL == "" {
+		return
+	}
+
+	evt := event{ID: newID("evt"), Object: "event", APIVersion: "2023-10-16", Type: eventType, Created: time.Now().Unix()}
+	evt.Data.Object = object
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return
+	}
+	signed := webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{Payload: payload, Secret: s.webhookSecret})
+
+	go func() {
+		req, err := http.NewRequest(http.MethodPost, s.webhookURL, bytes.NewReader(payload))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Stripe-Signature", signed.Header)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return
+		}
+		resp.Body.Close()
+	}()
+}
+
+// respond writes the object or error and remembers it for idempotent replays
+func (s *Server) respond(w http.ResponseWriter, key string, object interface{}, stubErr *stubError) {
+	status := http.StatusOK
+	var body []byte
+	if stubErr != nil {
+		status = stubErr.status
+		body, _ = json.Marshal(map[string]*stubError{"error": stubErr})
+	} else {
+		body, _ = json.Marshal(object)
+	}
+	if key != "" {
+		s.idempotent[key] = cachedResponse{status: status, body: body}
+	}
+	writeRaw(w, status, body)
+}
+
+func writeRaw(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// metadata collects the metadata[key]=value form parameters
+func metadata(r *http.Request) map[string]string {
+	values := map[string]string{}
+	for name, v := range r.PostForm {
+		if strings.HasPrefix(name, "metadata[") && strings.HasSuffix(name, "]") && len(v) > 0 {
+			values[name[len("metadata["):len(name)-1]] = v[0]
+		}
+	}
+	return values
+}
+
+func unexpectedState(pi *paymentIntent, what string) *stubError {
+	return &stubError{status: http.StatusBadRequest, Type: "invalid_request_error", Code: "payment_intent_unexpected_state",
+		Message: "This PaymentIntent's status is " + pi.Status + " and " + what + "."}
+}
+
+func notFound(message string) *stubError {
+	return &stubError{status: http.StatusNotFound, Type: "invalid_request_error", Code: "resource_missing", Message: message}
+}
+
+func invalidParam(param, message string) *stubError {
+	return &stubError{status: http.StatusBadRequest, Type: "invalid_request_error", Code: "parameter_invalid", Param: param, Message: message}
+}
+
+// newID returns a Stripe style object ID with the given prefix
+func newID(prefix string) string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return prefix + "_" + hex.EncodeToString(b)
+}
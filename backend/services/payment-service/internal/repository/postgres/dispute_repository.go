@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/infrastructure/models"
+	"gorm.io/gorm"
+)
+
+// DisputeRepository implements the dispute repository using PostgreSQL.
+// Disputes are created and changed by provider events through
+// PaymentRepository.RecordEvent, with the row of their payment locked; this
+// repository reads them and saves the changes made by the merchant.
+type DisputeRepository struct {
+	db *gorm.DB
+}
+
+// NewDisputeRepository creates a new PostgreSQL dispute repository
+func NewDisputeRepository(db *gorm.DB) *DisputeRepository {
+	return &DisputeRepository{db: db}
+}
+
+// FindByID finds a dispute by ID
+func (r *DisputeRepository) FindByID(ctx context.Context, id string) (*domain.Dispute, error) {
+	return r.find(r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+// FindByProviderDisputeID finds a dispute by the provider's dispute ID
+func (r *DisputeRepository) FindByProviderDisputeID(ctx context.Context, providerDisputeID string) (*domain.Dispute, error) {
+	return r.find(r.db.WithContext(ctx).Where("provider_dispute_id = ?", providerDisputeID))
+}
+
+// List finds the disputes matching the filter, those with the nearest
+// evidence deadline first
+func (r *DisputeRepository) List(ctx context.Context, filter domain.DisputeFilter, limit, offset int) ([]domain.Dispute, error) {
+	query := r.db.WithContext(ctx)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.PaymentID != "" {
+		query = query.Where("payment_id = ?", filter.PaymentID)
+	}
+	if filter.OrderID != "" {
+		query = query.Where("order_id = ?", filter.OrderID)
+	}
+	if filter.DueBefore != nil {
+		query = query.Where("evidence_due_by < ?", *filter.DueBefore)
+	}
+
+	var modelList []models.Dispute
+	err := query.
+		Order("evidence_due_by ASC NULLS LAST, opened_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&modelList).Error
+	if err != nil {
+		return nil, err
+	}
+	return toDomainDisputes(modelList), nil
+}
+
+// FindUnflagged finds disputes opened since the given time whose order has
+// not been flagged yet, oldest first
+func (r *DisputeRepository) FindUnflagged(ctx context.Context, openedSince time.Time, limit int) ([]domain.Dispute, error) {
+	var modelList []models.Dispute
+	err := r.db.WithContext(ctx).
+		Where("order_flagged_at IS NULL AND opened_at >= ?", openedSince).
+		Order("opened_at ASC").
+		Limit(limit).
+		Find(&modelList).Error
+	if err != nil {
+		return nil, err
+	}
+	return toDomainDisputes(modelList), nil
+}
+
+// SaveEvidence saves the evidence submitted for a dispute. The status only
+// moves to UNDER_REVIEW if no provider event has moved it on in the meantime.
+func (r *DisputeRepository) SaveEvidence(ctx context.Context, dispute *domain.Dispute) error {
+	model := &models.Dispute{}
+	if err := model.FromDomain(dispute); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Model(&models.Dispute{}).Where("id = ?", dispute.ID).Updates(map[string]interface{}{
+		"evidence":              model.Evidence,
+		"evidence_submitted_at": model.EvidenceSubmittedAt,
+		"status":                gorm.Expr("CASE WHEN status = ? THEN ? ELSE status END", domain.DisputeStatusNeedsResponse, model.Status),
+		"updated_at":            model.UpdatedAt,
+	}).Error
+}
+
+// MarkOrderFlagged saves when the order of a dispute was flagged
+func (r *DisputeRepository) MarkOrderFlagged(ctx context.Context, dispute *domain.Dispute) error {
+	return r.db.WithContext(ctx).Model(&models.Dispute{}).Where("id = ?", dispute.ID).Updates(map[string]interface{}{
+		"order_flagged_at": dispute.OrderFlaggedAt,
+		"updated_at":       dispute.UpdatedAt,
+	}).Error
+}
+
+// find loads the first dispute of the query
+func (r *DisputeRepository) find(query *gorm.DB) (*domain.Dispute, error) {
+	var model models.Dispute
+	if err := query.First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDisputeNotFound
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// saveDispute creates a dispute, setting its generated ID, or saves the
+// fields a provider event changes, within the given transaction. Evidence and
+// order flagging are left alone, as they are saved outside provider events.
+func saveDispute(tx *gorm.DB, dispute *domain.Dispute) error {
+	model := &models.Dispute{}
+	if err := model.FromDomain(dispute); err != nil {
+		return err
+	}
+	if dispute.ID == "" {
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		dispute.ID = model.ID
+		return nil
+	}
+	return tx.Model(model).
+		Select("status", "provider_status", "amount", "evidence_due_by", "closed_at", "updated_at").
+		Updates(model).Error
+}
+
+// toDomainDisputes converts GORM disputes to domain disputes
+func toDomainDisputes(modelList []models.Dispute) []domain.Dispute {
+	disputes := make([]domain.Dispute, len(modelList))
+	for i, model := range modelList {
+		disputes[i] = *model.ToDomain()
+	}
+	return disputes
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/infrastructure/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// pendingOutboxCondition keeps back every event of a payment while an earlier
+// one is still unpublished, so a payment's events go out in order
+const pendingOutboxCondition = `published_at IS NULL AND failed_at IS NULL
+	AND NOT EXISTS (SELECT 1 FROM payment_outbox earlier
+		WHERE earlier.aggregate_id = payment_outbox.aggregate_id AND earlier.created_at < payment_outbox.created_at
+		AND earlier.published_at IS NULL AND earlier.failed_at IS NULL)`
+
+// OutboxRepository implements the payment event outbox using PostgreSQL.
+// Events are written by PaymentRepository in the transaction that saves the
+// payment; this repository hands them to the relay. The outbox follows the
+// same protocol as order-service's, so both services' events reach
+// event-service with the same ordering and retry guarantees.
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new PostgreSQL outbox repository
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// ProcessPending locks up to limit pending events, oldest first, and hands
+// them to publish one by one, acknowledging each published event in the same
+// transaction. Only the oldest pending event of each payment is picked up and
+// rows locked by another relay are skipped, so relays never reorder the events
+// of a payment. A failed event holds back the later events of its payment
+// until its retry, and is dead-lettered after domain.MaxOutboxAttempts.
+//
+// Parameters:
+//   - ctx: Context for query cancellation
+//   - limit: Most events to publish
+//   - publish: Publishes one event to the message broker
+//
+// Returns:
+//   - int: Number of events published
+//   - error: The first publish failure, or the database error if reading or
+//     acknowledging the events fails
+func (r *OutboxRepository) ProcessPending(ctx context.Context, limit int, publish func(ctx context.Context, event *domain.OutboxEvent) error) (int, error) {
+	published := 0
+	var publishErr error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var modelList []models.OutboxEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where(pendingOutboxCondition).
+			Where("next_attempt_at IS NULL OR next_attempt_at <= ?", now).
+			Order("created_at ASC").
+			Limit(limit).
+			Find(&modelList).Error; err != nil {
+			return fmt.Errorf("failed to get pending outbox events: %w", err)
+		}
+
+		for i := range modelList {
+			model := &modelList[i]
+			if err := publish(ctx, model.ToDomain()); err != nil {
+				if publishErr == nil {
+					publishErr = fmt.Errorf("failed to publish outbox event %s: %w", model.ID, err)
+				}
+				if err := recordPublishFailure(tx, model, err, now); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := tx.Model(model).Updates(map[string]interface{}{
+				"attempts":     gorm.Expr("attempts + 1"),
+				"published_at": now,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to acknowledge outbox event %s: %w", model.ID, err)
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, publishErr
+}
+
+// recordPublishFailure schedules the next attempt of an event that failed to
+// publish, or dead-letters it once it used up domain.MaxOutboxAttempts
+func recordPublishFailure(tx *gorm.DB, model *models.OutboxEvent, publishErr error, now time.Time) error {
+	attempts := model.Attempts + 1
+	updates := map[string]interface{}{
+		"attempts":   attempts,
+		"last_error": publishErr.Error(),
+	}
+	if attempts >= domain.MaxOutboxAttempts {
+		updates["failed_at"] = now
+	} else {
+		updates["next_attempt_at"] = now.Add(domain.OutboxRetryDelay(attempts))
+	}
+
+	if err := tx.Model(model).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to record failure of outbox event %s: %w", model.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/infrastructure/models"
+	"gorm.io/gorm"
+)
+
+// PaymentMethodRepository implements the saved payment method repository using PostgreSQL
+type PaymentMethodRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentMethodRepository creates a new PostgreSQL payment method repository
+func NewPaymentMethodRepository(db *gorm.DB) *PaymentMethodRepository {
+	return &PaymentMethodRepository{db: db}
+}
+
+// Create saves a payment method. A user's first method, or one saved as
+// default, becomes the default and clears the flag on the others.
+func (r *PaymentMethodRepository) Create(ctx context.Context, method *domain.SavedPaymentMethod) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.SavedPaymentMethod{}).Where("user_id = ?", method.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			method.IsDefault = true
+		}
+		if method.IsDefault {
+			if err := clearDefault(tx, method.UserID); err != nil {
+				return err
+			}
+		}
+
+		model := &models.SavedPaymentMethod{}
+		model.FromDomain(method)
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		method.ID = model.ID
+		return nil
+	})
+}
+
+// FindByID finds a payment method by ID
+func (r *PaymentMethodRepository) FindByID(ctx context.Context, id string) (*domain.SavedPaymentMethod, error) {
+	var model models.SavedPaymentMethod
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrPaymentMethodNotFound
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// FindByUserID lists a user's payment methods, default first
+func (r *PaymentMethodRepository) FindByUserID(ctx context.Context, userID string) ([]domain.SavedPaymentMethod, error) {
+	var modelList []models.SavedPaymentMethod
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_default DESC, created_at DESC").
+		Find(&modelList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	methods := make([]domain.SavedPaymentMethod, len(modelList))
+	for i, model := range modelList {
+		methods[i] = *model.ToDomain()
+	}
+	return methods, nil
+}
+
+// FindCustomerID returns the provider customer ID the user's methods are
+// attached to, including removed ones, or "" if the user has none yet
+func (r *PaymentMethodRepository) FindCustomerID(ctx context.Context, userID string) (string, error) {
+	var model models.SavedPaymentMethod
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		First(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return model.ProviderCustomerID, nil
+}
+
+// Delete removes a payment method. If it was the default, the user's most
+// recently added remaining method becomes the default.
+func (r *PaymentMethodRepository) Delete(ctx context.Context, method *domain.SavedPaymentMethod) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.SavedPaymentMethod{}, "id = ?", method.ID).Error; err != nil {
+			return err
+		}
+		if !method.IsDefault {
+			return nil
+		}
+
+		var next models.SavedPaymentMethod
+		err := tx.Where("user_id = ?", method.UserID).Order("created_at DESC").First(&next).Error
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&next).Update("is_default", true).Error
+	})
+}
+
+// clearDefault unsets the default flag on all of a user's payment methods
+func clearDefault(tx *gorm.DB, userID string) error {
+	return tx.Model(&models.SavedPaymentMethod{}).
+		Where("user_id = ? AND is_default", userID).
+		Update("is_default", false).Error
+}
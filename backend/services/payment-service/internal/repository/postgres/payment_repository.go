@@ -2,12 +2,23 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
 	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/infrastructure/models"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// errEventAlreadyRecorded rolls back a transaction that lost the race to record an event
+var errEventAlreadyRecorded = errors.New("provider event already recorded")
+
+// activePaymentIndex is the partial unique index allowing one active payment per order
+const activePaymentIndex = "idx_payments_active_order"
+
 // PaymentRepository implements the payment repository using PostgreSQL
 type PaymentRepository struct {
 	db *gorm.DB
@@ -27,11 +38,16 @@ func NewPaymentRepository(db *gorm.DB) *PaymentRepository {
 //   - payment: Domain payment to persist (ID will be auto-generated)
 //
 // Returns:
-//   - error: Database error if the insert operation fails
+//   - error: ErrActivePaymentExists if the order already has an active
+//     payment, or the database error if the insert operation fails
 func (r *PaymentRepository) Create(ctx context.Context, payment *domain.Payment) error {
 	model := &models.Payment{}
 	model.FromDomain(payment)
-	return r.db.WithContext(ctx).Create(model).Error
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return translateError(err)
+	}
+	payment.ID = model.ID
+	return nil
 }
 
 // FindByID finds a payment by ID
@@ -46,10 +62,27 @@ func (r *PaymentRepository) FindByID(ctx context.Context, id string) (*domain.Pa
 	return model.ToDomain(), nil
 }
 
-// FindByOrderID finds a payment by order ID
+// FindByOrderID finds the active payment of an order, or its latest payment
+// if every payment of the order failed or was cancelled
 func (r *PaymentRepository) FindByOrderID(ctx context.Context, orderID string) (*domain.Payment, error) {
 	var model models.Payment
-	if err := r.db.WithContext(ctx).First(&model, "order_id = ?", orderID).Error; err != nil {
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("status IN ('FAILED', 'CANCELLED'), created_at DESC").
+		First(&model).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrPaymentNotFound
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// FindByProviderID finds a payment by its provider payment ID
+func (r *PaymentRepository) FindByProviderID(ctx context.Context, providerID string) (*domain.Payment, error) {
+	var model models.Payment
+	if err := r.db.WithContext(ctx).First(&model, "provider_id = ?", providerID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrPaymentNotFound
 		}
@@ -81,9 +114,252 @@ func (r *PaymentRepository) FindByUserID(ctx context.Context, userID string, lim
 	return payments, nil
 }
 
-// Update updates a payment
+// CountUserAttemptsSince counts the attempts to charge payments of a user
+// started since the given time, retries of failed payments included
+func (r *PaymentRepository) CountUserAttemptsSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.PaymentAttempt{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	return int(count), err
+}
+
+// CountMethodAttemptsSince counts the attempts to charge payments with a
+// saved payment method started since the given time, retries included
+func (r *PaymentRepository) CountMethodAttemptsSince(ctx context.Context, paymentMethodID string, since time.Time) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.PaymentAttempt{}).
+		Where("payment_method_id = ? AND created_at >= ?", paymentMethodID, since).
+		Count(&count).Error
+	return int(count), err
+}
+
+// FindExpiredAuthorizations finds AUTHORIZED payments whose hold lapses
+// before the given time, oldest first
+func (r *PaymentRepository) FindExpiredAuthorizations(ctx context.Context, before time.Time, limit int) ([]domain.Payment, error) {
+	var modelList []models.Payment
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND authorization_expires_at <= ?", string(domain.PaymentStatusAuthorized), before).
+		Order("authorization_expires_at ASC").
+		Limit(limit).
+		Find(&modelList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([]domain.Payment, len(modelList))
+	for i, model := range modelList {
+		payments[i] = *model.ToDomain()
+	}
+	return payments, nil
+}
+
+// Update updates a payment and writes its pending events to the outbox in
+// the same transaction. The payment is only written if it is still at the
+// version it was loaded at; otherwise domain.ErrPaymentConflict is returned
+// and nothing is saved.
 func (r *PaymentRepository) Update(ctx context.Context, payment *domain.Payment) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return savePayment(tx, payment)
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	payment.MarkPersisted()
+	return nil
+}
+
+// UpdateLocked loads a payment with its row locked and lets change modify it,
+// e.g. cancel it at the provider and locally. The payment is saved before the
+// lock is released if change reports it changed, so no other writer can
+// change the payment between the provider call and the save.
+func (r *PaymentRepository) UpdateLocked(ctx context.Context, id string, change func(payment *domain.Payment) (bool, error)) (*domain.Payment, error) {
+	var payment *domain.Payment
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var model models.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return domain.ErrPaymentNotFound
+			}
+			return err
+		}
+		payment = model.ToDomain()
+
+		changed, err := change(payment)
+		if err != nil || !changed {
+			return err
+		}
+		return savePayment(tx, payment)
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	payment.MarkPersisted()
+	return payment, nil
+}
+
+// RefundLocked loads a payment with its row locked and lets issue refund it.
+// The refund issue returns is recorded together with the refunded amount of
+// the payment before the lock is released, so concurrent refunds of a payment
+// run one after the other and each sees what the previous ones refunded. A
+// nil refund saves nothing.
+func (r *PaymentRepository) RefundLocked(ctx context.Context, id string, issue func(payment *domain.Payment) (*domain.Refund, error)) (*domain.Refund, error) {
+	var refund *domain.Refund
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var model models.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return domain.ErrPaymentNotFound
+			}
+			return err
+		}
+		payment := model.ToDomain()
+
+		var err error
+		if refund, err = issue(payment); err != nil || refund == nil {
+			return err
+		}
+		if err := savePayment(tx, payment); err != nil {
+			return err
+		}
+
+		refundModel := &models.Refund{}
+		refundModel.FromDomain(refund)
+		if err := tx.Create(refundModel).Error; err != nil {
+			return err
+		}
+		refund.ID = refundModel.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// FindRefundByIdempotencyKey finds a refund by the idempotency key it was issued with
+func (r *PaymentRepository) FindRefundByIdempotencyKey(ctx context.Context, idempotencyKey string) (*domain.Refund, error) {
+	var model models.Refund
+	if err := r.db.WithContext(ctx).First(&model, "idempotency_key = ?", idempotencyKey).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrRefundNotFound
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// IsEventProcessed reports whether a provider event has already been applied
+func (r *PaymentRepository) IsEventProcessed(ctx context.Context, eventID string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.ProviderEvent{}).Where("id = ?", eventID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RecordEvent marks a provider event as processed and lets apply change the
+// payment it belongs to, loaded with its row locked. The payment, if apply
+// changed it, and the dispute apply returns are saved in the same transaction,
+// so an event never overwrites a refund, capture or cancellation saved while
+// it was being handled. It returns false without applying the event when a
+// concurrent delivery of the same event was recorded first.
+func (r *PaymentRepository) RecordEvent(ctx context.Context, event *domain.ProviderEvent, paymentID string, apply func(payment *domain.Payment) (bool, *domain.Dispute, error)) (bool, error) {
+	var payment *domain.Payment
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		record := &models.ProviderEvent{
+			ID:          event.ID,
+			Type:        string(event.Type),
+			PaymentID:   paymentID,
+			ProcessedAt: time.Now(),
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errEventAlreadyRecorded
+		}
+
+		var model models.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model, "id = ?", paymentID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return domain.ErrPaymentNotFound
+			}
+			return err
+		}
+		payment = model.ToDomain()
+
+		changed, dispute, err := apply(payment)
+		if err != nil {
+			return err
+		}
+		if dispute != nil {
+			if err := saveDispute(tx, dispute); err != nil {
+				return err
+			}
+		}
+		if !changed {
+			return nil
+		}
+		return savePayment(tx, payment)
+	})
+	if errors.Is(err, errEventAlreadyRecorded) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	payment.MarkPersisted()
+	return true, nil
+}
+
+// translateError maps a violation of the active payment index to
+// ErrActivePaymentExists, e.g. when retrying a failed payment of an order
+// that has since been paid with another one
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == activePaymentIndex {
+		return domain.ErrActivePaymentExists
+	}
+	return err
+}
+
+// savePayment saves the payment if it is still at the version it was loaded
+// at and writes its pending events to the outbox within the given
+// transaction, so they commit or roll back together
+func savePayment(tx *gorm.DB, payment *domain.Payment) error {
 	model := &models.Payment{}
 	model.FromDomain(payment)
-	return r.db.WithContext(ctx).Save(model).Error
+	model.Version = payment.Version + 1
+	result := tx.Model(model).
+		Where("version = ?", payment.Version).
+		Select("*").
+		Omit("CreatedAt", "DeletedAt").
+		Updates(model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrPaymentConflict
+	}
+	payment.Version = model.Version
+
+	if payment.StartedAttempt() {
+		if err := tx.Create(models.NewPaymentAttempt(payment)).Error; err != nil {
+			return fmt.Errorf("failed to log payment attempt: %w", err)
+		}
+	}
+
+	for _, eventType := range payment.PendingEvents() {
+		event, err := models.NewOutboxEvent(eventType, payment)
+		if err != nil {
+			return fmt.Errorf("failed to build outbox event: %w", err)
+		}
+		if err := tx.Create(event).Error; err != nil {
+			return fmt.Errorf("failed to write outbox event: %w", err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/infrastructure/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReconciliationRepository reads the payments and refunds to reconcile and
+// stores reconciliation reports
+type ReconciliationRepository struct {
+	db *gorm.DB
+}
+
+// NewReconciliationRepository creates a new PostgreSQL reconciliation repository
+func NewReconciliationRepository(db *gorm.DB) *ReconciliationRepository {
+	return &ReconciliationRepository{db: db}
+}
+
+// FindPaymentsCreatedBetween finds the payments of a provider created in [from, to)
+func (r *ReconciliationRepository) FindPaymentsCreatedBetween(ctx context.Context, provider string, from, to time.Time) ([]domain.Payment, error) {
+	var modelList []models.Payment
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND created_at >= ? AND created_at < ?", provider, from, to).
+		Order("created_at ASC").
+		Find(&modelList).Error
+	if err != nil {
+		return nil, err
+	}
+	return toDomainPayments(modelList), nil
+}
+
+// FindPaymentsByProviderIDs finds the payments with the given provider payment IDs
+func (r *ReconciliationRepository) FindPaymentsByProviderIDs(ctx context.Context, providerIDs []string) ([]domain.Payment, error) {
+	if len(providerIDs) == 0 {
+		return nil, nil
+	}
+	var modelList []models.Payment
+	if err := r.db.WithContext(ctx).Where("provider_id IN ?", providerIDs).Find(&modelList).Error; err != nil {
+		return nil, err
+	}
+	return toDomainPayments(modelList), nil
+}
+
+// FindRefundsByProviderRefundIDs finds the refunds with the given provider refund IDs
+func (r *ReconciliationRepository) FindRefundsByProviderRefundIDs(ctx context.Context, providerRefundIDs []string) ([]domain.Refund, error) {
+	if len(providerRefundIDs) == 0 {
+		return nil, nil
+	}
+	var modelList []models.Refund
+	if err := r.db.WithContext(ctx).Where("provider_refund_id IN ?", providerRefundIDs).Find(&modelList).Error; err != nil {
+		return nil, err
+	}
+
+	refunds := make([]domain.Refund, len(modelList))
+	for i, model := range modelList {
+		refunds[i] = *model.ToDomain()
+	}
+	return refunds, nil
+}
+
+// SaveReport stores a report with its discrepancies, replacing an earlier
+// report of the same provider and period, and sets the generated IDs
+func (r *ReconciliationRepository) SaveReport(ctx context.Context, report *domain.ReconciliationReport) error {
+	model := &models.ReconciliationReport{}
+	model.FromDomain(report)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous []string
+		if err := tx.Model(&models.ReconciliationReport{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("provider = ? AND period_start = ?", report.Provider, report.PeriodStart).
+			Pluck("id", &previous).Error; err != nil {
+			return err
+		}
+		if len(previous) > 0 {
+			if err := tx.Where("report_id IN ?", previous).Delete(&models.ReconciliationDiscrepancy{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", previous).Delete(&models.ReconciliationReport{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(model).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save reconciliation report: %w", err)
+	}
+
+	saved := model.ToDomain()
+	report.ID = saved.ID
+	report.Discrepancies = saved.Discrepancies
+	return nil
+}
+
+// FindReport finds a report with its discrepancies by ID
+func (r *ReconciliationRepository) FindReport(ctx context.Context, id string) (*domain.ReconciliationReport, error) {
+	return r.findReport(ctx, r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+// FindReportByPeriod finds the report of a provider for the period starting at the given time
+func (r *ReconciliationRepository) FindReportByPeriod(ctx context.Context, provider string, periodStart time.Time) (*domain.ReconciliationReport, error) {
+	return r.findReport(ctx, r.db.WithContext(ctx).Where("provider = ? AND period_start = ?", provider, periodStart))
+}
+
+// FindLatestReport finds the report of a provider covering the most recent period
+func (r *ReconciliationRepository) FindLatestReport(ctx context.Context, provider string) (*domain.ReconciliationReport, error) {
+	return r.findReport(ctx, r.db.WithContext(ctx).Where("provider = ?", provider).Order("period_start DESC"))
+}
+
+// findReport loads the first report of the query with its discrepancies
+func (r *ReconciliationRepository) findReport(ctx context.Context, query *gorm.DB) (*domain.ReconciliationReport, error) {
+	var model models.ReconciliationReport
+	err := query.
+		Preload("Discrepancies", func(db *gorm.DB) *gorm.DB { return db.Order("type ASC, created_at ASC") }).
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrReconciliationReportNotFound
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// toDomainPayments converts GORM payments to domain payments
+func toDomainPayments(modelList []models.Payment) []domain.Payment {
+	payments := make([]domain.Payment, len(modelList))
+	for i, model := range modelList {
+		payments[i] = *model.ToDomain()
+	}
+	return payments
+}
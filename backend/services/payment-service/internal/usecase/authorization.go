@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// AuthorizePayment places a hold for the payment amount without capturing it.
+// Funds are collected later with CapturePayment, once the order can ship, or
+// released with VoidPayment.
+//
+// Returns:
+//   - *domain.Payment: The payment, AUTHORIZED on success or still PROCESSING
+//     while the customer completes 3D Secure or approves a PayPal order;
+//     authorizing it again then resumes with the provider
+//   - error: ErrPaymentAlreadyProcessed if the payment is not PENDING or
+//     FAILED, ErrPaymentRiskDenied if the risk assessment denied it,
+//     ErrPaymentFailed if the provider declined the authorization,
+//     ErrPaymentOutcomeUnknown if the provider's answer was lost
+func (uc *PaymentUseCase) AuthorizePayment(ctx context.Context, id string) (*domain.Payment, error) {
+	payment, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.startProcessing(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	providerID, response, err := uc.provider.AuthorizePayment(ctx, payment)
+	if errors.Is(err, domain.ErrPaymentPending) {
+		payment.MarkAsAwaitingAction(providerID, response)
+		return uc.saveOutcome(ctx, payment)
+	}
+	if errors.Is(err, domain.ErrPaymentOutcomeUnknown) {
+		// Left PROCESSING; authorizing again replays the attempt
+		return nil, err
+	}
+	if err != nil {
+		payment.MarkAsFailed(err.Error())
+		_ = uc.repo.Update(ctx, payment)
+		return payment, domain.ErrPaymentFailed
+	}
+
+	payment.MarkAsAuthorized(providerID, response, time.Now().Add(uc.authorizationValidity))
+	return uc.saveOutcome(ctx, payment)
+}
+
+// CapturePayment collects part or all of an authorized payment; an amount of
+// 0 captures the full authorization and the uncaptured rest is released. The
+// payment stays locked until the capture is saved, so a concurrent void or
+// expiry of the authorization waits and then finds it CAPTURED.
+func (uc *PaymentUseCase) CapturePayment(ctx context.Context, id string, amount float64, currency string) (*domain.Payment, error) {
+	return uc.repo.UpdateLocked(ctx, id, func(payment *domain.Payment) (bool, error) {
+		if amount > 0 && currency != "" && currency != payment.Currency {
+			return false, domain.ErrCurrencyMismatch
+		}
+		if err := payment.Capture(amount); err != nil {
+			return false, err
+		}
+
+		response, err := uc.provider.CapturePayment(ctx, payment)
+		if err != nil {
+			return false, fmt.Errorf("failed to capture payment: %w", err)
+		}
+		payment.ProviderResponse = response
+		return true, nil
+	})
+}
+
+// CancelPayment cancels a payment that has not collected any funds, e.g. when
+// checkout is compensated or an order expires unpaid. An authorization hold or
+// a provider payment still awaiting the customer is cancelled at the provider
+// first. The reason is recorded on the payment and PAYMENT_CANCELLED is
+// published through the outbox.
+//
+// The payment stays locked from checking its status until it is saved, so a
+// concurrent ProcessPayment either claimed it first, and the cancel is
+// refused, or finds it CANCELLED and never reaches the provider.
+//
+// Cancelling an already cancelled payment returns it unchanged, so callers
+// can retry safely.
+//
+// Returns:
+//   - *domain.Payment: The cancelled payment
+//   - error: ErrCancelNotAllowed if the payment already collected funds,
+//     failed or is being processed, or the provider/persistence error
+func (uc *PaymentUseCase) CancelPayment(ctx context.Context, id, reason string) (*domain.Payment, error) {
+	return uc.repo.UpdateLocked(ctx, id, func(payment *domain.Payment) (bool, error) {
+		if payment.Status == domain.PaymentStatusCancelled {
+			return false, nil
+		}
+		if err := payment.Cancel(reason); err != nil {
+			return false, err
+		}
+		if payment.ProviderID != "" {
+			if err := uc.provider.VoidPayment(ctx, payment); err != nil {
+				return false, fmt.Errorf("failed to cancel payment: %w", err)
+			}
+		}
+		return true, nil
+	})
+}
+
+// VoidPayment releases an authorization without collecting any funds. The
+// payment stays locked until the void is saved, so it cannot race a capture.
+func (uc *PaymentUseCase) VoidPayment(ctx context.Context, id string) (*domain.Payment, error) {
+	return uc.repo.UpdateLocked(ctx, id, func(payment *domain.Payment) (bool, error) {
+		if err := payment.Void(); err != nil {
+			return false, err
+		}
+		if err := uc.provider.VoidPayment(ctx, payment); err != nil {
+			return false, fmt.Errorf("failed to void payment: %w", err)
+		}
+		return true, nil
+	})
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/pkg/logger"
+)
+
+const (
+	authorizationExpirySweepInterval = 10 * time.Minute
+	authorizationExpirySweepBatch    = 100
+)
+
+// ExpireAuthorizations releases AUTHORIZED payments whose hold has lapsed,
+// so they can no longer be captured. The provider is asked to void each one
+// first; the payment is released locally even if that fails, since the card
+// issuer drops the hold on its own once it expires. Each payment is locked
+// while it is released, and one captured since it was listed is left alone.
+//
+// Returns:
+//   - int: Number of authorizations released
+//   - error: Failures of individual payments, joined
+func (uc *PaymentUseCase) ExpireAuthorizations(ctx context.Context) (int, error) {
+	var expired int
+	var errs []error
+	for {
+		payments, err := uc.repo.FindExpiredAuthorizations(ctx, time.Now(), authorizationExpirySweepBatch)
+		if err != nil {
+			return expired, fmt.Errorf("failed to get expired authorizations: %w", err)
+		}
+
+		for i := range payments {
+			id := payments[i].ID
+			var released bool
+			var voidErr error
+			_, err := uc.repo.UpdateLocked(ctx, id, func(payment *domain.Payment) (bool, error) {
+				if payment.Status != domain.PaymentStatusAuthorized {
+					// Captured or voided since it was listed
+					return false, nil
+				}
+				voidErr = uc.provider.VoidPayment(ctx, payment)
+				if err := payment.ExpireAuthorization(); err != nil {
+					return false, err
+				}
+				released = true
+				return true, nil
+			})
+			if voidErr != nil {
+				errs = append(errs, fmt.Errorf("payment %s: %w", id, voidErr))
+			}
+			if err != nil {
+				// Stop rather than fetch the same payments again
+				errs = append(errs, fmt.Errorf("payment %s: %w", id, err))
+				return expired, errors.Join(errs...)
+			}
+			if released {
+				expired++
+			}
+		}
+
+		if len(payments) < authorizationExpirySweepBatch {
+			return expired, errors.Join(errs...)
+		}
+	}
+}
+
+// StartAuthorizationExpiryJob starts a background job that releases lapsed authorizations
+func (uc *PaymentUseCase) StartAuthorizationExpiryJob(ctx context.Context, log logger.Logger) {
+	ticker := time.NewTicker(authorizationExpirySweepInterval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				expired, err := uc.ExpireAuthorizations(ctx)
+				if err != nil {
+					log.Error("Failed to expire some authorizations", "error", err)
+				}
+				if expired > 0 {
+					log.Info("Released expired authorizations", "count", expired)
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	log.Info("Started authorization expiry job", "validity", uc.authorizationValidity.String())
+}
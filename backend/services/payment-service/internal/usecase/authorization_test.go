@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// newAuthorizedPayment returns payment pay-1 of 25.00 USD holding an
+// authorization that lapses at expiresAt
+func newAuthorizedPayment(t *testing.T, expiresAt time.Time) *domain.Payment {
+	t.Helper()
+
+	payment := newOrderPayment(t, domain.PaymentStatusProcessing)
+	payment.MarkAsAuthorized("pi_1", "requires_capture", expiresAt)
+	return payment
+}
+
+func TestCapturePayment(t *testing.T) {
+	tests := []struct {
+		name         string
+		expiresAt    time.Duration // From now
+		setup        func(payment *domain.Payment)
+		amount       float64
+		currency     string
+		wantErr      error
+		wantCaptured float64
+	}{
+		{name: "whole authorization", expiresAt: time.Hour, wantCaptured: 25},
+		{name: "part of the authorization", expiresAt: time.Hour, amount: 10, currency: "USD", wantCaptured: 10},
+		{name: "more than was authorized", expiresAt: time.Hour, amount: 30, wantErr: domain.ErrInvalidAmount},
+		{name: "amount in another currency", expiresAt: time.Hour, amount: 10, currency: "EUR", wantErr: domain.ErrCurrencyMismatch},
+		{name: "lapsed authorization", expiresAt: -time.Minute, wantErr: domain.ErrAuthorizationExpired},
+		{
+			name:      "voided authorization",
+			expiresAt: time.Hour,
+			setup: func(payment *domain.Payment) {
+				if err := payment.Void(); err != nil {
+					t.Fatalf("Void: %v", err)
+				}
+			},
+			wantErr: domain.ErrCaptureNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payment := newAuthorizedPayment(t, time.Now().Add(tt.expiresAt))
+			if tt.setup != nil {
+				tt.setup(payment)
+			}
+			repo := newFakePaymentRepo(payment)
+			provider := newFakeProvider()
+			uc := NewPaymentUseCase(repo, nil, nil, provider, nil, nil, nil, time.Hour)
+			before := repo.get("pay-1")
+
+			_, err := uc.CapturePayment(context.Background(), "pay-1", tt.amount, tt.currency)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CapturePayment error = %v, want %v", err, tt.wantErr)
+				}
+				if len(provider.captured) != 0 {
+					t.Errorf("provider captured %v on a rejected capture", provider.captured)
+				}
+				if stored := repo.get("pay-1"); stored.Status != before.Status {
+					t.Errorf("payment is %s after a rejected capture, want %s", stored.Status, before.Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CapturePayment: %v", err)
+			}
+
+			stored := repo.get("pay-1")
+			if stored.Status != domain.PaymentStatusCaptured || stored.CapturedAmount != tt.wantCaptured {
+				t.Errorf("payment is %s with %v captured, want %s with %v", stored.Status, stored.CapturedAmount, domain.PaymentStatusCaptured, tt.wantCaptured)
+			}
+			if len(provider.captured) != 1 || provider.captured[0] != tt.wantCaptured {
+				t.Errorf("provider captured %v, want [%v]", provider.captured, tt.wantCaptured)
+			}
+		})
+	}
+}
+
+func TestVoidPayment(t *testing.T) {
+	tests := []struct {
+		name       string
+		captured   bool
+		wantErr    error
+		wantStatus domain.PaymentStatus
+	}{
+		{name: "authorization is released", wantStatus: domain.PaymentStatusCancelled},
+		{name: "captured payment cannot be voided", captured: true, wantErr: domain.ErrVoidNotAllowed, wantStatus: domain.PaymentStatusCaptured},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payment := newAuthorizedPayment(t, time.Now().Add(time.Hour))
+			if tt.captured {
+				if err := payment.Capture(0); err != nil {
+					t.Fatalf("Capture: %v", err)
+				}
+			}
+			repo := newFakePaymentRepo(payment)
+			provider := newFakeProvider()
+			uc := NewPaymentUseCase(repo, nil, nil, provider, nil, nil, nil, time.Hour)
+
+			_, err := uc.VoidPayment(context.Background(), "pay-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VoidPayment error = %v, want %v", err, tt.wantErr)
+			}
+			if stored := repo.get("pay-1"); stored.Status != tt.wantStatus {
+				t.Errorf("payment is %s, want %s", stored.Status, tt.wantStatus)
+			}
+			wantVoids := 1
+			if tt.wantErr != nil {
+				wantVoids = 0
+			}
+			if len(provider.voided) != wantVoids {
+				t.Errorf("provider voided %d times, want %d", len(provider.voided), wantVoids)
+			}
+		})
+	}
+}
+
+func TestExpireAuthorizations(t *testing.T) {
+	tests := []struct {
+		name         string
+		expiresAt    time.Duration // From now
+		captureFirst bool          // Captured after the job listed the payment
+		wantExpired  int
+		wantStatus   domain.PaymentStatus
+	}{
+		{name: "lapsed authorization is released", expiresAt: -time.Minute, wantExpired: 1, wantStatus: domain.PaymentStatusCancelled},
+		{name: "valid authorization is kept", expiresAt: time.Hour, wantStatus: domain.PaymentStatusAuthorized},
+		{name: "capture racing the job is kept", expiresAt: -time.Minute, captureFirst: true, wantStatus: domain.PaymentStatusCaptured},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakePaymentRepo(newAuthorizedPayment(t, time.Now().Add(tt.expiresAt)))
+			provider := newFakeProvider()
+			uc := NewPaymentUseCase(repo, nil, nil, provider, nil, nil, nil, time.Hour)
+			if tt.captureFirst {
+				repo.interleave = func(payment *domain.Payment) {
+					payment.Status = domain.PaymentStatusCaptured
+					payment.CapturedAmount = payment.Amount
+				}
+			}
+
+			expired, err := uc.ExpireAuthorizations(context.Background())
+			if err != nil {
+				t.Fatalf("ExpireAuthorizations: %v", err)
+			}
+			if expired != tt.wantExpired {
+				t.Errorf("expired %d authorizations, want %d", expired, tt.wantExpired)
+			}
+			stored := repo.get("pay-1")
+			if stored.Status != tt.wantStatus {
+				t.Errorf("payment is %s, want %s", stored.Status, tt.wantStatus)
+			}
+			if len(provider.voided) != tt.wantExpired {
+				t.Errorf("provider voided %d times, want %d", len(provider.voided), tt.wantExpired)
+			}
+			if tt.wantExpired > 0 && stored.FailureReason != domain.AuthorizationExpiredReason {
+				t.Errorf("FailureReason = %q, want %q", stored.FailureReason, domain.AuthorizationExpiredReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/pkg/logger"
+)
+
+const (
+	disputeFlagSweepInterval = 10 * time.Minute
+	disputeFlagSweepBatch    = 100
+	disputeFlagRetryWindow   = 7 * 24 * time.Hour // How long flagging an order is retried after a dispute opens
+	defaultDisputeListLimit  = 50
+	maxDisputeListLimit      = 100
+)
+
+// DisputeRepository defines the interface for dispute data access. Disputes
+// are created and changed by provider events through PaymentRepository.RecordEvent,
+// with the row of their payment locked.
+type DisputeRepository interface {
+	FindByID(ctx context.Context, id string) (*domain.Dispute, error)
+	FindByProviderDisputeID(ctx context.Context, providerDisputeID string) (*domain.Dispute, error)
+	List(ctx context.Context, filter domain.DisputeFilter, limit, offset int) ([]domain.Dispute, error)
+	FindUnflagged(ctx context.Context, openedSince time.Time, limit int) ([]domain.Dispute, error)
+	SaveEvidence(ctx context.Context, dispute *domain.Dispute) error
+	MarkOrderFlagged(ctx context.Context, dispute *domain.Dispute) error
+}
+
+// OrderService defines the interface for calls to the order service
+type OrderService interface {
+	FlagOrderDisputed(ctx context.Context, dispute *domain.Dispute) error
+}
+
+// ListDisputes lists the disputes matching the filter, those with the nearest
+// evidence deadline first
+func (uc *PaymentUseCase) ListDisputes(ctx context.Context, filter domain.DisputeFilter, limit, offset int) ([]domain.Dispute, error) {
+	if limit <= 0 {
+		limit = defaultDisputeListLimit
+	} else if limit > maxDisputeListLimit {
+		limit = maxDisputeListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return uc.disputes.List(ctx, filter, limit, offset)
+}
+
+// SubmitDisputeEvidence submits the merchant's evidence for a dispute to the
+// provider and records it. The provider's decision arrives later as a
+// provider event.
+//
+// Returns:
+//   - *domain.Dispute: The dispute, now UNDER_REVIEW
+//   - error: ErrDisputeNotFound, ErrEmptyEvidence, ErrEvidenceNotAllowed,
+//     ErrEvidenceDeadlinePassed, or the provider error
+func (uc *PaymentUseCase) SubmitDisputeEvidence(ctx context.Context, disputeID string, evidence domain.DisputeEvidence) (*domain.Dispute, error) {
+	dispute, err := uc.disputes.FindByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := dispute.SubmitEvidence(evidence, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := uc.provider.SubmitDisputeEvidence(ctx, dispute); err != nil {
+		return nil, err
+	}
+	if err := uc.disputes.SaveEvidence(ctx, dispute); err != nil {
+		return nil, fmt.Errorf("evidence was submitted but could not be saved: %w", err)
+	}
+	return dispute, nil
+}
+
+// FlagDisputedOrders flags the orders of recently opened disputes that the
+// order service has not flagged yet, e.g. because it was unavailable when the
+// dispute opened. Disputes older than the retry window are given up on.
+//
+// Returns:
+//   - int: Number of orders flagged
+//   - error: Failures of individual disputes, joined
+func (uc *PaymentUseCase) FlagDisputedOrders(ctx context.Context) (int, error) {
+	// A single batch: disputes that fail are fetched again on the next run
+	disputes, err := uc.disputes.FindUnflagged(ctx, time.Now().Add(-disputeFlagRetryWindow), disputeFlagSweepBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get disputes to flag: %w", err)
+	}
+
+	var flagged int
+	var errs []error
+	for i := range disputes {
+		if err := uc.flagDisputedOrder(ctx, &disputes[i]); err != nil {
+			errs = append(errs, fmt.Errorf("dispute %s: %w", disputes[i].ID, err))
+			continue
+		}
+		flagged++
+	}
+	return flagged, errors.Join(errs...)
+}
+
+// flagDisputedOrder asks the order service to flag the order of a dispute and
+// records that it did
+func (uc *PaymentUseCase) flagDisputedOrder(ctx context.Context, dispute *domain.Dispute) error {
+	if err := uc.orders.FlagOrderDisputed(ctx, dispute); err != nil {
+		return err
+	}
+	dispute.MarkOrderFlagged(time.Now())
+	return uc.disputes.MarkOrderFlagged(ctx, dispute)
+}
+
+// StartDisputeJob starts a background job that flags the orders of disputes
+// the order service missed
+func (uc *PaymentUseCase) StartDisputeJob(ctx context.Context, log logger.Logger) {
+	ticker := time.NewTicker(disputeFlagSweepInterval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				flagged, err := uc.FlagDisputedOrders(ctx)
+				if err != nil {
+					log.Error("Failed to flag some disputed orders", "error", err)
+				}
+				if flagged > 0 {
+					log.Info("Flagged disputed orders", "count", flagged)
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	log.Info("Started dispute job", "retry_window", disputeFlagRetryWindow.String())
+}
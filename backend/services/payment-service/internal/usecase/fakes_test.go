@@ -0,0 +1,338 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+var errNotImplemented = errors.New("not implemented by fake")
+
+// fakePaymentRepo keeps payments in memory. Payments are copied in and out,
+// so a use case only sees its own changes once it saved them.
+type fakePaymentRepo struct {
+	mu       sync.Mutex
+	payments map[string]*domain.Payment // by ID
+	events   map[string]bool            // IDs of the recorded provider events
+	updates  int
+	// concurrent, when set, is created by another request just before the
+	// next Create
+	concurrent *domain.Payment
+	// interleave, when set, changes the stored payment as another request
+	// would just before the next event or locked update gets to it
+	interleave func(payment *domain.Payment)
+	// beforeUpdate, when set, runs another request just before the next Update
+	beforeUpdate func()
+}
+
+func newFakePaymentRepo(payments ...*domain.Payment) *fakePaymentRepo {
+	repo := &fakePaymentRepo{
+		payments: make(map[string]*domain.Payment),
+		events:   make(map[string]bool),
+	}
+	for _, payment := range payments {
+		c := *payment
+		repo.payments[payment.ID] = &c
+	}
+	return repo
+}
+
+// Create refuses a second active payment for an order, as the partial unique
+// index on order_id does
+func (r *fakePaymentRepo) Create(ctx context.Context, payment *domain.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.concurrent != nil {
+		c := *r.concurrent
+		r.payments[c.ID] = &c
+		r.concurrent = nil
+	}
+	for _, existing := range r.payments {
+		if existing.OrderID == payment.OrderID && existing.IsActive() {
+			return domain.ErrActivePaymentExists
+		}
+	}
+	payment.ID = fmt.Sprintf("pay-%d", len(r.payments)+1)
+	c := *payment
+	r.payments[payment.ID] = &c
+	return nil
+}
+
+func (r *fakePaymentRepo) FindByID(ctx context.Context, id string) (*domain.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	payment, ok := r.payments[id]
+	if !ok {
+		return nil, domain.ErrPaymentNotFound
+	}
+	c := *payment
+	return &c, nil
+}
+
+// FindByOrderID returns the order's active payment, or its latest one
+func (r *fakePaymentRepo) FindByOrderID(ctx context.Context, orderID string) (*domain.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found *domain.Payment
+	for _, payment := range r.payments {
+		if payment.OrderID != orderID {
+			continue
+		}
+		if found == nil || payment.IsActive() || (!found.IsActive() && payment.CreatedAt.After(found.CreatedAt)) {
+			found = payment
+		}
+	}
+	if found == nil {
+		return nil, domain.ErrPaymentNotFound
+	}
+	c := *found
+	return &c, nil
+}
+
+func (r *fakePaymentRepo) FindByProviderID(ctx context.Context, providerID string) (*domain.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, payment := range r.payments {
+		if payment.ProviderID == providerID {
+			c := *payment
+			return &c, nil
+		}
+	}
+	return nil, domain.ErrPaymentNotFound
+}
+
+func (r *fakePaymentRepo) FindByUserID(ctx context.Context, userID string, limit, offset int) ([]domain.Payment, error) {
+	return nil, errNotImplemented
+}
+
+func (r *fakePaymentRepo) FindExpiredAuthorizations(ctx context.Context, before time.Time, limit int) ([]domain.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var expired []domain.Payment
+	for _, payment := range r.payments {
+		if payment.Status == domain.PaymentStatusAuthorized && payment.IsAuthorizationExpired(before) && len(expired) < limit {
+			expired = append(expired, *payment)
+		}
+	}
+	return expired, nil
+}
+
+// Update saves the payment if it is still at the version it was loaded at
+func (r *fakePaymentRepo) Update(ctx context.Context, payment *domain.Payment) error {
+	if before := r.beforeUpdate; before != nil {
+		r.beforeUpdate = nil
+		before()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.payments[payment.ID]
+	if !ok {
+		return domain.ErrPaymentNotFound
+	}
+	if stored.Version != payment.Version {
+		return domain.ErrPaymentConflict
+	}
+	r.save(payment)
+	return nil
+}
+
+// UpdateLocked runs change on the stored payment while no other change can
+// be saved
+func (r *fakePaymentRepo) UpdateLocked(ctx context.Context, id string, change func(payment *domain.Payment) (bool, error)) (*domain.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.payments[id]
+	if !ok {
+		return nil, domain.ErrPaymentNotFound
+	}
+	if r.interleave != nil {
+		r.interleave(stored)
+		r.interleave = nil
+	}
+	payment := *stored
+	changed, err := change(&payment)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		r.save(&payment)
+	}
+	return &payment, nil
+}
+
+// save stores a copy of the payment under its next version; the caller holds mu
+func (r *fakePaymentRepo) save(payment *domain.Payment) {
+	payment.Version++
+	c := *payment
+	r.payments[payment.ID] = &c
+	r.updates++
+}
+
+func (r *fakePaymentRepo) RefundLocked(ctx context.Context, id string, issue func(payment *domain.Payment) (*domain.Refund, error)) (*domain.Refund, error) {
+	return nil, errNotImplemented
+}
+
+func (r *fakePaymentRepo) FindRefundByIdempotencyKey(ctx context.Context, idempotencyKey string) (*domain.Refund, error) {
+	return nil, errNotImplemented
+}
+
+func (r *fakePaymentRepo) IsEventProcessed(ctx context.Context, eventID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.events[eventID], nil
+}
+
+// RecordEvent applies the event to the stored payment unless the event was
+// already recorded; disputes are not kept
+func (r *fakePaymentRepo) RecordEvent(ctx context.Context, event *domain.ProviderEvent, paymentID string, apply func(payment *domain.Payment) (bool, *domain.Dispute, error)) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.events[event.ID] {
+		return false, nil
+	}
+	stored, ok := r.payments[paymentID]
+	if !ok {
+		return false, domain.ErrPaymentNotFound
+	}
+	if r.interleave != nil {
+		r.interleave(stored)
+		r.interleave = nil
+	}
+	payment := *stored
+	changed, _, err := apply(&payment)
+	if err != nil {
+		return false, err
+	}
+	r.events[event.ID] = true
+	if changed {
+		r.save(&payment)
+	}
+	return true, nil
+}
+
+// get returns the stored payment, failing the lookup loudly in tests
+func (r *fakePaymentRepo) get(id string) *domain.Payment {
+	payment, err := r.FindByID(context.Background(), id)
+	if err != nil {
+		panic(err)
+	}
+	return payment
+}
+
+// fakeDisputeRepo knows no disputes, so every dispute event opens a new one
+type fakeDisputeRepo struct{}
+
+func (fakeDisputeRepo) FindByID(ctx context.Context, id string) (*domain.Dispute, error) {
+	return nil, domain.ErrDisputeNotFound
+}
+
+func (fakeDisputeRepo) FindByProviderDisputeID(ctx context.Context, providerDisputeID string) (*domain.Dispute, error) {
+	return nil, domain.ErrDisputeNotFound
+}
+
+func (fakeDisputeRepo) List(ctx context.Context, filter domain.DisputeFilter, limit, offset int) ([]domain.Dispute, error) {
+	return nil, errNotImplemented
+}
+
+func (fakeDisputeRepo) FindUnflagged(ctx context.Context, openedSince time.Time, limit int) ([]domain.Dispute, error) {
+	return nil, errNotImplemented
+}
+
+func (fakeDisputeRepo) SaveEvidence(ctx context.Context, dispute *domain.Dispute) error {
+	return errNotImplemented
+}
+
+func (fakeDisputeRepo) MarkOrderFlagged(ctx context.Context, dispute *domain.Dispute) error {
+	return errNotImplemented
+}
+
+// fakeOrderService cannot be reached, leaving orders for the dispute job to flag
+type fakeOrderService struct{}
+
+func (fakeOrderService) FlagOrderDisputed(ctx context.Context, dispute *domain.Dispute) error {
+	return errNotImplemented
+}
+
+// fakeProvider is a provider charging each idempotency key once and replaying
+// the charge for a retried key
+type fakeProvider struct {
+	mu      sync.Mutex
+	charges map[string]string // provider payment ID by idempotency key
+	sent    []string          // idempotency keys of the charges sent
+	lose    int               // responses to lose after charging
+	// charging, when set, runs another request while the next charge is sent
+	charging func()
+	captured []float64 // amounts captured
+	voided   []string  // IDs of the payments voided
+}
+
+func newFakeProvider() *fakeProvider {
+	return &fakeProvider{charges: make(map[string]string)}
+}
+
+func (p *fakeProvider) Route(method domain.PaymentMethod, currency string) (string, error) {
+	return "stripe", nil
+}
+
+func (p *fakeProvider) ProcessPayment(ctx context.Context, payment *domain.Payment) (string, string, error) {
+	if charging := p.charging; charging != nil {
+		p.charging = nil
+		charging()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := payment.IdempotencyKey("payment-intent")
+	p.sent = append(p.sent, key)
+	providerID, ok := p.charges[key]
+	if !ok {
+		providerID = fmt.Sprintf("pi_%d", len(p.charges)+1)
+		p.charges[key] = providerID
+	}
+	if p.lose > 0 {
+		p.lose--
+		return "", "", fmt.Errorf("%w: request timed out", domain.ErrPaymentOutcomeUnknown)
+	}
+	return providerID, "succeeded", nil
+}
+
+func (p *fakeProvider) RefundPayment(ctx context.Context, payment *domain.Payment, refund *domain.Refund) (string, error) {
+	return "", errNotImplemented
+}
+
+func (p *fakeProvider) AuthorizePayment(ctx context.Context, payment *domain.Payment) (string, string, error) {
+	return "", "", errNotImplemented
+}
+
+func (p *fakeProvider) CapturePayment(ctx context.Context, payment *domain.Payment) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.captured = append(p.captured, payment.CapturedAmount)
+	return "succeeded", nil
+}
+
+func (p *fakeProvider) VoidPayment(ctx context.Context, payment *domain.Payment) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.voided = append(p.voided, payment.ID)
+	return nil
+}
+
+func (p *fakeProvider) CreateCustomer(ctx context.Context, userID string) (string, error) {
+	return "", errNotImplemented
+}
+
+func (p *fakeProvider) AttachPaymentMethod(ctx context.Context, customerID, token string) (*domain.CardDetails, error) {
+	return nil, errNotImplemented
+}
+
+func (p *fakeProvider) DetachPaymentMethod(ctx context.Context, providerMethodID string) error {
+	return errNotImplemented
+}
+
+func (p *fakeProvider) SubmitDisputeEvidence(ctx context.Context, dispute *domain.Dispute) error {
+	return errNotImplemented
+}
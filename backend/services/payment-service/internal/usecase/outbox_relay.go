@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/pkg/logger"
+)
+
+const (
+	outboxRelayInterval = 2 * time.Second
+	outboxRelayBatch    = 100
+)
+
+// OutboxRepository defines the interface for reading pending outbox events
+type OutboxRepository interface {
+	ProcessPending(ctx context.Context, limit int, publish func(ctx context.Context, event *domain.OutboxEvent) error) (int, error)
+}
+
+// EventPublisher defines the interface for publishing events to the message broker
+type EventPublisher interface {
+	Publish(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// OutboxRelay publishes payment events written to the transactional outbox.
+// An event is only acknowledged after the broker accepted it, so delivery is at-least-once.
+type OutboxRelay struct {
+	outboxRepo OutboxRepository
+	publisher  EventPublisher
+}
+
+// NewOutboxRelay creates a new outbox relay
+func NewOutboxRelay(outboxRepo OutboxRepository, publisher EventPublisher) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo: outboxRepo,
+		publisher:  publisher,
+	}
+}
+
+// RelayPending publishes pending outbox events until none are left or a batch
+// has a failure. A batch holds one event per payment, so the later events of a
+// payment go out with the following batches.
+//
+// Returns:
+//   - int: Number of events published
+//   - error: The first publish or database failure
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	var total int
+	for {
+		published, err := r.outboxRepo.ProcessPending(ctx, outboxRelayBatch, r.publisher.Publish)
+		total += published
+		if err != nil {
+			return total, err
+		}
+		if published == 0 {
+			return total, nil
+		}
+	}
+}
+
+// Start starts a background job that relays outbox events
+func (r *OutboxRelay) Start(ctx context.Context, log logger.Logger) {
+	ticker := time.NewTicker(outboxRelayInterval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				published, err := r.RelayPending(ctx)
+				if err != nil {
+					log.Error("Failed to relay payment events", "error", err)
+				}
+				if published > 0 {
+					log.Info("Published payment events", "count", published)
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	log.Info("Started payment event outbox relay")
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// PaymentMethodRepository defines the interface for saved payment method data access
+type PaymentMethodRepository interface {
+	Create(ctx context.Context, method *domain.SavedPaymentMethod) error
+	FindByID(ctx context.Context, id string) (*domain.SavedPaymentMethod, error)
+	FindByUserID(ctx context.Context, userID string) ([]domain.SavedPaymentMethod, error)
+	FindCustomerID(ctx context.Context, userID string) (string, error)
+	Delete(ctx context.Context, method *domain.SavedPaymentMethod) error
+}
+
+// AddPaymentMethod saves a card the client tokenized with the payment
+// provider. The token is attached to the user's provider customer, created on
+// first use, and only the card's display details are stored.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - userID: Owner of the payment method
+//   - methodType: CREDIT_CARD or DEBIT_CARD
+//   - token: Provider token for the card, e.g. a Stripe payment method ID; never a card number
+//   - setAsDefault: Whether the method becomes the user's default
+//
+// Returns:
+//   - *domain.SavedPaymentMethod: The saved method
+//   - error: ErrRawCardNumberNotAllowed or ErrInvalidPaymentToken for bad tokens,
+//     ErrInvalidPaymentMethod for non-card types, ErrPaymentMethodExpired for expired cards
+func (uc *PaymentUseCase) AddPaymentMethod(ctx context.Context, userID string, methodType domain.PaymentMethod, token string, setAsDefault bool) (*domain.SavedPaymentMethod, error) {
+	if err := domain.ValidatePaymentToken(token); err != nil {
+		return nil, err
+	}
+	if methodType != domain.PaymentMethodCreditCard && methodType != domain.PaymentMethodDebitCard {
+		return nil, domain.ErrInvalidPaymentMethod
+	}
+
+	customerID, err := uc.methods.FindCustomerID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if customerID == "" {
+		if customerID, err = uc.provider.CreateCustomer(ctx, userID); err != nil {
+			return nil, fmt.Errorf("failed to create provider customer: %w", err)
+		}
+	}
+
+	card, err := uc.provider.AttachPaymentMethod(ctx, customerID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := domain.NewSavedPaymentMethod(userID, methodType, customerID, *card, setAsDefault)
+	if err == nil {
+		err = uc.methods.Create(ctx, method)
+	}
+	if err != nil {
+		// Do not leave a card attached that the user cannot see or remove
+		_ = uc.provider.DetachPaymentMethod(ctx, card.ProviderMethodID)
+		return nil, err
+	}
+	return method, nil
+}
+
+// ListPaymentMethods returns a user's saved payment methods, default first
+func (uc *PaymentUseCase) ListPaymentMethods(ctx context.Context, userID string) ([]domain.SavedPaymentMethod, error) {
+	return uc.methods.FindByUserID(ctx, userID)
+}
+
+// RemovePaymentMethod detaches a saved payment method at the provider and
+// deletes it. Methods of other users are reported as not found.
+func (uc *PaymentUseCase) RemovePaymentMethod(ctx context.Context, userID, id string) error {
+	method, err := uc.methods.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if method.UserID != userID {
+		return domain.ErrPaymentMethodNotFound
+	}
+
+	if err := uc.provider.DetachPaymentMethod(ctx, method.ProviderMethodID); err != nil {
+		return fmt.Errorf("failed to detach payment method: %w", err)
+	}
+	return uc.methods.Delete(ctx, method)
+}
@@ -2,6 +2,10 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
 )
@@ -11,27 +15,69 @@ type PaymentRepository interface {
 	Create(ctx context.Context, payment *domain.Payment) error
 	FindByID(ctx context.Context, id string) (*domain.Payment, error)
 	FindByOrderID(ctx context.Context, orderID string) (*domain.Payment, error)
+	FindByProviderID(ctx context.Context, providerID string) (*domain.Payment, error)
 	FindByUserID(ctx context.Context, userID string, limit, offset int) ([]domain.Payment, error)
-	Update(ctx context.Context, payment *domain.Payment) error
+	FindExpiredAuthorizations(ctx context.Context, before time.Time, limit int) ([]domain.Payment, error)
+	Update(ctx context.Context, payment *domain.Payment) error                                                                        // domain.ErrPaymentConflict if the payment changed since it was loaded
+	UpdateLocked(ctx context.Context, id string, change func(payment *domain.Payment) (bool, error)) (*domain.Payment, error)         // change runs with the payment row locked; the payment is saved if it reports a change
+	RefundLocked(ctx context.Context, id string, issue func(payment *domain.Payment) (*domain.Refund, error)) (*domain.Refund, error) // issue runs with the payment row locked; its refund is saved with the payment
+	FindRefundByIdempotencyKey(ctx context.Context, idempotencyKey string) (*domain.Refund, error)
+	IsEventProcessed(ctx context.Context, eventID string) (bool, error)
+	RecordEvent(ctx context.Context, event *domain.ProviderEvent, paymentID string, apply func(payment *domain.Payment) (bool, *domain.Dispute, error)) (bool, error) // apply runs with the payment row locked; false if the event was already recorded
 }
 
 // PaymentProvider defines the interface for payment processing
 type PaymentProvider interface {
-	ProcessPayment(ctx context.Context, payment *domain.Payment) (string, string, error) // returns providerID, response, error
-	RefundPayment(ctx context.Context, providerID string, amount float64) error
+	Route(method domain.PaymentMethod, currency string) (string, error) // returns the name of the provider taking such payments; domain.ErrNoPaymentProvider if none
+
+	ProcessPayment(ctx context.Context, payment *domain.Payment) (string, string, error)               // returns providerID, response, error; domain.ErrPaymentPending while customer action is required
+	RefundPayment(ctx context.Context, payment *domain.Payment, refund *domain.Refund) (string, error) // returns provider refund ID
+
+	// Two-phase payments
+	AuthorizePayment(ctx context.Context, payment *domain.Payment) (string, string, error) // returns providerID, response, error; domain.ErrPaymentPending while customer action is required
+	CapturePayment(ctx context.Context, payment *domain.Payment) (string, error)           // captures payment.CapturedAmount, returns response
+	VoidPayment(ctx context.Context, payment *domain.Payment) error
+
+	// Saved payment methods
+	CreateCustomer(ctx context.Context, userID string) (string, error) // returns provider customer ID
+	AttachPaymentMethod(ctx context.Context, customerID, token string) (*domain.CardDetails, error)
+	DetachPaymentMethod(ctx context.Context, providerMethodID string) error
+
+	// Disputes
+	SubmitDisputeEvidence(ctx context.Context, dispute *domain.Dispute) error
+}
+
+// ExchangeRateSource defines the interface for the exchange rates converting
+// payments into the merchant's settlement currency
+type ExchangeRateSource interface {
+	SettlementRate(ctx context.Context, currency string) (*domain.ExchangeRate, error) // domain.ErrExchangeRateUnavailable if there is none
 }
 
 // PaymentUseCase handles payment business logic
 type PaymentUseCase struct {
-	repo     PaymentRepository
-	provider PaymentProvider
+	repo                  PaymentRepository
+	methods               PaymentMethodRepository
+	disputes              DisputeRepository
+	provider              PaymentProvider
+	orders                OrderService
+	rates                 ExchangeRateSource // nil when payments take no exchange rate snapshot
+	risk                  RiskEvaluator      // nil when payments are charged without a risk assessment
+	authorizationValidity time.Duration      // How long an authorization hold can be captured
 }
 
-// NewPaymentUseCase creates a new payment use case
-func NewPaymentUseCase(repo PaymentRepository, provider PaymentProvider) *PaymentUseCase {
+// NewPaymentUseCase creates a new payment use case. Without an exchange rate
+// source, payments are created without an exchange rate snapshot; without a
+// risk evaluator, they are charged without a risk assessment.
+func NewPaymentUseCase(repo PaymentRepository, methods PaymentMethodRepository, disputes DisputeRepository, provider PaymentProvider, orders OrderService, rates ExchangeRateSource, risk RiskEvaluator, authorizationValidity time.Duration) *PaymentUseCase {
 	return &PaymentUseCase{
-		repo:     repo,
-		provider: provider,
+		repo:                  repo,
+		methods:               methods,
+		disputes:              disputes,
+		provider:              provider,
+		orders:                orders,
+		rates:                 rates,
+		risk:                  risk,
+		authorizationValidity: authorizationValidity,
 	}
 }
 
@@ -44,41 +90,125 @@ func NewPaymentUseCase(repo PaymentRepository, provider PaymentProvider) *Paymen
 //   - orderID: ID of the order being paid for (string format)
 //   - userID: ID of the user making the payment (string format)
 //   - amount: Payment amount (must be > 0)
-//   - currency: Currency code (e.g., "USD", "EUR")
+//   - currency: Currency code (e.g., "USD", "JPY"), the presentment currency
 //   - method: Payment method (CREDIT_CARD, STRIPE, etc.)
+//   - savedMethodID: Optional saved payment method of the user to charge; its type overrides method
+//
+// The payment is routed to a provider by method and currency here, or to the
+// provider storing the saved method, and stays with it for its lifetime. The
+// rate converting it into the settlement currency is recorded with it.
+//
+// An order has at most one active payment. Repeating the request for an order
+// whose active payment has the same user, amount and currency returns that
+// payment, so a client that lost the response can retry safely.
 //
 // Returns:
 //   - *domain.Payment: The created payment with auto-generated ID
-//   - error: Error if validation fails or persistence fails
-func (uc *PaymentUseCase) CreatePayment(ctx context.Context, orderID, userID string, amount float64, currency string, method domain.PaymentMethod) (*domain.Payment, error) {
+//   - error: ErrActivePaymentExists if the order has an active payment for a
+//     different request, ErrUnsupportedCurrency, ErrExchangeRateUnavailable,
+//     or the validation/persistence error
+func (uc *PaymentUseCase) CreatePayment(ctx context.Context, orderID, userID string, amount float64, currency string, method domain.PaymentMethod, savedMethodID string) (*domain.Payment, error) {
 	payment, err := domain.NewPayment(orderID, userID, amount, currency, method)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := uc.repo.Create(ctx, payment); err != nil {
+	existing, err := uc.repo.FindByOrderID(ctx, orderID)
+	switch {
+	case err == nil && existing.IsActive():
+		return existingPayment(existing, payment)
+	case err != nil && !errors.Is(err, domain.ErrPaymentNotFound):
+		return nil, err
+	}
+
+	if savedMethodID != "" {
+		saved, err := uc.methods.FindByID(ctx, savedMethodID)
+		if err != nil {
+			return nil, err
+		}
+		if err := payment.UseSavedMethod(saved); err != nil {
+			return nil, err
+		}
+	}
+	if payment.Provider == "" {
+		if payment.Provider, err = uc.provider.Route(payment.Method, payment.Currency); err != nil {
+			return nil, err
+		}
+	}
+	if uc.rates != nil {
+		rate, err := uc.rates.SettlementRate(ctx, payment.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if err := payment.SetExchangeRate(rate); err != nil {
+			return nil, err
+		}
+	}
+
+	err = uc.repo.Create(ctx, payment)
+	if errors.Is(err, domain.ErrActivePaymentExists) {
+		// A concurrent request created the order's payment first
+		if existing, err = uc.repo.FindByOrderID(ctx, orderID); err != nil {
+			return nil, err
+		}
+		return existingPayment(existing, payment)
+	}
+	if err != nil {
 		return nil, err
 	}
 
 	return payment, nil
 }
 
-// ProcessPayment processes a payment through the payment provider
+// existingPayment returns the order's active payment if the new request is a
+// retry of the one that created it
+func existingPayment(existing, requested *domain.Payment) (*domain.Payment, error) {
+	if !existing.IsActive() || !existing.Matches(requested) {
+		return nil, domain.ErrActivePaymentExists
+	}
+	return existing, nil
+}
+
+// ProcessPayment processes a payment through the payment provider. Calling it
+// again for a payment awaiting customer action, e.g. after the buyer approved
+// a PayPal order, completes the payment with the provider.
+//
+// When the provider's answer is lost, e.g. on a timeout, the payment stays
+// PROCESSING and ErrPaymentOutcomeUnknown is returned. Calling ProcessPayment
+// again replays the attempt with the same idempotency keys, so the customer is
+// never charged twice. A FAILED payment can be processed again as a new attempt.
+//
+// A new attempt is claimed by saving the payment as PROCESSING at the version
+// it was loaded at, so of concurrent calls only one reaches the provider and
+// the others fail with ErrPaymentAlreadyProcessed, as does a call racing a
+// cancel. The provider's answer is saved the same way: if the payment changed
+// meanwhile, e.g. the provider's webhook settled it first, the stored payment
+// is kept and returned.
+//
+// Each new attempt is assessed for fraud risk first. A denied payment fails
+// with ErrPaymentRiskDenied without reaching the provider; one flagged for
+// review is charged and keeps its risk decision for manual review.
 func (uc *PaymentUseCase) ProcessPayment(ctx context.Context, id string) (*domain.Payment, error) {
 	payment, err := uc.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := payment.MarkAsProcessing(); err != nil {
-		return nil, err
-	}
-	if err := uc.repo.Update(ctx, payment); err != nil {
+	if err := uc.startProcessing(ctx, payment); err != nil {
 		return nil, err
 	}
 
 	// Process payment with provider
 	providerID, response, err := uc.provider.ProcessPayment(ctx, payment)
+	if errors.Is(err, domain.ErrPaymentPending) {
+		// Settled later by the provider webhook, e.g. after 3D Secure
+		payment.MarkAsAwaitingAction(providerID, response)
+		return uc.saveOutcome(ctx, payment)
+	}
+	if errors.Is(err, domain.ErrPaymentOutcomeUnknown) {
+		// Left PROCESSING; processing again replays the attempt
+		return nil, err
+	}
 	if err != nil {
 		payment.MarkAsFailed(err.Error())
 		_ = uc.repo.Update(ctx, payment)
@@ -86,13 +216,59 @@ func (uc *PaymentUseCase) ProcessPayment(ctx context.Context, id string) (*domai
 	}
 
 	payment.MarkAsCompleted(providerID, response)
-	if err := uc.repo.Update(ctx, payment); err != nil {
+	return uc.saveOutcome(ctx, payment)
+}
+
+// saveOutcome saves the provider's answer for a payment being processed. If
+// the payment changed since it was claimed, e.g. the provider's webhook
+// settled it first and it was refunded since, the stored payment is returned
+// instead of overwriting it.
+func (uc *PaymentUseCase) saveOutcome(ctx context.Context, payment *domain.Payment) (*domain.Payment, error) {
+	err := uc.repo.Update(ctx, payment)
+	if errors.Is(err, domain.ErrPaymentConflict) {
+		return uc.repo.FindByID(ctx, payment.ID)
+	}
+	if err != nil {
 		return nil, err
 	}
-
 	return payment, nil
 }
 
+// startProcessing starts a new attempt for a pending or failed payment before
+// it is handed to the provider, once the attempt passed the risk assessment. A
+// payment already PROCESSING, awaiting the customer or with a lost provider
+// answer, is left as it is, so the provider picks up where it stopped with the
+// same idempotency keys.
+func (uc *PaymentUseCase) startProcessing(ctx context.Context, payment *domain.Payment) error {
+	if payment.Status == domain.PaymentStatusProcessing {
+		return nil
+	}
+	if !payment.CanStartAttempt() {
+		return domain.ErrPaymentAlreadyProcessed
+	}
+	if err := uc.assessRisk(ctx, payment); err != nil {
+		return err
+	}
+	if payment.Status == domain.PaymentStatusFailed && payment.ProviderID != "" {
+		// Cancel the failed attempt at the provider so it cannot still
+		// succeed, e.g. after a late 3D Secure, next to the new one
+		if err := uc.provider.VoidPayment(ctx, payment); err != nil {
+			return fmt.Errorf("failed to cancel previous payment attempt: %w", err)
+		}
+	}
+	if err := payment.MarkAsProcessing(); err != nil {
+		return err
+	}
+	if err := uc.repo.Update(ctx, payment); err != nil {
+		if errors.Is(err, domain.ErrPaymentConflict) {
+			// Claimed, cancelled or settled by a concurrent request
+			return domain.ErrPaymentAlreadyProcessed
+		}
+		return err
+	}
+	return nil
+}
+
 // GetPayment retrieves a payment by ID
 func (uc *PaymentUseCase) GetPayment(ctx context.Context, id string) (*domain.Payment, error) {
 	return uc.repo.FindByID(ctx, id)
@@ -108,20 +284,66 @@ func (uc *PaymentUseCase) GetUserPayments(ctx context.Context, userID string, li
 	return uc.repo.FindByUserID(ctx, userID, limit, offset)
 }
 
-// RefundPayment processes a refund
-func (uc *PaymentUseCase) RefundPayment(ctx context.Context, id string) error {
-	payment, err := uc.repo.FindByID(ctx, id)
-	if err != nil {
-		return err
-	}
+// RefundPayment refunds the given amount of a payment through the provider,
+// or everything not refunded yet when the amount is zero.
+// The payment becomes PARTIALLY_REFUNDED until the full amount has been
+// refunded, then REFUNDED.
+//
+// Parameters:
+//   - amount: Amount to refund in the payment currency (0 for the remainder)
+//   - currency: Currency of the amount; empty means the payment currency
+//   - reason: Reason code (duplicate, fraudulent, requested_by_customer) or free text
+//   - requestKey: Caller's idempotency key, if any; a retry with it returns the
+//     refund already issued
+//
+// Returns:
+//   - *domain.Refund: The recorded refund with the provider refund ID
+//   - error: ErrRefundNotAllowed, ErrInvalidAmount or ErrCurrencyMismatch when
+//     the refund is rejected, or the provider/persistence error
+func (uc *PaymentUseCase) RefundPayment(ctx context.Context, id string, amount float64, currency, reason, requestKey string) (*domain.Refund, error) {
+	// The payment stays locked from validating the refund until it is
+	// recorded, so concurrent refunds cannot both pass against the same
+	// refunded amount
+	var replayed *domain.Refund
+	refund, err := uc.repo.RefundLocked(ctx, id, func(payment *domain.Payment) (*domain.Refund, error) {
+		if currency != "" && !strings.EqualFold(currency, payment.Currency) {
+			return nil, domain.ErrCurrencyMismatch
+		}
 
-	if err := payment.Refund(); err != nil {
-		return err
-	}
+		// A retried request gets the refund it already issued
+		if requestKey != "" {
+			existing, err := uc.repo.FindRefundByIdempotencyKey(ctx, domain.RequestedRefundKey(payment.ID, requestKey))
+			if err == nil {
+				replayed = existing
+				return nil, nil
+			}
+			if !errors.Is(err, domain.ErrRefundNotFound) {
+				return nil, err
+			}
+		}
 
-	if err := uc.provider.RefundPayment(ctx, payment.ProviderID, payment.Amount); err != nil {
-		return err
+		refund, err := domain.NewRefund(payment, amount, reason, requestKey)
+		if err != nil {
+			return nil, err
+		}
+
+		providerRefundID, err := uc.provider.RefundPayment(ctx, payment, refund)
+		if err != nil {
+			return nil, err
+		}
+		refund.ProviderRefundID = providerRefundID
+
+		if err := payment.Refund(refund.Amount); err != nil {
+			return nil, err
+		}
+		return refund, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if replayed != nil {
+		return replayed, nil
 	}
 
-	return uc.repo.Update(ctx, payment)
+	return refund, nil
 }
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// newOrderPayment returns payment pay-1 of 25.00 USD for order-1 in the given status
+func newOrderPayment(t *testing.T, status domain.PaymentStatus) *domain.Payment {
+	t.Helper()
+
+	payment, err := domain.NewPayment("order-1", "user-1", 25, "USD", domain.PaymentMethodStripe)
+	if err != nil {
+		t.Fatalf("NewPayment: %v", err)
+	}
+	payment.ID = "pay-1"
+	payment.Status = status
+	return payment
+}
+
+func TestCreatePaymentSingleActivePayment(t *testing.T) {
+	tests := []struct {
+		name       string
+		stored     domain.PaymentStatus // Status of the order's payment pay-1; empty if it has none
+		concurrent bool                 // pay-1 is created by a concurrent request instead
+		amount     float64
+		wantErr    error
+		wantID     string
+	}{
+		{name: "first payment of the order", amount: 25, wantID: "pay-1"},
+		{name: "retried request returns the active payment", stored: domain.PaymentStatusProcessing, amount: 25, wantID: "pay-1"},
+		{name: "request for another amount is rejected", stored: domain.PaymentStatusPending, amount: 30, wantErr: domain.ErrActivePaymentExists},
+		{name: "failed payment is followed by a new one", stored: domain.PaymentStatusFailed, amount: 25, wantID: "pay-2"},
+		{name: "cancelled payment is followed by a new one", stored: domain.PaymentStatusCancelled, amount: 30, wantID: "pay-2"},
+		{name: "same request racing a concurrent one gets its payment", stored: domain.PaymentStatusPending, concurrent: true, amount: 25, wantID: "pay-1"},
+		{name: "other request racing a concurrent one is rejected", stored: domain.PaymentStatusPending, concurrent: true, amount: 30, wantErr: domain.ErrActivePaymentExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakePaymentRepo()
+			if tt.stored != "" {
+				if tt.concurrent {
+					repo.concurrent = newOrderPayment(t, tt.stored)
+				} else {
+					repo = newFakePaymentRepo(newOrderPayment(t, tt.stored))
+				}
+			}
+			uc := NewPaymentUseCase(repo, nil, nil, newFakeProvider(), nil, nil, nil, time.Hour)
+
+			payment, err := uc.CreatePayment(context.Background(), "order-1", "user-1", tt.amount, "USD", domain.PaymentMethodStripe, "")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CreatePayment error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreatePayment: %v", err)
+			}
+			if payment.ID != tt.wantID {
+				t.Errorf("CreatePayment returned %s, want %s", payment.ID, tt.wantID)
+			}
+
+			active := 0
+			for _, stored := range repo.payments {
+				if stored.IsActive() {
+					active++
+				}
+			}
+			if active != 1 {
+				t.Errorf("order has %d active payments, want 1", active)
+			}
+		})
+	}
+}
+
+// A retry of an attempt whose provider response was lost sends the same
+// idempotency key, so the provider replays the charge instead of charging again
+func TestProcessPaymentLostResponse(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakePaymentRepo(newOrderPayment(t, domain.PaymentStatusPending))
+	provider := newFakeProvider()
+	provider.lose = 1
+	uc := NewPaymentUseCase(repo, nil, nil, provider, nil, nil, nil, time.Hour)
+
+	if _, err := uc.ProcessPayment(ctx, "pay-1"); !errors.Is(err, domain.ErrPaymentOutcomeUnknown) {
+		t.Fatalf("ProcessPayment error = %v, want %v", err, domain.ErrPaymentOutcomeUnknown)
+	}
+	if payment := repo.get("pay-1"); payment.Status != domain.PaymentStatusProcessing {
+		t.Fatalf("payment is %s after the lost response, want %s", payment.Status, domain.PaymentStatusProcessing)
+	}
+
+	payment, err := uc.ProcessPayment(ctx, "pay-1")
+	if err != nil {
+		t.Fatalf("retried ProcessPayment: %v", err)
+	}
+	if payment.Status != domain.PaymentStatusCompleted || payment.ProviderID != "pi_1" {
+		t.Errorf("payment is %s with provider ID %q, want %s with pi_1", payment.Status, payment.ProviderID, domain.PaymentStatusCompleted)
+	}
+	if len(provider.sent) != 2 || provider.sent[0] != provider.sent[1] {
+		t.Errorf("idempotency keys sent = %q, want the same key twice", provider.sent)
+	}
+	if len(provider.charges) != 1 {
+		t.Errorf("provider charged %d times, want once", len(provider.charges))
+	}
+}
+
+// A cancel racing a charge never overwrites it: it is refused once the charge
+// claimed the payment, and the charge is never sent once the cancel saved first
+func TestCancelPaymentRacingProcessPayment(t *testing.T) {
+	tests := []struct {
+		name          string
+		cancelDuring  string // "claim" or "charge" of ProcessPayment
+		wantCancelErr error
+		wantStatus    domain.PaymentStatus
+		wantCharges   int
+	}{
+		{name: "cancel while the charge is sent is refused", cancelDuring: "charge", wantCancelErr: domain.ErrCancelNotAllowed, wantStatus: domain.PaymentStatusCompleted, wantCharges: 1},
+		{name: "cancel saved before the claim stops the charge", cancelDuring: "claim", wantStatus: domain.PaymentStatusCancelled, wantCharges: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newFakePaymentRepo(newOrderPayment(t, domain.PaymentStatusPending))
+			provider := newFakeProvider()
+			uc := NewPaymentUseCase(repo, nil, nil, provider, nil, nil, nil, time.Hour)
+
+			var cancelErr error
+			cancel := func() { _, cancelErr = uc.CancelPayment(ctx, "pay-1", "checkout compensated") }
+			if tt.cancelDuring == "claim" {
+				repo.beforeUpdate = cancel
+			} else {
+				provider.charging = cancel
+			}
+
+			_, processErr := uc.ProcessPayment(ctx, "pay-1")
+			if !errors.Is(cancelErr, tt.wantCancelErr) {
+				t.Errorf("CancelPayment error = %v, want %v", cancelErr, tt.wantCancelErr)
+			}
+			if tt.wantCharges == 0 && !errors.Is(processErr, domain.ErrPaymentAlreadyProcessed) {
+				t.Errorf("ProcessPayment error = %v, want %v", processErr, domain.ErrPaymentAlreadyProcessed)
+			}
+			if payment := repo.get("pay-1"); payment.Status != tt.wantStatus {
+				t.Errorf("payment is %s, want %s", payment.Status, tt.wantStatus)
+			}
+			if len(provider.charges) != tt.wantCharges {
+				t.Errorf("provider charged %d times, want %d", len(provider.charges), tt.wantCharges)
+			}
+		})
+	}
+}
+
+// Of two concurrent calls, only the one claiming the payment first charges it
+func TestProcessPaymentConcurrentCalls(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakePaymentRepo(newOrderPayment(t, domain.PaymentStatusPending))
+	provider := newFakeProvider()
+	uc := NewPaymentUseCase(repo, nil, nil, provider, nil, nil, nil, time.Hour)
+
+	var first *domain.Payment
+	var firstErr error
+	repo.beforeUpdate = func() { first, firstErr = uc.ProcessPayment(ctx, "pay-1") }
+
+	if _, err := uc.ProcessPayment(ctx, "pay-1"); !errors.Is(err, domain.ErrPaymentAlreadyProcessed) {
+		t.Errorf("losing ProcessPayment error = %v, want %v", err, domain.ErrPaymentAlreadyProcessed)
+	}
+	if firstErr != nil {
+		t.Fatalf("winning ProcessPayment: %v", firstErr)
+	}
+	if first.Status != domain.PaymentStatusCompleted {
+		t.Errorf("winning ProcessPayment returned a %s payment, want %s", first.Status, domain.PaymentStatusCompleted)
+	}
+	if len(provider.sent) != 1 {
+		t.Errorf("provider received %d charges, want 1", len(provider.sent))
+	}
+}
+
+// The provider's answer does not overwrite a payment settled and refunded
+// while it was on its way
+func TestProcessPaymentKeepsConcurrentRefund(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakePaymentRepo(newOrderPayment(t, domain.PaymentStatusPending))
+	provider := newFakeProvider()
+	uc := NewPaymentUseCase(repo, nil, nil, provider, nil, nil, nil, time.Hour)
+
+	provider.charging = func() {
+		_, err := repo.UpdateLocked(ctx, "pay-1", func(payment *domain.Payment) (bool, error) {
+			payment.MarkAsCompleted("pi_1", "succeeded")
+			return true, payment.Refund(25)
+		})
+		if err != nil {
+			t.Fatalf("settling and refunding: %v", err)
+		}
+	}
+
+	payment, err := uc.ProcessPayment(ctx, "pay-1")
+	if err != nil {
+		t.Fatalf("ProcessPayment: %v", err)
+	}
+	if payment.Status != domain.PaymentStatusRefunded {
+		t.Errorf("ProcessPayment returned a %s payment, want the stored %s one", payment.Status, domain.PaymentStatusRefunded)
+	}
+	if stored := repo.get("pay-1"); stored.Status != domain.PaymentStatusRefunded {
+		t.Errorf("payment is %s, want %s", stored.Status, domain.PaymentStatusRefunded)
+	}
+}
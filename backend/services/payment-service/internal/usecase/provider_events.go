@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// HandleProviderEvent applies an asynchronous provider notification to its
+// payment. Each event is applied at most once: redeliveries are recognised by
+// event ID and reported as duplicates.
+//
+// Dispute events also create or update the dispute, and the order of a newly
+// disputed payment is flagged with the order service. If that call fails, the
+// dispute job flags the order later.
+//
+// Returns:
+//   - bool: true if the event had already been processed
+//   - error: ErrPaymentNotFound if the event does not belong to a known payment
+func (uc *PaymentUseCase) HandleProviderEvent(ctx context.Context, event *domain.ProviderEvent) (bool, error) {
+	processed, err := uc.repo.IsEventProcessed(ctx, event.ID)
+	if err != nil {
+		return false, err
+	}
+	if processed {
+		return true, nil
+	}
+
+	payment, err := uc.findEventPayment(ctx, event)
+	if err != nil {
+		return false, err
+	}
+
+	if event.Type == domain.ProviderEventPaymentAuthorized && event.AuthorizationExpiresAt.IsZero() {
+		event.AuthorizationExpiresAt = event.OccurredAt.Add(uc.authorizationValidity)
+	}
+
+	// The event is applied to the payment as it is with its row locked, so a
+	// refund, capture or cancellation saved meanwhile is not overwritten
+	var dispute *domain.Dispute
+	recorded, err := uc.repo.RecordEvent(ctx, event, payment.ID, func(locked *domain.Payment) (bool, *domain.Dispute, error) {
+		changed := event.Apply(locked)
+		if event.IsDispute() {
+			var err error
+			if dispute, err = uc.applyDisputeEvent(ctx, event, locked); err != nil {
+				return false, nil, err
+			}
+		}
+		return changed, dispute, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if recorded && dispute != nil && dispute.NeedsOrderFlag() {
+		_ = uc.flagDisputedOrder(ctx, dispute)
+	}
+	return !recorded, nil
+}
+
+// applyDisputeEvent creates the dispute the event reports, or applies the
+// event to the known dispute. It returns the dispute if it is new or changed.
+func (uc *PaymentUseCase) applyDisputeEvent(ctx context.Context, event *domain.ProviderEvent, payment *domain.Payment) (*domain.Dispute, error) {
+	dispute, err := uc.disputes.FindByProviderDisputeID(ctx, event.DisputeID)
+	switch {
+	case errors.Is(err, domain.ErrDisputeNotFound):
+		return domain.NewDispute(payment, event), nil
+	case err != nil:
+		return nil, err
+	}
+	if !dispute.ApplyProviderEvent(event) {
+		return nil, nil
+	}
+	return dispute, nil
+}
+
+// findEventPayment looks the payment up by provider ID, falling back to the
+// payment ID in the provider metadata for events that arrive before the
+// provider ID has been stored
+func (uc *PaymentUseCase) findEventPayment(ctx context.Context, event *domain.ProviderEvent) (*domain.Payment, error) {
+	if event.ProviderPaymentID != "" {
+		payment, err := uc.repo.FindByProviderID(ctx, event.ProviderPaymentID)
+		if err == nil || !errors.Is(err, domain.ErrPaymentNotFound) {
+			return payment, err
+		}
+	}
+	if event.PaymentID == "" {
+		return nil, domain.ErrPaymentNotFound
+	}
+	return uc.repo.FindByID(ctx, event.PaymentID)
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// newAwaitingPayment returns a payment handed to Stripe that waits for the
+// customer's 3D Secure step
+func newAwaitingPayment(t *testing.T) *domain.Payment {
+	t.Helper()
+
+	payment, err := domain.NewPayment("order-1", "user-1", 25, "USD", domain.PaymentMethodStripe)
+	if err != nil {
+		t.Fatalf("NewPayment: %v", err)
+	}
+	payment.ID = "pay-1"
+	if err := payment.MarkAsProcessing(); err != nil {
+		t.Fatalf("MarkAsProcessing: %v", err)
+	}
+	payment.MarkAsAwaitingAction("pi_1", "requires_action")
+	return payment
+}
+
+// Each step runs against the state the previous ones left
+func TestHandleProviderEventDedupe(t *testing.T) {
+	repo := newFakePaymentRepo(newAwaitingPayment(t))
+	uc := NewPaymentUseCase(repo, nil, nil, nil, nil, nil, nil, time.Hour)
+
+	succeeded := domain.ProviderEvent{
+		ID:                "evt_1",
+		Type:              domain.ProviderEventPaymentSucceeded,
+		ProviderPaymentID: "pi_1",
+		Status:            "succeeded",
+		AmountReceived:    25,
+		OccurredAt:        time.Now(),
+	}
+	unknown := succeeded
+	unknown.ID = "evt_2"
+	unknown.ProviderPaymentID = "pi_other"
+
+	steps := []struct {
+		name          string
+		event         domain.ProviderEvent
+		wantDuplicate bool
+		wantErr       error
+	}{
+		{name: "first delivery settles the payment", event: succeeded},
+		{name: "redelivery is reported as a duplicate", event: succeeded, wantDuplicate: true},
+		{name: "event of an unknown payment", event: unknown, wantErr: domain.ErrPaymentNotFound},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			event := step.event
+			duplicate, err := uc.HandleProviderEvent(context.Background(), &event)
+			if step.wantErr != nil {
+				if !errors.Is(err, step.wantErr) {
+					t.Fatalf("HandleProviderEvent error = %v, want %v", err, step.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("HandleProviderEvent: %v", err)
+			}
+			if duplicate != step.wantDuplicate {
+				t.Errorf("duplicate = %v, want %v", duplicate, step.wantDuplicate)
+			}
+		})
+	}
+
+	if payment := repo.get("pay-1"); payment.Status != domain.PaymentStatusCompleted {
+		t.Errorf("payment is %s, want %s", payment.Status, domain.PaymentStatusCompleted)
+	}
+	if repo.updates != 1 {
+		t.Errorf("payment saved %d times, want once", repo.updates)
+	}
+}
+
+// A refund saved while a provider event is handled is kept
+func TestHandleProviderEventKeepsConcurrentRefund(t *testing.T) {
+	payment := newAwaitingPayment(t)
+	payment.MarkAsCompleted("pi_1", "succeeded")
+	repo := newFakePaymentRepo(payment)
+	uc := NewPaymentUseCase(repo, nil, nil, nil, nil, nil, nil, time.Hour)
+
+	repo.interleave = func(payment *domain.Payment) {
+		if err := payment.Refund(10); err != nil {
+			t.Fatalf("Refund: %v", err)
+		}
+	}
+	event := domain.ProviderEvent{
+		ID:                "evt_1",
+		Type:              domain.ProviderEventChargeRefunded,
+		ProviderPaymentID: "pi_1",
+		AmountRefunded:    5,
+		OccurredAt:        time.Now(),
+	}
+	if _, err := uc.HandleProviderEvent(context.Background(), &event); err != nil {
+		t.Fatalf("HandleProviderEvent: %v", err)
+	}
+
+	if got := repo.get("pay-1").RefundedAmount; got != 10 {
+		t.Errorf("RefundedAmount = %v, want the concurrent refund of 10", got)
+	}
+}
+
+// A dispute opened while a refund is saved marks the refunded payment disputed
+func TestHandleDisputeEventKeepsConcurrentRefund(t *testing.T) {
+	payment := newAwaitingPayment(t)
+	payment.MarkAsCompleted("pi_1", "succeeded")
+	repo := newFakePaymentRepo(payment)
+	uc := NewPaymentUseCase(repo, nil, fakeDisputeRepo{}, nil, fakeOrderService{}, nil, nil, time.Hour)
+
+	repo.interleave = func(payment *domain.Payment) {
+		if err := payment.Refund(10); err != nil {
+			t.Fatalf("Refund: %v", err)
+		}
+	}
+	event := domain.ProviderEvent{
+		ID:                "evt_1",
+		Type:              domain.ProviderEventDisputeCreated,
+		ProviderPaymentID: "pi_1",
+		DisputeID:         "dp_1",
+		OccurredAt:        time.Now(),
+	}
+	if _, err := uc.HandleProviderEvent(context.Background(), &event); err != nil {
+		t.Fatalf("HandleProviderEvent: %v", err)
+	}
+
+	stored := repo.get("pay-1")
+	if stored.DisputedAt == nil {
+		t.Error("payment is not marked disputed")
+	}
+	if stored.RefundedAmount != 10 {
+		t.Errorf("RefundedAmount = %v, want the concurrent refund of 10", stored.RefundedAmount)
+	}
+}
+
+// Funds the provider collects for a cancelled payment are flagged as owed back
+func TestHandleSucceededEventForCancelledPayment(t *testing.T) {
+	payment := newAwaitingPayment(t)
+	if err := payment.Cancel("order cancelled"); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	repo := newFakePaymentRepo(payment)
+	uc := NewPaymentUseCase(repo, nil, nil, nil, nil, nil, nil, time.Hour)
+
+	for i, id := range []string{"evt_1", "evt_2"} {
+		event := domain.ProviderEvent{
+			ID:                id,
+			Type:              domain.ProviderEventPaymentSucceeded,
+			ProviderPaymentID: "pi_1",
+			Status:            "succeeded",
+			AmountReceived:    25,
+			OccurredAt:        time.Now(),
+		}
+		if _, err := uc.HandleProviderEvent(context.Background(), &event); err != nil {
+			t.Fatalf("HandleProviderEvent(%d): %v", i, err)
+		}
+	}
+
+	stored := repo.get("pay-1")
+	if stored.Status != domain.PaymentStatusCancelled {
+		t.Errorf("payment is %s, want %s", stored.Status, domain.PaymentStatusCancelled)
+	}
+	if stored.CollectedAfterCancelAt == nil {
+		t.Error("payment is not flagged as collected after cancellation")
+	}
+	if repo.updates != 1 {
+		t.Errorf("payment saved %d times, want once", repo.updates)
+	}
+}
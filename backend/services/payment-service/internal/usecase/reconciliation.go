@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/pkg/logger"
+)
+
+const (
+	reconciliationInterval = time.Hour
+	reconciliationPeriod   = 24 * time.Hour
+	reconciliationBackfill = 7 // Most past periods a single run catches up on
+)
+
+// SettlementSource defines the interface for reading what a provider settled
+type SettlementSource interface {
+	ListBalanceTransactions(ctx context.Context, from, to time.Time) ([]domain.BalanceTransaction, error)
+}
+
+// ReconciliationRepository defines the interface for reconciliation data access
+type ReconciliationRepository interface {
+	FindPaymentsCreatedBetween(ctx context.Context, provider string, from, to time.Time) ([]domain.Payment, error)
+	FindPaymentsByProviderIDs(ctx context.Context, providerIDs []string) ([]domain.Payment, error)
+	FindRefundsByProviderRefundIDs(ctx context.Context, providerRefundIDs []string) ([]domain.Refund, error)
+	SaveReport(ctx context.Context, report *domain.ReconciliationReport) error // replaces the report of the same provider and period
+	FindReport(ctx context.Context, id string) (*domain.ReconciliationReport, error)
+	FindReportByPeriod(ctx context.Context, provider string, periodStart time.Time) (*domain.ReconciliationReport, error)
+	FindLatestReport(ctx context.Context, provider string) (*domain.ReconciliationReport, error)
+}
+
+// Reconciler compares payments with the settlement data of their provider
+// and records the differences in reconciliation reports. Periods are UTC days.
+type Reconciler struct {
+	repo    ReconciliationRepository
+	sources map[string]SettlementSource // By provider name
+	lag     time.Duration               // How long after a period its charges may still settle
+}
+
+// NewReconciler creates a reconciler for the providers with a settlement
+// source. A period is reconciled once lag has passed since its end.
+func NewReconciler(repo ReconciliationRepository, sources map[string]SettlementSource, lag time.Duration) *Reconciler {
+	return &Reconciler{
+		repo:    repo,
+		sources: sources,
+		lag:     lag,
+	}
+}
+
+// Reconcile reconciles a provider's payments created in [from, to) with the
+// charges and refunds it settled, and saves the report, replacing an earlier
+// report of the same period.
+//
+// Returns:
+//   - *domain.ReconciliationReport: The saved report
+//   - error: ErrNoPaymentProvider if the provider has no settlement source,
+//     or the provider or database error
+func (r *Reconciler) Reconcile(ctx context.Context, provider string, from, to time.Time) (*domain.ReconciliationReport, error) {
+	source, ok := r.sources[provider]
+	if !ok {
+		return nil, fmt.Errorf("%w: no settlement data for provider %q", domain.ErrNoPaymentProvider, provider)
+	}
+
+	// Charges of payments created just before the end settle after it
+	transactions, err := source.ListBalanceTransactions(ctx, from, to.Add(r.lag))
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := r.repo.FindPaymentsCreatedBetween(ctx, provider, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments to reconcile: %w", err)
+	}
+
+	// Load the payments and refunds the transactions refer to
+	known := make(map[string]bool, len(payments))
+	for _, payment := range payments {
+		known[payment.ProviderID] = true
+	}
+	var providerIDs, refundIDs []string
+	for _, txn := range transactions {
+		if txn.ProviderPaymentID != "" && !known[txn.ProviderPaymentID] {
+			known[txn.ProviderPaymentID] = true
+			providerIDs = append(providerIDs, txn.ProviderPaymentID)
+		}
+		if txn.ProviderRefundID != "" {
+			refundIDs = append(refundIDs, txn.ProviderRefundID)
+		}
+	}
+	referenced, err := r.repo.FindPaymentsByProviderIDs(ctx, providerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settled payments: %w", err)
+	}
+	refunds, err := r.repo.FindRefundsByProviderRefundIDs(ctx, refundIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settled refunds: %w", err)
+	}
+
+	report := domain.Reconcile(provider, from, to, append(payments, referenced...), refunds, transactions)
+	if err := r.repo.SaveReport(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ReconcilePending reconciles, for every provider with a settlement source,
+// the periods that have ended and settled since its latest report. A provider
+// without reports starts with the most recent settled period.
+//
+// Returns:
+//   - []*domain.ReconciliationReport: The reports written
+//   - error: Failures of individual providers, joined
+func (r *Reconciler) ReconcilePending(ctx context.Context) ([]*domain.ReconciliationReport, error) {
+	// The newest period whose charges have all settled
+	last := time.Now().UTC().Add(-r.lag).Truncate(reconciliationPeriod).Add(-reconciliationPeriod)
+
+	providers := make([]string, 0, len(r.sources))
+	for provider := range r.sources {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	var reports []*domain.ReconciliationReport
+	var errs []error
+	for _, provider := range providers {
+		next := last
+		latest, err := r.repo.FindLatestReport(ctx, provider)
+		switch {
+		case err == nil:
+			next = latest.PeriodStart.UTC().Add(reconciliationPeriod)
+			if oldest := last.Add(-(reconciliationBackfill - 1) * reconciliationPeriod); next.Before(oldest) {
+				next = oldest
+			}
+		case !errors.Is(err, domain.ErrReconciliationReportNotFound):
+			errs = append(errs, fmt.Errorf("provider %s: %w", provider, err))
+			continue
+		}
+
+		for from := next; !from.After(last); from = from.Add(reconciliationPeriod) {
+			report, err := r.Reconcile(ctx, provider, from, from.Add(reconciliationPeriod))
+			if err != nil {
+				// Retry from this period next time
+				errs = append(errs, fmt.Errorf("provider %s, period %s: %w", provider, from.Format(time.DateOnly), err))
+				break
+			}
+			reports = append(reports, report)
+		}
+	}
+	return reports, errors.Join(errs...)
+}
+
+// GetReport returns a reconciliation report. The report is looked up by ID
+// if one is given, otherwise by provider and the day its period starts, or
+// as the provider's latest report if no day is given.
+//
+// Returns:
+//   - *domain.ReconciliationReport: The report with its discrepancies
+//   - error: ErrReconciliationReportNotFound if no report matches
+func (r *Reconciler) GetReport(ctx context.Context, id, provider string, day time.Time) (*domain.ReconciliationReport, error) {
+	switch {
+	case id != "":
+		return r.repo.FindReport(ctx, id)
+	case provider != "" && !day.IsZero():
+		return r.repo.FindReportByPeriod(ctx, provider, day.UTC().Truncate(reconciliationPeriod))
+	case provider != "":
+		return r.repo.FindLatestReport(ctx, provider)
+	default:
+		return nil, domain.ErrReconciliationReportNotFound
+	}
+}
+
+// Start starts a background job that reconciles each period once it has settled
+func (r *Reconciler) Start(ctx context.Context, log logger.Logger) {
+	ticker := time.NewTicker(reconciliationInterval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				reports, err := r.ReconcilePending(ctx)
+				if err != nil {
+					log.Error("Failed to reconcile some payments", "error", err)
+				}
+				for _, report := range reports {
+					if report.IsClean() {
+						log.Info("Payments reconciled", "provider", report.Provider,
+							"period", report.PeriodStart.Format(time.DateOnly), "payments", report.PaymentCount)
+						continue
+					}
+					log.Warn("Payment reconciliation found discrepancies", "provider", report.Provider,
+						"period", report.PeriodStart.Format(time.DateOnly), "discrepancies", len(report.Discrepancies),
+						"report_id", report.ID)
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	log.Info("Started payment reconciliation job", "lag", r.lag.String())
+}
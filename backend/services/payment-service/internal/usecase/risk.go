@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cqchien/ecomerce-rec/backend/services/payment-service/internal/domain"
+)
+
+// RiskEvaluator defines the interface for assessing the fraud risk of a
+// payment attempt before it reaches the provider
+type RiskEvaluator interface {
+	Evaluate(ctx context.Context, payment *domain.Payment) (*domain.RiskAssessment, error)
+}
+
+// RiskHistory defines the interface for the payment attempts the velocity rules count
+type RiskHistory interface {
+	CountUserAttemptsSince(ctx context.Context, userID string, since time.Time) (int, error)
+	CountMethodAttemptsSince(ctx context.Context, paymentMethodID string, since time.Time) (int, error)
+}
+
+// OrderAddresses defines the interface for the addresses of the order a payment is for
+type OrderAddresses interface {
+	GetOrderCountries(ctx context.Context, orderID string) (string, string, error) // returns the shipping and billing countries
+}
+
+// UserAccounts defines the interface for the account of the user paying
+type UserAccounts interface {
+	GetAccountCreatedAt(ctx context.Context, userID string) (*time.Time, error) // nil if unknown
+}
+
+// RuleRiskEvaluator assesses payments with fixed rules: payment velocity per
+// user and per saved card, amount thresholds, a card country that differs
+// from the shipping country, and new accounts
+type RuleRiskEvaluator struct {
+	rules   domain.RiskRules
+	window  time.Duration // Window the velocity rules count payments in
+	history RiskHistory
+	methods PaymentMethodRepository
+	orders  OrderAddresses // nil to skip the country rule
+	users   UserAccounts   // nil to skip the new account rule
+}
+
+// NewRuleRiskEvaluator creates a rule-based risk evaluator. Rules whose data
+// source is nil are skipped.
+func NewRuleRiskEvaluator(rules domain.RiskRules, window time.Duration, history RiskHistory, methods PaymentMethodRepository, orders OrderAddresses, users UserAccounts) *RuleRiskEvaluator {
+	return &RuleRiskEvaluator{
+		rules:   rules,
+		window:  window,
+		history: history,
+		methods: methods,
+		orders:  orders,
+		users:   users,
+	}
+}
+
+// Evaluate gathers the signals of a payment and scores them with the rules.
+// It fails if a signal cannot be gathered, e.g. while the order service is
+// down, so the payment is not charged unassessed.
+func (e *RuleRiskEvaluator) Evaluate(ctx context.Context, payment *domain.Payment) (*domain.RiskAssessment, error) {
+	now := time.Now()
+	signals := domain.RiskSignals{VelocityWindow: e.window}
+
+	// The attempt being assessed has not been started, so it is not logged yet
+	userAttempts, err := e.history.CountUserAttemptsSince(ctx, payment.UserID, now.Add(-e.window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count payment attempts of user: %w", err)
+	}
+	signals.UserPayments = userAttempts + 1
+	if payment.PaymentMethodID != "" {
+		cardAttempts, err := e.history.CountMethodAttemptsSince(ctx, payment.PaymentMethodID, now.Add(-e.window))
+		if err != nil {
+			return nil, fmt.Errorf("failed to count payment attempts of card: %w", err)
+		}
+		signals.CardPayments = cardAttempts + 1
+		// The card may have been removed since the payment was created
+		method, err := e.methods.FindByID(ctx, payment.PaymentMethodID)
+		switch {
+		case err == nil:
+			signals.CardCountry = method.Country
+		case !errors.Is(err, domain.ErrPaymentMethodNotFound):
+			return nil, err
+		}
+	}
+
+	// Large amounts are compared in the payment currency or, without a
+	// threshold for it, in the settlement currency
+	signals.Amount, signals.AmountCurrency = payment.Amount, payment.Currency
+	if _, ok := e.rules.AmountThresholds[payment.Currency]; !ok && payment.SettlementCurrency != "" {
+		signals.Amount, signals.AmountCurrency = payment.SettlementAmount(), payment.SettlementCurrency
+	}
+
+	if e.orders != nil {
+		shipping, billing, err := e.orders.GetOrderCountries(ctx, payment.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		signals.ShippingCountry = shipping
+		if signals.CardCountry == "" {
+			signals.CardCountry = billing
+		}
+	}
+	if e.users != nil {
+		if signals.AccountCreatedAt, err = e.users.GetAccountCreatedAt(ctx, payment.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	return e.rules.Assess(signals, now), nil
+}
+
+// assessRisk assesses a payment attempt and records the assessment. A denied
+// payment is saved as FAILED.
+//
+// Returns:
+//   - error: ErrPaymentRiskDenied if the payment was denied, or the
+//     evaluator or database error
+func (uc *PaymentUseCase) assessRisk(ctx context.Context, payment *domain.Payment) error {
+	if uc.risk == nil {
+		return nil
+	}
+
+	assessment, err := uc.risk.Evaluate(ctx, payment)
+	if err != nil {
+		return fmt.Errorf("failed to assess payment risk: %w", err)
+	}
+	if err := payment.SetRiskAssessment(assessment); err != nil {
+		if updateErr := uc.repo.Update(ctx, payment); updateErr != nil {
+			return updateErr
+		}
+		return err
+	}
+	return nil
+}
@@ -30,9 +30,53 @@ type Config struct {
 	RedisDB       int
 	RedisURL      string
 
+	// Kafka
+	KafkaBrokers string
+	KafkaTopic   string
+
 	// Stripe
 	StripePublishableKey string
 	StripeSecretKey      string
+	StripeAPIBase        string // Overrides the Stripe API URL, e.g. to use the local stub server
+	StripeWebhookSecret  string // Signing secret of the webhook endpoint; the endpoint is disabled when empty
+
+	// PayPal
+	PayPalClientID     string // PayPal is only available when set
+	PayPalClientSecret string
+	PayPalAPIBase      string
+	PayPalReturnURL    string // Where buyers land after approving a PayPal order
+	PayPalCancelURL    string // Where buyers land after cancelling a PayPal order
+
+	// Mock provider for local development and integration tests
+	PaymentMockOutcome   string // succeed, decline or timeout; empty leaves the mock unregistered
+	PaymentMockTimeoutMs int    // How long a timing out mock call hangs
+
+	// Payments
+	AuthorizationValidityHours int    // How long an authorization can be captured before it is released
+	PaymentRoutes              string // Provider per payment method and currency, see payment.ParseRoutes
+	PaymentVaultProvider       string // Provider storing saved payment methods
+
+	// Currencies
+	SettlementCurrency string // Currency the merchant is paid out in; payments take no exchange rate snapshot when empty
+	ExchangeRates      string // Rates into the settlement currency, see exchange.NewStaticRates
+
+	// Risk assessment
+	RiskReviewScore           int    // Payments scoring at least this are charged and flagged for review; 0 never reviews
+	RiskDenyScore             int    // Payments scoring at least this are declined; 0 never denies
+	RiskVelocityWindowMinutes int    // Window the velocity rules count payments in
+	RiskMaxUserPayments       int    // Payment attempts a user may start within the window; 0 turns the rule off
+	RiskMaxCardPayments       int    // Payment attempts a saved card may start within the window; 0 turns the rule off
+	RiskAmountThresholds      string // Large amounts by currency, see domain.ParseAmountThresholds
+	RiskNewAccountHours       int    // Accounts younger than this are new; 0 turns the rule off
+
+	// Reconciliation
+	ReconciliationLagHours        int    // How long after a day its charges may still settle; the day is reconciled after that
+	ReconciliationFixturePath     string // JSON file of balance transactions standing in for a provider's settlement data
+	ReconciliationFixtureProvider string // Provider whose settlement data the fixture replaces
+
+	// Services
+	OrderServiceGRPC string // Order service address, to flag disputed orders and read shipping addresses
+	UserServiceGRPC  string // User service address, to read account ages for the new account rule
 }
 
 // Load loads configuration from environment variables
@@ -56,8 +100,45 @@ func Load() *Config {
 		RedisPassword: getEnv("REDIS_PASSWORD", "redis123"),
 		RedisDB:       getEnvAsInt("REDIS_DB", 0),
 
+		KafkaBrokers: getEnv("KAFKA_BROKERS", "localhost:9092"),
+		KafkaTopic:   getEnv("KAFKA_TOPIC", "ecommerce-events"),
+
 		StripePublishableKey: getEnv("STRIPE_PUBLISHABLE_KEY", ""),
 		StripeSecretKey:      getEnv("STRIPE_SECRET_KEY", ""),
+		StripeAPIBase:        getEnv("STRIPE_API_BASE", ""),
+		StripeWebhookSecret:  getEnv("STRIPE_WEBHOOK_SECRET", ""),
+
+		PayPalClientID:     getEnv("PAYPAL_CLIENT_ID", ""),
+		PayPalClientSecret: getEnv("PAYPAL_CLIENT_SECRET", ""),
+		PayPalAPIBase:      getEnv("PAYPAL_API_BASE", "https://api-m.sandbox.paypal.com"),
+		PayPalReturnURL:    getEnv("PAYPAL_RETURN_URL", "http://localhost:3000/checkout/paypal/return"),
+		PayPalCancelURL:    getEnv("PAYPAL_CANCEL_URL", "http://localhost:3000/checkout/paypal/cancel"),
+
+		PaymentMockOutcome:   getEnv("PAYMENT_MOCK_OUTCOME", ""),
+		PaymentMockTimeoutMs: getEnvAsInt("PAYMENT_MOCK_TIMEOUT_MS", 30000),
+
+		// Stripe card authorizations lapse after 7 days; release them a day earlier
+		AuthorizationValidityHours: getEnvAsInt("AUTHORIZATION_VALIDITY_HOURS", 144),
+		PaymentRoutes:              getEnv("PAYMENT_ROUTES", "CREDIT_CARD=stripe;DEBIT_CARD=stripe;STRIPE=stripe;PAYPAL=paypal"),
+		PaymentVaultProvider:       getEnv("PAYMENT_VAULT_PROVIDER", "stripe"),
+
+		SettlementCurrency: getEnv("SETTLEMENT_CURRENCY", ""),
+		ExchangeRates:      getEnv("EXCHANGE_RATES", ""),
+
+		RiskReviewScore:           getEnvAsInt("RISK_REVIEW_SCORE", 40),
+		RiskDenyScore:             getEnvAsInt("RISK_DENY_SCORE", 80),
+		RiskVelocityWindowMinutes: getEnvAsInt("RISK_VELOCITY_WINDOW_MINUTES", 60),
+		RiskMaxUserPayments:       getEnvAsInt("RISK_MAX_USER_PAYMENTS", 5),
+		RiskMaxCardPayments:       getEnvAsInt("RISK_MAX_CARD_PAYMENTS", 3),
+		RiskAmountThresholds:      getEnv("RISK_AMOUNT_THRESHOLDS", "USD=1000;EUR=1000;GBP=850;JPY=150000"),
+		RiskNewAccountHours:       getEnvAsInt("RISK_NEW_ACCOUNT_HOURS", 24),
+
+		ReconciliationLagHours:        getEnvAsInt("RECONCILIATION_LAG_HOURS", 6),
+		ReconciliationFixturePath:     getEnv("RECONCILIATION_FIXTURE_PATH", ""),
+		ReconciliationFixtureProvider: getEnv("RECONCILIATION_FIXTURE_PROVIDER", "mock"),
+
+		OrderServiceGRPC: getEnv("ORDER_SERVICE_GRPC", "localhost:50053"),
+		UserServiceGRPC:  getEnv("USER_SERVICE_GRPC", "localhost:5001"),
 	}
 
 	// Build composite URLs